@@ -0,0 +1,226 @@
+package automation
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wio"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const AttenuationModifierSpecVersion = 0
+
+type AttenuationModifierSpec struct {
+	Version     uint8                 `json:"version"`
+	Modifiers []AttenuationModifier `json:"modifiers"`
+}
+
+type AttenuationModifier struct {
+	// Attenuation to modify, or to clone from if New is set
+	Id          uint32                    `json:"id"`
+	// Clone the attenuation into a new attenuation with a new ID, and apply
+	// all modifications on the clone instead
+	New         bool                      `json:"new"`
+	// Set this to any non-positive value if it's unused
+	Scale       float32                   `json:"scale"`
+	// Set this to any non-positive value if it's unused. Applied after Scale
+	MaxDistance float32                   `json:"maxDistance"`
+	Presets   []AttenuationPresetModifier `json:"presets"`
+	// Hierarchy objects that will use the (new or modified) attenuation
+	Targets   []uint32                    `json:"targets"`
+}
+
+type AttenuationPresetModifier struct {
+	// Index of distance property (See AttenuationDistancePropertyG141)
+	Property  int                         `json:"property"`
+	Preset    wwise.AttenuationPresetType `json:"preset"`
+	// Default to max distance of the attenuation if non-positive
+	Distance  float32                     `json:"distance"`
+	// Reference distance for inverse square
+	Reference float32                     `json:"reference"`
+	// Floor volume in dB
+	Floor     float32                     `json:"floor"`
+	// Custom only
+	Scaling   wwise.CurveScalingType      `json:"scaling"`
+	Points  []wwise.RTPCGraphPoint        `json:"points"`
+}
+
+func ParseAttenuationModifierSpec(spec *AttenuationModifierSpec, fspec string) error {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return fmt.Errorf("Failed to open attenuation modifier script %s: %w", fspec, err)
+	}
+	err = json.Unmarshal(blob, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to decode attenuation modifier script %s: %w", fspec, err)
+	}
+	if spec.Version != AttenuationModifierSpecVersion {
+		return fmt.Errorf("Version spec should be %d!", AttenuationModifierSpecVersion)
+	}
+	return nil
+}
+
+func ModifyAttenuations(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+
+	var spec AttenuationModifierSpec
+	if err := ParseAttenuationModifierSpec(&spec, fspec); err != nil {
+		return err
+	}
+	if len(spec.Modifiers) <= 0 {
+		slog.Warn("No attenuation modifiers are provided. Do nothing")
+		return nil
+	}
+
+	ver := int(bnk.BKHD().BankGenerationVersion)
+
+	requireID := false
+	for _, m := range spec.Modifiers {
+		if m.New {
+			requireID = true
+			break
+		}
+	}
+
+	var commit func() error = func() error { return nil }
+	var rollback func() = func() {}
+	tryHid := func() (uint32, error) { panic("Panic Trap") }
+	if requireID {
+		q, closeConn, c, r, err := db.CreateConnWithTxQuery(ctx)
+		if err != nil {
+			return err
+		}
+		defer closeConn()
+		commit, rollback = c, r
+		tryHid = func() (uint32, error) { return db.TryHid(ctx, q) }
+	}
+
+	for _, m := range spec.Modifiers {
+		v, in := h.Attenuations.Load(m.Id)
+		if !in {
+			slog.Error(fmt.Sprintf("No attenuation has ID %d", m.Id))
+			continue
+		}
+		a := v.(*wwise.Attenuation)
+
+		if m.New {
+			id, err := tryHid()
+			if err != nil {
+				rollback()
+				return err
+			}
+			var clone wwise.Attenuation
+			a.Clone(id, &clone)
+			h.AppendNewAttenuation(&clone)
+			slog.Info(fmt.Sprintf("Created attenuation %d from attenuation %d", id, m.Id))
+			a = &clone
+		}
+
+		if m.Scale > 0 {
+			if err := a.ScaleMaxDistance(m.Scale); err != nil {
+				slog.Error(fmt.Sprintf("Failed to scale max distance of attenuation %d", a.Id), "error", err)
+			}
+		}
+		if m.MaxDistance > 0 {
+			if err := a.SetMaxDistance(m.MaxDistance); err != nil {
+				slog.Error(fmt.Sprintf("Failed to set max distance of attenuation %d", a.Id), "error", err)
+			}
+		}
+
+		for _, p := range m.Presets {
+			if err := applyAttenuationPreset(a, &p); err != nil {
+				slog.Error(fmt.Sprintf("Failed to apply preset to attenuation %d", a.Id), "error", err)
+			}
+		}
+
+		for _, target := range m.Targets {
+			if err := SetAttenuationID(h, target, a.Id, ver); err != nil {
+				slog.Error(err.Error())
+			}
+		}
+	}
+
+	if err := commit(); err != nil {
+		rollback()
+		return err
+	}
+
+	return nil
+}
+
+func applyAttenuationPreset(a *wwise.Attenuation, p *AttenuationPresetModifier) error {
+	var t wwise.AttenuationConversionTable
+	var err error
+	switch p.Preset {
+	case wwise.AttenuationPresetLinear, wwise.AttenuationPresetInverseSquare:
+		distance := p.Distance
+		if distance <= 0 {
+			distance = a.MaxDistance()
+		}
+		t, err = wwise.NewAttenuationPresetCurve(p.Preset, distance, p.Reference, p.Floor)
+		if err != nil {
+			return err
+		}
+	case wwise.AttenuationPresetCustom:
+		if len(p.Points) < 2 {
+			return fmt.Errorf("Custom curve requires at least two points")
+		}
+		if p.Scaling >= wwise.CurveScalingTypeCount {
+			return fmt.Errorf("Invalid curve scaling type %d", p.Scaling)
+		}
+		t.EnumScaling = uint8(p.Scaling)
+		t.RTPCGraphPointsX = make([]float32, len(p.Points))
+		t.RTPCGraphPointsY = make([]float32, len(p.Points))
+		t.RTPCGraphPointsInterp = make([]uint32, len(p.Points))
+		for i, point := range p.Points {
+			if wwise.InterpCurveType(point.Interp) >= wwise.InterpCurveTypeCount {
+				return fmt.Errorf("Invalid interpolation type %d", point.Interp)
+			}
+			t.RTPCGraphPointsX[i] = point.From
+			t.RTPCGraphPointsY[i] = point.To
+			t.RTPCGraphPointsInterp[i] = point.Interp
+		}
+	default:
+		return fmt.Errorf("Invalid attenuation preset %d", p.Preset)
+	}
+	return a.SetCurve(p.Property, t)
+}
+
+// Point a hierarchy object to an attenuation through its Attenuation ID
+// property.
+func SetAttenuationID(h *wwise.HIRC, id uint32, attenuationID uint32, v int) error {
+	value, in := h.ActorMixerHirc.Load(id)
+	if !in {
+		if value, in = h.MusicHirc.Load(id); !in {
+			return fmt.Errorf("No hierarchy object has ID %d", id)
+		}
+	}
+	o := value.(wwise.HircObj)
+	b := o.BaseParameter()
+	if b == nil {
+		return fmt.Errorf("Hierarchy object %d (type %s) does not have attenuation", id, wwise.HircTypeName[o.HircType()])
+	}
+	if !b.PositioningParam.OverrideParentAndHasListenerRelativeRouting() {
+		slog.Warn(fmt.Sprintf("Hierarchy object %d does not override 3D positioning of its parent. Attenuation %d is not in effect until it does.", id, attenuationID))
+	}
+	b.PositioningParam.EnableAttenuation(true)
+
+	p := &b.PropBundle
+	if idx, in := p.HasPid(wwise.TAttenuationID, v); !in {
+		buf := make([]byte, 4, 4)
+		binary.Encode(buf, wio.ByteOrder, attenuationID)
+		p.AddWithVal(wwise.TAttenuationID, [4]byte(buf), v)
+	} else {
+		p.SetPropByIdxU32(idx, attenuationID)
+	}
+	slog.Info(fmt.Sprintf("Hierarchy object %d uses attenuation %d", id, attenuationID))
+	return nil
+}
@@ -0,0 +1,72 @@
+package automation
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/parser"
+	"github.com/Dekr0/wwise-teller/wio"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestModifyAttenuations(t *testing.T) {
+	const v = 141
+	h := wwise.NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	a := &wwise.Attenuation{
+		Id: 100,
+		Curves: []int8{0, 0, -1, -1, -1, -1, -1},
+		AttenuationConversionTables: []wwise.AttenuationConversionTable{
+			{
+				EnumScaling: uint8(wwise.CurveScalingTypeDb),
+				RTPCGraphPointsX: []float32{0, 20},
+				RTPCGraphPointsY: []float32{0, -40},
+				RTPCGraphPointsInterp: []uint32{
+					uint32(wwise.InterpCurveTypeLinear),
+					uint32(wwise.InterpCurveTypeLinear),
+				},
+			},
+		},
+	}
+	h.HircObjs = append(h.HircObjs, a)
+	h.Attenuations.Store(a.Id, a)
+	bnk := wwise.NewBank()
+	bkhd := wwise.NewBKHD(0, []byte{'B', 'K', 'H', 'D'})
+	bkhd.BankGenerationVersion = v
+	bnk.AddChunk(bkhd)
+	bnk.AddChunk(h)
+
+	// Double the max distance, then give Volume (shared with Aux Game) its own
+	// linear curve down to -60 dB
+	script := filepath.Join(t.TempDir(), "attenuation.json")
+	spec := `{"version": 0, "modifiers": [{"id": 100, "maxDistance": 40, "presets": [{"property": 0, "preset": 0, "floor": -60}]}]}`
+	if err := os.WriteFile(script, []byte(spec), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := ModifyAttenuations(context.Background(), &bnk, script); err != nil {
+		t.Fatal(err)
+	}
+
+	data := a.Encode(v)
+	size := uint32(len(data)) - wwise.SizeOfHircObjHeader
+	r := wio.NewReader(bytes.NewReader(data[wwise.SizeOfHircObjHeader:]), wio.ByteOrder)
+	decoded := parser.ParseAttenuation(size, r, v)
+
+	if decoded.MaxDistance() != 40 {
+		t.Fatalf("Expecting max distance 40, got %f", decoded.MaxDistance())
+	}
+	if len(decoded.AttenuationConversionTables) != 2 || decoded.Curves[0] != 1 || decoded.Curves[1] != 0 {
+		t.Fatalf("Expecting Volume to use a new curve, got curves %v", decoded.Curves)
+	}
+	cases := []struct{ property int; d float32; v float32 }{
+		{0, 20, -30}, {0, 40, -60}, {1, 20, -20}, {1, 40, -40},
+	}
+	for _, c := range cases {
+		v, ok := decoded.Evaluate(c.property, c.d)
+		if !ok || v != c.v {
+			t.Fatalf("Expecting %f at distance %f of property %d, got %f", c.v, c.d, c.property, v)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const BusSpecVersion = 0
+
+// Buses are created first, then moved, then hierarchy objects are routed,
+// and ducking is edited last. A bus is referred by name (its short ID is used
+// as bus ID) or by ID if name is not provided.
+type BusSpec struct {
+	Version    uint8          `json:"version"`
+	Buses    []NewBusSpec    `json:"buses"`
+	Moves    []BusMove       `json:"moves"`
+	Outputs  []OutputBus     `json:"outputs"`
+	AuxSends []AuxSend       `json:"auxSends"`
+	Ducks    []Duck          `json:"ducks"`
+}
+
+type NewBusSpec struct {
+	Name       string   `json:"name"`
+	// Allocated from Wwise sound bank ID database if both name and ID are
+	// not provided
+	Id         uint32   `json:"id"`
+	// "bus" or "auxBus". Default to "bus".
+	Type       string   `json:"type"`
+	Parent     string   `json:"parent"`
+	ParentId   uint32   `json:"parentId"`
+	HDR        bool     `json:"hdr"`
+	// FX customs created with default parameters (see FxInsert)
+	Fx       []BusFx    `json:"fx"`
+}
+
+type BusFx struct {
+	Slot     uint8           `json:"slot"`
+	Plugin   uint32          `json:"plugin"`
+	Params   json.RawMessage `json:"params"`
+}
+
+type BusMove struct {
+	Bus      string `json:"bus"`
+	BusId    uint32 `json:"busId"`
+	Parent   string `json:"parent"`
+	ParentId uint32 `json:"parentId"`
+}
+
+type OutputBus struct {
+	// Hierarchy objects to route
+	Targets []uint32 `json:"targets"`
+	Bus       string `json:"bus"`
+	BusId     uint32 `json:"busId"`
+}
+
+type AuxSend struct {
+	// Hierarchy objects or buses to send
+	Targets  []uint32 `json:"targets"`
+	// User-defined auxiliary send slot (0 - 3)
+	Slot       uint8   `json:"slot"`
+	AuxBus     string  `json:"auxBus"`
+	AuxBusId   uint32  `json:"auxBusId"`
+	// Send volume in dB
+	Volume     float32 `json:"volume"`
+}
+
+type Duck struct {
+	// Bus that ducks
+	Bus       string  `json:"bus"`
+	BusId     uint32  `json:"busId"`
+	// Bus being ducked
+	Target    string  `json:"target"`
+	TargetId  uint32  `json:"targetId"`
+	// Ducking volume in dB. Default to -6 if it is 0.
+	Volume    float32 `json:"volume"`
+	FadeOut   int32   `json:"fadeOut"`
+	FadeIn    int32   `json:"fadeIn"`
+	// Curve name in wwise.InterpCurveTypeName. Default to "Linear".
+	Curve     string  `json:"curve"`
+	Remove    bool    `json:"remove"`
+}
+
+var NewBusTypes map[string]wwise.HircType = map[string]wwise.HircType{
+	"":       wwise.HircTypeBus,
+	"bus":    wwise.HircTypeBus,
+	"auxBus": wwise.HircTypeAuxBus,
+}
+
+func busRef(name string, id uint32) uint32 {
+	if name != "" {
+		return wwise.ShortID(name)
+	}
+	return id
+}
+
+func ParseBusSpec(spec *BusSpec, fspec string) error {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return fmt.Errorf("Failed to open bus script %s: %w", fspec, err)
+	}
+	err = json.Unmarshal(blob, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to decode bus script %s: %w", fspec, err)
+	}
+	if spec.Version != BusSpecVersion {
+		return fmt.Errorf("Version spec should be %d!", BusSpecVersion)
+	}
+	for _, b := range spec.Buses {
+		if _, in := NewBusTypes[b.Type]; !in {
+			return fmt.Errorf("Unknown bus type %s", b.Type)
+		}
+		if busRef(b.Parent, b.ParentId) == 0 {
+			return fmt.Errorf("New bus is missing parent bus")
+		}
+		for _, f := range b.Fx {
+			if f.Slot >= wwise.FxChunkMaxSlot {
+				return fmt.Errorf("FX slot %d is out of range (max %d)", f.Slot, wwise.FxChunkMaxSlot - 1)
+			}
+			if !slices.Contains(wwise.NewFxPluginIDs, f.Plugin) {
+				return fmt.Errorf("Creating FX plugin 0x%08X is not supported", f.Plugin)
+			}
+		}
+	}
+	for _, m := range spec.Moves {
+		if busRef(m.Bus, m.BusId) == 0 || busRef(m.Parent, m.ParentId) == 0 {
+			return fmt.Errorf("Bus move is missing bus or parent bus")
+		}
+	}
+	for _, o := range spec.Outputs {
+		if busRef(o.Bus, o.BusId) == 0 {
+			return fmt.Errorf("Output bus routing is missing bus")
+		}
+	}
+	for _, d := range spec.Ducks {
+		if busRef(d.Bus, d.BusId) == 0 || busRef(d.Target, d.TargetId) == 0 {
+			return fmt.Errorf("Ducking is missing bus or ducked bus")
+		}
+		if _, err := indexName(wwise.InterpCurveTypeName, d.Curve, "curve interpolation"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Create buses and auxiliary buses with FX, move buses, route hierarchy
+// objects to output buses and auxiliary buses, and edit ducking between buses
+func ModifyBuses(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+
+	var spec BusSpec
+	if err := ParseBusSpec(&spec, fspec); err != nil {
+		return err
+	}
+
+	q, closeConn, commit, rollback, err := db.CreateConnWithTxQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	v := int(bnk.BKHD().BankGenerationVersion)
+	for _, b := range spec.Buses {
+		if err := ctx.Err(); err != nil {
+			rollback()
+			return err
+		}
+		id := busRef(b.Name, b.Id)
+		if id == 0 {
+			if id, err = db.TryHid(ctx, q); err != nil {
+				rollback()
+				return err
+			}
+		}
+		parentID := busRef(b.Parent, b.ParentId)
+		var o wwise.HircObj
+		if NewBusTypes[b.Type] == wwise.HircTypeAuxBus {
+			bus := wwise.NewAuxBus(id, parentID)
+			bus.SetHDRBus(b.HDR)
+			o = bus
+		} else {
+			bus := wwise.NewBus(id, parentID)
+			bus.SetHDRBus(b.HDR)
+			o = bus
+		}
+		if err := h.AppendNewBus(o); err != nil {
+			rollback()
+			return fmt.Errorf("Failed to create bus %d: %w", id, err)
+		}
+		for _, f := range b.Fx {
+			fxID, err := db.TryHid(ctx, q)
+			if err != nil {
+				rollback()
+				return err
+			}
+			fx, err := wwise.NewFxCustom(fxID, f.Plugin)
+			if err != nil {
+				rollback()
+				return err
+			}
+			if len(f.Params) > 0 {
+				if err := SetFxParams(fx.PluginParam, f.Params, v); err != nil {
+					rollback()
+					return fmt.Errorf("Failed to set parameters of a new FX: %w", err)
+				}
+			}
+			if err := h.AppendNewFx(fx); err != nil {
+				rollback()
+				return err
+			}
+			if err := h.AttachFx(id, f.Slot, fxID, v); err != nil {
+				rollback()
+				return fmt.Errorf("Failed to insert FX %d into slot %d of %d: %w", fxID, f.Slot, id, err)
+			}
+		}
+		slog.Info(fmt.Sprintf("Created %s %d under %d", wwise.HircTypeName[o.HircType()], id, parentID))
+	}
+	for _, m := range spec.Moves {
+		id, parentID := busRef(m.Bus, m.BusId), busRef(m.Parent, m.ParentId)
+		if err := h.SetBusParent(id, parentID); err != nil {
+			rollback()
+			return err
+		}
+		slog.Info(fmt.Sprintf("Moved bus %d under %d", id, parentID))
+	}
+	for _, o := range spec.Outputs {
+		busID := busRef(o.Bus, o.BusId)
+		for _, target := range o.Targets {
+			if err := h.SetOutputBus(target, busID); err != nil {
+				rollback()
+				return err
+			}
+			slog.Info(fmt.Sprintf("Routed %d to bus %d", target, busID))
+		}
+	}
+	for _, a := range spec.AuxSends {
+		auxBusID := busRef(a.AuxBus, a.AuxBusId)
+		for _, target := range a.Targets {
+			if err := h.SetAuxSend(target, a.Slot, auxBusID, a.Volume, v); err != nil {
+				rollback()
+				return err
+			}
+			slog.Info(fmt.Sprintf("Sent %d to auxiliary bus %d through slot %d", target, auxBusID, a.Slot))
+		}
+	}
+	for _, d := range spec.Ducks {
+		id, target := busRef(d.Bus, d.BusId), busRef(d.Target, d.TargetId)
+		if d.Remove {
+			if err := h.RemoveDuck(id, target); err != nil {
+				rollback()
+				return err
+			}
+			slog.Info(fmt.Sprintf("Bus %d no longer ducks %d", id, target))
+			continue
+		}
+		info := wwise.NewDuckInfo(target, v)
+		if d.Volume != 0 {
+			info.DuckVolume = d.Volume
+		}
+		info.FadeOutTime, info.FadeInTime = d.FadeOut, d.FadeIn
+		if c, _ := indexName(wwise.InterpCurveTypeName, d.Curve, ""); c != -1 {
+			info.EnumFadeCurve = wwise.InterpCurveType(c)
+		}
+		if err := h.AddDuck(id, info); err != nil {
+			rollback()
+			return err
+		}
+		slog.Info(fmt.Sprintf("Bus %d ducks %d", id, target))
+	}
+	if err := commit(); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package automation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestModifyBuses(t *testing.T) {
+	withMemoryDB(t)
+
+	bnk, h := newTestBank(141)
+	master := wwise.NewBus(1, 0)
+	s := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{}}
+	h.HircObjs = append(h.HircObjs, master, s)
+	h.Buses.Store(master.Id, master)
+	h.ActorMixerHirc.Store(s.Id, s)
+	h.BuildTree()
+	h.HDRAvailability()
+
+	run := scriptRunner(t, bnk, "buses.json", ModifyBuses)
+
+	if err := run(fmt.Sprintf(`{"version": 0,
+		"buses": [
+			{"name": "Weapons", "parentId": 1},
+			{"name": "Music", "parentId": 1},
+			{"name": "Weapons Reverb", "type": "auxBus", "parent": "Weapons", "fx": [{"slot": 0, "plugin": %d}]}
+		],
+		"outputs": [{"targets": [10], "bus": "Weapons"}],
+		"auxSends": [{"targets": [10], "slot": 0, "auxBus": "Weapons Reverb", "volume": -6}],
+		"ducks": [{"bus": "Weapons", "target": "Music", "fadeOut": 200, "fadeIn": 800, "curve": "S-Curve"}]
+	}`, wwise.PluginIDGain)); err != nil {
+		t.Fatal(err)
+	}
+	weapons := wwise.ShortID("Weapons")
+	music := wwise.ShortID("Music")
+	reverb := wwise.ShortID("Weapons Reverb")
+	o, in := h.Bus(reverb)
+	if !in || o.HircType() != wwise.HircTypeAuxBus {
+		t.Fatal("Expecting auxiliary bus Weapons Reverb")
+	}
+	aux := o.(*wwise.AuxBus)
+	if aux.OverrideBusId != weapons || len(aux.BusFxParam.FxChunk.FxChunkItems) != 1 {
+		t.Fatalf("Unexpected auxiliary bus %+v", aux)
+	}
+	if s.BaseParam.OverrideBusId != weapons || s.BaseParam.AuxParam.AuxIds[0] != reverb {
+		t.Fatalf("Unexpected routing of sound %+v", s.BaseParam)
+	}
+	o, _ = h.Bus(weapons)
+	ducks := o.(*wwise.Bus).DuckInfoList
+	if len(ducks) != 1 || ducks[0].BusID != music || ducks[0].DuckVolume != -6 || ducks[0].FadeInTime != 800 ||
+	   ducks[0].EnumFadeCurve != wwise.InterpCurveTypeSCurve {
+		t.Fatalf("Unexpected ducking %+v", ducks)
+	}
+
+	if err := run(`{"version": 0,
+		"moves": [{"bus": "Music", "parent": "Weapons"}],
+		"ducks": [{"bus": "Weapons", "target": "Music", "remove": true}]
+	}`); err != nil {
+		t.Fatal(err)
+	}
+	o, _ = h.Bus(music)
+	if o.(*wwise.Bus).OverrideBusId != weapons || len(h.BusHircNodesMap[weapons].Leafs) != 2 {
+		t.Fatal("Expecting music bus is moved under weapons bus")
+	}
+
+	if err := run(`{"version": 0, "moves": [{"bus": "Weapons", "parent": "Music"}]}`); err == nil {
+		t.Fatal("Expecting error on moving a bus under its descendant")
+	}
+	if err := run(`{"version": 0, "buses": [{"name": "Orphan"}]}`); err == nil {
+		t.Fatal("Expecting error on missing parent bus")
+	}
+	if err := run(`{"version": 0, "buses": [{"name": "HDR", "parentId": 1, "hdr": true}, {"name": "Nested HDR", "parent": "HDR", "hdr": true}]}`); err == nil {
+		t.Fatal("Expecting error on nested HDR buses")
+	}
+}
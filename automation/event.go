@@ -0,0 +1,211 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const EventSpecVersion = 0
+
+// An event is referred by name (its short ID is used as event ID so that the
+// game can post it by name) or by ID if name is not provided.
+type EventSpec struct {
+	Version   uint8          `json:"version"`
+	Events  []NewEventSpec   `json:"events"`
+}
+
+type NewEventSpec struct {
+	Name       string          `json:"name"`
+	Id         uint32          `json:"id"`
+	// Add actions to an existing event instead of creating a new one
+	Existing   bool            `json:"existing"`
+	Actions  []NewActionSpec   `json:"actions"`
+}
+
+type NewActionSpec struct {
+	// Action type name in wwise.ActionTypeNames of the bank version (e.g.
+	// "Play", "Set State"), or action type code (high byte of the action type)
+	// if name is not provided
+	Type         string  `json:"type"`
+	TypeId       uint16  `json:"typeId"`
+	Target       uint32  `json:"target"`
+	// State group / switch group and state / switch of Set State and Set
+	// Switch. Names are hashed into short IDs.
+	Group        string  `json:"group"`
+	GroupId      uint32  `json:"groupId"`
+	State        string  `json:"state"`
+	StateId      uint32  `json:"stateId"`
+	// Value of Set Game Parameter and actions setting a property
+	Value        float32 `json:"value"`
+	// How Value is applied, in wwise.ValueMeaningNames. Set actions default
+	// to Independent and reset actions to Default.
+	ValueMeaning string  `json:"valueMeaning"`
+}
+
+func (e *NewEventSpec) eventID() uint32 {
+	if e.Name != "" {
+		return wwise.ShortID(e.Name)
+	}
+	return e.Id
+}
+
+func (a *NewActionSpec) groupID() uint32 {
+	if a.Group != "" {
+		return wwise.ShortID(a.Group)
+	}
+	return a.GroupId
+}
+
+func (a *NewActionSpec) stateID() uint32 {
+	if a.State != "" {
+		return wwise.ShortID(a.State)
+	}
+	return a.StateId
+}
+
+// Action type code of the action in a given bank version. Action types sharing
+// the same name resolve to the lowest code in that version.
+func (a *NewActionSpec) actionType(v int) (uint16, error) {
+	names := wwise.ActionTypeNames(v)
+	if a.Type != "" {
+		t, err := indexName(names, a.Type, "action type")
+		if err != nil {
+			return 0, err
+		}
+		return uint16(t), nil
+	}
+	if int(a.TypeId) >= len(names) || names[a.TypeId] == "" {
+		return 0, fmt.Errorf("Unknown action type %#x", a.TypeId)
+	}
+	return a.TypeId, nil
+}
+
+// Action types are resolved against bank version v
+func ParseEventSpec(spec *EventSpec, fspec string, v int) error {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return fmt.Errorf("Failed to open event script %s: %w", fspec, err)
+	}
+	err = json.Unmarshal(blob, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to decode event script %s: %w", fspec, err)
+	}
+	if spec.Version != EventSpecVersion {
+		return fmt.Errorf("Version spec should be %d!", EventSpecVersion)
+	}
+	for _, e := range spec.Events {
+		if e.eventID() == 0 {
+			return fmt.Errorf("Event is missing name or ID")
+		}
+		for _, a := range e.Actions {
+			if _, err := a.actionType(v); err != nil {
+				return err
+			}
+			if _, err := indexName(wwise.ValueMeaningNames, a.ValueMeaning, "value meaning"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Create events named by their short IDs and add actions with default
+// parameters to them
+func InsertEvents(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+
+	bkhd := bnk.BKHD()
+	if bkhd == nil {
+		return wwise.NoBKHD
+	}
+	v := int(bkhd.BankGenerationVersion)
+
+	var spec EventSpec
+	if err := ParseEventSpec(&spec, fspec, v); err != nil {
+		return err
+	}
+
+	q, closeConn, commit, rollback, err := db.CreateConnWithTxQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	for _, e := range spec.Events {
+		if err := ctx.Err(); err != nil {
+			rollback()
+			return err
+		}
+		id := e.eventID()
+		if e.Existing {
+			if _, in := h.Events.Load(id); !in {
+				rollback()
+				return fmt.Errorf("No event has ID %d", id)
+			}
+		} else {
+			if err := bnk.CheckEventID(id); err != nil {
+				rollback()
+				return fmt.Errorf("Failed to create event %s: %w", e.Name, err)
+			}
+			if err := h.AppendNewEvent(wwise.NewEvent(id)); err != nil {
+				rollback()
+				return err
+			}
+			slog.Info(fmt.Sprintf("Created event %s (%d)", e.Name, id))
+		}
+		for _, a := range e.Actions {
+			t, _ := a.actionType(v)
+			actionID, err := db.TryHid(ctx, q)
+			if err != nil {
+				rollback()
+				return err
+			}
+			action, err := bnk.NewAction(actionID, t, a.Target)
+			if err != nil {
+				rollback()
+				return err
+			}
+			groupID, stateID := a.groupID(), a.stateID()
+			meaning, _ := indexName(wwise.ValueMeaningNames, a.ValueMeaning, "value meaning")
+			switch p := action.ActionParam.(type) {
+			case *wwise.ActionSetStateParam:
+				p.StateGroupID, p.TargetStateID = groupID, stateID
+			case *wwise.ActionSetSwitchParam:
+				p.SwitchGroupID, p.SwitchStateID = groupID, stateID
+			case *wwise.ActionSetValueParam:
+				switch s := p.AkSpecificParam.(type) {
+				case *wwise.ActionSetPropSpecificParam:
+					s.Base = a.Value
+					if meaning != -1 {
+						s.EnumValueMeaning = uint8(meaning)
+					}
+				case *wwise.ActionSetGameParameterSpecificParam:
+					s.Base = a.Value
+					if meaning != -1 {
+						s.EnumValueMeaning = uint8(meaning)
+					}
+				}
+			}
+			if err := h.AppendNewActionToEvent(action, id); err != nil {
+				rollback()
+				return err
+			}
+			slog.Info(fmt.Sprintf("Added %s action %d targeting %d to event %d", wwise.ActionTypeNames(v)[t], actionID, a.Target, id))
+		}
+	}
+
+	if err = commit(); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestInsertEvents(t *testing.T) {
+	withMemoryDB(t)
+
+	bnk, h := newTestBank(141)
+	s := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{}}
+	h.HircObjs = append(h.HircObjs, s)
+	h.ActorMixerHirc.Store(s.Id, s)
+	bnk.BKHD().SoundbankID = 1000
+
+	run := scriptRunner(t, bnk, "events.json", InsertEvents)
+
+	if err := run(`{"version": 0,
+		"events": [
+			{"name": "Play_MyNewSound", "actions": [
+				{"type": "Play", "target": 10},
+				{"type": "Set State", "group": "Weather", "state": "Rain"}
+			]},
+			{"name": "Stop_MyNewSound", "actions": [{"typeId": 1, "target": 10}]}
+		]
+	}`); err != nil {
+		t.Fatal(err)
+	}
+	play := wwise.ShortID("Play_MyNewSound")
+	v, in := h.Events.Load(play)
+	if !in {
+		t.Fatal("Expecting event is created with the short ID of its name")
+	}
+	e := v.(*wwise.Event)
+	if len(e.ActionIDs) != 2 || e.NumActionIDs.Value != 2 {
+		t.Fatalf("Unexpected actions of event %v", e.ActionIDs)
+	}
+	v, _ = h.Actions.Load(e.ActionIDs[0])
+	a := v.(*wwise.Action)
+	if p, ok := a.ActionParam.(*wwise.ActionPlayParam); !ok || a.Type() != 0x04 || a.IdExt != 10 || p.BankID != 1000 {
+		t.Fatalf("Unexpected play action %+v", a)
+	}
+	v, _ = h.Actions.Load(e.ActionIDs[1])
+	a = v.(*wwise.Action)
+	if p, ok := a.ActionParam.(*wwise.ActionSetStateParam); !ok ||
+		p.StateGroupID != wwise.ShortID("Weather") || p.TargetStateID != wwise.ShortID("Rain") {
+		t.Fatalf("Unexpected set state action %+v", a)
+	}
+	if _, in := h.Events.Load(wwise.ShortID("Stop_MyNewSound")); !in {
+		t.Fatal("Expecting stop event is created")
+	}
+
+	if err := run(`{"version": 0, "events": [{"name": "play_mynewsound"}]}`); err == nil {
+		t.Fatal("Expecting error on event ID collision")
+	}
+	if err := run(`{"version": 0, "events": [{"name": "Play_MyNewSound", "existing": true, "actions": [{"type": "Pause", "target": 10}]}]}`); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.ActionIDs) != 3 {
+		t.Fatal("Expecting action is added to existing event")
+	}
+	if err := run(`{"version": 0, "events": [{"name": "Play_Other", "actions": [{"type": "Jump"}]}]}`); err == nil {
+		t.Fatal("Expecting error on unknown action type")
+	}
+	if _, in := h.Events.Load(wwise.ShortID("Play_Other")); in {
+		t.Fatal("Expecting invalid script does not create event")
+	}
+
+	if err := run(`{"version": 0, "events": [{"name": "Duck_MyNewSound", "actions": [
+		{"type": "Set Volume", "target": 10, "value": -6},
+		{"type": "Set Volume", "target": 10, "value": -3, "valueMeaning": "offset"}
+	]}]}`); err != nil {
+		t.Fatal(err)
+	}
+	v, _ = h.Events.Load(wwise.ShortID("Duck_MyNewSound"))
+	duck := v.(*wwise.Event)
+	for i, expect := range []uint8{wwise.ValueMeaningIndependent, wwise.ValueMeaningOffset} {
+		o, _ := h.Actions.Load(duck.ActionIDs[i])
+		s := o.(*wwise.Action).ActionParam.SpecificParam().(*wwise.ActionSetPropSpecificParam)
+		if s.EnumValueMeaning != expect || s.Base != []float32{-6, -3}[i] {
+			t.Fatalf("Unexpected set volume parameters %+v", s)
+		}
+	}
+	if err := run(`{"version": 0, "events": [{"name": "Duck_Other", "actions": [
+		{"type": "Set Volume", "target": 10, "valueMeaning": "Relative"}
+	]}]}`); err == nil {
+		t.Fatal("Expecting error on unknown value meaning")
+	}
+}
+
+func TestInsertEventsActionTypeByVersion(t *testing.T) {
+	withMemoryDB(t)
+
+	for _, c := range []struct {
+		v      uint32
+		bypass uint16
+	}{{141, 0x1A}, {150, 0x33}} {
+		bnk, h := newTestBank(c.v)
+		s := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{}}
+		h.HircObjs = append(h.HircObjs, s)
+		h.ActorMixerHirc.Store(s.Id, s)
+
+		run := scriptRunner(t, bnk, "events.json", InsertEvents)
+		if err := run(`{"version": 0, "events": [{"name": "Mute_Radio", "actions": [
+			{"type": "Bypass FX", "target": 10},
+			{"type": "Break", "target": 10}
+		]}]}`); err != nil {
+			t.Fatal(err)
+		}
+		v, _ := h.Events.Load(wwise.ShortID("Mute_Radio"))
+		e := v.(*wwise.Event)
+		v, _ = h.Actions.Load(e.ActionIDs[0])
+		a := v.(*wwise.Action)
+		if _, ok := a.ActionParam.(*wwise.ActionByPassFXParam); !ok || a.Type() != c.bypass {
+			t.Fatalf("Expecting Bypass FX action %#x in version %d, got %#x %T", c.bypass, c.v, a.Type(), a.ActionParam)
+		}
+		v, _ = h.Actions.Load(e.ActionIDs[1])
+		a = v.(*wwise.Action)
+		if _, ok := a.ActionParam.(*wwise.ActionNoParam); !ok {
+			t.Fatalf("Expecting Break action in version %d, got %#x %T", c.v, a.Type(), a.ActionParam)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package automation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Swap the Wwise ID database with an in-memory one until the test finishes
+func withMemoryDB(t *testing.T) {
+	t.Helper()
+	prev := db.WwiseIdDB
+	if err := db.InitMemoryDatabase(); err != nil {
+		t.Fatal(err)
+	}
+	conn := db.WwiseIdDB
+	t.Cleanup(func() {
+		db.WwiseIdDB = prev
+		conn.Close()
+	})
+}
+
+// Sound bank of a given version with an empty HIRC
+func newTestBank(v uint32) (*wwise.Bank, *wwise.HIRC) {
+	h := wwise.NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	bnk := wwise.NewBank()
+	bkhd := wwise.NewBKHD(0, []byte{'B', 'K', 'H', 'D'})
+	bkhd.BankGenerationVersion = v
+	bnk.AddChunk(bkhd)
+	bnk.AddChunk(h)
+	return &bnk, h
+}
+
+// Register every object in HircObjs as an actor mixer hierarchy object
+func storeActorMixerHircs(h *wwise.HIRC) {
+	for _, o := range h.HircObjs {
+		id, _ := o.HircID()
+		h.ActorMixerHirc.Store(id, o)
+	}
+}
+
+// Bind a process script function and a sound bank into a function that writes
+// a script spec into a temporary directory of the test and runs it
+func scriptRunner(
+	t *testing.T,
+	bnk *wwise.Bank,
+	name string,
+	run func(context.Context, *wwise.Bank, string) error,
+) func(string) error {
+	return func(spec string) error {
+		script := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(script, []byte(spec), 0666); err != nil {
+			t.Fatal(err)
+		}
+		return run(context.Background(), bnk, script)
+	}
+}
@@ -0,0 +1,257 @@
+package automation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const FxParamModifierSpecVersion = 0
+
+type FxParamModifierSpec struct {
+	Version     uint8             `json:"version"`
+	Modifiers []FxParamModifier `json:"modifiers"`
+}
+
+type FxParamModifier struct {
+	// FX share set or FX custom
+	Id     uint32          `json:"id"`
+	// Plugin parameters to overwrite. Keys are field names of the decoded
+	// plugin parameter struct (e.g. {"Feedback": 40, "LFO": {"Frequency": 2}}).
+	// Omitted parameters are left unchanged.
+	Params json.RawMessage `json:"params"`
+}
+
+func ParseFxParamModifierSpec(spec *FxParamModifierSpec, fspec string) error {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return fmt.Errorf("Failed to open FX parameter modifier script %s: %w", fspec, err)
+	}
+	err = json.Unmarshal(blob, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to decode FX parameter modifier script %s: %w", fspec, err)
+	}
+	if spec.Version != FxParamModifierSpecVersion {
+		return fmt.Errorf("Version spec should be %d!", FxParamModifierSpecVersion)
+	}
+	return nil
+}
+
+func ModifyFxParams(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+
+	var spec FxParamModifierSpec
+	if err := ParseFxParamModifierSpec(&spec, fspec); err != nil {
+		return err
+	}
+	if len(spec.Modifiers) <= 0 {
+		slog.Warn("No FX parameter modifiers are provided. Do nothing")
+		return nil
+	}
+
+	v := int(bnk.BKHD().BankGenerationVersion)
+	for _, m := range spec.Modifiers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var p *wwise.PluginParam
+		if value, in := h.FxShareSets.Load(m.Id); in {
+			p = value.(*wwise.FxShareSet).PluginParam
+		} else if value, in := h.FxCustoms.Load(m.Id); in {
+			p = value.(*wwise.FxCustom).PluginParam
+		} else {
+			slog.Warn(fmt.Sprintf("No FX share set or FX custom has ID %d", m.Id))
+			continue
+		}
+		if err := SetFxParams(p, m.Params, v); err != nil {
+			return fmt.Errorf("Failed to modify parameters of FX %d: %w", m.Id, err)
+		}
+		slog.Info(fmt.Sprintf("Modified parameters of FX %d", m.Id))
+	}
+	return nil
+}
+
+// Overwrite decoded plugin parameters with the given JSON object. Parameters
+// are left untouched if decoding fails.
+func SetFxParams(p *wwise.PluginParam, params json.RawMessage, v int) error {
+	if p == nil {
+		return fmt.Errorf("FX does not have plugin parameters")
+	}
+	if _, ok := p.PluginParamData.(*wwise.FxPlaceholder); ok {
+		return fmt.Errorf("Plugin parameters of this FX are not decoded")
+	}
+	// Snapshot for restoring parameters if the script is bad so that they are
+	// not left half modified
+	blob, err := json.Marshal(p.PluginParamData)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(params))
+	d.DisallowUnknownFields()
+	if err := d.Decode(p.PluginParamData); err != nil {
+		if rerr := json.Unmarshal(blob, p.PluginParamData); rerr != nil {
+			panic(rerr)
+		}
+		return err
+	}
+	// Optional parameters (e.g. InputStereoWidth) change the block size
+	p.PluginParamSize = p.PluginParamData.Size(v)
+	return nil
+}
+
+const FxInsertSpecVersion = 0
+
+type FxInsertSpec struct {
+	Version   uint8      `json:"version"`
+	Inserts []FxInsert `json:"inserts"`
+}
+
+type FxInsert struct {
+	// Hierarchy objects or buses to insert the new FX into
+	Targets []uint32        `json:"targets"`
+	Slot      uint8         `json:"slot"`
+	// Plugin ID of a built-in FX plugin (see wwise.NewFxPluginIDs)
+	Plugin    uint32        `json:"plugin"`
+	// Create one FX share set used by all targets instead of one FX custom
+	// per target
+	ShareSet  bool          `json:"shareSet"`
+	// Render the FX into the audio source offline
+	Render    bool          `json:"render"`
+	// Bypass the FX. Requires version > 145.
+	Bypass    bool          `json:"bypass"`
+	// Overwrite default plugin parameters (see FxParamModifier)
+	Params json.RawMessage `json:"params"`
+}
+
+func ParseFxInsertSpec(spec *FxInsertSpec, fspec string) error {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return fmt.Errorf("Failed to open FX insert script %s: %w", fspec, err)
+	}
+	err = json.Unmarshal(blob, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to decode FX insert script %s: %w", fspec, err)
+	}
+	if spec.Version != FxInsertSpecVersion {
+		return fmt.Errorf("Version spec should be %d!", FxInsertSpecVersion)
+	}
+	for _, i := range spec.Inserts {
+		if i.Slot >= wwise.FxChunkMaxSlot {
+			return fmt.Errorf("FX slot %d is out of range (max %d)", i.Slot, wwise.FxChunkMaxSlot - 1)
+		}
+		if !slices.Contains(wwise.NewFxPluginIDs, i.Plugin) {
+			return fmt.Errorf("Creating FX plugin 0x%08X is not supported", i.Plugin)
+		}
+	}
+	return nil
+}
+
+// Create FX with default parameters and insert them into FX slots of
+// hierarchy objects or buses
+func InsertFx(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+
+	var spec FxInsertSpec
+	if err := ParseFxInsertSpec(&spec, fspec); err != nil {
+		return err
+	}
+	if len(spec.Inserts) <= 0 {
+		slog.Warn("No FX inserts are provided. Do nothing")
+		return nil
+	}
+
+	v := int(bnk.BKHD().BankGenerationVersion)
+	for _, i := range spec.Inserts {
+		if i.Bypass && v <= 145 {
+			return fmt.Errorf("Bypassing a single FX is not supported in version %d", v)
+		}
+	}
+
+	q, closeConn, commit, rollback, err := db.CreateConnWithTxQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	newFx := func(pluginID uint32, shareSet bool, params json.RawMessage) (uint32, error) {
+		id, err := db.TryHid(ctx, q)
+		if err != nil {
+			return 0, err
+		}
+		var fx wwise.HircObj
+		var p *wwise.PluginParam
+		if shareSet {
+			f, err := wwise.NewFxShareSet(id, pluginID)
+			if err != nil {
+				return 0, err
+			}
+			fx, p = f, f.PluginParam
+		} else {
+			f, err := wwise.NewFxCustom(id, pluginID)
+			if err != nil {
+				return 0, err
+			}
+			fx, p = f, f.PluginParam
+		}
+		if len(params) > 0 {
+			if err := SetFxParams(p, params, v); err != nil {
+				return 0, fmt.Errorf("Failed to set parameters of a new FX: %w", err)
+			}
+		}
+		return id, h.AppendNewFx(fx)
+	}
+
+	for _, i := range spec.Inserts {
+		if err := ctx.Err(); err != nil {
+			rollback()
+			return err
+		}
+		var shareSetID uint32
+		if i.ShareSet {
+			if shareSetID, err = newFx(i.Plugin, true, i.Params); err != nil {
+				rollback()
+				return err
+			}
+		}
+		for _, target := range i.Targets {
+			fxID := shareSetID
+			if !i.ShareSet {
+				if fxID, err = newFx(i.Plugin, false, i.Params); err != nil {
+					rollback()
+					return err
+				}
+			}
+			if err := h.AttachFx(target, i.Slot, fxID, v); err != nil {
+				rollback()
+				return fmt.Errorf("Failed to insert FX %d into slot %d of %d: %w", fxID, i.Slot, target, err)
+			}
+			f, err := h.FxChunkOf(target)
+			if err == nil {
+				err = f.SetSlotFlags(i.Slot, i.Render, i.Bypass, v)
+			}
+			if err != nil {
+				rollback()
+				return fmt.Errorf("Failed to set flags of FX %d in slot %d of %d: %w", fxID, i.Slot, target, err)
+			}
+			slog.Info(fmt.Sprintf("Inserted FX %d (%s) into slot %d of %d", fxID, wwise.PluginNameLUT[int32(i.Plugin)], i.Slot, target))
+		}
+	}
+	if err := commit(); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
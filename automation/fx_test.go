@@ -0,0 +1,136 @@
+package automation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/parser"
+	"github.com/Dekr0/wwise-teller/wio"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestModifyFxParams(t *testing.T) {
+	bnk, h := newTestBank(141)
+	flanger := &wwise.Flanger{DelayTime: 2, ModDepth: 50, LFO: wwise.FxLFO{Frequency: 1}, Data: []byte{}}
+	fx := &wwise.FxShareSet{
+		Id: 10,
+		PluginTypeId: 0x007D0003,
+		PluginParam: &wwise.PluginParam{PluginParamSize: flanger.Size(141), PluginParamData: flanger},
+	}
+	h.HircObjs = append(h.HircObjs, fx)
+	h.FxShareSets.Store(fx.Id, fx)
+
+	run := scriptRunner(t, bnk, "flanger.json", ModifyFxParams)
+	if err := run(`{"version": 0, "modifiers": [{"id": 10, "params": {"ModDepth": 80, "LFO": {"Waveform": 1}}}]}`); err != nil {
+		t.Fatal(err)
+	}
+	if flanger.ModDepth != 80 || flanger.DelayTime != 2 || flanger.LFO.Frequency != 1 || flanger.LFO.Waveform != wwise.LFOWaveformTriangle {
+		t.Fatalf("Unexpected flanger parameters %+v", flanger)
+	}
+
+	// Typo in parameter name must not modify anything
+	if err := run(`{"version": 0, "modifiers": [{"id": 10, "params": {"DelayTime": 5, "ModDepht": 10}}]}`); err == nil {
+		t.Fatal("Expecting error on unknown parameter")
+	}
+	if flanger.DelayTime != 2 || flanger.ModDepth != 80 {
+		t.Fatalf("Flanger parameters are modified by a bad script %+v", flanger)
+	}
+
+	c := &wwise.ConvolutionReverb{}
+	p := &wwise.PluginParam{PluginParamSize: c.Size(150), PluginParamData: c}
+	if err := SetFxParams(p, []byte(`{"InputStereoWidth": 90}`), 150); err != nil {
+		t.Fatal(err)
+	}
+	if c.InputStereoWidth == nil || *c.InputStereoWidth != 90 || p.PluginParamSize != wwise.SizeOfConvolutionReverb + 4 {
+		t.Fatalf("Unexpected convolution reverb %+v with size %d", c, p.PluginParamSize)
+	}
+}
+
+func TestInsertFx(t *testing.T) {
+	withMemoryDB(t)
+
+	bnk, h := newTestBank(141)
+	a := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{DirectParentId: 30}}
+	b := &wwise.Sound{Id: 20, BaseParam: &wwise.BaseParameter{DirectParentId: 30}}
+	mixer := &wwise.ActorMixer{Id: 30, BaseParam: &wwise.BaseParameter{}}
+	mixer.Container.Children = []uint32{10, 20}
+	h.HircObjs = append(h.HircObjs, a, b, mixer)
+	storeActorMixerHircs(h)
+
+	run := scriptRunner(t, bnk, "radio.json", InsertFx)
+	if err := run(`{"version": 0, "inserts": [
+		{"targets": [10, 20], "slot": 1, "plugin": 6881283, "shareSet": true, "params": {"OutputLevel": -3}},
+		{"targets": [10, 20], "slot": 0, "plugin": 8257539, "params": {"Drive": 90}}
+	]}`); err != nil {
+		t.Fatal(err)
+	}
+
+	var eqID uint32
+	customs := map[uint32]bool{}
+	for _, s := range []*wwise.Sound{a, b} {
+		items := s.BaseParam.FxChunk.FxChunkItems
+		if len(items) != 2 || items[0].UniqueFxIndex != 0 || items[1].UniqueFxIndex != 1 {
+			t.Fatalf("Unexpected FX slots %+v of sound %d", items, s.Id)
+		}
+		if eqID != 0 && items[1].FxId != eqID {
+			t.Fatal("Expecting both sounds use the same FX share set")
+		}
+		eqID = items[1].FxId
+		customs[items[0].FxId] = true
+	}
+	v, in := h.FxShareSets.Load(eqID)
+	if !in || v.(*wwise.FxShareSet).PluginParam.PluginParamData.(*wwise.ParametricEQ).OutputLevel != -3 {
+		t.Fatalf("Unexpected FX share set %d", eqID)
+	}
+	if len(customs) != 2 {
+		t.Fatalf("Expecting one FX custom per sound, got %v", customs)
+	}
+	for id := range customs {
+		v, in := h.FxCustoms.Load(id)
+		if !in || v.(*wwise.FxCustom).PluginParam.PluginParamData.(*wwise.GuitarDistortion).Drive != 90 {
+			t.Fatalf("Unexpected FX custom %d", id)
+		}
+	}
+	if len(h.HircObjs) != 6 {
+		t.Fatalf("Expecting 3 new FX, got %d hierarchy objects", len(h.HircObjs))
+	}
+}
+
+func TestInsertFxFlags(t *testing.T) {
+	withMemoryDB(t)
+
+	for _, v := range []int{141, 150} {
+		bnk, h := newTestBank(uint32(v))
+		s := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{}}
+		h.HircObjs = append(h.HircObjs, s)
+		storeActorMixerHircs(h)
+
+		run := scriptRunner(t, bnk, "flags.json", InsertFx)
+		if err := run(`{"version": 0, "inserts": [
+			{"targets": [10], "slot": 0, "plugin": 8257539, "render": true},
+			{"targets": [10], "slot": 1, "plugin": 6881283, "shareSet": true}
+		]}`); err != nil {
+			t.Fatal(err)
+		}
+		bypass := `{"version": 0, "inserts": [{"targets": [10], "slot": 2, "plugin": 8257539, "bypass": true}]}`
+		if err := run(bypass); v <= 145 && err == nil {
+			t.Fatalf("Expecting error on bypassing a single FX in version %d", v)
+		} else if v > 145 && err != nil {
+			t.Fatal(err)
+		}
+
+		var f wwise.FxChunk
+		parser.ParseFxChunk(wio.NewReader(bytes.NewReader(s.BaseParam.FxChunk.Encode(v)), wio.ByteOrder), &f, v)
+		if item := f.Slot(0); item == nil || !item.IsRendered(v) || item.IsShareSet(v) {
+			t.Fatalf("Expecting rendered FX custom in slot 0 in version %d, got %+v", v, item)
+		}
+		if item := f.Slot(1); item == nil || item.IsRendered(v) || !item.IsShareSet(v) {
+			t.Fatalf("Expecting FX share set in slot 1 in version %d, got %+v", v, item)
+		}
+		if v > 145 {
+			if item := f.Slot(2); item == nil || !wio.GetBit(item.BitVector, 0) || item.IsRendered(v) {
+				t.Fatalf("Expecting bypassed FX in slot 2, got %+v", item)
+			}
+		}
+	}
+}
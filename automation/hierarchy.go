@@ -158,11 +158,6 @@ func ImportAsRanSeqCntr(ctx context.Context, bnk *wwise.Bank, script string) err
 	if h == nil {
 		return wwise.NoHIRC
 	}
-	proj, err := waapi.GetProject()
-	if err != nil {
-		return err
-	}
-
 	s := ImportAsRanSeqCntrScript{}
 	inputsMap, err := ParseImportAsRanSeqCntrScript(&s, script)
 	if err != nil {
@@ -221,24 +216,25 @@ func ImportAsRanSeqCntr(ctx context.Context, bnk *wwise.Bank, script string) err
 	}
 	refAction := v.(*wwise.Action)
 
-	wems := make([]string, len(inputsMap))
-	wsource, err := waapi.CreateConversionListInOrder(ctx, inputsMap, wems, s.Conversion, false)
-	if len(inputsMap) != len(wems) {
-		panic("Length of input maps does not equal to the length of output array")
+	wavs := make([]string, len(inputsMap))
+	for wav, idx := range inputsMap {
+		wavs[idx] = wav
 	}
+	staging, jobs, err := waapi.StageConversion(wavs)
 	if err != nil {
 		return err
 	}
+	defer os.RemoveAll(staging)
 
-	if err := waapi.WwiseConversion(ctx, wsource, proj); err != nil {
+	if err := waapi.ConverterFrom(ctx).Convert(ctx, jobs, s.Conversion, s.Format); err != nil {
 		return err
 	}
 
-	newAudioDatas := make([][]byte, 0, len(wems))
-	for _, wem := range wems {
-		audioData, err := os.ReadFile(wem)
+	newAudioDatas := make([][]byte, 0, len(jobs))
+	for _, job := range jobs {
+		audioData, err := os.ReadFile(job.Wem)
 		if err != nil {
-			slog.Error(fmt.Sprintf("Failed to read audio data from %s", wem))
+			slog.Error(fmt.Sprintf("Failed to read audio data from %s", job.Wem))
 			continue
 		}
 		newAudioDatas = append(newAudioDatas, audioData)
@@ -299,18 +295,8 @@ func ImportAsRanSeqCntr(ctx context.Context, bnk *wwise.Bank, script string) err
 	}
 
 	var newSound *wwise.Sound
-	var pluginID uint32
+	pluginID := s.Format.PluginID()
 	for i := range newAudioDatas {
-		switch s.Format {
-		case waapi.ConversionFormatTypePCM:
-			pluginID = wwise.PCM
-		case waapi.ConversionFormatTypeADPCM:
-			pluginID = wwise.ADPCM
-		case waapi.ConversionFormatTypeVORBIS:
-			pluginID = wwise.VORBIS
-		case waapi.ConversionFormatTypeWEMOpus:
-			pluginID = wwise.WEM_OPUS
-		}
 		newSound = &wwise.Sound{
 			Id: newSoundIDs[i],
 			BankSourceData: wwise.BankSourceData{
@@ -400,11 +386,6 @@ func NewSoundToRanSeqCntr(ctx context.Context, bnk *wwise.Bank, script string) e
 	if h == nil {
 		return wwise.NoHIRC
 	}
-	proj, err := waapi.GetProject()
-	if err != nil {
-		return err
-	}
-
 	s := ImportAsRanSeqCntrScript{}
 	inputsMap, err := ParseImportAsRanSeqCntrScript(&s, script)
 	if err != nil {
@@ -448,24 +429,25 @@ func NewSoundToRanSeqCntr(ctx context.Context, bnk *wwise.Bank, script string) e
 		return fmt.Errorf("There's no reference sound in container %d to create new sound objects", s.Parent)
 	}
 
-	wems := make([]string, len(inputsMap))
-	wsource, err := waapi.CreateConversionListInOrder(ctx, inputsMap, wems, s.Conversion, false)
-	if len(inputsMap) != len(wems) {
-		panic("Length of input maps does not equal to the length of output array")
+	wavs := make([]string, len(inputsMap))
+	for wav, idx := range inputsMap {
+		wavs[idx] = wav
 	}
+	staging, jobs, err := waapi.StageConversion(wavs)
 	if err != nil {
 		return err
 	}
+	defer os.RemoveAll(staging)
 
-	if err := waapi.WwiseConversion(ctx, wsource, proj); err != nil {
+	if err := waapi.ConverterFrom(ctx).Convert(ctx, jobs, s.Conversion, s.Format); err != nil {
 		return err
 	}
 
-	newAudioDatas := make([][]byte, 0, len(wems))
-	for _, wem := range wems {
-		audioData, err := os.ReadFile(wem)
+	newAudioDatas := make([][]byte, 0, len(jobs))
+	for _, job := range jobs {
+		audioData, err := os.ReadFile(job.Wem)
 		if err != nil {
-			slog.Error(fmt.Sprintf("Failed to read audio data from %s", wem))
+			slog.Error(fmt.Sprintf("Failed to read audio data from %s", job.Wem))
 			continue
 		}
 		newAudioDatas = append(newAudioDatas, audioData)
@@ -505,18 +487,8 @@ func NewSoundToRanSeqCntr(ctx context.Context, bnk *wwise.Bank, script string) e
 	}
 
 	var newSound *wwise.Sound
-	var pluginID uint32
+	pluginID := s.Format.PluginID()
 	for i := range newAudioDatas {
-		switch s.Format {
-		case waapi.ConversionFormatTypePCM:
-			pluginID = wwise.PCM
-		case waapi.ConversionFormatTypeADPCM:
-			pluginID = wwise.ADPCM
-		case waapi.ConversionFormatTypeVORBIS:
-			pluginID = wwise.VORBIS
-		case waapi.ConversionFormatTypeWEMOpus:
-			pluginID = wwise.WEM_OPUS
-		}
 		newSound = &wwise.Sound{
 			Id: newSoundIDs[i],
 			BankSourceData: wwise.BankSourceData{
@@ -0,0 +1,272 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const LayerCntrModifierSpecVersion = 0
+
+type LayerCntrModifierSpec struct {
+	Version     uint8               `json:"version"`
+	Modifiers []LayerCntrModifier `json:"modifiers"`
+}
+
+type LayerCntrModifier struct {
+	// Layer container to modify
+	Id             uint32          `json:"id"`
+	// Hierarchy objects moved into the layer container
+	AddChildren  []uint32          `json:"addChildren"`
+	// Children detached from the layer container. They are left without a
+	// parent.
+	RemoveChildren []uint32        `json:"removeChildren"`
+	RemoveLayers []uint32          `json:"removeLayers"`
+	Layers       []LayerModifier   `json:"layers"`
+}
+
+type LayerModifier struct {
+	// Layer to modify. Create a new layer if zero.
+	Id                 uint32               `json:"id"`
+	// Game parameter driving crossfade curves. Unchanged if zero.
+	RTPC               uint32               `json:"rtpc"`
+	// See wwise.RTPCTypeName. Only used if RTPC is set.
+	RTPCType           uint8                `json:"rtpcType"`
+	Associations     []LayerAssociation     `json:"associations"`
+	Unassociate      []uint32               `json:"unassociate"`
+	// Add, or replace RTPC curves with the same RTPC and parameter
+	InitialRTPCs     []LayerInitialRTPC     `json:"initialRTPCs"`
+	// Remove all RTPC curves of these RTPCs
+	RemoveInitialRTPCs []uint32             `json:"removeInitialRTPCs"`
+}
+
+// Either Points or Crossfade is used. Points take precedence.
+type LayerAssociation struct {
+	Child       uint32                 `json:"child"`
+	Points    []wwise.RTPCGraphPoint   `json:"points"`
+	Crossfade  *LayerCrossfade         `json:"crossfade"`
+}
+
+// See wwise.LayerCrossfadeCurve
+type LayerCrossfade struct {
+	Start   float32 `json:"start"`
+	End     float32 `json:"end"`
+	FadeIn  float32 `json:"fadeIn"`
+	FadeOut float32 `json:"fadeOut"`
+}
+
+type LayerInitialRTPC struct {
+	RTPC      uint32                 `json:"rtpc"`
+	// See wwise.RTPCTypeName
+	RTPCType  uint8                  `json:"rtpcType"`
+	Accum     wwise.RTPCAccumType    `json:"accum"`
+	Param     wwise.RTPCParameterType `json:"param"`
+	Scaling   wwise.CurveScalingType `json:"scaling"`
+	Points  []wwise.RTPCGraphPoint   `json:"points"`
+}
+
+func ParseLayerCntrModifierSpec(spec *LayerCntrModifierSpec, fspec string) error {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return fmt.Errorf("Failed to open layer container modifier script %s: %w", fspec, err)
+	}
+	err = json.Unmarshal(blob, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to decode layer container modifier script %s: %w", fspec, err)
+	}
+	if spec.Version != LayerCntrModifierSpecVersion {
+		return fmt.Errorf("Version spec should be %d!", LayerCntrModifierSpecVersion)
+	}
+	return nil
+}
+
+func ModifyLayerCntrs(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+
+	var spec LayerCntrModifierSpec
+	if err := ParseLayerCntrModifierSpec(&spec, fspec); err != nil {
+		return err
+	}
+	if len(spec.Modifiers) <= 0 {
+		slog.Warn("No layer container modifiers are provided. Do nothing")
+		return nil
+	}
+
+	q, closeConn, commit, rollback, err := db.CreateConnWithTxQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	v := int(bnk.BKHD().BankGenerationVersion)
+	for _, m := range spec.Modifiers {
+		if err := modifyLayerCntr(h, &m, v, func() (uint32, error) { return db.TryHid(ctx, q) }); err != nil {
+			rollback()
+			return fmt.Errorf("Failed to modify layer container %d: %w", m.Id, err)
+		}
+	}
+
+	if err := commit(); err != nil {
+		rollback()
+		return err
+	}
+	h.BuildTree()
+	return nil
+}
+
+func modifyLayerCntr(h *wwise.HIRC, m *LayerCntrModifier, bnkVer int, newId func() (uint32, error)) error {
+	v, in := h.ActorMixerHirc.Load(m.Id)
+	if !in {
+		return fmt.Errorf("No actor mixer hierarchy object has ID %d.", m.Id)
+	}
+	l, ok := v.(*wwise.LayerCntr)
+	if !ok {
+		return fmt.Errorf("Actor mixer hierarchy object %d is not type of layer container.", m.Id)
+	}
+
+	for _, child := range m.AddChildren {
+		v, in := h.ActorMixerHirc.Load(child)
+		if !in {
+			return fmt.Errorf("No actor mixer hierarchy object has ID %d.", child)
+		}
+		o := v.(wwise.HircObj)
+		if !wwise.ActorMixerHircType(o) || o.BaseParameter() == nil {
+			return fmt.Errorf("%s %d cannot be a child of layer container", wwise.HircTypeName[o.HircType()], child)
+		}
+		// Parent of a child must not be the child itself or its descendant
+		for p := l.Id; p != 0; {
+			if p == child {
+				return fmt.Errorf("%d is the layer container or one of its ancestors", child)
+			}
+			v, in := h.ActorMixerHirc.Load(p)
+			if !in {
+				break
+			}
+			p = v.(wwise.HircObj).BaseParameter().DirectParentId
+		}
+		parent := o.BaseParameter().DirectParentId
+		if parent == l.Id {
+			continue
+		}
+		if _, in := h.ActorMixerHirc.Load(parent); parent != 0 && !in {
+			return fmt.Errorf("Parent %d of %d is not in this sound bank", parent, child)
+		}
+		// Descendants of a container child are moved along with it so they stay
+		// in front of their parent
+		h.ChangeRoot(child, l.Id, parent, false)
+		slog.Info(fmt.Sprintf("Moved %d into layer container %d", child, l.Id))
+	}
+	for _, child := range m.RemoveChildren {
+		v, in := h.ActorMixerHirc.Load(child)
+		if !in || v.(wwise.HircObj).BaseParameter().DirectParentId != l.Id {
+			return fmt.Errorf("%d is not in layer container %d", child, l.Id)
+		}
+		h.RemoveRoot(child, l.Id, false)
+		slog.Warn(fmt.Sprintf("Detached %d from layer container %d. It does not have a parent.", child, l.Id))
+	}
+
+	for _, id := range m.RemoveLayers {
+		if l.Layer(id) == nil {
+			return fmt.Errorf("No layer has ID %d", id)
+		}
+		l.RemoveLayer(id)
+	}
+
+	for _, lm := range m.Layers {
+		var layer *wwise.Layer
+		if lm.Id == 0 {
+			id, err := newId()
+			if err != nil {
+				return err
+			}
+			layer = l.NewLayer(id)
+			slog.Info(fmt.Sprintf("Created layer %d in layer container %d", id, l.Id))
+		} else if layer = l.Layer(lm.Id); layer == nil {
+			return fmt.Errorf("No layer has ID %d", lm.Id)
+		}
+		if lm.RTPC != 0 {
+			if int(lm.RTPCType) >= len(wwise.RTPCTypeName) {
+				return fmt.Errorf("Invalid RTPC type %d", lm.RTPCType)
+			}
+			layer.RTPCId = lm.RTPC
+			layer.RTPCType = lm.RTPCType
+		}
+		for _, child := range lm.Unassociate {
+			layer.Unassociate(child)
+		}
+		for _, a := range lm.Associations {
+			points := a.Points
+			if len(points) == 0 && a.Crossfade != nil {
+				c := a.Crossfade
+				points = wwise.LayerCrossfadeCurve(c.Start, c.End, c.FadeIn, c.FadeOut)
+			}
+			if err := l.Associate(layer.Id, a.Child, points); err != nil {
+				return err
+			}
+		}
+		for _, rtpc := range lm.RemoveInitialRTPCs {
+			layer.InitialRTPC.RTPCItems = slices.DeleteFunc(layer.InitialRTPC.RTPCItems, func(r wwise.RTPCItem) bool {
+				return r.RTPCID == rtpc
+			})
+		}
+		for _, r := range lm.InitialRTPCs {
+			item, err := newLayerRTPCItem(&r, l, bnkVer, newId)
+			if err != nil {
+				return err
+			}
+			layer.InitialRTPC.RTPCItems = slices.DeleteFunc(layer.InitialRTPC.RTPCItems, func(i wwise.RTPCItem) bool {
+				return i.RTPCID == r.RTPC && i.ParamID.Value == uint64(r.Param)
+			})
+			if err := layer.InitialRTPC.AddRTPCItem(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Initial RTPC parameters of a layer must be available on its layer container
+func newLayerRTPCItem(
+	r *LayerInitialRTPC, l *wwise.LayerCntr, bnkVer int, newId func() (uint32, error),
+) (wwise.RTPCItem, error) {
+	if r.RTPC == 0 {
+		return wwise.RTPCItem{}, fmt.Errorf("RTPC ID is not provided")
+	}
+	if r.Accum >= wwise.RTPCAccumTypeCount {
+		return wwise.RTPCItem{}, fmt.Errorf("Invalid RTPC accumulation type %d", r.Accum)
+	}
+	if r.Scaling >= wwise.CurveScalingTypeCount {
+		return wwise.RTPCItem{}, fmt.Errorf("Invalid curve scaling type %d", r.Scaling)
+	}
+	if err := wwise.CheckRTPCParameter(l, r.Param, bnkVer); err != nil {
+		return wwise.RTPCItem{}, err
+	}
+	item, err := wwise.NewRTPCItem(r.RTPC, r.RTPCType, 0, r.Param)
+	if err != nil {
+		return wwise.RTPCItem{}, err
+	}
+	item.RTPCAccum = r.Accum
+	item.Scaling = r.Scaling
+	xs := make([]float32, len(r.Points))
+	ys := make([]float32, len(r.Points))
+	interps := make([]uint32, len(r.Points))
+	for i, p := range r.Points {
+		xs[i], ys[i], interps[i] = p.From, p.To, p.Interp
+	}
+	if err := item.SetCurve(xs, ys, interps); err != nil {
+		return wwise.RTPCItem{}, fmt.Errorf("Invalid RTPC %d curve: %w", r.RTPC, err)
+	}
+	if item.RTPCCurveID, err = newId(); err != nil {
+		return wwise.RTPCItem{}, err
+	}
+	return item, nil
+}
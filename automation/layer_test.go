@@ -0,0 +1,105 @@
+package automation
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestModifyLayerCntrs(t *testing.T) {
+	withMemoryDB(t)
+
+	bnk, h := newTestBank(141)
+	mixer := &wwise.ActorMixer{Id: 1, BaseParam: &wwise.BaseParameter{}}
+	mixer.Container.Children = []uint32{11, 12, 20}
+	layerCntr := &wwise.LayerCntr{Id: 20, BaseParam: &wwise.BaseParameter{DirectParentId: 1}}
+	layerCntr.Container.Children = []uint32{10}
+	layerCntr.Layers = []wwise.Layer{{Id: 30, LayerRTPCs: []wwise.LayerRTPC{{AssociatedChildID: 10}}}}
+	idle := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{DirectParentId: 20}}
+	rev := &wwise.Sound{Id: 11, BaseParam: &wwise.BaseParameter{DirectParentId: 1}}
+	// A random / sequence container placed after the layer container must be
+	// moved with its children
+	ranSeq := &wwise.RanSeqCntr{Id: 12, BaseParam: wwise.BaseParameter{DirectParentId: 1}}
+	ranSeq.Container.Children = []uint32{13}
+	step := &wwise.Sound{Id: 13, BaseParam: &wwise.BaseParameter{DirectParentId: 12}}
+	// Parent 99 is in another sound bank
+	foreign := &wwise.Sound{Id: 14, BaseParam: &wwise.BaseParameter{DirectParentId: 99}}
+	h.HircObjs = append(h.HircObjs, foreign, idle, layerCntr, rev, step, ranSeq, mixer)
+	storeActorMixerHircs(h)
+
+	run := scriptRunner(t, bnk, "layers.json", ModifyLayerCntrs)
+	if err := run(`{
+		"version": 0,
+		"modifiers": [{
+			"id": 20,
+			"addChildren": [11, 12],
+			"layers": [
+				{"id": 30, "unassociate": [10]},
+				{
+					"rtpc": 900,
+					"associations": [
+						{"child": 10, "crossfade": {"start": 0, "end": 60, "fadeOut": 20}},
+						{"child": 11, "points": [{"from": 40, "to": -96.3}, {"from": 60, "to": 0}, {"from": 100, "to": 0}]}
+					],
+					"initialRTPCs": [{"rtpc": 900, "param": 2, "points": [{"from": 0, "to": 0}, {"from": 100, "to": 1200}]}]
+				}
+			]
+		}]
+	}`); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(layerCntr.Container.Children, []uint32{10, 11, 12}) || rev.BaseParam.DirectParentId != 20 {
+		t.Fatalf("Expecting 11 to be moved into layer container, got children %v", layerCntr.Container.Children)
+	}
+	order := []uint32{}
+	for _, o := range h.HircObjs {
+		id, _ := o.HircID()
+		order = append(order, id)
+	}
+	if !slices.Equal(order, []uint32{14, 10, 11, 13, 12, 20, 1}) || !slices.Equal(mixer.Container.Children, []uint32{20}) {
+		t.Fatalf("Expecting moved children and their descendants to be placed before layer container, got %v", order)
+	}
+	if len(layerCntr.Layers) != 2 || len(layerCntr.Layers[0].LayerRTPCs) != 0 {
+		t.Fatalf("Unexpected layers %+v", layerCntr.Layers)
+	}
+	layer := &layerCntr.Layers[1]
+	if layer.Id == 0 || layer.RTPCId != 900 || len(layer.LayerRTPCs) != 2 || len(layer.InitialRTPC.RTPCItems) != 1 {
+		t.Fatalf("Unexpected new layer %+v", layer)
+	}
+	expects := []wwise.RTPCGraphPoint{
+		{From: 0, To: 0, Interp: uint32(wwise.InterpCurveTypeLinear)},
+		{From: 40, To: 0, Interp: uint32(wwise.InterpCurveTypeLinear)},
+		{From: 60, To: wwise.LayerCurveSilence, Interp: uint32(wwise.InterpCurveTypeLinear)},
+	}
+	if !slices.Equal(layer.Association(10).RTPCGraphPoints, expects) {
+		t.Fatalf("Unexpected crossfade curve %v", layer.Association(10).RTPCGraphPoints)
+	}
+	if item := layer.InitialRTPC.RTPCItems[0]; item.RTPCCurveID == 0 || item.ParamID.Value != uint64(wwise.RTPCParameterTypePitch) {
+		t.Fatalf("Unexpected initial RTPC %+v", item)
+	}
+
+	h.RemoveRoot(10, 20, false)
+	if layer.Association(10) != nil || idle.BaseParam.DirectParentId != 0 {
+		t.Fatal("Expecting removed child to be unassociated from all layers")
+	}
+
+	if err := run(`{"version": 0, "modifiers": [{"id": 20, "layers": [{"id": 30, "associations": [{"child": 1, "crossfade": {"end": 100}}]}]}]}`); err == nil {
+		t.Fatal("Expecting association with a non child to fail")
+	}
+
+	if err := run(`{"version": 0, "modifiers": [{"id": 20, "addChildren": [14]}]}`); err == nil {
+		t.Fatal("Expecting child with a parent in another sound bank to fail")
+	}
+	if foreign.BaseParam.DirectParentId != 99 || slices.Contains(layerCntr.Container.Children, 14) {
+		t.Fatal("Expecting child with a parent in another sound bank to stay in place")
+	}
+	if err := run(fmt.Sprintf(
+		`{"version": 0, "modifiers": [{"id": 20, "layers": [{"id": 30, "initialRTPCs": [{"rtpc": 900, "param": %d, "points": [{"from": 0, "to": 0}, {"from": 100, "to": 1}]}]}]}]}`,
+		wwise.RTPCParameterTypeBusVolume,
+	)); err == nil {
+		t.Fatal("Expecting bus only initial RTPC to fail")
+	}
+}
@@ -0,0 +1,47 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Dekr0/wwise-teller/parser"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Run lint on a sound bank and log all diagnostics. Return whether the sound
+// bank is safe to encode (i.e. no error diagnostic).
+func LintBank(bnk *wwise.Bank, name string) bool {
+	diagnostics := bnk.Lint()
+	for _, d := range diagnostics {
+		switch d.Severity {
+		case wwise.SeverityError:
+			slog.Error(fmt.Sprintf("%s: %s", name, d.String()))
+		case wwise.SeverityWarning:
+			slog.Warn(fmt.Sprintf("%s: %s", name, d.String()))
+		default:
+			slog.Info(fmt.Sprintf("%s: %s", name, d.String()))
+		}
+	}
+	return !wwise.HasLintError(diagnostics)
+}
+
+// Parse and lint each sound bank. Return false if any sound bank fails to
+// parse or has any error diagnostic.
+func LintBanks(ctx context.Context, banks []string) bool {
+	ok := true
+	for _, bank := range banks {
+		bnk, err := parser.ParseBank(bank, ctx, false)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to parse sound bank %s", bank), "error", err)
+			ok = false
+			continue
+		}
+		if LintBank(bnk, bank) {
+			slog.Info(fmt.Sprintf("Sound bank %s passes lint", bank))
+		} else {
+			ok = false
+		}
+	}
+	return ok
+}
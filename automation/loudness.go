@@ -0,0 +1,323 @@
+package automation
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/Dekr0/wwise-teller/parser"
+	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/Dekr0/wwise-teller/wio"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const LoudnessNormalizeSpecVersion = 0
+
+type LoudnessNormalizeSpec struct {
+	Version         uint8          `json:"version"`
+	// Sound or container IDs. Containers are expanded into all sounds under
+	// them.
+	IDs           []uint32         `json:"ids"`
+	// Target integrated loudness in LUFS
+	Target          float32        `json:"target"`
+	// Either Volume or Make Up Gain
+	Prop            wwise.PropType `json:"prop"`
+	// Apply one shared offset so that the set as a whole hits the target
+	// loudness while keeping the relative balance between sounds.
+	Group           bool           `json:"group"`
+	// Set this to any positive value if it's unused
+	TruePeakCeiling float32        `json:"truePeakCeiling"`
+}
+
+func ParseLoudnessNormalizeSpec(spec *LoudnessNormalizeSpec, fspec string) error {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return fmt.Errorf("Failed to open loudness normalization script %s: %w", fspec, err)
+	}
+	err = json.Unmarshal(blob, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to decode loudness normalization script %s: %w", fspec, err)
+	}
+	if spec.Version != LoudnessNormalizeSpecVersion {
+		return fmt.Errorf("Version spec should be %d!", LoudnessNormalizeSpecVersion)
+	}
+	if spec.Prop != wwise.TVolume && spec.Prop != wwise.TMakeUpGain {
+		return fmt.Errorf("Loudness normalization only supports property %s and %s", wwise.PropLabel(wwise.TVolume), wwise.PropLabel(wwise.TMakeUpGain))
+	}
+	return nil
+}
+
+// Decode every WEM in DATA and measure its loudness.
+func AnalyzeLoudness(ctx context.Context, bnk *wwise.Bank) (map[uint32]waapi.Loudness, error) {
+	da := bnk.DATA()
+	if da == nil {
+		return nil, wwise.NoDATA
+	}
+	sids := make([]uint32, 0, len(da.AudiosMap))
+	for sid := range da.AudiosMap {
+		sids = append(sids, sid)
+	}
+	slices.Sort(sids)
+	return measureSources(ctx, da, sids), nil
+}
+
+type SourceLoudness struct {
+	SID uint32 `json:"sid"`
+	waapi.Loudness
+}
+
+type BankLoudness struct {
+	Bank      string           `json:"bank"`
+	Sources []SourceLoudness   `json:"sources"`
+}
+
+// Parse each sound bank and measure loudness of every audio source embedded
+// in it. Sources are in ascending order of source ID. Return false if any
+// sound bank fails to parse or does not have DATA.
+func AnalyzeBanksLoudness(ctx context.Context, banks []string) ([]BankLoudness, bool) {
+	ok := true
+	reports := make([]BankLoudness, 0, len(banks))
+	for _, bank := range banks {
+		bnk, err := parser.ParseBank(bank, ctx, false)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to parse sound bank %s", bank), "error", err)
+			ok = false
+			continue
+		}
+		results, err := AnalyzeLoudness(ctx, bnk)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to analyze loudness of sound bank %s", bank), "error", err)
+			ok = false
+			continue
+		}
+		r := BankLoudness{Bank: bank, Sources: make([]SourceLoudness, 0, len(results))}
+		for _, sid := range slices.Sorted(maps.Keys(results)) {
+			r.Sources = append(r.Sources, SourceLoudness{sid, results[sid]})
+		}
+		reports = append(reports, r)
+	}
+	return reports, ok
+}
+
+func measureSources(ctx context.Context, da *wwise.DATA, sids []uint32) map[uint32]waapi.Loudness {
+	var w sync.WaitGroup
+	var l sync.Mutex
+	sem := make(chan struct{}, 4)
+
+	results := make(map[uint32]waapi.Loudness, len(sids))
+
+	measure := func(sid uint32, main bool) {
+		if !main {
+			defer func() {
+				<- sem
+				w.Done()
+			}()
+		}
+		wem, in := da.AudiosMap[sid]
+		if !in {
+			slog.Error(fmt.Sprintf("No media data for audio source %d", sid))
+			return
+		}
+		r, err := waapi.MeasureLoudnessWEMByte(ctx, wem)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to measure loudness of audio source %d", sid), "error", err)
+			return
+		}
+		slog.Info(
+			fmt.Sprintf("Audio source %d", sid),
+			"LUFS", fmt.Sprintf("%.2f", r.Integrated),
+			"truePeak", fmt.Sprintf("%.2f", r.TruePeak),
+			"RMS", fmt.Sprintf("%.2f", r.RMS),
+		)
+		l.Lock()
+		results[sid] = r
+		l.Unlock()
+	}
+
+	for _, sid := range sids {
+		select {
+		case <- ctx.Done():
+			w.Wait()
+			close(sem)
+			return results
+		default:
+		}
+		select {
+		case sem <- struct{}{}:
+			w.Add(1)
+			go measure(sid, false)
+		default:
+			measure(sid, true)
+		}
+	}
+	w.Wait()
+	close(sem)
+
+	return results
+}
+
+func collectSounds(h *wwise.HIRC, id uint32, sounds []*wwise.Sound) []*wwise.Sound {
+	v, in := h.ActorMixerHirc.Load(id)
+	if !in {
+		slog.Error(fmt.Sprintf("No hierarchy object has ID %d", id))
+		return sounds
+	}
+	o := v.(wwise.HircObj)
+	if s, ok := o.(*wwise.Sound); ok {
+		if !slices.Contains(sounds, s) {
+			sounds = append(sounds, s)
+		}
+		return sounds
+	}
+	for _, leaf := range o.Leafs() {
+		sounds = collectSounds(h, leaf, sounds)
+	}
+	return sounds
+}
+
+func propValF32(p *wwise.PropBundle, pid wwise.PropType, v int) float32 {
+	var val float32
+	_, prop := p.Prop(pid, v)
+	if prop != nil {
+		binary.Decode(prop.V, wio.ByteOrder, &val)
+	}
+	return val
+}
+
+// Adjust Volume or Make Up Gain of each sound so that their media hit a
+// target loudness.
+func LoudnessNormalize(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	return loudnessNormalize(ctx, bnk, fspec, measureSources)
+}
+
+func loudnessNormalize(
+	ctx context.Context,
+	bnk *wwise.Bank,
+	fspec string,
+	measure func(context.Context, *wwise.DATA, []uint32) map[uint32]waapi.Loudness,
+) error {
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+	da := bnk.DATA()
+	if da == nil {
+		return wwise.NoDATA
+	}
+
+	var spec LoudnessNormalizeSpec
+	if err := ParseLoudnessNormalizeSpec(&spec, fspec); err != nil {
+		return err
+	}
+	if len(spec.IDs) <= 0 {
+		slog.Warn("No hierarchy IDs are provided. Do nothing")
+		return nil
+	}
+
+	ver := int(bnk.BKHD().BankGenerationVersion)
+
+	sounds := make([]*wwise.Sound, 0, len(spec.IDs))
+	for _, id := range spec.IDs {
+		sounds = collectSounds(h, id, sounds)
+	}
+	sounds = slices.DeleteFunc(sounds, func(s *wwise.Sound) bool {
+		if s.BankSourceData.StreamType != wwise.SourceTypeDATA {
+			slog.Warn(fmt.Sprintf("Sound %d uses streamed media. Its loudness cannot be measured.", s.Id))
+			return true
+		}
+		return false
+	})
+	if len(sounds) <= 0 {
+		slog.Warn("No sound with in-memory media is found. Do nothing")
+		return nil
+	}
+
+	sids := make([]uint32, 0, len(sounds))
+	for _, s := range sounds {
+		if !slices.Contains(sids, s.BankSourceData.SourceID) {
+			sids = append(sids, s.BankSourceData.SourceID)
+		}
+	}
+	loudness := measure(ctx, da, sids)
+
+	// Current gain applied on top of the media by Volume and Make Up Gain
+	gains := make([]float64, len(sounds))
+	for i, s := range sounds {
+		p := &s.BaseParam.PropBundle
+		gains[i] = float64(propValF32(p, wwise.TVolume, ver) + propValF32(p, wwise.TMakeUpGain, ver))
+	}
+
+	// The group shares one offset. It is limited by the sound with the least
+	// headroom so that the relative balance is kept.
+	groupOffset := 0.0
+	if spec.Group {
+		sum, count := 0.0, 0
+		headroom := math.Inf(1)
+		for i, s := range sounds {
+			r, in := loudness[s.BankSourceData.SourceID]
+			if !in || r.Integrated <= waapi.LoudnessFloor {
+				continue
+			}
+			sum += math.Pow(10, (r.Integrated + gains[i]) / 10)
+			count += 1
+			headroom = min(headroom, float64(spec.TruePeakCeiling) - (r.TruePeak + gains[i]))
+		}
+		if count == 0 {
+			return fmt.Errorf("None of the sounds has measurable loudness")
+		}
+		groupOffset = float64(spec.Target) - 10 * math.Log10(sum / float64(count))
+		if spec.TruePeakCeiling <= 0 && groupOffset > headroom {
+			slog.Warn("Gain of the group is limited by true peak ceiling")
+			groupOffset = headroom
+		}
+	}
+
+	buf := make([]byte, 4, 4)
+	for i, s := range sounds {
+		r, in := loudness[s.BankSourceData.SourceID]
+		if !in {
+			continue
+		}
+		if r.Integrated <= waapi.LoudnessFloor {
+			slog.Warn(fmt.Sprintf("Media of sound %d is silent. Skip", s.Id))
+			continue
+		}
+
+		offset := groupOffset
+		if !spec.Group {
+			offset = float64(spec.Target) - (r.Integrated + gains[i])
+			headroom := float64(spec.TruePeakCeiling) - (r.TruePeak + gains[i])
+			if spec.TruePeakCeiling <= 0 && offset > headroom {
+				slog.Warn(fmt.Sprintf("Gain of sound %d is limited by true peak ceiling", s.Id))
+				offset = headroom
+			}
+		}
+
+		p := &s.BaseParam.PropBundle
+		val := propValF32(p, spec.Prop, ver) + float32(offset)
+		if err := wwise.CheckBasePropVal(spec.Prop, val); err != nil {
+			slog.Error(fmt.Sprintf("Failed to normalize sound %d", s.Id), "error", err)
+			continue
+		}
+		if idx, in := p.HasPid(spec.Prop, ver); !in {
+			binary.Encode(buf, wio.ByteOrder, val)
+			p.AddWithVal(spec.Prop, [4]byte(buf), ver)
+		} else {
+			p.SetPropByIdxF32(idx, val)
+		}
+		slog.Info(
+			fmt.Sprintf("Normalized sound %d", s.Id),
+			"LUFS", fmt.Sprintf("%.2f", r.Integrated),
+			wwise.PropLabel(spec.Prop), fmt.Sprintf("%.2f", val),
+		)
+	}
+
+	return nil
+}
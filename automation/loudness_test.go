@@ -0,0 +1,78 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestLoudnessNormalize(t *testing.T) {
+	const v = 141
+	measured := map[uint32]waapi.Loudness{
+		100: {Integrated: -20, TruePeak: -6},
+		200: {Integrated: -26, TruePeak: -3},
+	}
+	measure := func(ctx context.Context, da *wwise.DATA, sids []uint32) map[uint32]waapi.Loudness {
+		return measured
+	}
+
+	script := filepath.Join(t.TempDir(), "loudness.json")
+	run := func(group bool, ceiling float32) (float32, float32) {
+		h := wwise.NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+		mixer := &wwise.ActorMixer{Id: 1, BaseParam: &wwise.BaseParameter{}}
+		mixer.Container.Children = []uint32{10, 20}
+		a := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{DirectParentId: 1}}
+		a.BankSourceData.SourceID = 100
+		b := &wwise.Sound{Id: 20, BaseParam: &wwise.BaseParameter{DirectParentId: 1}}
+		b.BankSourceData.SourceID = 200
+		h.HircObjs = append(h.HircObjs, a, b, mixer)
+		for _, o := range h.HircObjs {
+			id, _ := o.HircID()
+			h.ActorMixerHirc.Store(id, o)
+		}
+		bnk := wwise.NewBank()
+		bkhd := wwise.NewBKHD(0, []byte{'B', 'K', 'H', 'D'})
+		bkhd.BankGenerationVersion = v
+		bnk.AddChunk(bkhd)
+		bnk.AddChunk(h)
+		bnk.AddChunk(&wwise.DATA{
+			T: []byte{'D', 'A', 'T', 'A'},
+			AudiosMap: map[uint32][]byte{100: {}, 200: {}},
+		})
+
+		spec := fmt.Sprintf(
+			`{"version": 0, "ids": [1], "target": -16, "prop": %d, "group": %t, "truePeakCeiling": %f}`,
+			wwise.TVolume, group, ceiling,
+		)
+		if err := os.WriteFile(script, []byte(spec), 0666); err != nil {
+			t.Fatal(err)
+		}
+		if err := loudnessNormalize(context.Background(), &bnk, script, measure); err != nil {
+			t.Fatal(err)
+		}
+		return propValF32(&a.BaseParam.PropBundle, wwise.TVolume, v),
+			propValF32(&b.BaseParam.PropBundle, wwise.TVolume, v)
+	}
+	near := func(x float32, y float64) bool {
+		return math.Abs(float64(x) - y) < 0.01
+	}
+
+	// Power average of -20 and -26 LUFS is 6.04 dB below the target
+	if a, b := run(true, 1); !near(a, 6.04) || !near(b, 6.04) {
+		t.Fatalf("Expecting one shared offset for the group, got %f and %f", a, b)
+	}
+	// Sound 20 only has 2 dB of headroom under the ceiling, and it limits the
+	// whole group
+	if a, b := run(true, -1); !near(a, 2) || !near(b, 2) {
+		t.Fatalf("Expecting the group offset to be limited by true peak once, got %f and %f", a, b)
+	}
+	if a, b := run(false, -1); !near(a, 4) || !near(b, 2) {
+		t.Fatalf("Expecting each sound to hit the target within its own headroom, got %f and %f", a, b)
+	}
+}
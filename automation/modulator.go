@@ -0,0 +1,155 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const ModulatorBindingSpecVersion = 0
+
+var ModulatorTypeNames map[string]wwise.HircType = map[string]wwise.HircType{
+	"lfo":      wwise.HircTypeLFOModulator,
+	"envelope": wwise.HircTypeEnvelopeModulator,
+	"time":     wwise.HircTypeTimeModulator,
+}
+
+type ModulatorBindingSpec struct {
+	Version      uint8              `json:"version"`
+	Modulators []ModulatorBinding `json:"modulators"`
+}
+
+type ModulatorBinding struct {
+	// "lfo", "envelope" or "time"
+	Type      string             `json:"type"`
+	// Overwrite default modulator properties. Keys are property names in
+	// wwise.ModulatorPropTypeName (e.g. {"LFO Frequency": 6, "LFO Depth": 30}).
+	Props     map[string]float32 `json:"props"`
+	// Hierarchy objects or buses driven by the new modulator
+	Targets []uint32             `json:"targets"`
+	Params  []ModulatorParam     `json:"params"`
+}
+
+type ModulatorParam struct {
+	// Parameter name in wwise.RTPCParameterIDName (e.g. "Pitch")
+	Param string  `json:"param"`
+	// Parameter value when modulator output is 0 and 1 respectively
+	Min   float32 `json:"min"`
+	Max   float32 `json:"max"`
+}
+
+func ParseModulatorBindingSpec(spec *ModulatorBindingSpec, fspec string) error {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return fmt.Errorf("Failed to open modulator binding script %s: %w", fspec, err)
+	}
+	err = json.Unmarshal(blob, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to decode modulator binding script %s: %w", fspec, err)
+	}
+	if spec.Version != ModulatorBindingSpecVersion {
+		return fmt.Errorf("Version spec should be %d!", ModulatorBindingSpecVersion)
+	}
+	for _, m := range spec.Modulators {
+		if _, in := ModulatorTypeNames[m.Type]; !in {
+			return fmt.Errorf("Unknown modulator type %s", m.Type)
+		}
+		for name := range m.Props {
+			if i, err := indexName(wwise.ModulatorPropTypeName, name, "modulator property"); err != nil {
+				return err
+			} else if i == -1 {
+				return fmt.Errorf("Modulator property name is empty")
+			}
+		}
+		for _, p := range m.Params {
+			if i, err := indexName(wwise.RTPCParameterIDName, p.Param, "RTPC parameter"); err != nil {
+				return err
+			} else if i == -1 {
+				return fmt.Errorf("RTPC parameter is not provided")
+			}
+		}
+	}
+	return nil
+}
+
+// Create modulators and drive parameters of hierarchy objects or buses with
+// them via RTPC
+func BindModulators(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+
+	var spec ModulatorBindingSpec
+	if err := ParseModulatorBindingSpec(&spec, fspec); err != nil {
+		return err
+	}
+	if len(spec.Modulators) <= 0 {
+		slog.Warn("No modulators are provided. Do nothing")
+		return nil
+	}
+
+	q, closeConn, commit, rollback, err := db.CreateConnWithTxQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	v := int(bnk.BKHD().BankGenerationVersion)
+	for _, b := range spec.Modulators {
+		if err := ctx.Err(); err != nil {
+			rollback()
+			return err
+		}
+		id, err := db.TryHid(ctx, q)
+		if err != nil {
+			rollback()
+			return err
+		}
+		m, err := wwise.NewModulator(id, ModulatorTypeNames[b.Type])
+		if err != nil {
+			rollback()
+			return err
+		}
+		for name, val := range b.Props {
+			i, _ := indexName(wwise.ModulatorPropTypeName, name, "")
+			p := wwise.ModulatorPropType(i)
+			if err := m.SetProp(p, val); err != nil {
+				rollback()
+				return err
+			}
+		}
+		if err := h.AppendNewModulator(m); err != nil {
+			rollback()
+			return err
+		}
+		slog.Info(fmt.Sprintf("Created %s %d", wwise.HircTypeName[m.ModulatorType], id))
+
+		for _, target := range b.Targets {
+			for _, p := range b.Params {
+				curveID, err := db.TryHid(ctx, q)
+				if err != nil {
+					rollback()
+					return err
+				}
+				i, _ := indexName(wwise.RTPCParameterIDName, p.Param, "")
+				param := wwise.RTPCParameterType(i)
+				if err := h.BindModulator(target, id, curveID, param, p.Min, p.Max, v); err != nil {
+					rollback()
+					return fmt.Errorf("Failed to bind modulator %d to %s of %d: %w", id, p.Param, target, err)
+				}
+				slog.Info(fmt.Sprintf("Bound modulator %d to %s of %d", id, p.Param, target))
+			}
+		}
+	}
+	if err := commit(); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestBindModulators(t *testing.T) {
+	withMemoryDB(t)
+
+	bnk, h := newTestBank(141)
+	a := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{}}
+	b := &wwise.Sound{Id: 20, BaseParam: &wwise.BaseParameter{}}
+	h.HircObjs = append(h.HircObjs, a, b)
+	storeActorMixerHircs(h)
+
+	run := scriptRunner(t, bnk, "engine.json", BindModulators)
+	if err := run(`{"version": 0, "modulators": [
+		{"type": "lfo", "props": {"LFO Frequency": 6, "LFO Waveform": 1}, "targets": [10, 20], "params": [
+			{"param": "Pitch", "min": -25, "max": 25},
+			{"param": "volume", "min": -2, "max": 0}
+		]}
+	]}`); err != nil {
+		t.Fatal(err)
+	}
+
+	m, ok := h.HircObjs[0].(*wwise.Modulator)
+	if !ok || m.ModulatorType != wwise.HircTypeLFOModulator {
+		t.Fatalf("Expecting a new LFO modulator, got %T", h.HircObjs[0])
+	}
+	if f, _ := m.Prop(wwise.ModulatorPropTypeLFOFrequency); f != 6 {
+		t.Fatalf("Expecting LFO frequency 6, got %f", f)
+	}
+	if w, _ := m.Prop(wwise.ModulatorPropTypeLFOWaveform); w != 1 {
+		t.Fatalf("Expecting LFO waveform 1, got %f", w)
+	}
+	curves := map[uint32]bool{}
+	for _, s := range []*wwise.Sound{a, b} {
+		items := s.BaseParam.RTPC.RTPCItems
+		if len(items) != 2 {
+			t.Fatalf("Expecting 2 RTPC items of sound %d, got %+v", s.Id, items)
+		}
+		for i, p := range []wwise.RTPCParameterType{wwise.RTPCParameterTypePitch, wwise.RTPCParameterTypeVolume} {
+			if items[i].RTPCID != m.Id || items[i].RTPCType != wwise.RTPCTypeModulator || items[i].ParamID.Value != uint64(p) {
+				t.Fatalf("Unexpected RTPC item %+v", items[i])
+			}
+			curves[items[i].RTPCCurveID] = true
+		}
+	}
+	if len(curves) != 4 {
+		t.Fatalf("Expecting unique RTPC curve IDs, got %v", curves)
+	}
+
+	if err := run(`{"version": 0, "modulators": [{"type": "lfo", "props": {"LFO Frequncy": 6}}]}`); err == nil {
+		t.Fatal("Expecting error on unknown modulator property")
+	}
+	if err := run(`{"version": 0, "modulators": [{"type": "lfo", "targets": [10], "params": [{"min": 0, "max": 1}]}]}`); err == nil {
+		t.Fatal("Expecting error on missing RTPC parameter")
+	}
+}
@@ -0,0 +1,367 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const MusicSegmentFromStemsSpecVersion = 0
+
+// Build a music segment playing wave stems in parallel, one music track per
+// stem. All positions and durations are in milliseconds.
+type MusicSegmentFromStemsSpec struct {
+	Version     uint8                      `json:"version"`
+	// Relative stem paths are resolved against workspace. Default to the
+	// directory of the script.
+	Workspace   string                     `json:"workspace"`
+	Conversion  string                     `json:"conversion"`
+	Format      waapi.ConversionFormatType `json:"format"`
+	// Music random / sequence container or music switch container the new
+	// music segment is attached to. Under a music random / sequence container,
+	// the new music segment is appended to the play list. Under a music switch
+	// container, the decision tree path in Path is pointed at it.
+	Parent      uint32                     `json:"parent"`
+	// Switch / state IDs of the decision tree path of a music switch
+	// container, one per argument of the decision tree in order. 0 is the
+	// default path (*).
+	Path      []uint32                     `json:"path"`
+	// Music segment providing the setting of the new music segment. Its first
+	// music track provides the setting of new music tracks. Default to the
+	// first music segment of the parent if zero.
+	RefSegment  uint32                     `json:"refSegment"`
+	// Beats per minute
+	Tempo       float32                    `json:"tempo"`
+	BeatsPerBar uint8                      `json:"beatsPerBar"`
+	BeatValue   uint8                      `json:"beatValue"`
+	// Position of the entry cue
+	Entry       float64                    `json:"entry"`
+	// Position of the exit cue. Default to the end of Bars bars after the
+	// entry cue if Bars is non zero, otherwise the end of the longest stem.
+	Exit        float64                    `json:"exit"`
+	Bars        uint32                     `json:"bars"`
+	// Loop count of the new music segment in the play list of a music random /
+	// sequence container. 0 means infinite.
+	Loop        int16                      `json:"loop"`
+	Stems     []MusicStem                  `json:"stems"`
+}
+
+type MusicStem struct {
+	Wav       string  `json:"wav"`
+	// Position of the stem in the music segment
+	PlayAt    float64 `json:"playAt"`
+	// Trimmed from the beginning of the stem (>= 0)
+	BeginTrim float64 `json:"beginTrim"`
+	// Trimmed from the end of the stem relative to its end (<= 0)
+	EndTrim   float64 `json:"endTrim"`
+}
+
+// Duration of one bar
+func (s *MusicSegmentFromStemsSpec) BarDuration() float64 {
+	return 60000.0 / float64(s.Tempo) * float64(s.BeatsPerBar) * 4.0 / float64(s.BeatValue)
+}
+
+// Return wave files of each stem
+func ParseMusicSegmentFromStemsSpec(spec *MusicSegmentFromStemsSpec, fspec string) ([]string, error) {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open music segment script %s: %w", fspec, err)
+	}
+	if err = json.Unmarshal(blob, spec); err != nil {
+		return nil, fmt.Errorf("Failed to decode music segment script %s: %w", fspec, err)
+	}
+	if spec.Version != MusicSegmentFromStemsSpecVersion {
+		return nil, fmt.Errorf("Version spec should be %d!", MusicSegmentFromStemsSpecVersion)
+	}
+	if spec.Workspace == "" {
+		spec.Workspace = filepath.Dir(fspec)
+	} else if !filepath.IsAbs(spec.Workspace) {
+		return nil, fmt.Errorf("Workspace path %s is not an absolute path.", spec.Workspace)
+	}
+	if spec.Format < waapi.ConversionFormatTypePCM || spec.Format > waapi.ConversionFormatTypeWEMOpus {
+		return nil, fmt.Errorf("Invalid conversion format type %d", spec.Format)
+	}
+	if spec.Tempo <= 0 {
+		return nil, fmt.Errorf("Invalid tempo %f", spec.Tempo)
+	}
+	if spec.BeatsPerBar == 0 {
+		return nil, fmt.Errorf("Invalid # of beats per bar %d", spec.BeatsPerBar)
+	}
+	if !slices.Contains([]uint8{1, 2, 4, 8, 16, 32}, spec.BeatValue) {
+		return nil, fmt.Errorf("Invalid beat value %d", spec.BeatValue)
+	}
+	if spec.Entry < 0 {
+		return nil, fmt.Errorf("Entry cue cannot be negative")
+	}
+	if spec.Exit != 0 && spec.Exit <= spec.Entry {
+		return nil, fmt.Errorf("Exit cue %f must be after entry cue %f", spec.Exit, spec.Entry)
+	}
+	if spec.Loop < 0 {
+		return nil, fmt.Errorf("Invalid loop count %d", spec.Loop)
+	}
+	if len(spec.Stems) <= 0 {
+		return nil, fmt.Errorf("No stem is provided")
+	}
+	wavs := make([]string, len(spec.Stems))
+	for i := range spec.Stems {
+		s := &spec.Stems[i]
+		if s.BeginTrim < 0 || s.EndTrim > 0 {
+			return nil, fmt.Errorf("Invalid trims of stem %s", s.Wav)
+		}
+		if !filepath.IsAbs(s.Wav) {
+			s.Wav = filepath.Join(spec.Workspace, s.Wav)
+		}
+		wavs[i] = s.Wav
+	}
+	return wavs, nil
+}
+
+func MusicSegmentFromStems(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	if bnk.DIDX() == nil {
+		return wwise.NoDIDX
+	}
+	if bnk.DATA() == nil {
+		return wwise.NoDATA
+	}
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+	var spec MusicSegmentFromStemsSpec
+	wavs, err := ParseMusicSegmentFromStemsSpec(&spec, fspec)
+	if err != nil {
+		return err
+	}
+
+	v, in := h.MusicHirc.Load(spec.Parent)
+	if !in {
+		return fmt.Errorf("No music hierarchy object has ID %d.", spec.Parent)
+	}
+	var children []uint32
+	var playList *wwise.MusicRanSeqCntr
+	var switchCntr *wwise.MusicSwitchCntr
+	switch parent := v.(type) {
+	case *wwise.MusicRanSeqCntr:
+		children = parent.Children.Children
+		playList = parent
+	case *wwise.MusicSwitchCntr:
+		children = parent.Children.Children
+		switchCntr = parent
+		tree, err := parent.DecisionTree()
+		if err != nil {
+			return fmt.Errorf("Failed to decode decision tree of music switch container %d: %w", spec.Parent, err)
+		}
+		if len(spec.Path) != len(tree.Arguments) {
+			return fmt.Errorf(
+				"Music switch container %d needs a path of %d switches / states, got %d",
+				spec.Parent, len(tree.Arguments), len(spec.Path),
+			)
+		}
+	default:
+		return fmt.Errorf("Parent music hierarchy type %s is yet supported.", wwise.HircTypeName[v.(wwise.HircObj).HircType()])
+	}
+
+	var refSegment *wwise.MusicSegment
+	if spec.RefSegment != 0 {
+		v, in := h.MusicHirc.Load(spec.RefSegment)
+		if !in {
+			return fmt.Errorf("No reference music segment has ID %d.", spec.RefSegment)
+		}
+		var ok bool
+		if refSegment, ok = v.(*wwise.MusicSegment); !ok {
+			return fmt.Errorf("Music hierarchy object %d is not type of music segment.", spec.RefSegment)
+		}
+	}
+	for i := 0; i < len(children) && refSegment == nil; i++ {
+		if v, in := h.MusicHirc.Load(children[i]); in {
+			refSegment, _ = v.(*wwise.MusicSegment)
+		}
+	}
+	if refSegment == nil {
+		return fmt.Errorf("There's no reference music segment in %d to create a new music segment", spec.Parent)
+	}
+	var refTrack *wwise.MusicTrack
+	for i := 0; i < len(refSegment.Children.Children) && refTrack == nil; i++ {
+		if v, in := h.MusicHirc.Load(refSegment.Children.Children[i]); in {
+			refTrack, _ = v.(*wwise.MusicTrack)
+		}
+	}
+	if refTrack == nil {
+		return fmt.Errorf("There's no reference music track in music segment %d to create new music tracks", refSegment.Id)
+	}
+
+	durations := make([]float64, len(wavs))
+	end := 0.0
+	for i, wav := range wavs {
+		d, err := waapi.WAVDuration(wav)
+		if err != nil {
+			return err
+		}
+		durations[i] = float64(d.Microseconds()) / 1000.0
+		s := &spec.Stems[i]
+		if s.BeginTrim - s.EndTrim >= durations[i] {
+			return fmt.Errorf("Stem %s is trimmed entirely", wav)
+		}
+		end = max(end, s.PlayAt + durations[i] + s.EndTrim)
+	}
+	if spec.Exit == 0 {
+		if spec.Bars != 0 {
+			spec.Exit = spec.Entry + float64(spec.Bars) * spec.BarDuration()
+		} else {
+			spec.Exit = end
+		}
+	}
+	if spec.Exit <= spec.Entry {
+		return fmt.Errorf("Exit cue %f must be after entry cue %f", spec.Exit, spec.Entry)
+	}
+
+	staging, jobs, err := waapi.StageConversion(wavs)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+	if err := waapi.ConverterFrom(ctx).Convert(ctx, jobs, spec.Conversion, spec.Format); err != nil {
+		return err
+	}
+	audioDatas := make([][]byte, len(jobs))
+	for i, job := range jobs {
+		if audioDatas[i], err = os.ReadFile(job.Wem); err != nil {
+			return fmt.Errorf("Failed to read audio data from %s: %w", job.Wem, err)
+		}
+	}
+
+	// Hierarchy IDs generation and Source IDs generation
+	q, closeConn, commit, rollback, err := db.CreateConnWithTxQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+	segmentId, err := db.TryHid(ctx, q)
+	if err != nil {
+		rollback()
+		return err
+	}
+	trackIds := make([]uint32, len(audioDatas))
+	sourceIds := make([]uint32, len(audioDatas))
+	for i := range audioDatas {
+		if trackIds[i], err = db.TryHid(ctx, q); err != nil {
+			rollback()
+			return err
+		}
+		if sourceIds[i], err = db.TrySid(ctx, q); err != nil {
+			rollback()
+			return err
+		}
+	}
+	itemId := uint32(0)
+	if playList != nil {
+		if itemId, err = db.TryHid(ctx, q); err != nil {
+			rollback()
+			return err
+		}
+	}
+
+	for i, audioData := range audioDatas {
+		if err := bnk.AppendAudio(audioData, sourceIds[i]); err != nil {
+			rollback()
+			return fmt.Errorf("Failed to add a new audio source file: %w.", err)
+		}
+	}
+	bnk.ComputeDIDXOffset()
+	if err := bnk.CheckDIDXDATA(); err != nil {
+		rollback()
+		return fmt.Errorf("Invalid Integrity appear in DIDX and DATA chunk: %w", err)
+	}
+
+	segment := &wwise.MusicSegment{
+		Id: segmentId,
+		OverrideFlags: refSegment.OverrideFlags,
+		BaseParam: *refSegment.BaseParam.Clone(false),
+		Children: wwise.Container{Children: []uint32{}},
+		MeterInfo: wwise.MeterInfo{
+			GridPeriod: spec.BarDuration(),
+			GridOffset: spec.Entry,
+			Tempo: spec.Tempo,
+			TimeSigNumBeatsBar: spec.BeatsPerBar,
+			TimeSigBeatVal: spec.BeatValue,
+			MeterInfoFlag: wwise.MeterInfoFlagOverrideParent,
+		},
+		Stingers: []wwise.Stinger{},
+		Duration: max(end, spec.Exit),
+		Markers: []wwise.MusicSegmentMarker{
+			{ID: wwise.MusicMarkerEntryID, Position: spec.Entry, MarkerName: []byte{0}},
+			{ID: wwise.MusicMarkerExitID, Position: spec.Exit, MarkerName: []byte{0}},
+		},
+	}
+	if err := h.AppendNewMusicSegmentToMusicCntr(segment, spec.Parent, false); err != nil {
+		rollback()
+		return fmt.Errorf("Failed to add a new music segment to %d: %w", spec.Parent, err)
+	}
+
+	pluginID := spec.Format.PluginID()
+	for i, s := range spec.Stems {
+		track := &wwise.MusicTrack{
+			Id: trackIds[i],
+			OverrideFlags: refTrack.OverrideFlags,
+			Sources: []wwise.BankSourceData{{
+				PluginID: pluginID,
+				StreamType: wwise.SourceTypeDATA,
+				SourceID: sourceIds[i],
+				InMemoryMediaSize: uint32(len(audioDatas[i])),
+				SourceBits: 0,
+			}},
+			PlayListItems: []wwise.MusicTrackPlayListItem{{
+				TrackID: 0,
+				SourceID: sourceIds[i],
+				PlayAt: s.PlayAt,
+				BeginTrimOffset: s.BeginTrim,
+				EndTrimOffset: s.EndTrim,
+				SrcDuration: durations[i],
+			}},
+			NumSubTrack: 1,
+			ClipAutomations: []wwise.ClipAutomation{},
+			BaseParam: *refTrack.BaseParam.Clone(false),
+			LookAheadTime: refTrack.LookAheadTime,
+		}
+		if err := h.AppendNewMusicTrackToMusicSegment(track, segmentId, false); err != nil {
+			rollback()
+			return fmt.Errorf("Failed to add a new music track to music segment %d: %w", segmentId, err)
+		}
+	}
+
+	if playList != nil {
+		if err := playList.AddPlayListSegment(playList.PlayListNode.PlayListItemID, itemId, segmentId); err != nil {
+			rollback()
+			return fmt.Errorf("Failed to add music segment %d to the play list of %d: %w", segmentId, spec.Parent, err)
+		}
+		playList.PlayListNode.Node(itemId).Loop = spec.Loop
+	}
+	if switchCntr != nil {
+		if err := switchCntr.AssignDecisionTreePath(spec.Path, segmentId); err != nil {
+			rollback()
+			return fmt.Errorf("Failed to assign music segment %d to a path of %d: %w", segmentId, spec.Parent, err)
+		}
+	}
+
+	if err := commit(); err != nil {
+		rollback()
+		return err
+	}
+
+	slog.Info(fmt.Sprintf("Music segment %d -> %d (entry %.3f ms, exit %.3f ms)", segmentId, spec.Parent, spec.Entry, spec.Exit))
+	for i, wav := range wavs {
+		slog.Info(fmt.Sprintf("Music track %d -> Audio Source %d (%s)", trackIds[i], sourceIds[i], filepath.Base(wav)))
+	}
+	if switchCntr != nil {
+		slog.Info(fmt.Sprintf("Decision tree path %v of music switch container %d -> music segment %d", spec.Path, spec.Parent, segmentId))
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package automation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/utils"
+	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/Dekr0/wwise-teller/wwise"
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+)
+
+func writeTestWAV(t *testing.T, path string, frames int) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	e := wav.NewEncoder(f, 8000, 16, 1, 1)
+	err = e.Write(&audio.IntBuffer{
+		Format: &audio.Format{NumChannels: 1, SampleRate: 8000},
+		Data: make([]int, frames),
+		SourceBitDepth: 16,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMusicSegmentFromStems(t *testing.T) {
+	withMemoryDB(t)
+
+	if err := utils.InitTmp(); err != nil {
+		t.Fatal(err)
+	}
+	defer utils.CleanTmp()
+
+	dir := t.TempDir()
+	// 2000 ms and 1000 ms
+	writeTestWAV(t, filepath.Join(dir, "drums.wav"), 16000)
+	writeTestWAV(t, filepath.Join(dir, "bass.wav"), 8000)
+	script := filepath.Join(dir, "combat.json")
+	spec := `{
+		"version": 0,
+		"conversion": "default",
+		"format": 0,
+		"parent": 1,
+		"tempo": 120,
+		"beatsPerBar": 4,
+		"beatValue": 4,
+		"entry": 500,
+		"bars": 1,
+		"loop": 2,
+		"stems": [
+			{"wav": "drums.wav"},
+			{"wav": "bass.wav", "playAt": 500, "beginTrim": 100, "endTrim": -200}
+		]
+	}`
+	if err := os.WriteFile(script, []byte(spec), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	bnk, h := newTestBank(141)
+	cntr := &wwise.MusicRanSeqCntr{Id: 1, PlayListNode: wwise.NewMusicPlayListGroup(2, wwise.MusicRSTypeContinuousSequence)}
+	cntr.Children.Children = []uint32{10}
+	cntr.PlayListNode.AppendLeaf(wwise.NewMusicPlayListSegment(3, 10))
+	ref := &wwise.MusicSegment{Id: 10, BaseParam: wwise.BaseParameter{DirectParentId: 1}}
+	ref.Children.Children = []uint32{100}
+	refTrack := &wwise.MusicTrack{Id: 100, LookAheadTime: 100, BaseParam: wwise.BaseParameter{DirectParentId: 10}}
+	// Music switch container with one switch path pointing at its segment
+	sw := &wwise.MusicSwitchCntr{Id: 2}
+	sw.Children.Children = []uint32{20}
+	tree := wwise.DecisionTree{
+		Arguments: []wwise.DecisionTreeArgument{{GroupID: 500, GroupType: wwise.GroupTypeSwitch}},
+		Root: wwise.DecisionTreeNode{Children: []wwise.DecisionTreeNode{{Key: 503, AudioNodeID: 20}}},
+	}
+	sw.DecisionTreeData = tree.Encode()
+	swRef := &wwise.MusicSegment{Id: 20, BaseParam: wwise.BaseParameter{DirectParentId: 2}}
+	swRef.Children.Children = []uint32{200}
+	swRefTrack := &wwise.MusicTrack{Id: 200, BaseParam: wwise.BaseParameter{DirectParentId: 20}}
+	h.HircObjs = append(h.HircObjs, refTrack, ref, cntr, swRefTrack, swRef, sw)
+	for _, o := range h.HircObjs {
+		id, _ := o.HircID()
+		h.MusicHirc.Store(id, o)
+	}
+	bnk.AddChunk(wwise.NewDIDX(1, []byte{'D', 'I', 'D', 'X'}, 0))
+	bnk.AddChunk(&wwise.DATA{I: 2, T: []byte{'D', 'A', 'T', 'A'}, Audios: [][]byte{}, AudiosMap: map[uint32][]byte{}})
+
+	ctx := waapi.WithConverter(context.Background(), &waapi.PlaceholderConverter{})
+	if err := MusicSegmentFromStems(ctx, bnk, script); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cntr.Children.Children) != 2 {
+		t.Fatalf("Expecting a new music segment, got children %v", cntr.Children.Children)
+	}
+	segmentId := cntr.Children.Children[1]
+	v, _ := h.MusicHirc.Load(segmentId)
+	m, ok := v.(*wwise.MusicSegment)
+	if !ok {
+		t.Fatalf("Expecting a music segment, got %T", v)
+	}
+	if m.BaseParam.DirectParentId != 1 || m.MeterInfo.GridPeriod != 2000 || m.MeterInfo.GridOffset != 500 {
+		t.Fatalf("Unexpected music segment %+v", m)
+	}
+	// One bar at 120 BPM in 4 / 4 after the entry cue
+	if len(m.Markers) != 2 || m.Markers[0].Position != 500 || m.Markers[1].Position != 2500 || m.Duration != 2500 {
+		t.Fatalf("Unexpected markers %+v with duration %f", m.Markers, m.Duration)
+	}
+	if len(m.Children.Children) != 2 {
+		t.Fatalf("Expecting two music tracks, got %v", m.Children.Children)
+	}
+	for i, expect := range []wwise.MusicTrackPlayListItem{
+		{PlayAt: 0, SrcDuration: 2000},
+		{PlayAt: 500, BeginTrimOffset: 100, EndTrimOffset: -200, SrcDuration: 1000},
+	} {
+		v, _ := h.MusicHirc.Load(m.Children.Children[i])
+		track := v.(*wwise.MusicTrack)
+		if track.BaseParam.DirectParentId != m.Id || track.LookAheadTime != 100 || len(track.Sources) != 1 {
+			t.Fatalf("Unexpected music track %+v", track)
+		}
+		expect.SourceID = track.Sources[0].SourceID
+		if len(track.PlayListItems) != 1 || track.PlayListItems[0] != expect {
+			t.Fatalf("Expecting play list item %+v, got %+v", expect, track.PlayListItems)
+		}
+		if _, in := bnk.DATA().AudiosMap[expect.SourceID]; !in {
+			t.Fatalf("Audio source %d is not added", expect.SourceID)
+		}
+	}
+	leafs := cntr.PlayListNode.PlayListLeafs
+	if len(leafs) != 2 || leafs[1].SegmentID != m.Id || leafs[1].Loop != 2 {
+		t.Fatalf("Unexpected play list %+v", leafs)
+	}
+	switchSpec := `{"version": 0, "conversion": "default", "format": 0, "parent": 2,
+		"tempo": 120, "beatsPerBar": 4, "beatValue": 4, "stems": [{"wav": "drums.wav"}]}`
+	if err := os.WriteFile(script, []byte(switchSpec), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := MusicSegmentFromStems(ctx, bnk, script); err == nil || len(sw.Children.Children) != 1 {
+		t.Fatal("Expecting music switch container parent without a path to fail")
+	}
+	switchSpec = strings.Replace(switchSpec, `"parent": 2,`, `"parent": 2, "path": [501],`, 1)
+	if err := os.WriteFile(script, []byte(switchSpec), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := MusicSegmentFromStems(ctx, bnk, script); err != nil {
+		t.Fatal(err)
+	}
+	if len(sw.Children.Children) != 2 {
+		t.Fatalf("Expecting a new music segment under music switch container, got %v", sw.Children.Children)
+	}
+	d, err := sw.DecisionTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := d.Root.Children
+	if len(paths) != 2 || paths[0].Key != 501 || paths[0].AudioNodeID != sw.Children.Children[1] || paths[1].AudioNodeID != 20 {
+		t.Fatalf("Expecting switch 501 to play the new music segment, got %+v", paths)
+	}
+
+	order := map[uint32]int{}
+	for i, o := range h.HircObjs {
+		id, _ := o.HircID()
+		order[id] = i
+	}
+	for _, o := range h.HircObjs {
+		id, _ := o.HircID()
+		if p := o.BaseParameter(); p != nil && p.DirectParentId != 0 && order[id] > order[p.DirectParentId] {
+			t.Fatalf("%d is placed after its parent %d", id, p.DirectParentId)
+		}
+	}
+}
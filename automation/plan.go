@@ -0,0 +1,147 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Dekr0/wwise-teller/integration"
+	"github.com/Dekr0/wwise-teller/parser"
+	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+type PlanFailure struct {
+	Script string `json:"script"`
+	Error  string `json:"error"`
+}
+
+// What a pipeline would do to one sound bank
+type BankPlan struct {
+	Bank         string         `json:"bank"`
+	Applied    []string         `json:"applied"`
+	Failed     []PlanFailure    `json:"failed"`
+	// Reason why the sound bank would not be written. Empty if it would be
+	// written.
+	Discarded    string         `json:"discarded,omitempty"`
+	Lint       []string         `json:"lint,omitempty"`
+	wwise.BankDiff
+	SizeBefore   int64          `json:"sizeBefore"`
+	SizeAfter    int64          `json:"sizeAfter"`
+}
+
+type ProcessPlan struct {
+	// IDs of created objects and media come from a throwaway database. They
+	// will be different in a real run.
+	PlaceholderIDs      bool      `json:"placeholderIds"`
+	// Wave files are not converted. Sizes of their media are estimated by
+	// the size of wave files.
+	EstimatedMediaSizes bool      `json:"estimatedMediaSizes"`
+	Banks             []BankPlan  `json:"banks"`
+}
+
+type planner struct {
+	l         sync.Mutex
+	plan      ProcessPlan
+	converter waapi.PlaceholderConverter
+	// Label IDs in sound bank diffs. Can be nil.
+	names    *wwise.NameDB
+}
+
+type plannerKey struct{}
+
+func plannerFrom(ctx context.Context) *planner {
+	p, _ := ctx.Value(plannerKey{}).(*planner)
+	return p
+}
+
+// Run every pipeline of a processor without writing sound banks, allocating
+// real IDs or converting audio. The Wwise sound bank ID database must be
+// replaced by a throwaway one (see db.InitMemoryDatabase) beforehand. IDs in
+// sound bank diffs are labeled with known names if names is not nil.
+func Plan(ctx context.Context, fspec string, names *wwise.NameDB) (*ProcessPlan, error) {
+	spec, err := ParseProcessor(fspec)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse processor %s: %w", fspec, err)
+	}
+	pl := &planner{plan: ProcessPlan{PlaceholderIDs: true, Banks: []BankPlan{}}, names: names}
+	runProcessor(context.WithValue(ctx, plannerKey{}, pl), spec)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	pl.plan.EstimatedMediaSizes = pl.converter.Converted() > 0
+	return &pl.plan, nil
+}
+
+func bankPath(p *ProcessPipeline, bank string) string {
+	if !filepath.IsAbs(bank) {
+		return filepath.Join(p.BanksWorkspace, bank)
+	}
+	return bank
+}
+
+// Compare each processed sound bank against its original instead of
+// encoding it into the output.
+func (pl *planner) planPipeline(ctx context.Context, p *ProcessPipeline, bnks []*wwise.Bank, results []BankResult) {
+	for i, bnk := range bnks {
+		r := &results[i]
+		bp := BankPlan{
+			Bank: bankPath(p, r.Bank),
+			Applied: r.Applied,
+			Failed: make([]PlanFailure, len(r.Failed)),
+			Discarded: r.Discarded,
+		}
+		if bp.Applied == nil {
+			bp.Applied = []string{}
+		}
+		for j, f := range r.Failed {
+			bp.Failed[j] = PlanFailure{f.Script, f.Err.Error()}
+		}
+		if stat, err := os.Stat(bp.Bank); err == nil {
+			bp.SizeBefore = stat.Size()
+		}
+
+		if bnk != nil {
+			pl.planBank(ctx, p, bnk, &bp)
+		}
+
+		pl.l.Lock()
+		pl.plan.Banks = append(pl.plan.Banks, bp)
+		pl.l.Unlock()
+	}
+}
+
+func (pl *planner) planBank(ctx context.Context, p *ProcessPipeline, bnk *wwise.Bank, bp *BankPlan) {
+	original, err := parser.ParseBank(bp.Bank, ctx, false)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to parse original sound bank %s", bp.Bank), "error", err)
+		bp.Discarded = "Failed to parse original"
+		return
+	}
+	bp.BankDiff = wwise.DiffBank(original, bnk, pl.names)
+
+	if !p.SkipLint {
+		for _, d := range bnk.Lint() {
+			if d.Severity == wwise.SeverityError {
+				bp.Lint = append(bp.Lint, d.String())
+			}
+		}
+		if len(bp.Lint) > 0 {
+			bp.Discarded = "Lint error"
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second * 8)
+	defer cancel()
+	data, err := bnk.Encode(ctx, p.Integration == integration.IntegrationTypeHelldivers2, false)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to encode sound bank %s", bp.Bank), "error", err)
+		bp.Discarded = "Failed to encode"
+		return
+	}
+	bp.SizeAfter = int64(len(data))
+}
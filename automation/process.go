@@ -1,4 +1,3 @@
-// TODO: nodes can be run in parallel since some nodes are completely isolated
 package automation
 
 import (
@@ -9,21 +8,22 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
-	"sync"
 	"time"
 
+	"github.com/Dekr0/wwise-teller/db"
 	"github.com/Dekr0/wwise-teller/integration"
 	"github.com/Dekr0/wwise-teller/integration/helldivers"
 	"github.com/Dekr0/wwise-teller/parser"
+	"github.com/Dekr0/wwise-teller/waapi"
 	"github.com/Dekr0/wwise-teller/wwise"
 )
 
-const ProcessSpecVersion = 0
+const ProcessSpecVersion = 1
 
+// Script types are tagged by name in the spec (see ProcessScriptTypeName).
+// The numeric value is only used by version 0 spec. New types are appended.
 type ProcessScriptType uint8
 
-// TODO: this will cause migration problem on the user side since they use 
-// number to tag type
 // No need to add placeholder creation because this can be achive by rewiring 
 // with new sources, and then use type 1 integration
 const (
@@ -37,6 +37,19 @@ const (
 	TypeBulkProcessBaseProp  // Apply one base property to all listed hierarchies
 	TypeStreamTypeModifiers  // Change stream type of a sound object
 	TypeCreateActionRef
+	TypeLoudnessNormalize    // Adjust Volume / Make Up Gain to hit a target loudness
+	TypeAttenuationModifiers // Modify / clone attenuations and retarget hierarchies to them
+	TypeStarlark             // Starlark script (see RunStarlarkSource)
+	TypeSwitchCntrFromFolders // Switch container with a random / sequence container per switch folder
+	TypeLayerCntrModifiers   // Edit children, layers and crossfade curves of layer containers
+	TypeMusicSegmentFromStems // Music segment with a music track per wave stem
+	TypeFxParamModifiers     // Overwrite decoded plugin parameters of FX share sets / customs
+	TypeFxInserts            // Create FX and insert them into FX slots
+	TypeModulatorBindings    // Create modulators and drive parameters with them via RTPC
+	TypeRTPCInserts          // Add RTPC items with initial curves in bulk
+	TypeStateGroupModifiers  // Add / remove state groups and set property values of states
+	TypeBusModifiers         // Create / move buses, route output buses and aux sends, edit ducking
+	TypeEventInserts         // Create events by name and add actions to them
 	ProcessScriptTypeCount
 )
 
@@ -46,6 +59,9 @@ type IProcessScript interface {
 
 type Processor struct {
 	Veriosn     uint8           `json:"version"`
+	// Number of nodes (sound banks, integrations) running at the same time.
+	// Default to DefaultWorkers.
+	Workers     int             `json:"workers,omitempty"`
 	Pipelines []ProcessPipeline `json:"pipelines"`
 }
 
@@ -61,12 +77,23 @@ func (p *Processor) NewPipeline() {
 }
 
 type ProcessPipeline struct {
+	// Used by dependsOn of other pipelines and in logs
+	Name             string                      `json:"name,omitempty"`
+	// Pipelines that must finish before this pipeline runs. Pipelines whose
+	// output contains a sound bank of this pipeline are added implicitly.
+	DependsOn      []string                      `json:"dependsOn,omitempty"`
 	BanksWorkspace   string                      `json:"banksWorkspace"`
 	ScriptsWorkspace string                      `json:"scriptsWorkspace"`
 	Banks          []string                      `json:"banks"`
 	Scripts        []ProcessScript               `json:"scripts"`
 	Integration      integration.IntegrationType `json:"integration"`
 	Output           string                      `json:"output"`
+	// Sound banks with lint error are not encoded unless this is set
+	SkipLint         bool                        `json:"skipLint"`
+	// Backend used to convert wave files. Default to WwiseConsole.
+	Converter        waapi.ConverterSpec         `json:"converter"`
+	// What to do when a process script fails. Default to skipBank.
+	OnFailure        FailurePolicy               `json:"onFailure"`
 }
 
 func (p *ProcessPipeline) Script(name string) bool {
@@ -86,212 +113,285 @@ func (p *ProcessPipeline) NewScript(name string, t ProcessScriptType) {
 }
 
 type ProcessScript struct {
-	Type ProcessScriptType `json:"type"`
-	Script string          `json:"script"`
+	Type     ProcessScriptType `json:"type"`
+	// Path of the script. Relative path is resolved against scripts
+	// workspace.
+	Script   string            `json:"script,omitempty"`
+	// Script body embedded in the pipeline instead of a separate file. A JSON
+	// string is used verbatim (e.g., CSV based scripts). Any other JSON value
+	// is used as a JSON script.
+	Inline   json.RawMessage   `json:"inline,omitempty"`
+	// Resolved file path of the script at run time
+	path     string
+	// Type as it appears in the spec. Kept for validation.
+	rawType  any
 }
 
-// Perform validation and filtering as well
-func ParseProcessor(fspec string) (*Processor, error) {
-	blob, err := os.ReadFile(fspec)
+func Process(ctx context.Context, fspec string) {
+	spec, err := ParseProcessor(fspec)
 	if err != nil {
-		return nil, err
+		slog.Error(fmt.Sprintf("Failed to parse processor %s", fspec), "error", err)
+		return
 	}
-	var spec Processor
-	err = json.Unmarshal(blob, &spec)
+	// Pipelines and banks share the cache so that a wave file is converted
+	// once per processor
+	cache, err := waapi.OpenConversionCache()
 	if err != nil {
-		return nil, err
-	}
-	if spec.Veriosn != ProcessSpecVersion {
-		return nil, fmt.Errorf("Version spec should be %d.", ProcessSpecVersion)
+		slog.Warn("Conversion cache is disabled", "error", err)
+	} else {
+		ctx = waapi.WithConversionCache(ctx, cache)
+		defer cache.LogStats()
 	}
-	spec.Pipelines = slices.DeleteFunc(spec.Pipelines, func(p ProcessPipeline) bool {
-		if !filepath.IsAbs(p.BanksWorkspace) {
-			slog.Error(fmt.Sprintf("%s is not an absolute path.", p.BanksWorkspace))
-			return true
-		}
-		stat, err := os.Lstat(p.BanksWorkspace)
-		if err != nil {
-			if os.IsNotExist(err) {
-				slog.Error(fmt.Sprintf("%s does not exist.", p.ScriptsWorkspace))
-			} else {
-				slog.Error(fmt.Sprintf("Failed to obtain information about %s", p.BanksWorkspace), "error", err)
-			}
-			return true
-		}
-		if !stat.IsDir() {
-			slog.Error(fmt.Sprintf("Bank workspace %s is not a directory.", p.BanksWorkspace))
-			return true
-		}
+	runProcessor(ctx, spec)
+}
 
-		if !filepath.IsAbs(p.ScriptsWorkspace) {
-			slog.Error(fmt.Sprintf("%s is not an absolute path.", p.ScriptsWorkspace))
-			return true
+// Every pipeline is split into nodes: a setup node, one node per sound bank
+// and an integration node. Sound banks of all pipelines share one worker pool.
+// A pipeline runs after pipelines it depends on are integrated (see
+// pipelineDeps).
+func runProcessor(ctx context.Context, spec *Processor) {
+	s := newScheduler(spec.Workers)
+	runs := make([]*pipelineRun, len(spec.Pipelines))
+	integrates := make([]int, len(spec.Pipelines))
+	bankNodes := make([][]int, len(spec.Pipelines))
+	for i := range spec.Pipelines {
+		r := newPipelineRun(i, &spec.Pipelines[i])
+		runs[i] = r
+		setup := s.add(fmt.Sprintf("pipeline %s setup", r.label()), r.setup)
+		bankNodes[i] = make([]int, len(r.p.Banks))
+		for j := range r.p.Banks {
+			bankNodes[i][j] = s.add(
+				fmt.Sprintf("pipeline %s bank %s", r.label(), filepath.Base(r.p.Banks[j])),
+				func(ctx context.Context) error { return r.runBank(ctx, j) },
+				setup,
+			)
 		}
-		stat, err = os.Lstat(p.ScriptsWorkspace)
-		if err != nil {
-			if os.IsNotExist(err) {
-				slog.Error(fmt.Sprintf("%s does not exist.", p.ScriptsWorkspace))
-			} else {
-				slog.Error(fmt.Sprintf("Failed to obtain information about %s", p.ScriptsWorkspace), "error", err)
+		integrates[i] = s.add(fmt.Sprintf("pipeline %s integration", r.label()), r.integrate, bankNodes[i]...)
+	}
+	for i, deps := range pipelineDeps(spec) {
+		for _, d := range deps {
+			for j := range runs[i].p.Banks {
+				s.depend(bankNodes[i][j], integrates[d])
+				if producesBank(&spec.Pipelines[d], bankPath(runs[i].p, runs[i].p.Banks[j])) {
+					runs[i].upstream[j] = append(runs[i].upstream[j], runs[d])
+				}
 			}
-			return true
-		}
-		if !stat.IsDir() {
-			slog.Error(fmt.Sprintf("Script workspace %s is not a directory.", p.ScriptsWorkspace))
-			return true
 		}
+	}
 
-		if len(p.Banks) <= 0 {
-			return true
-		}
-		if len(p.Scripts) <= 0 {
-			return true
-		}
-		if p.Integration == integration.IntegrationTypeNone {
-			return true
-		}
+	err := s.run(ctx)
+	for _, r := range runs {
+		r.close()
+	}
+	if err != nil {
+		slog.Error("Failed to schedule process pipelines", "error", err)
+		return
+	}
+	if ctx.Err() != nil {
+		slog.Error("Process is cancelled", "error", ctx.Err().Error())
+	}
+	s.logTimings()
+}
 
-		stat, err = os.Lstat(p.Output)
-		if err != nil {
-			if os.IsNotExist(err) {
-				err := os.MkdirAll(p.Output, 0777)
-				if err != nil {
-					slog.Error(fmt.Sprintf("Failed to create output directory %s", p.Output), "error", err)
-					return true
-				}
-			} else {
-				slog.Error(fmt.Sprintf("Failed to obtain information about output %s", p.Output))
-				return true
-			}
-		} else {
-			if !stat.IsDir() {
-				slog.Error(fmt.Sprintf("Output %s is not a directory.", p.Output))
-			}
-		}
+// Run a single pipeline on its own
+func RunProcessPipeline(ctx context.Context, p *ProcessPipeline) {
+	runProcessor(ctx, &Processor{Veriosn: ProcessSpecVersion, Pipelines: []ProcessPipeline{*p}})
+}
 
+// Whether a sound bank at the given path is written by a pipeline. Only default
+// integration writes sound banks as is.
+func producesBank(p *ProcessPipeline, bank string) bool {
+	if p.Integration != integration.IntegrationTypeDefault {
 		return false
-	})
+	}
+	for _, b := range p.Banks {
+		if filepath.Clean(filepath.Join(p.Output, filepath.Base(b))) == filepath.Clean(bank) {
+			return true
+		}
+	}
+	return false
+}
 
+// Pipelines each pipeline depends on. A pipeline depends on pipelines listed
+// in its dependsOn and pipelines whose output contains one of its sound
+// banks.
+func pipelineDeps(spec *Processor) [][]int {
+	deps := make([][]int, len(spec.Pipelines))
 	for i := range spec.Pipelines {
-		banksWorkspace := spec.Pipelines[i].BanksWorkspace
-		nodesWorkspace := spec.Pipelines[i].ScriptsWorkspace
-		spec.Pipelines[i].Banks = slices.DeleteFunc(spec.Pipelines[i].Banks, func(b string) bool {
-			if !filepath.IsAbs(b) {
-				b = filepath.Join(banksWorkspace, b)
-			}
-			stat, err := os.Lstat(b)
-			if err != nil {
-				if os.IsNotExist(err) {
-					slog.Error(fmt.Sprintf("Bank %s does not exist.", b))
-				} else {
-					slog.Error(fmt.Sprintf("Fail to obtain information about bank %s", b))
-				}
-				return true
-			}
-			if stat.IsDir() {
-				slog.Error(fmt.Sprintf("%s is a directory.", b))
-				return true
+		p := &spec.Pipelines[i]
+		deps[i] = []int{}
+		for _, name := range p.DependsOn {
+			d := slices.IndexFunc(spec.Pipelines, func(p ProcessPipeline) bool { return p.Name == name })
+			if d != -1 && d != i && !slices.Contains(deps[i], d) {
+				deps[i] = append(deps[i], d)
 			}
-			return false
-		})
-		spec.Pipelines[i].Scripts = slices.DeleteFunc(spec.Pipelines[i].Scripts, func(n ProcessScript) bool {
-			if !filepath.IsAbs(n.Script) {
-				n.Script = filepath.Join(nodesWorkspace, n.Script)
+		}
+		for d := range spec.Pipelines {
+			if d == i || slices.Contains(deps[i], d) {
+				continue
 			}
-			stat, err := os.Lstat(n.Script)
-			if err != nil {
-				if os.IsNotExist(err) {
-					slog.Error(fmt.Sprintf("Process script %s does not exist.", n.Script))
-				} else {
-					slog.Error(fmt.Sprintf("Fail to obtain information about process script %s", n.Script))
+			for _, b := range p.Banks {
+				if producesBank(&spec.Pipelines[d], bankPath(p, b)) {
+					deps[i] = append(deps[i], d)
+					break
 				}
-				return true
-			}
-			if stat.IsDir() {
-				slog.Error(fmt.Sprintf("%s is a directory.", n.Script))
-				return true
-			}
-			if n.Type < TypeRewireWithNewSources || n.Type >= ProcessScriptTypeCount {
-				slog.Error(fmt.Sprintf("Unsupported process script type %d", n.Type))
-				return true
 			}
-			return false
-		})
+		}
 	}
-	spec.Pipelines = slices.DeleteFunc(spec.Pipelines, func(p ProcessPipeline) bool {
-		if len(p.Banks) <= 0 {
-			return true
+	return deps
+}
+
+// State of one pipeline shared by its nodes
+type pipelineRun struct {
+	id         int
+	p         *ProcessPipeline
+	pl        *planner
+	// Context carrying the converter of this pipeline. Cancelled when a sound
+	// bank fails under fail fast policy.
+	pctx       context.Context
+	abort      context.CancelFunc
+	cleanup    func()
+	results  []BankResult
+	bnks     []*wwise.Bank
+	// Pipelines writing each sound bank of this pipeline
+	upstream [][]*pipelineRun
+	finished   bool
+}
+
+func newPipelineRun(id int, p *ProcessPipeline) *pipelineRun {
+	r := &pipelineRun{
+		id: id,
+		p: p,
+		abort: func() {},
+		cleanup: func() {},
+		results: make([]BankResult, len(p.Banks)),
+		bnks: make([]*wwise.Bank, len(p.Banks)),
+		upstream: make([][]*pipelineRun, len(p.Banks)),
+	}
+	for i := range p.Banks {
+		r.results[i].Bank = p.Banks[i]
+	}
+	return r
+}
+
+func (r *pipelineRun) label() string {
+	if r.p.Name != "" {
+		return fmt.Sprintf("%d (%s)", r.id, r.p.Name)
+	}
+	return fmt.Sprintf("%d", r.id)
+}
+
+func (r *pipelineRun) setup(ctx context.Context) error {
+	slog.Info(fmt.Sprintf("Running process pipeline %s", r.label()))
+	c, err := waapi.NewConverter(&r.p.Converter)
+	if err != nil {
+		return fmt.Errorf("Failed to create converter: %w", err)
+	}
+	if cache := waapi.ConversionCacheFrom(ctx); cache != nil && !r.p.Converter.NoCache {
+		c = &waapi.CachedConverter{Converter: c, Cache: cache}
+	}
+	r.pl = plannerFrom(ctx)
+	if r.pl != nil {
+		c = &r.pl.converter
+	}
+	cleanup, err := r.p.resolveScripts()
+	if err != nil {
+		return fmt.Errorf("Failed to resolve process scripts: %w", err)
+	}
+	r.cleanup = cleanup
+	r.pctx, r.abort = context.WithCancel(waapi.WithConverter(ctx, c))
+	return nil
+}
+
+// A sound bank node only fails if the pipeline is aborted. Otherwise, a
+// failing sound bank is discarded and the pipeline carries on.
+func (r *pipelineRun) runBank(ctx context.Context, j int) error {
+	res := &r.results[j]
+	for _, u := range r.upstream[j] {
+		if r.pl != nil {
+			res.discard(fmt.Sprintf("Input is written by pipeline %s which is not run in plan mode", u.label()))
+			return nil
 		}
-		if len(p.Scripts) <= 0 {
-			return true
+		if !u.wrote(bankPath(r.p, r.p.Banks[j])) {
+			res.discard(fmt.Sprintf("Pipeline %s did not write this sound bank", u.label()))
+			return nil
 		}
-		return false
-	})
-	return &spec, nil
+	}
+	if r.pctx.Err() != nil {
+		res.discard("Pipeline is cancelled")
+		return r.pctx.Err()
+	}
+	slog.Info(fmt.Sprintf("Running process script on bank %s", r.p.Banks[j]))
+	r.bnks[j] = RunProcessScripts(r.pctx, r.p.Banks[j], r.p, res)
+	slog.Info(fmt.Sprintf("Processed bank %s", r.p.Banks[j]))
+	if r.bnks[j] == nil && r.p.Policy() == FailurePolicyFailFast {
+		r.abort()
+		return fmt.Errorf("Sound bank %s failed under fail fast policy", r.p.Banks[j])
+	}
+	return nil
 }
 
-func Process(ctx context.Context, fspec string) {
-	spec, err := ParseProcessor(fspec)
-	if err != nil {
-		slog.Error(fmt.Sprintf("Failed to parse processor %s", fspec), "error", err)
-		return
+// Whether a sound bank written by this pipeline is at the given path
+func (r *pipelineRun) wrote(bank string) bool {
+	if !r.finished || r.pl != nil {
+		return false
 	}
-	sems := make(chan struct{}, 8)
-	var w sync.WaitGroup
-	for i := range spec.Pipelines {
-		p := &spec.Pipelines[i]
-		select {
-		case <- ctx.Done():
-			slog.Error("Process is cancelled", "error", ctx.Err().Error())
-			w.Wait()
-			return
-		case sems <- struct{}{}:
-			w.Add(1)
-			go func(j int) {
-				defer func() {
-					<- sems
-					w.Done()
-				}()
-				slog.Info(fmt.Sprintf("(Routine) Running process pipeline %d", j))
-				RunProcessPipeline(ctx, p, sems)
-				slog.Info(fmt.Sprintf("(Routine) Finishing process pipeline %d", j))
-			}(i)
-		default:
-			slog.Info(fmt.Sprintf("Running process pipeline %d", i))
-			RunProcessPipeline(ctx, p, sems)
-			slog.Info(fmt.Sprintf("Finishing process pipeline %d", i))
+	for i := range r.results {
+		out := filepath.Join(r.p.Output, filepath.Base(r.p.Banks[i]))
+		if filepath.Clean(out) == filepath.Clean(bank) && r.results[i].Discarded == "" {
+			return true
 		}
 	}
-	w.Wait()
+	return false
 }
 
-func RunProcessPipeline(ctx context.Context, p *ProcessPipeline, sems chan struct{}) {
-	var w sync.WaitGroup
-	bnks := make([]*wwise.Bank, len(p.Banks), len(p.Banks))
-	for i := range p.Banks {
-		select {
-		case <- ctx.Done():
-			slog.Error("Process pipeline is cancelled", "error", ctx.Err().Error())
-			w.Wait()
-			return
-		case sems <- struct{}{}:
-			w.Add(1)
-			go func(j int) {
-				defer func() {
-					<- sems
-					w.Done()
-				}()
-				slog.Info(fmt.Sprintf("(Routine) Running process script on bank %s", p.Banks[j]))
-				bnks[i] = RunProcessScripts(ctx, p.Banks[j], p)
-				slog.Info(fmt.Sprintf("(Routine) Processed bank %s", p.Banks[j]))
-			}(i)
-		default:
-			slog.Info(fmt.Sprintf("Running process script on bank %s", p.Banks[i]))
-			bnks[i] = RunProcessScripts(ctx, p.Banks[i], p)
-			slog.Info(fmt.Sprintf("Processed bank %s", p.Banks[i]))
+// Discarded sound banks give back their IDs, even if the pipeline is
+// cancelled or a node is skipped
+func (r *pipelineRun) close() {
+	r.abort()
+	r.cleanup()
+	for i := range r.results {
+		if !r.finished {
+			r.results[i].discard("Pipeline did not finish")
+		}
+		if r.results[i].Discarded != "" {
+			r.results[i].rollback(context.Background())
+		}
+	}
+	logPipelineSummary(r.results, len(r.p.Scripts))
+}
+
+func (r *pipelineRun) integrate(ctx context.Context) error {
+	p := r.p
+	results := r.results
+	bnks := r.bnks
+	if ctx.Err() != nil {
+		for i := range results {
+			results[i].discard("Pipeline is cancelled")
+		}
+		return ctx.Err()
+	}
+	if r.pctx.Err() != nil {
+		for i := range results {
+			results[i].discard("Another sound bank failed under fail fast policy")
 		}
+		return fmt.Errorf("Process pipeline is aborted due to fail fast policy")
 	}
-	w.Wait()
+	defer slog.Info(fmt.Sprintf("Finishing process pipeline %s", r.label()))
+
+	if r.pl != nil {
+		r.pl.planPipeline(ctx, p, bnks, results)
+		r.finished = true
+		return nil
+	}
+
+	if err := os.MkdirAll(p.Output, 0777); err != nil {
+		for i := range results {
+			results[i].discard("Failed to create output directory")
+		}
+		return fmt.Errorf("Failed to create output directory %s: %w", p.Output, err)
+	}
+	r.finished = true
 
 	switch p.Integration {
 	case integration.IntegrationTypeNone:
@@ -301,109 +401,199 @@ func RunProcessPipeline(ctx context.Context, p *ProcessPipeline, sems chan struc
 			if bnk == nil {
 				continue
 			}
+			if !p.SkipLint && !LintBank(bnk, p.Banks[i]) {
+				slog.Error(fmt.Sprintf("Sound bank %s fails lint", p.Banks[i]))
+				slog.Warn(fmt.Sprintf("Skipping sound bank %s", p.Banks[i]))
+				results[i].discard("Lint error")
+				continue
+			}
 			ctx, cancel := context.WithTimeout(ctx, time.Second * 8)
 			defer cancel()
 			bnkData, err := bnk.Encode(ctx, false, false)
 			if err != nil {
 				slog.Error(fmt.Sprintf("Failed to encode sound bank %s", p.Banks[i]), "error", err)
 				slog.Warn(fmt.Sprintf("Skipping sound bank %s", p.Banks[i]))
+				results[i].discard("Failed to encode")
 				continue
 			}
 			basename := filepath.Base(p.Banks[i])
 			err = os.WriteFile(filepath.Join(p.Output, basename), bnkData, 0777)
 			if err != nil {
 				slog.Error(fmt.Sprintf("Failed to save sound bank %s to %s", basename, p.Output), "error", err)
+				results[i].discard("Failed to save")
 			}
 		}
 	case integration.IntegrationTypeHelldivers2:
 		slog.Info("Using Helldivers 2 integration")
 		bnksData := [][]byte{}
 		metasData := [][]byte{}
+		included := []int{}
 		for i, bnk := range bnks {
 			if bnk == nil {
 				continue
 			}
+			if !p.SkipLint && !LintBank(bnk, p.Banks[i]) {
+				slog.Error(fmt.Sprintf("Sound bank %s fails lint", p.Banks[i]))
+				slog.Warn(fmt.Sprintf("Skipping sound bank %s", p.Banks[i]))
+				results[i].discard("Lint error")
+				continue
+			}
 			ctx, cancel := context.WithTimeout(ctx, time.Second * 8)
 			defer cancel()
 			bnkData, err := bnk.Encode(ctx, true, false)
 			if err != nil {
 				slog.Error(fmt.Sprintf("Failed to encode sound bank %s", p.Banks[i]), "error", err)
 				slog.Warn(fmt.Sprintf("Skipping sound bank %s", p.Banks[i]))
+				results[i].discard("Failed to encode")
 				continue
 			}
 			meta := bnk.META()
 			if meta == nil {
 				slog.Error(fmt.Sprintf("Sound bank %s is missing META data for integration", p.Banks[i]))
 				slog.Warn(fmt.Sprintf("Skipping sound bank %s", p.Banks[i]))
+				results[i].discard("Missing META data")
 				continue
 			}
 			bnksData = append(bnksData, bnkData)
 			metasData = append(metasData, meta.B)
+			included = append(included, i)
 		}
 		if len(bnksData) <= 0  {
 			slog.Warn("No sound bank data available for integration.")
-			return
+			return nil
 		}
 		err := helldivers.GenHelldiversPatchStableMulti(bnksData, metasData, p.Output)
 		if err != nil {
-			slog.Error("Failed to run Helldivers 2 integration", "error", err)
-		} else {
-			slog.Info("Generated Helldivers 2 patch")
+			for _, i := range included {
+				results[i].discard("Failed to generate Helldivers 2 patch")
+			}
+			return fmt.Errorf("Failed to run Helldivers 2 integration: %w", err)
 		}
+		slog.Info("Generated Helldivers 2 patch")
 	default:
 		panic("Panic Trap")
 	}
+	return nil
 }
 
-func RunProcessScripts(ctx context.Context, bank string, p *ProcessPipeline) *wwise.Bank {
-	if !filepath.IsAbs(bank) {
-		bank = filepath.Join(p.BanksWorkspace, bank)
-	}
+// Run the script chain of a pipeline on a sound bank. Each sound bank has its
+// own ID journal so that IDs allocated by its scripts can be rolled back.
+// Return nil if the sound bank is discarded.
+func RunProcessScripts(ctx context.Context, bank string, p *ProcessPipeline, r *BankResult) *wwise.Bank {
+	bank = bankPath(p, bank)
+	ctx = db.WithJournal(ctx, &r.journal)
 	bnk, err := parser.ParseBank(bank, ctx, false)
 	if err != nil {
 		slog.Error(fmt.Sprintf("Failed to parse sound bank %s", bank), "error", err)
+		r.discard("Failed to parse")
 		return nil
 	}
+	policy := p.Policy()
 	for _, script := range p.Scripts {
 		select {
 		case <- ctx.Done():
 			slog.Error("Process scripts execution is cancelled", "error", ctx.Err().Error())
+			r.discard("Pipeline is cancelled")
 			return nil
 		default:
 		}
-		if !filepath.IsAbs(script.Script) {
-			script.Script = filepath.Join(p.ScriptsWorkspace, script.Script)
+		label := script.Label()
+
+		mark := r.journal.Mark()
+		snapshot := ""
+		if policy == FailurePolicySkipScript {
+			snapshot, err = SnapshotBank(ctx, bnk)
+			if err != nil {
+				slog.Error(fmt.Sprintf("Failed to take snapshot of sound bank %s", bank), "error", err)
+				r.discard("Failed to take snapshot")
+				return nil
+			}
 		}
-		slog.Info(fmt.Sprintf("Running processing script %s", script.Script))
-		switch script.Type {
-		case TypeRewireWithNewSources:
-			err = RewireWithNewSources(ctx, bnk, script.Script, false)
-		case TypeBasePropModifiers:
-			err = ProcessBaseProps(bnk, script.Script)
-		case TypeImportAsRanSeqCntr:
-			err = ImportAsRanSeqCntr(ctx, bnk, script.Script)
-		case TypeReplaceAudioSources:
-			err = ReplaceAudioSources(ctx, bnk, script.Script, false)
-		case TypeRanSeqModifiers:
-			err = ProcessRanSeq(bnk, script.Script)
-		case TypeNewSoundToRanSeqCntr:
-			err = NewSoundToRanSeqCntr(ctx, bnk, script.Script)
-		case TypeBulkProcessBaseProp:
-			err = BulkProcessBaseProp(bnk, script.Script)
-		case TypeStreamTypeModifiers:
-			err = ToStreamTypeBnk(bnk, script.Script)
-		case TypeCreateActionRef:
-			err = CreateActionRef(ctx, bnk, script.Script)
-		default:
-			panic(fmt.Sprintf("Unsupport process script type %d.", script.Type))
+
+		slog.Info(fmt.Sprintf("Running processing script %s", label))
+		err = RunProcessScript(ctx, bnk, script)
+		if snapshot != "" && err == nil {
+			os.Remove(snapshot)
+		}
+		if err == nil {
+			r.Applied = append(r.Applied, label)
+			continue
 		}
+
+		slog.Error(fmt.Sprintf("Failed to run process script %s", label), "error", err)
+		r.Failed = append(r.Failed, ScriptFailure{label, err})
+		if policy != FailurePolicySkipScript {
+			r.discard(fmt.Sprintf("Process script %s failed", filepath.Base(label)))
+			return nil
+		}
+
+		bnk, err = RestoreBank(ctx, snapshot)
+		os.Remove(snapshot)
 		if err != nil {
-			slog.Error(fmt.Sprintf("Failed to run process script %s", script.Script), "error", err)
+			slog.Error(fmt.Sprintf("Failed to roll back sound bank %s", bank), "error", err)
+			r.discard("Failed to roll back a failing script")
+			return nil
+		}
+		if err := r.journal.RollbackTo(context.Background(), mark); err != nil {
+			slog.Error(fmt.Sprintf("Failed to roll back IDs allocated by process script %s", label), "error", err)
 		}
+		slog.Warn(fmt.Sprintf("Rolled back process script %s on sound bank %s", label, bank))
 	}
 	return bnk
 }
 
+func RunProcessScript(ctx context.Context, bnk *wwise.Bank, script ProcessScript) error {
+	path := script.Path()
+	switch script.Type {
+	case TypeRewireWithNewSources:
+		return RewireWithNewSources(ctx, bnk, path, false)
+	case TypeBasePropModifiers:
+		return ProcessBaseProps(bnk, path)
+	case TypeImportAsRanSeqCntr:
+		return ImportAsRanSeqCntr(ctx, bnk, path)
+	case TypeReplaceAudioSources:
+		return ReplaceAudioSources(ctx, bnk, path, false)
+	case TypeRanSeqModifiers:
+		return ProcessRanSeq(bnk, path)
+	case TypeNewSoundToRanSeqCntr:
+		return NewSoundToRanSeqCntr(ctx, bnk, path)
+	case TypeBulkProcessBaseProp:
+		return BulkProcessBaseProp(bnk, path)
+	case TypeStreamTypeModifiers:
+		return ToStreamTypeBnk(bnk, path)
+	case TypeCreateActionRef:
+		return CreateActionRef(ctx, bnk, path)
+	case TypeLoudnessNormalize:
+		return LoudnessNormalize(ctx, bnk, path)
+	case TypeAttenuationModifiers:
+		return ModifyAttenuations(ctx, bnk, path)
+	case TypeStarlark:
+		return RunStarlark(ctx, bnk, path)
+	case TypeSwitchCntrFromFolders:
+		return SwitchCntrFromFolders(ctx, bnk, path)
+	case TypeLayerCntrModifiers:
+		return ModifyLayerCntrs(ctx, bnk, path)
+	case TypeMusicSegmentFromStems:
+		return MusicSegmentFromStems(ctx, bnk, path)
+	case TypeFxParamModifiers:
+		return ModifyFxParams(ctx, bnk, path)
+	case TypeFxInserts:
+		return InsertFx(ctx, bnk, path)
+	case TypeModulatorBindings:
+		return BindModulators(ctx, bnk, path)
+	case TypeRTPCInserts:
+		return InsertRTPCs(ctx, bnk, path)
+	case TypeStateGroupModifiers:
+		return ModifyStateGroups(ctx, bnk, path)
+	case TypeBusModifiers:
+		return ModifyBuses(ctx, bnk, path)
+	case TypeEventInserts:
+		return InsertEvents(ctx, bnk, path)
+	default:
+		return fmt.Errorf("Unsupported process script type %d", script.Type)
+	}
+}
+
 func ProcessActiveBank(ctx context.Context, bnk *wwise.Bank, fspec string) error {
 	spec, err := ParseProcessor(fspec)
 	if err != nil {
@@ -417,14 +607,19 @@ func ProcessActiveBank(ctx context.Context, bnk *wwise.Bank, fspec string) error
 }
 
 func ProcessPipelineActiveBank(ctx context.Context, bnk *wwise.Bank, p *ProcessPipeline) error {
+	cleanup, err := p.resolveScripts()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 	for _, node := range p.Scripts {
 		switch node.Type {
 		case TypeRewireWithNewSources:
-			if err := RewireWithNewSources(ctx, bnk, node.Script, false); err != nil {
+			if err := RewireWithNewSources(ctx, bnk, node.Path(), false); err != nil {
 				return err
 			}
 		case TypeBasePropModifiers:
-			if err := ProcessBaseProps(bnk, node.Script); err != nil {
+			if err := ProcessBaseProps(bnk, node.Path()); err != nil {
 				return err
 			}
 		default:
@@ -12,7 +12,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/Dekr0/wwise-teller/waapi"
 	"github.com/Dekr0/wwise-teller/wwise"
 )
 
@@ -30,11 +29,6 @@ func ReplaceAudioSources(
 		return wwise.NoHIRC
 	}
 
-	proj, err := waapi.GetProject()
-	if err != nil {
-		return err
-	}
-
 	f, err := os.Open(mappingFile)
 	if err != nil {
 		return err
@@ -62,49 +56,14 @@ func ReplaceAudioSources(
 		}
 	}
 
-	wemsMapSounds := make(map[string][]*wwise.Sound, len(wavsMapSounds))
-	wsource, err := waapi.CreateConversionList(ctx, wavsMapSounds, wemsMapSounds, header.Conversion, dry)
-	if len(wavsMapSounds) != len(wemsMapSounds) {
-		panic("# of wavs file in indexing map does not equal # of output wem in indexing map")
-	}
+	wemsMapSounds, wemsMapAudioData, wemsMapPluginID, err := prepareAudioData(ctx, wavsMapSounds, &header, dry)
 	if err != nil {
 		return err
 	}
-	stagingDir := filepath.Dir(wsource)
-	defer os.RemoveAll(stagingDir)
-
 	if dry {
-		for wem, sounds := range wemsMapSounds {
-			fmt.Println(wem)
-			for _, s := range sounds {
-				fmt.Println(s.Id)
-			}
-			fmt.Println()
-		}
 		return nil
 	}
 
-	if err := waapi.WwiseConversion(ctx, wsource, proj); err != nil {
-		return err
-	}
-
-	wemsMapAudioData := make(map[string][]byte, len(wemsMapSounds))
-	errorWems := make([]string, 0, len(wemsMapSounds))
-	for wem := range wemsMapSounds {
-		if _, in := wemsMapAudioData[wem]; in {
-			panic(fmt.Sprintf("Detect duplicated wem file %s when storing audio data", wem))
-		}
-		wemsMapAudioData[wem], err = os.ReadFile(wem)
-		if err != nil {
-			slog.Error(fmt.Sprintf("Failed to read audio data from %s", wem))
-			slog.Error(fmt.Sprintf("Discard all rewiring related to file %s", wem))
-			errorWems = append(errorWems, wem)
-		}
-	}
-	for _, errorWem := range errorWems {
-		delete(wemsMapSounds, errorWem)
-	}
-
 	for wem, sounds := range wemsMapSounds {
 		audioData, in := wemsMapAudioData[wem]
 		if !in {
@@ -114,18 +73,7 @@ func ReplaceAudioSources(
 			if err := bnk.ReplaceAudio(audioData, sound.BankSourceData.SourceID); err != nil {
 				return err
 			}
-			switch header.Format {
-			case waapi.ConversionFormatTypePCM:
-				sound.BankSourceData.PluginID = wwise.PCM
-			case waapi.ConversionFormatTypeADPCM:
-				sound.BankSourceData.PluginID = wwise.ADPCM
-			case waapi.ConversionFormatTypeVORBIS:
-				sound.BankSourceData.PluginID = wwise.VORBIS
-			case waapi.ConversionFormatTypeWEMOpus:
-				sound.BankSourceData.PluginID = wwise.WEM_OPUS
-			default:
-				panic(fmt.Sprintf("Unsupported conversion format %d", header.Format))
-			}
+			sound.BankSourceData.PluginID = wemsMapPluginID[wem]
 			sound.BankSourceData.InMemoryMediaSize = uint32(len(audioData))
 		}
 	}
@@ -170,6 +118,7 @@ func DecodeReplaceAudioSourcesScript(p *WavSoundMap, fspec string) error {
 	if err != nil {
 		return err
 	}
+	defer f.Close()
 
 	reader := csv.NewReader(f)
 	iniDir := filepath.Base(fspec)
@@ -197,13 +146,13 @@ func DecodeReplaceAudioSourcesScript(p *WavSoundMap, fspec string) error {
 		}
 
 		input = row[0]
-		ext = filepath.Ext(input)
+		ext = strings.ToLower(filepath.Ext(input))
 		if ext == "" {
 			ext = ".wav"
 			input += ext
 		}
-		if ext != ".wav" {
-			slog.Error("Wave file is the only supported file format.")
+		if ext != ".wav" && ext != ".wem" {
+			slog.Error("Wave file and pre-converted WEM file are the only supported file formats.")
 			rowNum += 1
 			continue
 		}
@@ -242,6 +191,7 @@ func DecodeReplaceAudioSourcesScript(p *WavSoundMap, fspec string) error {
 				soundIDs = append(soundIDs, uint32(id))
 				columnNum += 1
 			}
+			p.Wavs = append(p.Wavs, input)
 			p.SoundIDs = append(p.SoundIDs, soundIDs)
 		} else {
 			slog.Warn(fmt.Sprintf("Duplicated input file %s at row %d, column 1", input, rowNum))
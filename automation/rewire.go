@@ -46,11 +46,7 @@ func RewireWithNewSources(
 		return wwise.NoHIRC
 	}
 
-	proj, err := waapi.GetProject()
-	if err != nil {
-		return err
-	}
-	err = db.Ping()
+	err := db.Ping()
 	if err != nil {
 		return err
 	}
@@ -82,47 +78,15 @@ func RewireWithNewSources(
 		}
 	}
 
-	wemsMapSounds := make(map[string][]*wwise.Sound, len(wavsMapSound))
-	wsource, err := waapi.CreateConversionList(ctx, wavsMapSound, wemsMapSounds, header.Conversion, dry)
-	if len(wavsMapSound) != len(wemsMapSounds) {
-		panic("# of wavs file in indexing map does not equal # of output wem in indexing map")
-	}
+	wemsMapSounds, wemsMapAudioData, wemsMapPluginID, err := prepareAudioData(ctx, wavsMapSound, &header, dry)
 	if err != nil {
 		return err
 	}
-	stagingDir := filepath.Dir(wsource)
-	defer os.RemoveAll(stagingDir)
-
 	if dry {
-		for wem, sounds := range wemsMapSounds {
-			fmt.Println(wem)
-			for _, s := range sounds {
-				fmt.Println(s.Id)
-			}
-			fmt.Println()
-		}
 		return nil
 	}
-
-	if err := waapi.WwiseConversion(ctx, wsource, proj); err != nil {
-		return err
-	}
-
-	wemsMapAudioData := make(map[string][]byte, len(wemsMapSounds))
-	errorWems := make([]string, 0, len(wemsMapSounds))
-	for wem := range wemsMapSounds {
-		if _, in := wemsMapAudioData[wem]; in {
-			panic(fmt.Sprintf("Detect duplicated wem file %s when storing audio data", wem))
-		}
-		wemsMapAudioData[wem], err = os.ReadFile(wem)
-		if err != nil {
-			slog.Error(fmt.Sprintf("Failed to read audio data from %s", wem))
-			slog.Error(fmt.Sprintf("Discard all rewiring related to file %s", wem))
-			errorWems = append(errorWems, wem)
-		}
-	}
-	for _, errorWem := range errorWems {
-		delete(wemsMapSounds, errorWem)
+	if len(wemsMapSounds) <= 0 {
+		return nil
 	}
 
 	sids := make([]uint32, len(wemsMapSounds))
@@ -169,18 +133,7 @@ func RewireWithNewSources(
 			panic(fmt.Sprintf("Cannot find audio data with wem file %s", wem)) 
 		}
 		for _, sound := range sounds {
-			switch header.Format {
-			case waapi.ConversionFormatTypePCM:
-				sound.BankSourceData.PluginID = wwise.PCM
-			case waapi.ConversionFormatTypeADPCM:
-				sound.BankSourceData.PluginID = wwise.ADPCM
-			case waapi.ConversionFormatTypeVORBIS:
-				sound.BankSourceData.PluginID = wwise.VORBIS
-			case waapi.ConversionFormatTypeWEMOpus:
-				sound.BankSourceData.PluginID = wwise.WEM_OPUS
-			default:
-				panic(fmt.Sprintf("Unsupported conversion format %d", header.Format))
-			}
+			sound.BankSourceData.PluginID = wemsMapPluginID[wem]
 			sound.BankSourceData.SourceID = m.Sid
 			sound.BankSourceData.InMemoryMediaSize = m.Size
 		}
@@ -236,11 +189,11 @@ func ParseFilesToSoundsHeader(
 // Assumption
 // Skip if a row has the following error
 // - less than 2 columns
-// - an input is not in wave format
 // - an input does not exist
 // - not an unsigned integer for # of sound IDs specified
 // - provided # of sound IDs is less than # of sound IDs specified
 // - not an unsigned integer for a sound ID
+// - an input is neither in wave format nor a pre-converted WEM
 // It will append .wav extension if no extension is provided
 // It will use workspace to construct full path if a relative path is provided
 // Skip a sound ID if it doesn't exist
@@ -272,13 +225,13 @@ func ParseSoundMapping(
 
 		// Extension
 		input = row[0]
-		ext = filepath.Ext(input)
+		ext = strings.ToLower(filepath.Ext(input))
 		if ext == "" {
 			ext = ".wav"
 			input += ext
 		}
-		if ext != ".wav" {
-			slog.Error("Wave file is the only supported file format.")
+		if ext != ".wav" && ext != ".wem" {
+			slog.Error("Wave file and pre-converted WEM file are the only supported file formats.")
 			*rowNum += 1
 			continue
 		}
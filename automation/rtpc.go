@@ -0,0 +1,196 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const RTPCInsertSpecVersion = 0
+
+type RTPCInsertSpec struct {
+	Version   uint8        `json:"version"`
+	Inserts []RTPCInsert `json:"inserts"`
+}
+
+type RTPCInsert struct {
+	// Hierarchy objects, buses or attenuations to add the RTPC to
+	Targets     []uint32     `json:"targets"`
+	// Game parameter name. Its short ID is used as RTPC ID.
+	GameParameter string     `json:"gameParameter"`
+	// RTPC ID if game parameter name is not provided
+	Id            uint32     `json:"id"`
+	// RTPC type name in wwise.RTPCTypeName. Default to "Game Parameter".
+	Type          string     `json:"type"`
+	// Parameter name in wwise.RTPCParameterIDName (e.g. "Volume")
+	Param         string     `json:"param"`
+	// Accumulation name in wwise.RTPCAccumTypeName. Default to the
+	// accumulation Wwise uses for this parameter.
+	Accum         string     `json:"accum"`
+	// Scaling name in wwise.CurveScalingTypeName. Default to the scaling
+	// Wwise uses for this parameter.
+	Scaling       string     `json:"scaling"`
+	// At least 2 points in ascending X
+	Curve       []RTPCPoint  `json:"curve"`
+}
+
+type RTPCPoint struct {
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	// Interpolation name in wwise.InterpCurveTypeName. Default to "Linear".
+	Interp string  `json:"interp"`
+}
+
+func (i *RTPCInsert) rtpcID() uint32 {
+	if i.GameParameter != "" {
+		return wwise.ShortID(i.GameParameter)
+	}
+	return i.Id
+}
+
+// Look up an optional enum name case-insensitively. Return -1 if name is
+// empty. Every enum name given by a script is looked up through here.
+func indexName(names []string, name string, kind string) (int, error) {
+	if name == "" {
+		return -1, nil
+	}
+	i := slices.IndexFunc(names, func(n string) bool { return strings.EqualFold(n, name) })
+	if i == -1 {
+		return -1, fmt.Errorf("Unknown %s %s", kind, name)
+	}
+	return i, nil
+}
+
+func ParseRTPCInsertSpec(spec *RTPCInsertSpec, fspec string) error {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return fmt.Errorf("Failed to open RTPC insert script %s: %w", fspec, err)
+	}
+	err = json.Unmarshal(blob, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to decode RTPC insert script %s: %w", fspec, err)
+	}
+	if spec.Version != RTPCInsertSpecVersion {
+		return fmt.Errorf("Version spec should be %d!", RTPCInsertSpecVersion)
+	}
+	for _, i := range spec.Inserts {
+		if i.rtpcID() == 0 {
+			return fmt.Errorf("RTPC insert is missing game parameter name or RTPC ID")
+		}
+		if i.Param == "" {
+			return fmt.Errorf("RTPC insert is missing parameter")
+		}
+		if _, err := indexName(wwise.RTPCParameterIDName, i.Param, "RTPC parameter"); err != nil {
+			return err
+		}
+		if _, err := indexName(wwise.RTPCTypeName, i.Type, "RTPC type"); err != nil {
+			return err
+		}
+		if _, err := indexName(wwise.RTPCAccumTypeName, i.Accum, "RTPC accumulation"); err != nil {
+			return err
+		}
+		if _, err := indexName(wwise.CurveScalingTypeName, i.Scaling, "curve scaling"); err != nil {
+			return err
+		}
+		for _, p := range i.Curve {
+			if _, err := indexName(wwise.InterpCurveTypeName, p.Interp, "curve interpolation"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (i *RTPCInsert) item(curveID uint32) (wwise.RTPCItem, error) {
+	rtpcType := wwise.RTPCTypeGameParameter
+	if t, _ := indexName(wwise.RTPCTypeName, i.Type, ""); t != -1 {
+		rtpcType = uint8(t)
+	}
+	p, _ := indexName(wwise.RTPCParameterIDName, i.Param, "")
+	item, err := wwise.NewRTPCItem(i.rtpcID(), rtpcType, curveID, wwise.RTPCParameterType(p))
+	if err != nil {
+		return item, err
+	}
+	if a, _ := indexName(wwise.RTPCAccumTypeName, i.Accum, ""); a != -1 {
+		item.RTPCAccum = wwise.RTPCAccumType(a)
+	}
+	if s, _ := indexName(wwise.CurveScalingTypeName, i.Scaling, ""); s != -1 {
+		item.Scaling = wwise.CurveScalingType(s)
+	}
+	if len(i.Curve) > 0 {
+		xs := make([]float32, len(i.Curve))
+		ys := make([]float32, len(i.Curve))
+		interps := make([]uint32, len(i.Curve))
+		for j, p := range i.Curve {
+			xs[j], ys[j] = p.X, p.Y
+			interps[j] = uint32(wwise.InterpCurveTypeLinear)
+			if c, _ := indexName(wwise.InterpCurveTypeName, p.Interp, ""); c != -1 {
+				interps[j] = uint32(c)
+			}
+		}
+		if err := item.SetCurve(xs, ys, interps); err != nil {
+			return item, err
+		}
+	}
+	return item, nil
+}
+
+// Add RTPC items to hierarchy objects, buses or attenuations in bulk
+func InsertRTPCs(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+
+	var spec RTPCInsertSpec
+	if err := ParseRTPCInsertSpec(&spec, fspec); err != nil {
+		return err
+	}
+	if len(spec.Inserts) <= 0 {
+		slog.Warn("No RTPC inserts are provided. Do nothing")
+		return nil
+	}
+
+	q, closeConn, commit, rollback, err := db.CreateConnWithTxQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	v := int(bnk.BKHD().BankGenerationVersion)
+	for _, i := range spec.Inserts {
+		for _, target := range i.Targets {
+			if err := ctx.Err(); err != nil {
+				rollback()
+				return err
+			}
+			curveID, err := db.TryHid(ctx, q)
+			if err != nil {
+				rollback()
+				return err
+			}
+			item, err := i.item(curveID)
+			if err != nil {
+				rollback()
+				return fmt.Errorf("Failed to create RTPC %d for %d: %w", i.rtpcID(), target, err)
+			}
+			if err := h.AddRTPC(target, item, v); err != nil {
+				rollback()
+				return fmt.Errorf("Failed to add RTPC %d to %d: %w", i.rtpcID(), target, err)
+			}
+			slog.Info(fmt.Sprintf("Added RTPC %d driving %s of %d", i.rtpcID(), i.Param, target))
+		}
+	}
+	if err := commit(); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
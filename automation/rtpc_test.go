@@ -0,0 +1,65 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestInsertRTPCs(t *testing.T) {
+	withMemoryDB(t)
+
+	bnk, h := newTestBank(141)
+	s := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{}}
+	bus := &wwise.Bus{Id: 20}
+	h.HircObjs = append(h.HircObjs, s, bus)
+	h.ActorMixerHirc.Store(s.Id, s)
+	h.Buses.Store(bus.Id, bus)
+
+	run := scriptRunner(t, bnk, "rpm.json", InsertRTPCs)
+	if err := run(`{"version": 0, "inserts": [
+		{"targets": [10, 20], "gameParameter": "RPM", "param": "Volume", "curve": [
+			{"x": 0, "y": -12, "interp": "S-Curve"}, {"x": 8000, "y": 0}
+		]},
+		{"targets": [10], "id": 1234, "param": "LPF", "accum": "Maximum", "scaling": "None"}
+	]}`); err != nil {
+		t.Fatal(err)
+	}
+	rpm := wwise.ShortID("rpm")
+	items := s.BaseParam.RTPC.RTPCItems
+	if len(items) != 2 || len(bus.BusRTPC.RTPCItems) != 1 {
+		t.Fatalf("Unexpected RTPC items %+v %+v", items, bus.BusRTPC.RTPCItems)
+	}
+	if items[0].RTPCID != rpm || items[0].Scaling != wwise.CurveScalingTypeDb || items[0].RTPCAccum != wwise.RTPCAccumTypeAdditive {
+		t.Fatalf("Unexpected RTPC item %+v", items[0])
+	}
+	if items[0].RTPCGraphPointsX[1] != 8000 || items[0].RTPCGraphPointsInterp[0] != uint32(wwise.InterpCurveTypeSCurve) {
+		t.Fatalf("Unexpected RTPC curve %+v", items[0])
+	}
+	if items[1].RTPCID != 1234 || items[1].RTPCAccum != wwise.RTPCAccumTypeMaximum || items[1].ParamID.Value != uint64(wwise.RTPCParameterTypeLPF) {
+		t.Fatalf("Unexpected RTPC item %+v", items[1])
+	}
+	if items[0].RTPCCurveID == items[1].RTPCCurveID {
+		t.Fatal("Expecting unique RTPC curve IDs")
+	}
+
+	// Bus volume is not available on sounds
+	if err := run(`{"version": 0, "inserts": [{"targets": [10], "gameParameter": "RPM", "param": "Bus Volume"}]}`); err == nil {
+		t.Fatal("Expecting error on bus only parameter")
+	}
+	if err := run(`{"version": 0, "inserts": [{"targets": [10], "gameParameter": "RPM", "param": "Volume", "accum": "Sum"}]}`); err == nil {
+		t.Fatal("Expecting error on unknown accumulation")
+	}
+}
+
+func TestIndexName(t *testing.T) {
+	if i, err := indexName(wwise.RTPCParameterIDName, "lpf", "RTPC parameter"); err != nil || i != int(wwise.RTPCParameterTypeLPF) {
+		t.Fatalf("Expecting case-insensitive match of LPF, got %d, %v", i, err)
+	}
+	if i, err := indexName(wwise.RTPCParameterIDName, "", "RTPC parameter"); err != nil || i != -1 {
+		t.Fatalf("Expecting empty name to be optional, got %d, %v", i, err)
+	}
+	if _, err := indexName(wwise.RTPCParameterIDName, "LFP", "RTPC parameter"); err == nil {
+		t.Fatal("Expecting unknown name to fail")
+	}
+}
@@ -0,0 +1,211 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultWorkers = 8
+
+type nodeStatus uint8
+
+const (
+	nodePending   nodeStatus = 0
+	nodeDone      nodeStatus = 1
+	nodeFailed    nodeStatus = 2
+	// A dependency did not finish
+	nodeSkipped   nodeStatus = 3
+	nodeCancelled nodeStatus = 4
+)
+
+var nodeStatusName []string = []string{
+	"pending",
+	"done",
+	"failed",
+	"skipped",
+	"cancelled",
+}
+
+type schedNode struct {
+	name         string
+	run          func(context.Context) error
+	deps       []int
+	dependents []int
+	// Number of dependencies not yet settled
+	waiting      int
+	// A dependency did not finish successfully
+	blocked      bool
+	status       nodeStatus
+	err          error
+	ready        time.Time
+	start        time.Time
+	end          time.Time
+}
+
+// Run a DAG of nodes on a fixed number of workers. A node runs once all of
+// its dependencies are done. If a dependency fails, is skipped or is
+// cancelled, the node is skipped.
+type scheduler struct {
+	workers   int
+	nodes   []*schedNode
+	start     time.Time
+	end       time.Time
+}
+
+func newScheduler(workers int) *scheduler {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	return &scheduler{workers: workers, nodes: []*schedNode{}}
+}
+
+func (s *scheduler) add(name string, run func(context.Context) error, deps ...int) int {
+	s.nodes = append(s.nodes, &schedNode{name: name, run: run})
+	n := len(s.nodes) - 1
+	s.depend(n, deps...)
+	return n
+}
+
+func (s *scheduler) depend(n int, deps ...int) {
+	for _, d := range deps {
+		if d == n || slices.Contains(s.nodes[n].deps, d) {
+			continue
+		}
+		s.nodes[n].deps = append(s.nodes[n].deps, d)
+		s.nodes[d].dependents = append(s.nodes[d].dependents, n)
+	}
+}
+
+// Nodes that are part of or blocked by a dependency cycle. Empty if the graph
+// is acyclic.
+func (s *scheduler) cycle() []int {
+	waiting := make([]int, len(s.nodes))
+	queue := []int{}
+	for i, n := range s.nodes {
+		waiting[i] = len(n.deps)
+		if waiting[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, d := range s.nodes[i].dependents {
+			waiting[d] -= 1
+			if waiting[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+	cyclic := []int{}
+	for i, w := range waiting {
+		if w > 0 {
+			cyclic = append(cyclic, i)
+		}
+	}
+	return cyclic
+}
+
+func (s *scheduler) run(ctx context.Context) error {
+	if c := s.cycle(); len(c) > 0 {
+		names := make([]string, len(c))
+		for i, n := range c {
+			names[i] = s.nodes[n].name
+		}
+		return fmt.Errorf("Dependency cycle among %s", strings.Join(names, ", "))
+	}
+	s.start = time.Now()
+	defer func() { s.end = time.Now() }()
+
+	ready := make(chan int, len(s.nodes))
+	done := make(chan int)
+	var w sync.WaitGroup
+	for range min(s.workers, len(s.nodes)) {
+		w.Add(1)
+		go func() {
+			defer w.Done()
+			for i := range ready {
+				n := s.nodes[i]
+				n.start = time.Now()
+				if ctx.Err() != nil {
+					n.status = nodeCancelled
+					n.err = ctx.Err()
+				} else if n.err = n.run(ctx); n.err != nil {
+					n.status = nodeFailed
+				} else {
+					n.status = nodeDone
+				}
+				n.end = time.Now()
+				done <- i
+			}
+		}()
+	}
+
+	pending := len(s.nodes)
+	for i, n := range s.nodes {
+		n.waiting = len(n.deps)
+		if n.waiting == 0 {
+			n.ready = s.start
+			ready <- i
+		}
+	}
+	// Settle a node and release or skip its dependents. Skipped nodes are
+	// settled right away.
+	settle := func(i int) {
+		stack := []int{i}
+		for len(stack) > 0 {
+			i := stack[len(stack) - 1]
+			stack = stack[:len(stack) - 1]
+			pending -= 1
+			for _, d := range s.nodes[i].dependents {
+				dn := s.nodes[d]
+				dn.waiting -= 1
+				if s.nodes[i].status != nodeDone {
+					dn.blocked = true
+				}
+				if dn.waiting > 0 {
+					continue
+				}
+				if dn.blocked {
+					dn.status = nodeSkipped
+					stack = append(stack, d)
+					continue
+				}
+				dn.ready = time.Now()
+				ready <- d
+			}
+		}
+	}
+	for pending > 0 {
+		settle(<- done)
+	}
+	close(ready)
+	w.Wait()
+	return nil
+}
+
+func (s *scheduler) logTimings() {
+	for _, n := range s.nodes {
+		switch n.status {
+		case nodeDone:
+			slog.Info(fmt.Sprintf(
+				"[%s] %s: waited %s, took %s", nodeStatusName[n.status], n.name,
+				n.start.Sub(n.ready).Round(time.Millisecond), n.end.Sub(n.start).Round(time.Millisecond),
+			))
+		case nodeFailed, nodeCancelled:
+			slog.Error(fmt.Sprintf(
+				"[%s] %s: took %s", nodeStatusName[n.status], n.name, n.end.Sub(n.start).Round(time.Millisecond),
+			), "error", n.err)
+		default:
+			slog.Warn(fmt.Sprintf("[%s] %s", nodeStatusName[n.status], n.name))
+		}
+	}
+	slog.Info(fmt.Sprintf(
+		"Ran %d nodes on %d workers in %s", len(s.nodes), s.workers, s.end.Sub(s.start).Round(time.Millisecond),
+	))
+}
@@ -0,0 +1,99 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/integration"
+)
+
+func TestScheduler(t *testing.T) {
+	var l sync.Mutex
+	order := []string{}
+	var running, peak atomic.Int32
+	node := func(name string, fail bool) func(context.Context) error {
+		return func(context.Context) error {
+			if r := running.Add(1); r > peak.Load() {
+				peak.Store(r)
+			}
+			defer running.Add(-1)
+			l.Lock()
+			order = append(order, name)
+			l.Unlock()
+			if fail {
+				return fmt.Errorf("%s failed", name)
+			}
+			return nil
+		}
+	}
+
+	s := newScheduler(2)
+	a := s.add("a", node("a", false))
+	b := s.add("b", node("b", false), a)
+	c := s.add("c", node("c", true), a)
+	d := s.add("d", node("d", false), b, c)
+	e := s.add("e", node("e", false), d)
+	f := s.add("f", node("f", false))
+	if err := s.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if peak.Load() > 2 {
+		t.Fatalf("Expecting at most 2 nodes running at the same time, got %d", peak.Load())
+	}
+	for _, n := range [][]int{{a, b}, {a, c}} {
+		if slices.Index(order, s.nodes[n[0]].name) > slices.Index(order, s.nodes[n[1]].name) {
+			t.Fatalf("Expecting %s to run before %s, got %v", s.nodes[n[0]].name, s.nodes[n[1]].name, order)
+		}
+	}
+	expects := map[int]nodeStatus{
+		a: nodeDone, b: nodeDone, c: nodeFailed, d: nodeSkipped, e: nodeSkipped, f: nodeDone,
+	}
+	for n, status := range expects {
+		if s.nodes[n].status != status {
+			t.Fatalf("Expecting node %s to be %s, got %s", s.nodes[n].name, nodeStatusName[status], nodeStatusName[s.nodes[n].status])
+		}
+	}
+
+	s = newScheduler(1)
+	a = s.add("a", node("a", false))
+	b = s.add("b", node("b", false), a)
+	s.depend(a, b)
+	if err := s.run(context.Background()); err == nil {
+		t.Fatal("Expecting dependency cycle to be rejected")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s = newScheduler(1)
+	a = s.add("a", node("a", false))
+	b = s.add("b", node("b", false), a)
+	if err := s.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s.nodes[a].status != nodeCancelled || s.nodes[b].status != nodeSkipped {
+		t.Fatalf("Expecting cancelled and skipped, got %s and %s", nodeStatusName[s.nodes[a].status], nodeStatusName[s.nodes[b].status])
+	}
+}
+
+func TestPipelineDeps(t *testing.T) {
+	spec := Processor{Pipelines: []ProcessPipeline{
+		{Name: "mix", Banks: []string{"/out/base/a.bnk"}, Integration: integration.IntegrationTypeDefault, Output: "/out/mix"},
+		{Name: "base", Banks: []string{"/in/a.bnk", "/in/b.bnk"}, Integration: integration.IntegrationTypeDefault, Output: "/out/base"},
+		{Name: "patch", Banks: []string{"/in/c.bnk"}, DependsOn: []string{"mix"}, Integration: integration.IntegrationTypeHelldivers2, Output: "/out/patch"},
+	}}
+	deps := pipelineDeps(&spec)
+	if !slices.Equal(deps[0], []int{1}) || len(deps[1]) != 0 || !slices.Equal(deps[2], []int{0}) {
+		t.Fatalf("Unexpected pipeline dependencies %v", deps)
+	}
+
+	spec.Pipelines[1].DependsOn = []string{"patch"}
+	errs := ValidationErrors{}
+	spec.validateDeps(&errs)
+	if len(errs) != 3 {
+		t.Fatalf("Expecting every pipeline to be reported as part of a cycle, got %v", errs)
+	}
+}
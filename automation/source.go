@@ -0,0 +1,121 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Resolve every input file of a files to sounds mapping into audio data.
+// Pre-converted WEM inputs are validated and used as-is. Their codec must match
+// the codec of every sound they're mapped to. Wave inputs are converted through
+// the converter attached to the context (see waapi.ConverterFrom) into the
+// format in the header. No converter is involved if all inputs are
+// pre-converted.
+// Return WEM file -> sounds, WEM file -> audio data and WEM file -> codec
+// plugin ID to write into BankSourceData.PluginID of its sounds. All are nil in
+// dry run.
+func prepareAudioData(
+	ctx              context.Context,
+	inputsMapSounds  map[string][]*wwise.Sound,
+	header          *WavSoundMapHeader,
+	dry              bool,
+) (map[string][]*wwise.Sound, map[string][]byte, map[string]uint32, error) {
+	wemsMapSounds := make(map[string][]*wwise.Sound, len(inputsMapSounds))
+	wemsMapAudioData := make(map[string][]byte, len(inputsMapSounds))
+	wemsMapPluginID := make(map[string]uint32, len(inputsMapSounds))
+	wavsMapSounds := make(map[string][]*wwise.Sound, len(inputsMapSounds))
+
+	for input, sounds := range inputsMapSounds {
+		if !strings.EqualFold(filepath.Ext(input), ".wem") {
+			wavsMapSounds[input] = sounds
+			continue
+		}
+		audioData, err := os.ReadFile(input)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to read audio data from %s", input), "error", err)
+			slog.Error(fmt.Sprintf("Discard all rewiring related to file %s", input))
+			continue
+		}
+		codec, err := waapi.WEMPluginID(audioData)
+		if err != nil {
+			slog.Error(fmt.Sprintf("%s is not a valid WEM file", input), "error", err)
+			slog.Error(fmt.Sprintf("Discard all rewiring related to file %s", input))
+			continue
+		}
+		i := slices.IndexFunc(sounds, func(s *wwise.Sound) bool {
+			return s.BankSourceData.PluginID != codec
+		})
+		if i != -1 {
+			pluginID := sounds[i].BankSourceData.PluginID
+			slog.Error(fmt.Sprintf(
+				"Pre-converted WEM file %s is encoded in %s but sound %d is encoded in %s",
+				input, wwise.PluginTypeCodecFmt[codec], sounds[i].Id, wwise.PluginTypeCodecFmt[pluginID],
+			))
+			slog.Error(fmt.Sprintf("Discard all rewiring related to file %s", input))
+			continue
+		}
+		wemsMapSounds[input] = sounds
+		wemsMapAudioData[input] = audioData
+		wemsMapPluginID[input] = codec
+	}
+
+	if len(wavsMapSounds) > 0 {
+		wavs := make([]string, 0, len(wavsMapSounds))
+		for wav := range wavsMapSounds {
+			wavs = append(wavs, wav)
+		}
+		staging, jobs, err := waapi.StageConversion(wavs)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		defer os.RemoveAll(staging)
+
+		if !dry {
+			c := waapi.ConverterFrom(ctx)
+			if err := c.Convert(ctx, jobs, header.Conversion, header.Format); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+
+		for _, job := range jobs {
+			if _, in := wemsMapSounds[job.Wem]; in {
+				panic(fmt.Sprintf("Detect duplicated wem file %s when storing audio data", job.Wem))
+			}
+			sounds := wavsMapSounds[job.Wav]
+			if dry {
+				wemsMapSounds[job.Wem] = sounds
+				continue
+			}
+			audioData, err := os.ReadFile(job.Wem)
+			if err != nil {
+				slog.Error(fmt.Sprintf("Failed to read audio data from %s", job.Wem))
+				slog.Error(fmt.Sprintf("Discard all rewiring related to file %s", job.Wav))
+				continue
+			}
+			wemsMapSounds[job.Wem] = sounds
+			wemsMapAudioData[job.Wem] = audioData
+			wemsMapPluginID[job.Wem] = header.Format.PluginID()
+		}
+	}
+
+	if dry {
+		for wem, sounds := range wemsMapSounds {
+			fmt.Println(wem)
+			for _, s := range sounds {
+				fmt.Println(s.Id)
+			}
+			fmt.Println()
+		}
+		return nil, nil, nil, nil
+	}
+
+	return wemsMapSounds, wemsMapAudioData, wemsMapPluginID, nil
+}
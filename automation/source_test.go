@@ -0,0 +1,53 @@
+package automation
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Write a minimal WEM file with the given format tag
+func writeWEM(t *testing.T, tag uint16) string {
+	b := []byte("RIFF\x00\x00\x00\x00WAVEfmt \x10\x00\x00\x00")
+	b = append(b, make([]byte, 16)...)
+	binary.LittleEndian.PutUint16(b[20:22], tag)
+	binary.LittleEndian.PutUint32(b[4:8], uint32(len(b) - 8))
+	wem := filepath.Join(t.TempDir(), "input.wem")
+	if err := os.WriteFile(wem, b, 0666); err != nil {
+		t.Fatal(err)
+	}
+	return wem
+}
+
+func TestPrepareAudioDataPreConverted(t *testing.T) {
+	vorbis := &wwise.Sound{Id: 10, BankSourceData: wwise.BankSourceData{PluginID: wwise.VORBIS}}
+	pcm := &wwise.Sound{Id: 11, BankSourceData: wwise.BankSourceData{PluginID: wwise.PCM}}
+	// Format setting of the mapping is for wave inputs only
+	header := &WavSoundMapHeader{Format: waapi.ConversionFormatTypePCM}
+
+	wem := writeWEM(t, waapi.WEMFormatTagVorbis)
+	sounds, audioData, pluginIDs, err := prepareAudioData(
+		context.Background(), map[string][]*wwise.Sound{wem: {vorbis}}, header, false,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sounds[wem]) != 1 || len(audioData[wem]) == 0 || pluginIDs[wem] != wwise.VORBIS {
+		t.Fatalf("Expecting Vorbis WEM file is used as-is, got plugin ID 0x%08X", pluginIDs[wem])
+	}
+
+	sounds, _, _, err = prepareAudioData(
+		context.Background(), map[string][]*wwise.Sound{wem: {vorbis, pcm}}, header, false,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, in := sounds[wem]; in {
+		t.Fatal("Expecting Vorbis WEM file mapped to a PCM sound is discarded")
+	}
+}
@@ -0,0 +1,564 @@
+package automation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Dekr0/wwise-teller/integration"
+	"github.com/Dekr0/wwise-teller/utils"
+	"github.com/Dekr0/wwise-teller/waapi"
+)
+
+// Names used to tag script types in version 1 spec. Index is the numeric
+// value used in version 0 spec.
+var ProcessScriptTypeName []string = []string{
+	"rewireWithNewSources",
+	"rewireWithOldSources",
+	"basePropModifiers",
+	"importAsRanSeqCntr",
+	"replaceAudioSources",
+	"ranSeqModifiers",
+	"newSoundToRanSeqCntr",
+	"bulkProcessBaseProp",
+	"streamTypeModifiers",
+	"createActionRef",
+	"loudnessNormalize",
+	"attenuationModifiers",
+	"starlark",
+	"switchCntrFromFolders",
+	"layerCntrModifiers",
+	"musicSegmentFromStems",
+	"fxParamModifiers",
+	"fxInserts",
+	"modulatorBindings",
+	"rtpcInserts",
+	"stateGroupModifiers",
+	"busModifiers",
+	"eventInserts",
+}
+
+// Script types that have a name reserved but no implementation. They are
+// rejected by validation.
+var unsupportedScriptTypes = []ProcessScriptType{TypeRewireWithOldSources}
+
+func (t ProcessScriptType) Supported() bool {
+	return t < ProcessScriptTypeCount && !slices.Contains(unsupportedScriptTypes, t)
+}
+
+// Names of script types that can be run
+func SupportedScriptTypeNames() []string {
+	names := []string{}
+	for i, n := range ProcessScriptTypeName {
+		if ProcessScriptType(i).Supported() {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+func ParseProcessScriptType(name string) (ProcessScriptType, bool) {
+	for i, n := range ProcessScriptTypeName {
+		if n == name {
+			return ProcessScriptType(i), true
+		}
+	}
+	return ProcessScriptTypeCount, false
+}
+
+func (t ProcessScriptType) MarshalText() ([]byte, error) {
+	if t >= ProcessScriptTypeCount {
+		return nil, fmt.Errorf("Unsupported process script type %d", t)
+	}
+	return []byte(ProcessScriptTypeName[t]), nil
+}
+
+func (s *ProcessScript) UnmarshalJSON(b []byte) error {
+	var aux struct {
+		Type   any             `json:"type"`
+		Script string          `json:"script"`
+		Inline json.RawMessage `json:"inline"`
+	}
+	// A custom unmarshaler does not inherit DisallowUnknownFields of the outer
+	// decoder
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&aux); err != nil {
+		return err
+	}
+	s.Script = aux.Script
+	s.Inline = aux.Inline
+	s.rawType = aux.Type
+	s.Type = ProcessScriptTypeCount
+	if name, ok := aux.Type.(string); ok {
+		s.Type, _ = ParseProcessScriptType(name)
+	}
+	return nil
+}
+
+func (s *ProcessScript) HasInline() bool {
+	return len(s.Inline) > 0 && !bytes.Equal(bytes.TrimSpace(s.Inline), []byte("null"))
+}
+
+// Name used in logs and summaries
+func (s *ProcessScript) Label() string {
+	if s.HasInline() {
+		if s.Type < ProcessScriptTypeCount {
+			return "inline " + ProcessScriptTypeName[s.Type]
+		}
+		return "inline"
+	}
+	return s.Script
+}
+
+// File path used to run the script. Resolved by the pipeline at run time.
+func (s *ProcessScript) Path() string {
+	if s.path != "" {
+		return s.path
+	}
+	return s.Script
+}
+
+// Resolve every script of a pipeline into a file path. Inline scripts are
+// written into temporary files since script parsers operate on files.
+// Relative paths inside an inline script are therefore not resolved against
+// the scripts workspace. Return a function that removes temporary files.
+func (p *ProcessPipeline) resolveScripts() (func(), error) {
+	inlines := []string{}
+	cleanup := func() {
+		for _, f := range inlines {
+			os.Remove(f)
+		}
+	}
+	for i := range p.Scripts {
+		s := &p.Scripts[i]
+		if !s.HasInline() {
+			s.path = s.Script
+			if !filepath.IsAbs(s.path) {
+				s.path = filepath.Join(p.ScriptsWorkspace, s.path)
+			}
+			continue
+		}
+
+		body := []byte(s.Inline)
+		var text string
+		if err := json.Unmarshal(s.Inline, &text); err == nil {
+			body = []byte(text)
+		}
+		if err := utils.EnsureTmp(); err != nil {
+			cleanup()
+			return nil, err
+		}
+		f, err := os.CreateTemp(utils.Tmp, "inline-*")
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		inlines = append(inlines, f.Name())
+		_, err = f.Write(body)
+		f.Close()
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("Failed to write inline script %d: %w", i, err)
+		}
+		s.path = f.Name()
+	}
+	return cleanup, nil
+}
+
+// One validation error of a processor spec. Path is a JSON path into the spec
+// (e.g., $.pipelines[0].scripts[2].type).
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, v := range e {
+		lines[i] = v.Error()
+	}
+	return fmt.Sprintf("%d validation error(s) in processor spec:\n%s", len(e), strings.Join(lines, "\n"))
+}
+
+func (e *ValidationErrors) add(path string, format string, a ...any) {
+	*e = append(*e, ValidationError{path, fmt.Sprintf(format, a...)})
+}
+
+// Parse a processor spec. Version 0 spec is migrated on the fly. All
+// validation errors are reported at once (see ValidationErrors).
+func ParseProcessor(fspec string) (*Processor, error) {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open processor %s: %w", fspec, err)
+	}
+	var header struct {
+		Version uint8 `json:"version"`
+	}
+	if err := json.Unmarshal(blob, &header); err != nil {
+		return nil, fmt.Errorf("Failed to decode processor %s: %w", fspec, err)
+	}
+	// Version 0 spec never rejected unknown fields, and migration keeps them.
+	// Script entries reject unknown fields in every version.
+	strict := true
+	switch header.Version {
+	case 0:
+		slog.Warn(fmt.Sprintf("Processor %s uses version 0 spec. Migrating to version %d.", fspec, ProcessSpecVersion))
+		blob, err = MigrateProcessorV0(blob)
+		if err != nil {
+			return nil, err
+		}
+		strict = false
+	case ProcessSpecVersion:
+	default:
+		return nil, fmt.Errorf("Version spec should be %d!", ProcessSpecVersion)
+	}
+
+	var spec Processor
+	d := json.NewDecoder(bytes.NewReader(blob))
+	if strict {
+		d.DisallowUnknownFields()
+	}
+	if err := d.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("Failed to decode processor %s: %w", fspec, err)
+	}
+	if errs := spec.Validate(); len(errs) > 0 {
+		return nil, errs
+	}
+	return &spec, nil
+}
+
+func (spec *Processor) Validate() ValidationErrors {
+	errs := ValidationErrors{}
+	if len(spec.Pipelines) <= 0 {
+		errs.add("$.pipelines", "at least one pipeline is required")
+	}
+	if spec.Workers < 0 {
+		errs.add("$.workers", "number of workers must not be negative")
+	}
+	for i := range spec.Pipelines {
+		spec.Pipelines[i].validate(fmt.Sprintf("$.pipelines[%d]", i), &errs)
+	}
+	spec.validateDeps(&errs)
+	return errs
+}
+
+func (spec *Processor) validateDeps(errs *ValidationErrors) {
+	for i := range spec.Pipelines {
+		p := &spec.Pipelines[i]
+		path := fmt.Sprintf("$.pipelines[%d]", i)
+		if p.Name != "" && slices.ContainsFunc(spec.Pipelines[:i], func(o ProcessPipeline) bool { return o.Name == p.Name }) {
+			errs.add(path + ".name", "duplicated pipeline name %s", p.Name)
+		}
+		for j, name := range p.DependsOn {
+			if name == p.Name {
+				errs.add(fmt.Sprintf("%s.dependsOn[%d]", path, j), "pipeline depends on itself")
+			} else if !slices.ContainsFunc(spec.Pipelines, func(o ProcessPipeline) bool { return o.Name == name }) {
+				errs.add(fmt.Sprintf("%s.dependsOn[%d]", path, j), "no pipeline is named %s", name)
+			}
+		}
+	}
+
+	s := newScheduler(0)
+	for range spec.Pipelines {
+		s.add("", nil)
+	}
+	for i, deps := range pipelineDeps(spec) {
+		s.depend(i, deps...)
+	}
+	for _, i := range s.cycle() {
+		errs.add(
+			fmt.Sprintf("$.pipelines[%d].dependsOn", i),
+			"pipeline is part of or waits on a dependency cycle (explicit or through its sound banks)",
+		)
+	}
+}
+
+func checkDir(errs *ValidationErrors, path string, dir string) {
+	if !filepath.IsAbs(dir) {
+		errs.add(path, "%s is not an absolute path", dir)
+		return
+	}
+	stat, err := os.Lstat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			errs.add(path, "%s does not exist", dir)
+		} else {
+			errs.add(path, "failed to obtain information about %s: %s", dir, err)
+		}
+		return
+	}
+	if !stat.IsDir() {
+		errs.add(path, "%s is not a directory", dir)
+	}
+}
+
+func checkFile(errs *ValidationErrors, path string, file string) {
+	stat, err := os.Lstat(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			errs.add(path, "%s does not exist", file)
+		} else {
+			errs.add(path, "failed to obtain information about %s: %s", file, err)
+		}
+		return
+	}
+	if stat.IsDir() {
+		errs.add(path, "%s is a directory", file)
+	}
+}
+
+func (p *ProcessPipeline) validate(path string, errs *ValidationErrors) {
+	relativeBank := false
+	if len(p.Banks) <= 0 {
+		errs.add(path + ".banks", "at least one sound bank is required")
+	}
+	for i, b := range p.Banks {
+		if !filepath.IsAbs(b) {
+			relativeBank = true
+			if p.BanksWorkspace == "" {
+				errs.add(fmt.Sprintf("%s.banks[%d]", path, i), "relative path %s requires banksWorkspace", b)
+				continue
+			}
+			b = filepath.Join(p.BanksWorkspace, b)
+		}
+		checkFile(errs, fmt.Sprintf("%s.banks[%d]", path, i), b)
+	}
+	if relativeBank && p.BanksWorkspace != "" {
+		checkDir(errs, path + ".banksWorkspace", p.BanksWorkspace)
+	}
+
+	relativeScript := false
+	if len(p.Scripts) <= 0 {
+		errs.add(path + ".scripts", "at least one process script is required")
+	}
+	for i := range p.Scripts {
+		s := &p.Scripts[i]
+		sPath := fmt.Sprintf("%s.scripts[%d]", path, i)
+		switch t := s.rawType.(type) {
+		case nil:
+			errs.add(sPath + ".type", "type is required")
+		case string:
+			if s.Type >= ProcessScriptTypeCount {
+				errs.add(sPath + ".type", "unknown script type %q (expecting one of %s)", t, strings.Join(SupportedScriptTypeNames(), ", "))
+			} else if !s.Type.Supported() {
+				errs.add(sPath + ".type", "script type %q is not supported", t)
+			}
+		case float64:
+			errs.add(sPath + ".type", "numeric script type %v is only allowed in version 0 spec. Use %q instead", t, typeNameOf(t))
+		default:
+			errs.add(sPath + ".type", "script type must be a string")
+		}
+		if s.HasInline() == (s.Script != "") {
+			errs.add(sPath, "exactly one of script and inline is required")
+			continue
+		}
+		if s.HasInline() {
+			continue
+		}
+		script := s.Script
+		if !filepath.IsAbs(script) {
+			relativeScript = true
+			if p.ScriptsWorkspace == "" {
+				errs.add(sPath + ".script", "relative path %s requires scriptsWorkspace", script)
+				continue
+			}
+			script = filepath.Join(p.ScriptsWorkspace, script)
+		}
+		checkFile(errs, sPath + ".script", script)
+	}
+	if relativeScript && p.ScriptsWorkspace != "" {
+		checkDir(errs, path + ".scriptsWorkspace", p.ScriptsWorkspace)
+	}
+
+	switch p.Integration {
+	case integration.IntegrationTypeDefault, integration.IntegrationTypeHelldivers2:
+	default:
+		errs.add(path + ".integration", "unsupported integration type %d", p.Integration)
+	}
+
+	if p.Output == "" {
+		errs.add(path + ".output", "output directory is required")
+	} else if !filepath.IsAbs(p.Output) {
+		errs.add(path + ".output", "%s is not an absolute path", p.Output)
+	} else if stat, err := os.Lstat(p.Output); err == nil && !stat.IsDir() {
+		errs.add(path + ".output", "%s is not a directory", p.Output)
+	}
+
+	if err := p.Converter.Check(); err != nil {
+		errs.add(path + ".converter", "%s", err)
+	}
+	if err := p.OnFailure.Check(); err != nil {
+		errs.add(path + ".onFailure", "%s", err)
+	}
+}
+
+func typeNameOf(t float64) string {
+	if t < 0 || t >= float64(ProcessScriptTypeCount) || t != float64(int(t)) {
+		return "a script type name"
+	}
+	return ProcessScriptTypeName[int(t)]
+}
+
+// Convert a version 0 processor spec into a version 1 processor spec.
+// Numeric script types are replaced by their names.
+func MigrateProcessorV0(blob []byte) ([]byte, error) {
+	var root map[string]any
+	if err := json.Unmarshal(blob, &root); err != nil {
+		return nil, fmt.Errorf("Failed to decode version 0 processor: %w", err)
+	}
+	if v, _ := root["version"].(float64); v != 0 {
+		return nil, fmt.Errorf("Expecting version 0 processor, got version %v", root["version"])
+	}
+	errs := ValidationErrors{}
+	pipelines, _ := root["pipelines"].([]any)
+	for i, p := range pipelines {
+		pipeline, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		scripts, _ := pipeline["scripts"].([]any)
+		for j, s := range scripts {
+			script, ok := s.(map[string]any)
+			if !ok {
+				continue
+			}
+			path := fmt.Sprintf("$.pipelines[%d].scripts[%d].type", i, j)
+			t, ok := script["type"].(float64)
+			if !ok {
+				errs.add(path, "expecting numeric script type in version 0 spec")
+				continue
+			}
+			if t < 0 || t >= float64(ProcessScriptTypeCount) || t != float64(int(t)) {
+				errs.add(path, "unsupported process script type %v", t)
+				continue
+			}
+			script["type"] = ProcessScriptTypeName[int(t)]
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	root["version"] = ProcessSpecVersion
+	return json.MarshalIndent(root, "", "    ")
+}
+
+// JSON schema of version 1 processor spec for editor autocompletion and
+// validation.
+func ProcessorSchema() ([]byte, error) {
+	converterTypes := make([]any, waapi.ConverterTypeCount)
+	converterDesc := []string{}
+	for i := range converterTypes {
+		converterTypes[i] = i
+		converterDesc = append(converterDesc, fmt.Sprintf("%d = %s", i, waapi.ConverterTypeName[i]))
+	}
+	path := func(desc string) map[string]any {
+		return map[string]any{"type": "string", "description": desc}
+	}
+	schema := map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "wwise-teller processor",
+		"type": "object",
+		"required": []string{"version", "pipelines"},
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"version": map[string]any{"const": ProcessSpecVersion},
+			"workers": map[string]any{
+				"type": "integer",
+				"minimum": 0,
+				"description": fmt.Sprintf("Number of nodes running at the same time. Default to %d.", DefaultWorkers),
+			},
+			"pipelines": map[string]any{
+				"type": "array",
+				"minItems": 1,
+				"items": map[string]any{
+					"type": "object",
+					"required": []string{"banks", "scripts", "integration", "output"},
+					"additionalProperties": false,
+					"properties": map[string]any{
+						"name": path("Used by dependsOn of other pipelines and in logs"),
+						"dependsOn": map[string]any{
+							"type": "array",
+							"items": map[string]any{"type": "string"},
+							"description": "Names of pipelines that must finish before this pipeline runs",
+						},
+						"banksWorkspace": path("Absolute path. Relative sound bank paths are resolved against it."),
+						"scriptsWorkspace": path("Absolute path. Relative script paths are resolved against it."),
+						"banks": map[string]any{
+							"type": "array",
+							"minItems": 1,
+							"items": path("Sound bank file path"),
+						},
+						"scripts": map[string]any{
+							"type": "array",
+							"minItems": 1,
+							"items": map[string]any{
+								"type": "object",
+								"required": []string{"type"},
+								"additionalProperties": false,
+								"properties": map[string]any{
+									"type": map[string]any{"enum": SupportedScriptTypeNames()},
+									"script": path("Script file path"),
+									"inline": map[string]any{
+										"description": "Script body. A string is used verbatim (e.g., CSV). Any other value is used as a JSON script.",
+									},
+								},
+								"oneOf": []any{
+									map[string]any{"required": []string{"script"}},
+									map[string]any{"required": []string{"inline"}},
+								},
+							},
+						},
+						"integration": map[string]any{
+							"enum": []int{int(integration.IntegrationTypeDefault), int(integration.IntegrationTypeHelldivers2)},
+							"description": "1 = write sound banks, 2 = Helldivers 2 patch",
+						},
+						"output": path("Absolute path of output directory"),
+						"skipLint": map[string]any{"type": "boolean"},
+						"onFailure": map[string]any{
+							"enum": []string{
+								string(FailurePolicyFailFast),
+								string(FailurePolicySkipScript),
+								string(FailurePolicySkipBank),
+							},
+						},
+						"converter": map[string]any{
+							"type": "object",
+							"additionalProperties": false,
+							"properties": map[string]any{
+								"type": map[string]any{
+									"enum": converterTypes,
+									"description": strings.Join(converterDesc, ", "),
+								},
+								"project": path("Absolute path of Wwise project. Default to WWISETELLER_WPROJ."),
+								"wine": path("Wine binary. Default to wine."),
+								"command": map[string]any{
+									"type": "array",
+									"items": map[string]any{"type": "string"},
+									"description": fmt.Sprintf(
+										"External command template. Placeholders: %s, %s, %s, %s",
+										waapi.ConverterInput, waapi.ConverterOutput,
+										waapi.ConverterConversion, waapi.ConverterFormat,
+									),
+								},
+								"noCache": map[string]any{"type": "boolean"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return json.MarshalIndent(schema, "", "    ")
+}
@@ -0,0 +1,228 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/utils"
+)
+
+func TestMigrateProcessorV0(t *testing.T) {
+	v0 := []byte(`{
+		"version": 0,
+		"pipelines": [{
+			"banks": ["a.bnk"],
+			"scripts": [{"type": 0, "script": "a.csv"}, {"type": 11, "script": "b.json"}],
+			"integration": 1,
+			"output": "/tmp"
+		}]
+	}`)
+	v1, err := MigrateProcessorV0(v0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec Processor
+	if err := json.Unmarshal(v1, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Veriosn != ProcessSpecVersion {
+		t.Fatalf("Expecting version %d, got %d", ProcessSpecVersion, spec.Veriosn)
+	}
+	scripts := spec.Pipelines[0].Scripts
+	if scripts[0].Type != TypeRewireWithNewSources || scripts[1].Type != TypeAttenuationModifiers {
+		t.Fatalf("Unexpected script types %d and %d after migration", scripts[0].Type, scripts[1].Type)
+	}
+
+	_, err = MigrateProcessorV0([]byte(`{"pipelines": [{"scripts": [{"type": 1}, {"type": 99}]}]}`))
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "$.pipelines[0].scripts[1].type" {
+		t.Fatalf("Expecting one error at $.pipelines[0].scripts[1].type, got %v", err)
+	}
+}
+
+func TestProcessScriptTypeName(t *testing.T) {
+	if len(ProcessScriptTypeName) != int(ProcessScriptTypeCount) {
+		t.Fatalf("Expecting %d script type names, got %d", ProcessScriptTypeCount, len(ProcessScriptTypeName))
+	}
+	for i := range ProcessScriptTypeCount {
+		s := ProcessScript{Type: i, Script: "a"}
+		blob, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var d ProcessScript
+		if err := json.Unmarshal(blob, &d); err != nil {
+			t.Fatal(err)
+		}
+		if d.Type != i {
+			t.Fatalf("Expecting script type %d after round trip, got %d", i, d.Type)
+		}
+		if !i.Supported() {
+			if err := RunProcessScript(context.Background(), nil, s); err == nil {
+				t.Fatalf("Expecting unsupported script type %d to fail", i)
+			}
+		}
+	}
+	schema, err := ProcessorSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(schema), ProcessScriptTypeName[TypeRewireWithOldSources]) {
+		t.Fatal("Expecting schema to exclude unsupported script types")
+	}
+}
+
+func TestValidateProcessor(t *testing.T) {
+	dir := t.TempDir()
+	bank := filepath.Join(dir, "a.bnk")
+	if err := os.WriteFile(bank, []byte{}, 0666); err != nil {
+		t.Fatal(err)
+	}
+	spec := filepath.Join(dir, "spec.json")
+	blob := `{
+		"version": 1,
+		"pipelines": [
+			{
+				"banksWorkspace": "` + dir + `",
+				"banks": ["a.bnk", "missing.bnk"],
+				"scripts": [
+					{"type": "basePropModifiers", "inline": {}},
+					{"type": "noSuchType", "script": "/nonexistent.json"},
+					{"type": 2, "script": "x.json", "inline": "x"},
+					{"type": "rewireWithOldSources", "inline": "id\n"}
+				],
+				"integration": 1,
+				"output": "` + dir + `"
+			},
+			{
+				"banks": ["` + bank + `"],
+				"scripts": [{"type": "ranSeqModifiers", "inline": "id,prop\n"}],
+				"integration": 0,
+				"output": "` + bank + `"
+			}
+		]
+	}`
+	if err := os.WriteFile(spec, []byte(blob), 0666); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ParseProcessor(spec)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expecting validation errors, got %v", err)
+	}
+	expects := []string{
+		"$.pipelines[0].banks[1]",
+		"$.pipelines[0].scripts[1].type",
+		"$.pipelines[0].scripts[1].script",
+		"$.pipelines[0].scripts[2].type",
+		"$.pipelines[0].scripts[2]",
+		"$.pipelines[0].scripts[3].type",
+		"$.pipelines[1].integration",
+		"$.pipelines[1].output",
+	}
+	if len(errs) != len(expects) {
+		t.Fatalf("Expecting %d validation errors, got %d:\n%s", len(expects), len(errs), errs.Error())
+	}
+	for i, e := range expects {
+		if errs[i].Path != e {
+			t.Fatalf("Expecting validation error at %s, got %s", e, errs[i].Error())
+		}
+	}
+}
+
+func TestParseProcessorUnknownField(t *testing.T) {
+	dir := t.TempDir()
+	bank := filepath.Join(dir, "a.bnk")
+	if err := os.WriteFile(bank, []byte{}, 0666); err != nil {
+		t.Fatal(err)
+	}
+	spec := filepath.Join(dir, "spec.json")
+	parse := func(blob string) error {
+		if err := os.WriteFile(spec, []byte(blob), 0666); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ParseProcessor(spec)
+		return err
+	}
+	pipeline := func(scriptType string, typo string) string {
+		return `{
+			"banks": ["` + bank + `"],
+			"scripts": [{"type": ` + scriptType + `, "script": "` + bank + `"}],
+			"integration": 1,
+			"output": "` + dir + `"` + typo + `
+		}`
+	}
+
+	// Version 0 spec is migrated as is
+	if err := parse(`{"version": 0, "pipelines": [` + pipeline(`0`, `, "outptu": ""`) + `]}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := parse(`{"version": 1, "pipelines": [` + pipeline(`"basePropModifiers"`, "") + `]}`); err != nil {
+		t.Fatal(err)
+	}
+	err := parse(`{"version": 1, "pipelines": [` + pipeline(`"basePropModifiers"`, `, "outptu": ""`) + `]}`)
+	if err == nil || !strings.Contains(err.Error(), "outptu") {
+		t.Fatalf("Expecting unknown field outptu to be rejected, got %v", err)
+	}
+	err = parse(`{"version": 1, "pipelines": [{
+		"banks": ["` + bank + `"],
+		"scripts": [{"type": "basePropModifiers", "scirpt": "` + bank + `"}],
+		"integration": 1,
+		"output": "` + dir + `"
+	}]}`)
+	if err == nil || !strings.Contains(err.Error(), "scirpt") {
+		t.Fatalf("Expecting unknown field scirpt in a script to be rejected, got %v", err)
+	}
+}
+
+func TestResolveInlineScripts(t *testing.T) {
+	if err := utils.InitTmp(); err != nil {
+		t.Fatal(err)
+	}
+	defer utils.CleanTmp()
+	var p ProcessPipeline
+	err := json.Unmarshal([]byte(`{
+		"scriptsWorkspace": "/scripts",
+		"scripts": [
+			{"type": "ranSeqModifiers", "inline": "id,prop\n"},
+			{"type": "basePropModifiers", "inline": {"a": 1}},
+			{"type": "basePropModifiers", "script": "b.json"}
+		]
+	}`), &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup, err := p.resolveScripts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := func(i int) string {
+		b, err := os.ReadFile(p.Scripts[i].Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	if s := read(0); s != "id,prop\n" {
+		t.Fatalf("Expecting string inline script to be written verbatim, got %q", s)
+	}
+	if s := read(1); strings.ReplaceAll(s, " ", "") != `{"a":1}` {
+		t.Fatalf("Expecting JSON inline script to be written as is, got %q", s)
+	}
+	if p.Scripts[2].Path() != filepath.Join("/scripts", "b.json") {
+		t.Fatalf("Expecting relative script to be resolved against workspace, got %s", p.Scripts[2].Path())
+	}
+	if p.Scripts[0].Label() != "inline ranSeqModifiers" {
+		t.Fatalf("Unexpected label %s", p.Scripts[0].Label())
+	}
+	inline := p.Scripts[0].Path()
+	cleanup()
+	if _, err := os.Stat(inline); !os.IsNotExist(err) {
+		t.Fatal("Expecting inline script to be removed after cleanup")
+	}
+}
@@ -0,0 +1,880 @@
+package automation
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/db/id"
+	"github.com/Dekr0/wwise-teller/wio"
+	"github.com/Dekr0/wwise-teller/wwise"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// Upper bound of Starlark computation steps per script so that a runaway loop
+// cannot hang a pipeline or the GUI
+const StarlarkMaxSteps = 1 << 28
+
+var starlarkFileOptions = syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+}
+
+// Properties that hold an ID or a bit mask instead of a float
+var starlarkU32Props []wwise.PropType = []wwise.PropType{
+	wwise.TAttenuationID,
+	wwise.TAttachedPluginFXID,
+	wwise.TMidiTargetNode,
+	wwise.TMidiChannelMask,
+}
+
+// Run a Starlark script on a sound bank. The script sees the sound bank
+// through the predeclared global `bank`. Scripts are sandboxed: they cannot
+// load modules or touch the file system. See RunStarlarkSource.
+func RunStarlark(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	src, err := os.ReadFile(fspec)
+	if err != nil {
+		return err
+	}
+	return RunStarlarkSource(ctx, bnk, fspec, src, nil)
+}
+
+// Run Starlark source on a sound bank. print receives output of print() and
+// defaults to the logger. IDs allocated by the script are committed only if
+// the script succeeds. Changes made to the sound bank before a failure are
+// kept (see FailurePolicySkipScript for rolling them back).
+//
+// API exposed to scripts:
+//
+//	bank.version                         bank generation version
+//	bank.objects(type=None)              hierarchy objects, optionally filtered by type name (e.g., "Sound")
+//	bank.get(id)                         hierarchy object or None
+//	bank.descendants(id)                 every object under a hierarchy object
+//	bank.new_id()                        allocate a hierarchy ID
+//	bank.new_sound(ref, parent)          clone a sound (sharing its audio source) into a random / sequence container
+//	bank.new_ran_seq_cntr(ref, parent)   clone an empty random / sequence container under an actor mixer or a switch container
+//	bank.new_switch_cntr(ref, parent)    clone an empty switch container under an actor mixer
+//	bank.new_music_segment(ref, parent)  clone an empty music segment under a music random / sequence or switch container
+//	bank.new_event(name)                 create an empty event whose ID is the short ID of its name
+//	bank.new_action(ref, event, target)  clone an action into an event
+//
+//	obj.id, obj.type, obj.parent, obj.children
+//	obj.prop(name), obj.props(), obj.set_prop(name, value), obj.remove_prop(name)
+//	obj.range_prop(name), obj.set_range_prop(name, min, max), obj.remove_range_prop(name)
+//	obj.rtpcs(), obj.add_rtpc(rtpc, param, points, curve="Linear", scaling="None", accum="Additive", type="Game Parameter")
+//
+// Property names are labels shown by the object editor (e.g., "Volume",
+// "Make Up Gain").
+func RunStarlarkSource(
+	ctx context.Context, bnk *wwise.Bank, name string, src []byte, print func(string),
+) (err error) {
+	bkhd := bnk.BKHD()
+	if bkhd == nil {
+		return wwise.NoBKHD
+	}
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+	if print == nil {
+		print = func(msg string) { slog.Info(fmt.Sprintf("[%s] %s", name, msg)) }
+	}
+	b := &starlarkBank{ctx: ctx, bnk: bnk, h: h, v: int(bkhd.BankGenerationVersion)}
+	b.index = make(map[uint32]wwise.HircObj, len(h.HircObjs))
+	for _, o := range h.HircObjs {
+		if id, err := o.HircID(); err == nil {
+			b.index[id] = o
+		}
+	}
+	defer func() {
+		if b.q == nil {
+			return
+		}
+		defer b.closeConn()
+		if err != nil {
+			b.rollback()
+			return
+		}
+		if cerr := b.commit(); cerr != nil {
+			b.rollback()
+			err = cerr
+		}
+	}()
+
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) { print(msg) },
+	}
+	thread.SetMaxExecutionSteps(StarlarkMaxSteps)
+	stop := context.AfterFunc(ctx, func() { thread.Cancel(ctx.Err().Error()) })
+	defer stop()
+
+	_, err = starlark.ExecFileOptions(&starlarkFileOptions, thread, name, src, starlark.StringDict{"bank": b})
+	if evalErr, ok := err.(*starlark.EvalError); ok {
+		return errors.New(evalErr.Backtrace())
+	}
+	return err
+}
+
+type starlarkBank struct {
+	ctx        context.Context
+	bnk       *wwise.Bank
+	h         *wwise.HIRC
+	v          int
+	index      map[uint32]wwise.HircObj
+	// Opened on first ID allocation
+	q         *id.Queries
+	closeConn  func()
+	commit     func() error
+	rollback   func()
+}
+
+func (b *starlarkBank) String() string        { return "<bank>" }
+func (b *starlarkBank) Type() string          { return "bank" }
+func (b *starlarkBank) Freeze()               {}
+func (b *starlarkBank) Truth() starlark.Bool  { return starlark.True }
+func (b *starlarkBank) Hash() (uint32, error) { return 0, fmt.Errorf("unhashable type: bank") }
+
+var starlarkBankAttrs []string = []string{
+	"descendants", "get", "new_action", "new_event", "new_id", "new_music_segment",
+	"new_ran_seq_cntr", "new_sound", "new_switch_cntr", "objects", "version",
+}
+
+func (b *starlarkBank) AttrNames() []string { return starlarkBankAttrs }
+
+func (b *starlarkBank) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "version":
+		return starlark.MakeInt(b.v), nil
+	case "objects":
+		return starlark.NewBuiltin(name, b.objects), nil
+	case "get":
+		return starlark.NewBuiltin(name, b.get), nil
+	case "descendants":
+		return starlark.NewBuiltin(name, b.descendants), nil
+	case "new_id":
+		return starlark.NewBuiltin(name, b.newID), nil
+	case "new_sound":
+		return starlark.NewBuiltin(name, b.newSound), nil
+	case "new_ran_seq_cntr":
+		return starlark.NewBuiltin(name, b.newRanSeqCntr), nil
+	case "new_switch_cntr":
+		return starlark.NewBuiltin(name, b.newSwitchCntr), nil
+	case "new_music_segment":
+		return starlark.NewBuiltin(name, b.newMusicSegment), nil
+	case "new_event":
+		return starlark.NewBuiltin(name, b.newEvent), nil
+	case "new_action":
+		return starlark.NewBuiltin(name, b.newAction), nil
+	}
+	return nil, nil
+}
+
+func (b *starlarkBank) wrap(o wwise.HircObj) *starlarkObj {
+	return &starlarkObj{b, o}
+}
+
+func (b *starlarkBank) lookup(v starlark.Value) (wwise.HircObj, error) {
+	if o, ok := v.(*starlarkObj); ok {
+		return o.o, nil
+	}
+	id, err := starlarkID(v)
+	if err != nil {
+		return nil, err
+	}
+	o, in := b.index[id]
+	if !in {
+		return nil, fmt.Errorf("no hierarchy object has ID %d", id)
+	}
+	return o, nil
+}
+
+func (b *starlarkBank) objects(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var t starlark.Value = starlark.None
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "type?", &t); err != nil {
+		return nil, err
+	}
+	filter := ""
+	if t != starlark.None {
+		s, ok := starlark.AsString(t)
+		if !ok || !slices.Contains(wwise.HircTypeName, s) {
+			return nil, fmt.Errorf("%s: unknown hierarchy type %s", fn.Name(), t)
+		}
+		filter = s
+	}
+	objs := []starlark.Value{}
+	for _, o := range b.h.HircObjs {
+		if filter == "" || wwise.HircTypeName[o.HircType()] == filter {
+			objs = append(objs, b.wrap(o))
+		}
+	}
+	return starlark.NewList(objs), nil
+}
+
+func (b *starlarkBank) get(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var v starlark.Value
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &v); err != nil {
+		return nil, err
+	}
+	id, err := starlarkID(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	if o, in := b.index[id]; in {
+		return b.wrap(o), nil
+	}
+	return starlark.None, nil
+}
+
+func (b *starlarkBank) descendants(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var v starlark.Value
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &v); err != nil {
+		return nil, err
+	}
+	root, err := b.lookup(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	objs := []starlark.Value{}
+	stack := slices.Clone(root.Leafs())
+	slices.Reverse(stack)
+	for len(stack) > 0 {
+		id := stack[len(stack) - 1]
+		stack = stack[:len(stack) - 1]
+		o, in := b.index[id]
+		if !in {
+			continue
+		}
+		objs = append(objs, b.wrap(o))
+		leafs := slices.Clone(o.Leafs())
+		slices.Reverse(leafs)
+		stack = append(stack, leafs...)
+	}
+	return starlark.NewList(objs), nil
+}
+
+func (b *starlarkBank) allocate() (uint32, error) {
+	if b.q == nil {
+		q, closeConn, commit, rollback, err := db.CreateConnWithTxQuery(b.ctx)
+		if err != nil {
+			return 0, err
+		}
+		b.q, b.closeConn, b.commit, b.rollback = q, closeConn, commit, rollback
+	}
+	return db.TryHid(b.ctx, b.q)
+}
+
+func (b *starlarkBank) newID(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	id, err := b.allocate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.MakeUint64(uint64(id)), nil
+}
+
+// Resolve the reference object and the parent object of a constructor, and
+// allocate an ID for the new object
+func (b *starlarkBank) unpackClone(
+	fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (ref wwise.HircObj, parent wwise.HircObj, id uint32, err error) {
+	var refV, parentV starlark.Value
+	if err = starlark.UnpackArgs(fn.Name(), args, kwargs, "ref", &refV, "parent", &parentV); err != nil {
+		return nil, nil, 0, err
+	}
+	if ref, err = b.lookup(refV); err != nil {
+		return nil, nil, 0, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	if parent, err = b.lookup(parentV); err != nil {
+		return nil, nil, 0, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	if id, err = b.allocate(); err != nil {
+		return nil, nil, 0, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return ref, parent, id, nil
+}
+
+func (b *starlarkBank) newSound(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	ref, parent, id, err := b.unpackClone(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	refSound, ok := ref.(*wwise.Sound)
+	if !ok {
+		return nil, fmt.Errorf("%s: reference object is %s, not Sound", fn.Name(), wwise.HircTypeName[ref.HircType()])
+	}
+	if refSound.BankSourceData.PluginParam != nil {
+		return nil, fmt.Errorf("%s: reference sound %d has plugin parameters", fn.Name(), refSound.Id)
+	}
+	if _, ok := parent.(*wwise.RanSeqCntr); !ok {
+		return nil, fmt.Errorf("%s: parent object is %s, not Random / Sequence Container", fn.Name(), wwise.HircTypeName[parent.HircType()])
+	}
+	parentID, _ := parent.HircID()
+	sound := &wwise.Sound{
+		Id: id,
+		BankSourceData: refSound.BankSourceData,
+		BaseParam: refSound.BaseParam.Clone(false),
+	}
+	if err := b.h.AppendNewSoundToRanSeqContainer(sound, parentID, false); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	b.index[id] = sound
+	return b.wrap(sound), nil
+}
+
+func (b *starlarkBank) newRanSeqCntr(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	ref, parent, id, err := b.unpackClone(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	refCntr, ok := ref.(*wwise.RanSeqCntr)
+	if !ok {
+		return nil, fmt.Errorf("%s: reference object is %s, not Random / Sequence Container", fn.Name(), wwise.HircTypeName[ref.HircType()])
+	}
+	parentID, _ := parent.HircID()
+	cntr := refCntr.Clone(id, false)
+	switch parent.(type) {
+	case *wwise.ActorMixer:
+		err = b.h.AppendNewRanSeqCntrToActorMixer(&cntr, parentID, false)
+	case *wwise.SwitchCntr:
+		err = b.h.AppendNewRanSeqCntrToSwitchCntr(&cntr, parentID, false)
+	default:
+		return nil, fmt.Errorf("%s: parent object is %s, not Actor Mixer or Switch Container", fn.Name(), wwise.HircTypeName[parent.HircType()])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	b.index[id] = &cntr
+	return b.wrap(&cntr), nil
+}
+
+func (b *starlarkBank) newSwitchCntr(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	ref, parent, id, err := b.unpackClone(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	refSwitch, ok := ref.(*wwise.SwitchCntr)
+	if !ok {
+		return nil, fmt.Errorf("%s: reference object is %s, not Switch Container", fn.Name(), wwise.HircTypeName[ref.HircType()])
+	}
+	if _, ok := parent.(*wwise.ActorMixer); !ok {
+		return nil, fmt.Errorf("%s: parent object is %s, not Actor Mixer", fn.Name(), wwise.HircTypeName[parent.HircType()])
+	}
+	parentID, _ := parent.HircID()
+	cntr := &wwise.SwitchCntr{
+		Id: id,
+		BaseParam: refSwitch.BaseParam.Clone(false),
+		GroupType: refSwitch.GroupType,
+		GroupID: refSwitch.GroupID,
+		DefaultSwitch: refSwitch.DefaultSwitch,
+		IsContinuousValidation: refSwitch.IsContinuousValidation,
+		Container: wwise.Container{Children: []uint32{}},
+		SwitchGroups: []wwise.SwitchGroupItem{},
+		SwitchParams: []wwise.SwitchParam{},
+	}
+	if err := b.h.AppendNewSwitchCntrToActorMixer(cntr, parentID, false); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	b.index[id] = cntr
+	return b.wrap(cntr), nil
+}
+
+func (b *starlarkBank) newMusicSegment(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	ref, parent, id, err := b.unpackClone(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	refSegment, ok := ref.(*wwise.MusicSegment)
+	if !ok {
+		return nil, fmt.Errorf("%s: reference object is %s, not Music Segment", fn.Name(), wwise.HircTypeName[ref.HircType()])
+	}
+	parentID, _ := parent.HircID()
+	segment := &wwise.MusicSegment{
+		Id: id,
+		OverrideFlags: refSegment.OverrideFlags,
+		BaseParam: *refSegment.BaseParam.Clone(false),
+		Children: wwise.Container{Children: []uint32{}},
+		MeterInfo: refSegment.MeterInfo,
+		Stingers: []wwise.Stinger{},
+		Duration: refSegment.Duration,
+		Markers: make([]wwise.MusicSegmentMarker, len(refSegment.Markers)),
+	}
+	for i, m := range refSegment.Markers {
+		segment.Markers[i] = wwise.MusicSegmentMarker{ID: m.ID, Position: m.Position, MarkerName: slices.Clone(m.MarkerName)}
+	}
+	if err := b.h.AppendNewMusicSegmentToMusicCntr(segment, parentID, false); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	b.index[id] = segment
+	return b.wrap(segment), nil
+}
+
+func (b *starlarkBank) newEvent(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "name", &name); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%s: event name is empty", fn.Name())
+	}
+	e := wwise.NewEvent(wwise.ShortID(name))
+	if err := b.h.AppendNewEvent(e); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	b.index[e.Id] = e
+	return b.wrap(e), nil
+}
+
+func (b *starlarkBank) newAction(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var refV, eventV, targetV starlark.Value
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "ref", &refV, "event", &eventV, "target", &targetV); err != nil {
+		return nil, err
+	}
+	ref, err := b.lookup(refV)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	refAction, ok := ref.(*wwise.Action)
+	if !ok {
+		return nil, fmt.Errorf("%s: reference object is %s, not Action", fn.Name(), wwise.HircTypeName[ref.HircType()])
+	}
+	event, err := b.lookup(eventV)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	if _, ok := event.(*wwise.Event); !ok {
+		return nil, fmt.Errorf("%s: event object is %s, not Event", fn.Name(), wwise.HircTypeName[event.HircType()])
+	}
+	target, err := b.lookup(targetV)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	id, err := b.allocate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	eventID, _ := event.HircID()
+	targetID, _ := target.HircID()
+	action := refAction.Clone(id, targetID)
+	if err := b.h.AppendNewActionToEvent(&action, eventID); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	b.index[id] = &action
+	return b.wrap(&action), nil
+}
+
+// A hierarchy object seen by a Starlark script
+type starlarkObj struct {
+	b *starlarkBank
+	o  wwise.HircObj
+}
+
+func (o *starlarkObj) id() uint32 {
+	id, _ := o.o.HircID()
+	return id
+}
+
+func (o *starlarkObj) String() string {
+	return fmt.Sprintf("<%s %d>", wwise.HircTypeName[o.o.HircType()], o.id())
+}
+func (o *starlarkObj) Type() string          { return "object" }
+func (o *starlarkObj) Freeze()               {}
+func (o *starlarkObj) Truth() starlark.Bool  { return starlark.True }
+func (o *starlarkObj) Hash() (uint32, error) { return o.id(), nil }
+
+func (o *starlarkObj) CompareSameType(op syntax.Token, y starlark.Value, depth int) (bool, error) {
+	switch op {
+	case syntax.EQL:
+		return o.id() == y.(*starlarkObj).id(), nil
+	case syntax.NEQ:
+		return o.id() != y.(*starlarkObj).id(), nil
+	}
+	return false, fmt.Errorf("%s not implemented for object", op)
+}
+
+var starlarkObjAttrs []string = []string{
+	"add_rtpc", "children", "id", "parent", "prop", "props", "range_prop",
+	"remove_prop", "remove_range_prop", "rtpcs", "set_prop", "set_range_prop", "type",
+}
+
+func (o *starlarkObj) AttrNames() []string { return starlarkObjAttrs }
+
+func (o *starlarkObj) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "id":
+		return starlark.MakeUint64(uint64(o.id())), nil
+	case "type":
+		return starlark.String(wwise.HircTypeName[o.o.HircType()]), nil
+	case "parent":
+		return starlark.MakeUint64(uint64(o.o.ParentID())), nil
+	case "children":
+		leafs := o.o.Leafs()
+		ids := make([]starlark.Value, len(leafs))
+		for i, l := range leafs {
+			ids[i] = starlark.MakeUint64(uint64(l))
+		}
+		return starlark.NewList(ids), nil
+	}
+	fns := map[string]func(*starlark.Thread, *starlark.Builtin, starlark.Tuple, []starlark.Tuple) (starlark.Value, error){
+		"prop": o.prop,
+		"props": o.props,
+		"set_prop": o.setProp,
+		"remove_prop": o.removeProp,
+		"range_prop": o.rangeProp,
+		"set_range_prop": o.setRangeProp,
+		"remove_range_prop": o.removeRangeProp,
+		"rtpcs": o.rtpcs,
+		"add_rtpc": o.addRTPC,
+	}
+	if fn, in := fns[name]; in {
+		if o.o.BaseParameter() == nil {
+			return nil, fmt.Errorf("%s has no base parameter", o)
+		}
+		return starlark.NewBuiltin(name, fn), nil
+	}
+	return nil, nil
+}
+
+// Translate a property label into a property type supported by the bank
+// version
+func (o *starlarkObj) propType(name string) (wwise.PropType, error) {
+	for t, label := range wwise.TranslateName {
+		if strings.EqualFold(label, name) {
+			if _, ok := wwise.LookupForwardTranslateProp(t, o.b.v); !ok {
+				return 0, fmt.Errorf("property %s is not supported in version %d", name, o.b.v)
+			}
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown property %s", name)
+}
+
+func starlarkPropValue(p wwise.PropType, b []byte) starlark.Value {
+	if slices.Contains(starlarkU32Props, p) {
+		var u uint32
+		binary.Decode(b, wio.ByteOrder, &u)
+		return starlark.MakeUint64(uint64(u))
+	}
+	var f float32
+	binary.Decode(b, wio.ByteOrder, &f)
+	return starlark.Float(f)
+}
+
+func encodeStarlarkPropValue(p wwise.PropType, v starlark.Value) ([4]byte, error) {
+	var b [4]byte
+	if slices.Contains(starlarkU32Props, p) {
+		u, err := starlarkID(v)
+		if err != nil {
+			return b, err
+		}
+		binary.Encode(b[:], wio.ByteOrder, u)
+		return b, nil
+	}
+	f, ok := starlark.AsFloat(v)
+	if !ok {
+		return b, fmt.Errorf("property %s expects a number, got %s", wwise.PropLabel(p), v.Type())
+	}
+	binary.Encode(b[:], wio.ByteOrder, float32(f))
+	if c, in := wwise.BasePropChecker[p]; in {
+		if err := c(float32(f)); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+func (o *starlarkObj) prop(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &name); err != nil {
+		return nil, err
+	}
+	p, err := o.propType(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	_, pv := o.o.BaseParameter().PropBundle.Prop(p, o.b.v)
+	if pv == nil {
+		return starlark.None, nil
+	}
+	return starlarkPropValue(p, pv.V), nil
+}
+
+func (o *starlarkObj) props(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	d := starlark.NewDict(len(o.o.BaseParameter().PropBundle.PropValues))
+	for _, pv := range o.o.BaseParameter().PropBundle.PropValues {
+		p, ok := wwise.LookupInverseTranslateProp(pv.P, o.b.v)
+		if !ok {
+			return nil, fmt.Errorf("%s: property ID %d is not supported in version %d", fn.Name(), pv.P, o.b.v)
+		}
+		d.SetKey(starlark.String(wwise.PropLabel(p)), starlarkPropValue(p, pv.V))
+	}
+	return d, nil
+}
+
+func (o *starlarkObj) setProp(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var name string
+	var v starlark.Value
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &name, &v); err != nil {
+		return nil, err
+	}
+	p, err := o.propType(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	val, err := encodeStarlarkPropValue(p, v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	bundle := &o.o.BaseParameter().PropBundle
+	if _, pv := bundle.Prop(p, o.b.v); pv != nil {
+		copy(pv.V, val[:])
+	} else {
+		bundle.AddWithVal(p, val, o.b.v)
+	}
+	return starlark.None, nil
+}
+
+func (o *starlarkObj) removeProp(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &name); err != nil {
+		return nil, err
+	}
+	p, err := o.propType(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	o.o.BaseParameter().PropBundle.Remove(p, o.b.v)
+	return starlark.None, nil
+}
+
+func (o *starlarkObj) rangeProp(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &name); err != nil {
+		return nil, err
+	}
+	p, err := o.propType(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	bundle := &o.o.BaseParameter().RangePropBundle
+	i, in := bundle.HasPid(p, o.b.v)
+	if !in {
+		return starlark.None, nil
+	}
+	rv := bundle.RangeValues[i]
+	return starlark.Tuple{starlarkPropValue(p, rv.Min), starlarkPropValue(p, rv.Max)}, nil
+}
+
+func (o *starlarkObj) setRangeProp(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var name string
+	var minV, maxV float64
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 3, &name, &minV, &maxV); err != nil {
+		return nil, err
+	}
+	p, err := o.propType(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	if _, in := wwise.BaseRangePropChecker[p]; in {
+		if err := wwise.CheckBaseRangeProp(p, float32(minV), float32(maxV)); err != nil {
+			return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+		}
+	}
+	bundle := &o.o.BaseParameter().RangePropBundle
+	i, in := bundle.HasPid(p, o.b.v)
+	if !in {
+		bundle.Add(p, o.b.v)
+		i, _ = bundle.HasPid(p, o.b.v)
+	}
+	bundle.SetPropMinByIdxF32(i, float32(minV))
+	bundle.SetPropMaxByIdxF32(i, float32(maxV))
+	return starlark.None, nil
+}
+
+func (o *starlarkObj) removeRangeProp(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &name); err != nil {
+		return nil, err
+	}
+	p, err := o.propType(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	o.o.BaseParameter().RangePropBundle.Remove(p, o.b.v)
+	return starlark.None, nil
+}
+
+func (o *starlarkObj) rtpcs(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	items := o.o.BaseParameter().RTPC.RTPCItems
+	l := make([]starlark.Value, len(items))
+	for i, r := range items {
+		d := starlark.NewDict(4)
+		d.SetKey(starlark.String("rtpc"), starlark.MakeUint64(uint64(r.RTPCID)))
+		if int(r.RTPCType) < len(wwise.RTPCTypeName) {
+			d.SetKey(starlark.String("type"), starlark.String(wwise.RTPCTypeName[r.RTPCType]))
+		}
+		if r.ParamID.Value < uint64(len(wwise.RTPCParameterIDName)) {
+			d.SetKey(starlark.String("param"), starlark.String(wwise.RTPCParameterIDName[r.ParamID.Value]))
+		}
+		points := make([]starlark.Value, len(r.RTPCGraphPointsX))
+		for j := range r.RTPCGraphPointsX {
+			points[j] = starlark.Tuple{
+				starlark.Float(r.RTPCGraphPointsX[j]), starlark.Float(r.RTPCGraphPointsY[j]),
+			}
+		}
+		d.SetKey(starlark.String("points"), starlark.NewList(points))
+		l[i] = d
+	}
+	return starlark.NewList(l), nil
+}
+
+// Add an RTPC curve to the object. The curve ID is allocated from the same
+// database as hierarchy IDs. The parameter must be available on the object
+// (see wwise.CheckRTPCParameter).
+func (o *starlarkObj) addRTPC(
+	_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	var rtpcV starlark.Value
+	var param string
+	var points *starlark.List
+	curve, scaling, accum, rtpcType := "Linear", "None", "Additive", "Game Parameter"
+	if err := starlark.UnpackArgs(
+		fn.Name(), args, kwargs,
+		"rtpc", &rtpcV, "param", &param, "points", &points,
+		"curve?", &curve, "scaling?", &scaling, "accum?", &accum, "type?", &rtpcType,
+	); err != nil {
+		return nil, err
+	}
+	rtpcID, err := starlarkID(rtpcV)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	paramID, err := indexName(wwise.RTPCParameterIDName, param, "RTPC parameter")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	interp, err := indexName(wwise.InterpCurveTypeName, curve, "curve interpolation")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	s, err := indexName(wwise.CurveScalingTypeName, scaling, "curve scaling")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	a, err := indexName(wwise.RTPCAccumTypeName, accum, "RTPC accumulation")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	t, err := indexName(wwise.RTPCTypeName, rtpcType, "RTPC type")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	if min(paramID, interp, s, a, t) == -1 {
+		return nil, fmt.Errorf("%s: param, curve, scaling, accum and type must not be empty", fn.Name())
+	}
+	item, err := wwise.NewRTPCItem(rtpcID, uint8(t), 0, wwise.RTPCParameterType(paramID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	item.Scaling = wwise.CurveScalingType(s)
+	item.RTPCAccum = wwise.RTPCAccumType(a)
+
+	xs := make([]float32, points.Len())
+	ys := make([]float32, points.Len())
+	interps := make([]uint32, points.Len())
+	for i := range points.Len() {
+		p, ok := points.Index(i).(starlark.Tuple)
+		if !ok || len(p) != 2 {
+			return nil, fmt.Errorf("%s: point %d is not a (x, y) tuple", fn.Name(), i)
+		}
+		x, okX := starlark.AsFloat(p[0])
+		y, okY := starlark.AsFloat(p[1])
+		if !okX || !okY {
+			return nil, fmt.Errorf("%s: point %d is not a pair of numbers", fn.Name(), i)
+		}
+		xs[i], ys[i], interps[i] = float32(x), float32(y), uint32(interp)
+	}
+	if err := item.SetCurve(xs, ys, interps); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	item.RTPCCurveID, err = o.b.allocate()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	if err := o.b.h.AddRTPC(o.id(), item, o.b.v); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+	}
+	return starlark.None, nil
+}
+
+func starlarkID(v starlark.Value) (uint32, error) {
+	if o, ok := v.(*starlarkObj); ok {
+		return o.id(), nil
+	}
+	i, ok := v.(starlark.Int)
+	if !ok {
+		return 0, fmt.Errorf("expecting an ID, got %s", v.Type())
+	}
+	u, ok := i.Uint64()
+	if !ok || u > 0xFFFFFFFF {
+		return 0, fmt.Errorf("%s is not a valid ID", i)
+	}
+	return uint32(u), nil
+}
+
+var _ starlark.HasAttrs = (*starlarkBank)(nil)
+var _ starlark.HasAttrs = (*starlarkObj)(nil)
+var _ starlark.Comparable = (*starlarkObj)(nil)
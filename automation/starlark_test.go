@@ -0,0 +1,185 @@
+package automation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestRunStarlark(t *testing.T) {
+	withMemoryDB(t)
+
+	const v = 141
+	bnk, h := newTestBank(v)
+	mixer := &wwise.ActorMixer{Id: 1, BaseParam: &wwise.BaseParameter{}}
+	mixer.Container.Children = []uint32{30}
+	cntr := &wwise.RanSeqCntr{Id: 30, BaseParam: wwise.BaseParameter{DirectParentId: 1}}
+	cntr.Container.Children = []uint32{10, 11}
+	sounds := []*wwise.Sound{}
+	for i, volume := range []float32{-12, 0} {
+		s := &wwise.Sound{Id: uint32(10 + i), BaseParam: &wwise.BaseParameter{DirectParentId: 30}}
+		s.BaseParam.PropBundle.AddBaseProp(v)
+		s.BaseParam.PropBundle.SetPropByIdxF32(0, volume)
+		sounds = append(sounds, s)
+		h.HircObjs = append(h.HircObjs, s)
+	}
+	h.HircObjs = append(h.HircObjs, cntr, mixer)
+	storeActorMixerHircs(h)
+
+	src := `
+raised = []
+for o in bank.descendants(1):
+    if o.type != "Sound":
+        continue
+    volume = o.prop("Volume")
+    if volume != None and volume < -6:
+        o.set_prop("Volume", volume + 6)
+        o.add_rtpc(rtpc=5000, param="lpf", points=[(0, 0), (100, 50)])
+        raised.append(o.id)
+print(raised)
+new = bank.new_ran_seq_cntr(ref=30, parent=1)
+print(new.type, new.parent)
+`
+	output := []string{}
+	err := RunStarlarkSource(context.Background(), bnk, "test.star", []byte(src), func(s string) {
+		output = append(output, s)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output) != 2 || output[0] != "[10]" || output[1] != "Random / Sequence Container 1" {
+		t.Fatalf("Unexpected script output %q", output)
+	}
+	if _, pv := sounds[0].BaseParam.PropBundle.Prop(wwise.TVolume, v); wwise.PropValueString(pv.V) != "-6" {
+		t.Fatalf("Expecting volume -6, got %s", wwise.PropValueString(pv.V))
+	}
+	if len(sounds[0].BaseParam.RTPC.RTPCItems) != 1 || len(sounds[1].BaseParam.RTPC.RTPCItems) != 0 {
+		t.Fatal("Expecting one RTPC curve on the quiet sound only")
+	}
+	if item := sounds[0].BaseParam.RTPC.RTPCItems[0]; item.RTPCID != 5000 || item.ParamID.Value != uint64(wwise.RTPCParameterTypeLPF) {
+		t.Fatalf("Unexpected RTPC curve %v", item)
+	}
+	if len(mixer.Container.Children) != 2 {
+		t.Fatalf("Expecting new container under actor mixer, got children %v", mixer.Container.Children)
+	}
+
+	err = RunStarlarkSource(context.Background(), bnk, "bad.star", []byte(`bank.get(10).set_prop("Volume", 100)`), nil)
+	if err == nil || !strings.Contains(err.Error(), "bad.star") {
+		t.Fatalf("Expecting out of bound volume to fail with a backtrace, got %v", err)
+	}
+	for _, src := range []string{
+		`bank.get(11).add_rtpc(rtpc=0, param="LPF", points=[(0, 0), (100, 50)])`,
+		`bank.get(11).add_rtpc(rtpc=5000, param="Bus Volume", points=[(0, 0), (100, 50)])`,
+		`bank.get(11).add_rtpc(rtpc=5000, param="LPF", points=[(100, 0), (0, 50)])`,
+		`bank.get(11).add_rtpc(rtpc=5000, param="", points=[(0, 0), (100, 50)])`,
+		`bank.get(11).add_rtpc(rtpc=5000, param="LPF", points=[(0, 0), (100, 50)], curve="Bezier")`,
+	} {
+		if err := RunStarlarkSource(context.Background(), bnk, "rtpc.star", []byte(src), nil); err == nil {
+			t.Fatalf("Expecting invalid RTPC to fail: %s", src)
+		}
+	}
+	if len(sounds[1].BaseParam.RTPC.RTPCItems) != 0 {
+		t.Fatal("Expecting invalid RTPC is not added")
+	}
+	sounds[1].BaseParam.PropBundle.PropValues = append(sounds[1].BaseParam.PropBundle.PropValues, wwise.PropValue{P: 0xFF, V: make([]byte, 4)})
+	err = RunStarlarkSource(context.Background(), bnk, "unknown.star", []byte(`bank.get(11).props()`), nil)
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Fatalf("Expecting unknown property ID to fail, got %v", err)
+	}
+	sounds[1].BaseParam.PropBundle.PropValues = sounds[1].BaseParam.PropBundle.PropValues[:1]
+	err = RunStarlarkSource(context.Background(), bnk, "loop.star", []byte("while True:\n    pass\n"), nil)
+	if err == nil {
+		t.Fatal("Expecting runaway loop to be stopped")
+	}
+
+	noBKHD := wwise.NewBank()
+	noBKHD.AddChunk(h)
+	err = RunStarlarkSource(context.Background(), &noBKHD, "print.star", []byte("print(1)"), nil)
+	if err != wwise.NoBKHD {
+		t.Fatalf("Expecting sound bank without BKHD to be rejected, got %v", err)
+	}
+}
+
+func TestRunStarlarkConstructors(t *testing.T) {
+	withMemoryDB(t)
+
+	bnk, h := newTestBank(141)
+	mixer := &wwise.ActorMixer{Id: 1, BaseParam: &wwise.BaseParameter{}}
+	mixer.Container.Children = []uint32{20, 30}
+	switchCntr := &wwise.SwitchCntr{Id: 20, BaseParam: &wwise.BaseParameter{DirectParentId: 1}, GroupID: 7}
+	cntr := &wwise.RanSeqCntr{Id: 30, BaseParam: wwise.BaseParameter{DirectParentId: 1}}
+	cntr.Container.Children = []uint32{10}
+	sound := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{DirectParentId: 30}}
+	sound.BankSourceData.SourceID = 99
+	h.HircObjs = append(h.HircObjs, sound, cntr, switchCntr, mixer)
+	storeActorMixerHircs(h)
+	musicCntr := &wwise.MusicRanSeqCntr{Id: 2}
+	segment := &wwise.MusicSegment{Id: 40, Duration: 4000}
+	segment.BaseParam.DirectParentId = 2
+	segment.Markers = []wwise.MusicSegmentMarker{{ID: wwise.MusicMarkerEntryID, MarkerName: []byte{0}}}
+	musicCntr.Children.Children = []uint32{40}
+	h.HircObjs = append(h.HircObjs, segment, musicCntr)
+	h.MusicHirc.Store(segment.Id, segment)
+	h.MusicHirc.Store(musicCntr.Id, musicCntr)
+
+	src := `
+s = bank.new_switch_cntr(ref=20, parent=1)
+r = bank.new_ran_seq_cntr(ref=30, parent=s)
+print(bank.new_sound(ref=10, parent=r).parent == r.id)
+print(bank.new_music_segment(ref=40, parent=2).parent)
+e = bank.new_event("Play_Variation")
+print(e.id)
+`
+	output := []string{}
+	err := RunStarlarkSource(context.Background(), bnk, "new.star", []byte(src), func(s string) {
+		output = append(output, s)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output) != 3 || output[0] != "True" || output[1] != "2" {
+		t.Fatalf("Unexpected script output %q", output)
+	}
+	if len(mixer.Container.Children) != 3 {
+		t.Fatalf("Expecting new switch container under actor mixer, got children %v", mixer.Container.Children)
+	}
+	v, _ := h.ActorMixerHirc.Load(mixer.Container.Children[2])
+	newSwitch := v.(*wwise.SwitchCntr)
+	if newSwitch.GroupID != 7 || len(newSwitch.Container.Children) != 1 {
+		t.Fatalf("Unexpected switch container %+v", newSwitch)
+	}
+	v, _ = h.ActorMixerHirc.Load(newSwitch.Container.Children[0])
+	newCntr := v.(*wwise.RanSeqCntr)
+	if len(newCntr.Container.Children) != 1 || len(newCntr.PlayListItems) != 1 {
+		t.Fatalf("Expecting new sound in the new container and its playlist, got %+v", newCntr)
+	}
+	v, _ = h.ActorMixerHirc.Load(newCntr.Container.Children[0])
+	if s := v.(*wwise.Sound); s.BankSourceData.SourceID != 99 || s.Id == sound.Id {
+		t.Fatalf("Expecting new sound shares the audio source of reference sound, got %+v", s)
+	}
+	if len(musicCntr.Children.Children) != 2 {
+		t.Fatalf("Expecting new segment under music container, got %v", musicCntr.Children.Children)
+	}
+	v, _ = h.MusicHirc.Load(musicCntr.Children.Children[1])
+	if s := v.(*wwise.MusicSegment); s.Duration != 4000 || len(s.Markers) != 1 || len(s.Children.Children) != 0 {
+		t.Fatalf("Unexpected music segment %+v", s)
+	}
+	if _, in := h.Events.Load(wwise.ShortID("Play_Variation")); !in {
+		t.Fatal("Expecting event is created with the short ID of its name")
+	}
+
+	for _, src := range []string{
+		`bank.new_sound(ref=30, parent=30)`,
+		`bank.new_sound(ref=10, parent=1)`,
+		`bank.new_switch_cntr(ref=20, parent=30)`,
+		`bank.new_ran_seq_cntr(ref=30, parent=10)`,
+		`bank.new_music_segment(ref=40, parent=1)`,
+		`bank.new_event("play_variation")`,
+	} {
+		if err := RunStarlarkSource(context.Background(), bnk, "bad.star", []byte(src), nil); err == nil {
+			t.Fatalf("Expecting invalid constructor to fail: %s", src)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const StateGroupSpecVersion = 0
+
+type StateGroupSpec struct {
+	Version       uint8            `json:"version"`
+	StateGroups []StateGroupEdit `json:"stateGroups"`
+}
+
+type StateGroupEdit struct {
+	// Hierarchy objects, buses or auxiliary buses to edit
+	Targets    []uint32    `json:"targets"`
+	// State group name. Its short ID is used as state group ID.
+	StateGroup   string    `json:"stateGroup"`
+	// State group ID if state group name is not provided
+	Id           uint32    `json:"id"`
+	// Sync type name in wwise.SyncTypeName. Default to "Immediate" for a new
+	// state group, and unchanged for an existing one.
+	SyncType     string    `json:"syncType"`
+	// Remove this state group from targets. States are ignored.
+	Remove       bool      `json:"remove"`
+	States     []StateEdit `json:"states"`
+}
+
+type StateEdit struct {
+	// State name. Its short ID is used as state ID.
+	State   string             `json:"state"`
+	// State ID if state name is not provided
+	Id      uint32             `json:"id"`
+	// Remove this state from the state group. Props are ignored.
+	Remove  bool               `json:"remove"`
+	// Property values keyed by name in wwise.RTPCParameterIDName (e.g.
+	// "Volume", "Pitch", "LPF")
+	Props   map[string]float32 `json:"props"`
+}
+
+func (s *StateGroupEdit) groupID() uint32 {
+	if s.StateGroup != "" {
+		return wwise.ShortID(s.StateGroup)
+	}
+	return s.Id
+}
+
+func (s *StateEdit) stateID() uint32 {
+	if s.State != "" {
+		return wwise.ShortID(s.State)
+	}
+	return s.Id
+}
+
+func ParseStateGroupSpec(spec *StateGroupSpec, fspec string) error {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return fmt.Errorf("Failed to open state group script %s: %w", fspec, err)
+	}
+	err = json.Unmarshal(blob, spec)
+	if err != nil {
+		return fmt.Errorf("Failed to decode state group script %s: %w", fspec, err)
+	}
+	if spec.Version != StateGroupSpecVersion {
+		return fmt.Errorf("Version spec should be %d!", StateGroupSpecVersion)
+	}
+	for _, g := range spec.StateGroups {
+		if g.groupID() == 0 {
+			return fmt.Errorf("State group edit is missing state group name or state group ID")
+		}
+		if _, err := indexName(wwise.SyncTypeName, g.SyncType, "sync type"); err != nil {
+			return err
+		}
+		for _, s := range g.States {
+			if s.stateID() == 0 {
+				return fmt.Errorf("State edit is missing state name or state ID")
+			}
+			for name := range s.Props {
+				if _, err := indexName(wwise.RTPCParameterIDName, name, "state property"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Add / remove state groups and states of hierarchy objects, buses or
+// auxiliary buses, and set property values of states in bulk
+func ModifyStateGroups(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+
+	var spec StateGroupSpec
+	if err := ParseStateGroupSpec(&spec, fspec); err != nil {
+		return err
+	}
+	if len(spec.StateGroups) <= 0 {
+		slog.Warn("No state group edits are provided. Do nothing")
+		return nil
+	}
+
+	q, closeConn, commit, rollback, err := db.CreateConnWithTxQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	v := int(bnk.BKHD().BankGenerationVersion)
+	for _, g := range spec.StateGroups {
+		groupID := g.groupID()
+		syncType, _ := indexName(wwise.SyncTypeName, g.SyncType, "")
+		for _, target := range g.Targets {
+			if err := ctx.Err(); err != nil {
+				rollback()
+				return err
+			}
+			if g.Remove {
+				if err := h.RemoveStateGroup(target, groupID); err != nil {
+					rollback()
+					return err
+				}
+				slog.Info(fmt.Sprintf("Removed state group %d from %d", groupID, target))
+				continue
+			}
+
+			_, sg, err := h.StateGroupOf(target)
+			if err != nil {
+				rollback()
+				return err
+			}
+			if sg.Group(groupID) == nil {
+				err = h.AddStateGroup(target, groupID, uint8(max(syncType, 0)))
+			} else if syncType != -1 {
+				err = h.SetStateSyncType(target, groupID, uint8(syncType))
+			}
+			if err != nil {
+				rollback()
+				return fmt.Errorf("Failed to add state group %d to %d: %w", groupID, target, err)
+			}
+
+			for _, s := range g.States {
+				stateID := s.stateID()
+				if s.Remove {
+					if err := h.RemoveState(target, groupID, stateID); err != nil {
+						rollback()
+						return err
+					}
+					slog.Info(fmt.Sprintf("Removed state %d of state group %d from %d", stateID, groupID, target))
+					continue
+				}
+				if sg.Group(groupID).State(stateID) == nil {
+					var instanceID uint32 = 0
+					if v <= 145 {
+						instanceID, err = db.TryHid(ctx, q)
+						if err != nil {
+							rollback()
+							return err
+						}
+					}
+					if err := h.AddState(target, groupID, stateID, instanceID, v); err != nil {
+						rollback()
+						return fmt.Errorf("Failed to add state %d to %d: %w", stateID, target, err)
+					}
+				}
+
+				names := make([]string, 0, len(s.Props))
+				for name := range s.Props {
+					names = append(names, name)
+				}
+				slices.Sort(names)
+				for _, name := range names {
+					p, _ := indexName(wwise.RTPCParameterIDName, name, "")
+					if err := h.SetStateProp(target, groupID, stateID, wwise.RTPCParameterType(p), s.Props[name], v); err != nil {
+						rollback()
+						return fmt.Errorf("Failed to set %s of state %d of %d: %w", name, stateID, target, err)
+					}
+				}
+				slog.Info(fmt.Sprintf("Set state %d of state group %d of %d", stateID, groupID, target))
+			}
+		}
+	}
+	if err := commit(); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestModifyStateGroups(t *testing.T) {
+	withMemoryDB(t)
+
+	bnk, h := newTestBank(141)
+	s := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{}}
+	bus := &wwise.Bus{Id: 20}
+	h.HircObjs = append(h.HircObjs, s, bus)
+	h.ActorMixerHirc.Store(s.Id, s)
+	h.Buses.Store(bus.Id, bus)
+
+	run := scriptRunner(t, bnk, "states.json", ModifyStateGroups)
+
+	// Duck weapon sounds when the player is in low health
+	if err := run(`{"version": 0, "stateGroups": [
+		{"targets": [10], "stateGroup": "Health", "syncType": "Next Bar", "states": [
+			{"state": "Low", "props": {"Volume": -9, "LPF": 40}},
+			{"state": "Dead", "props": {"Volume": -96}}
+		]},
+		{"targets": [20], "stateGroup": "Health", "states": [
+			{"state": "Low", "props": {"Bus Volume": -3}}
+		]}
+	]}`); err != nil {
+		t.Fatal(err)
+	}
+	health := wwise.ShortID("Health")
+	low := wwise.ShortID("Low")
+	g := s.BaseParam.StateGroup.Group(health)
+	if g == nil || g.StateSyncType != 2 || len(g.States) != 2 {
+		t.Fatalf("Unexpected state group %+v", s.BaseParam.StateGroup)
+	}
+	if val, in := h.StateValue(g.State(low), wwise.RTPCParameterTypeVolume, 141); !in || val != -9 {
+		t.Fatalf("Expecting volume -9 in low health, got %f", val)
+	}
+	if val, in := h.StateValue(bus.StateGroup.Group(health).State(low), wwise.RTPCParameterTypeBusVolume, 141); !in || val != -3 {
+		t.Fatalf("Expecting bus volume -3 in low health, got %f", val)
+	}
+	if len(s.BaseParam.StateProp.StatePropItems) != 2 || len(h.HircObjs) != 5 {
+		t.Fatalf("Expecting 2 state properties and 3 state instances")
+	}
+
+	if err := run(`{"version": 0, "stateGroups": [
+		{"targets": [10], "stateGroup": "Health", "states": [
+			{"state": "Low", "props": {"Volume": -6}},
+			{"state": "Dead", "remove": true}
+		]},
+		{"targets": [20], "stateGroup": "Health", "remove": true}
+	]}`); err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := h.StateValue(g.State(low), wwise.RTPCParameterTypeVolume, 141); val != -6 || len(g.States) != 1 || g.StateSyncType != 2 {
+		t.Fatalf("Unexpected state group %+v", g)
+	}
+	if len(bus.StateGroup.StateGroupItems) != 0 || len(h.HircObjs) != 3 {
+		t.Fatal("Expecting removed states and their instances are gone")
+	}
+
+	if err := run(`{"version": 0, "stateGroups": [
+		{"targets": [10], "stateGroup": "Health", "states": [{"state": "Low", "props": {"Bus Volume": 0}}]}
+	]}`); err == nil {
+		t.Fatal("Expecting error on a bus only property")
+	}
+	if err := run(`{"version": 0, "stateGroups": [{"targets": [10], "stateGroup": "Health", "syncType": "Whenever"}]}`); err == nil {
+		t.Fatal("Expecting error on unknown sync type")
+	}
+}
@@ -0,0 +1,330 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+const SwitchCntrFromFoldersSpecVersion = 0
+
+// Build a switch container with one random / sequence container per switch.
+// Each container plays wave files in a folder (e.g., footsteps per surface).
+type SwitchCntrFromFoldersSpec struct {
+	Version            uint8                      `json:"version"`
+	// Relative folders are resolved against workspace. Default to the
+	// directory of the script.
+	Workspace          string                     `json:"workspace"`
+	Conversion         string                     `json:"conversion"`
+	Format             waapi.ConversionFormatType `json:"format"`
+	// Actor mixer the new switch container is attached to
+	Parent             uint32                     `json:"parent"`
+	// Random / sequence container providing the setting of container of each
+	// switch. Its first sound provides the setting of new sound objects.
+	RefContainer       uint32                     `json:"refContainer"`
+	// Switch container providing the setting of the new switch container.
+	// Default to the setting of the reference random / sequence container if
+	// zero.
+	RefSwitchContainer uint32                     `json:"refSwitchContainer"`
+	GroupType          wwise.GroupType            `json:"groupType"`
+	// Switch group or state group ID
+	Group              uint32                     `json:"group"`
+	// Default to the first switch if zero
+	DefaultSwitch      uint32                     `json:"defaultSwitch"`
+	// Container of each switch plays in sequence instead of randomly
+	Seq                bool                       `json:"seq"`
+	Switches         []SwitchFolder               `json:"switches"`
+	// Set either Event or RefAction to zero for creating a switch container
+	// without an action
+	Event              uint32                     `json:"event"`
+	RefAction          uint32                     `json:"refAction"`
+}
+
+type SwitchFolder struct {
+	Switch uint32 `json:"switch"`
+	Folder string `json:"folder"`
+}
+
+// Return wave files of each switch in file name order
+func ParseSwitchCntrFromFoldersSpec(spec *SwitchCntrFromFoldersSpec, fspec string) ([][]string, error) {
+	blob, err := os.ReadFile(fspec)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open switch container script %s: %w", fspec, err)
+	}
+	if err = json.Unmarshal(blob, spec); err != nil {
+		return nil, fmt.Errorf("Failed to decode switch container script %s: %w", fspec, err)
+	}
+	if spec.Version != SwitchCntrFromFoldersSpecVersion {
+		return nil, fmt.Errorf("Version spec should be %d!", SwitchCntrFromFoldersSpecVersion)
+	}
+	if spec.Workspace == "" {
+		spec.Workspace = filepath.Dir(fspec)
+	} else if !filepath.IsAbs(spec.Workspace) {
+		return nil, fmt.Errorf("Workspace path %s is not an absolute path.", spec.Workspace)
+	}
+	if spec.Format < waapi.ConversionFormatTypePCM || spec.Format > waapi.ConversionFormatTypeWEMOpus {
+		return nil, fmt.Errorf("Invalid conversion format type %d", spec.Format)
+	}
+	if spec.GroupType >= wwise.GroupTypeCount {
+		return nil, fmt.Errorf("Invalid group type %d", spec.GroupType)
+	}
+	if spec.Group == 0 {
+		return nil, fmt.Errorf("Switch group ID is not provided")
+	}
+	if len(spec.Switches) <= 0 {
+		return nil, fmt.Errorf("No switch is provided")
+	}
+	if spec.DefaultSwitch == 0 {
+		spec.DefaultSwitch = spec.Switches[0].Switch
+	}
+	if !slices.ContainsFunc(spec.Switches, func(s SwitchFolder) bool { return s.Switch == spec.DefaultSwitch }) {
+		return nil, fmt.Errorf("Default switch %d is not one of the switches", spec.DefaultSwitch)
+	}
+
+	wavs := make([][]string, len(spec.Switches))
+	for i := range spec.Switches {
+		s := &spec.Switches[i]
+		if slices.ContainsFunc(spec.Switches[:i], func(o SwitchFolder) bool { return o.Switch == s.Switch }) {
+			return nil, fmt.Errorf("Switch %d is duplicated", s.Switch)
+		}
+		if !filepath.IsAbs(s.Folder) {
+			s.Folder = filepath.Join(spec.Workspace, s.Folder)
+		}
+		entries, err := os.ReadDir(s.Folder)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read folder %s of switch %d: %w", s.Folder, s.Switch, err)
+		}
+		wavs[i] = []string{}
+		for _, e := range entries {
+			if !e.IsDir() && strings.EqualFold(filepath.Ext(e.Name()), ".wav") {
+				wavs[i] = append(wavs[i], filepath.Join(s.Folder, e.Name()))
+			}
+		}
+		if len(wavs[i]) <= 0 {
+			return nil, fmt.Errorf("Folder %s of switch %d has no wave file", s.Folder, s.Switch)
+		}
+	}
+	return wavs, nil
+}
+
+func SwitchCntrFromFolders(ctx context.Context, bnk *wwise.Bank, fspec string) error {
+	if bnk.DIDX() == nil {
+		return wwise.NoDIDX
+	}
+	if bnk.DATA() == nil {
+		return wwise.NoDATA
+	}
+	h := bnk.HIRC()
+	if h == nil {
+		return wwise.NoHIRC
+	}
+	var spec SwitchCntrFromFoldersSpec
+	wavs, err := ParseSwitchCntrFromFoldersSpec(&spec, fspec)
+	if err != nil {
+		return err
+	}
+
+	v, in := h.ActorMixerHirc.Load(spec.Parent)
+	if !in {
+		return fmt.Errorf("No actor mixer hierarchy object has ID %d.", spec.Parent)
+	}
+	if _, ok := v.(*wwise.ActorMixer); !ok {
+		return fmt.Errorf("Parent actor mixer hierarchy type %s is yet supported.", wwise.HircTypeName[v.(wwise.HircObj).HircType()])
+	}
+
+	v, in = h.ActorMixerHirc.Load(spec.RefContainer)
+	if !in {
+		return fmt.Errorf("No reference random / sequence container has ID %d.", spec.RefContainer)
+	}
+	refCntr, ok := v.(*wwise.RanSeqCntr)
+	if !ok {
+		return fmt.Errorf("Actor mixer hierarchy object %d is not type of random / sequence container.", spec.RefContainer)
+	}
+	var refSound *wwise.Sound
+	for i := 0; i < len(refCntr.Container.Children) && refSound == nil; i++ {
+		if v, in := h.ActorMixerHirc.Load(refCntr.Container.Children[i]); in {
+			refSound, _ = v.(*wwise.Sound)
+		}
+	}
+	if refSound == nil {
+		return fmt.Errorf("There's no reference sound in random / sequence container %d to create new sound objects", spec.RefContainer)
+	}
+
+	var refSwitch *wwise.SwitchCntr
+	if spec.RefSwitchContainer != 0 {
+		v, in = h.ActorMixerHirc.Load(spec.RefSwitchContainer)
+		if !in {
+			return fmt.Errorf("No reference switch container has ID %d.", spec.RefSwitchContainer)
+		}
+		if refSwitch, ok = v.(*wwise.SwitchCntr); !ok {
+			return fmt.Errorf("Actor mixer hierarchy object %d is not type of switch container.", spec.RefSwitchContainer)
+		}
+	}
+
+	var refAction *wwise.Action
+	if spec.Event != 0 && spec.RefAction != 0 {
+		if _, in := h.Events.Load(spec.Event); !in {
+			return fmt.Errorf("No event object has ID %d.", spec.Event)
+		}
+		v, in := h.Actions.Load(spec.RefAction)
+		if !in {
+			return fmt.Errorf("No action has ID %d.", spec.RefAction)
+		}
+		refAction = v.(*wwise.Action)
+	}
+
+	staging, jobs, err := waapi.StageConversion(slices.Concat(wavs...))
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+	if err := waapi.ConverterFrom(ctx).Convert(ctx, jobs, spec.Conversion, spec.Format); err != nil {
+		return err
+	}
+	audioDatas := make([][]byte, len(jobs))
+	for i, job := range jobs {
+		if audioDatas[i], err = os.ReadFile(job.Wem); err != nil {
+			return fmt.Errorf("Failed to read audio data from %s: %w", job.Wem, err)
+		}
+	}
+
+	// Hierarchy IDs generation and Source IDs generation
+	q, closeConn, commit, rollback, err := db.CreateConnWithTxQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+	switchId, err := db.TryHid(ctx, q)
+	if err != nil {
+		rollback()
+		return err
+	}
+	cntrIds := make([]uint32, len(spec.Switches))
+	for i := range cntrIds {
+		if cntrIds[i], err = db.TryHid(ctx, q); err != nil {
+			rollback()
+			return err
+		}
+	}
+	soundIds := make([]uint32, len(audioDatas))
+	sourceIds := make([]uint32, len(audioDatas))
+	for i := range audioDatas {
+		if soundIds[i], err = db.TryHid(ctx, q); err != nil {
+			rollback()
+			return err
+		}
+		if sourceIds[i], err = db.TrySid(ctx, q); err != nil {
+			rollback()
+			return err
+		}
+	}
+	actionId := uint32(0)
+	if refAction != nil {
+		if actionId, err = db.TryHid(ctx, q); err != nil {
+			rollback()
+			return err
+		}
+	}
+
+	for i, audioData := range audioDatas {
+		if err := bnk.AppendAudio(audioData, sourceIds[i]); err != nil {
+			rollback()
+			return fmt.Errorf("Failed to add a new audio source file: %w.", err)
+		}
+	}
+	bnk.ComputeDIDXOffset()
+	if err := bnk.CheckDIDXDATA(); err != nil {
+		rollback()
+		return fmt.Errorf("Invalid Integrity appear in DIDX and DATA chunk: %w", err)
+	}
+
+	newSwitch := &wwise.SwitchCntr{
+		Id: switchId,
+		GroupType: uint8(spec.GroupType),
+		GroupID: spec.Group,
+		DefaultSwitch: spec.DefaultSwitch,
+		Container: wwise.Container{Children: []uint32{}},
+		SwitchGroups: []wwise.SwitchGroupItem{},
+		SwitchParams: []wwise.SwitchParam{},
+	}
+	if refSwitch != nil {
+		newSwitch.BaseParam = refSwitch.BaseParam.Clone(false)
+		newSwitch.IsContinuousValidation = refSwitch.IsContinuousValidation
+	} else {
+		newSwitch.BaseParam = refCntr.BaseParam.Clone(false)
+	}
+	if err := h.AppendNewSwitchCntrToActorMixer(newSwitch, spec.Parent, false); err != nil {
+		rollback()
+		return fmt.Errorf("Failed to add a new switch container to actor mixer %d: %w", spec.Parent, err)
+	}
+
+	pluginID := spec.Format.PluginID()
+	k := 0
+	for i, s := range spec.Switches {
+		cntr := refCntr.Clone(cntrIds[i], false)
+		if spec.Seq {
+			cntr.PlayListSetting.Mode = wwise.ModeSequence
+		} else {
+			cntr.PlayListSetting.Mode = wwise.ModeRandom
+		}
+		if err := h.AppendNewRanSeqCntrToSwitchCntr(&cntr, switchId, false); err != nil {
+			rollback()
+			return fmt.Errorf("Failed to add a new random / sequence container to switch container %d: %w", switchId, err)
+		}
+		newSwitch.AssignSwitch(s.Switch, cntrIds[i])
+		for range wavs[i] {
+			sound := &wwise.Sound{
+				Id: soundIds[k],
+				BankSourceData: wwise.BankSourceData{
+					PluginID: pluginID,
+					StreamType: wwise.SourceTypeDATA,
+					SourceID: sourceIds[k],
+					InMemoryMediaSize: uint32(len(audioDatas[k])),
+					SourceBits: 0,
+				},
+				BaseParam: refSound.BaseParam.Clone(false),
+			}
+			if err := h.AppendNewSoundToRanSeqContainer(sound, cntrIds[i], false); err != nil {
+				rollback()
+				return fmt.Errorf("Failed to add a new sound object to random / sequence container %d: %w", cntrIds[i], err)
+			}
+			k += 1
+		}
+	}
+
+	if refAction != nil {
+		action := refAction.Clone(actionId, switchId)
+		if err := h.AppendNewActionToEvent(&action, spec.Event); err != nil {
+			rollback()
+			return fmt.Errorf("Failed to add a new action to event %d: %w", spec.Event, err)
+		}
+	}
+
+	if err := commit(); err != nil {
+		rollback()
+		return err
+	}
+
+	slog.Info(fmt.Sprintf("Switch container %d -> Group %d", switchId, spec.Group))
+	k = 0
+	for i, s := range spec.Switches {
+		slog.Info(fmt.Sprintf("Switch %d -> Random / Sequence container %d", s.Switch, cntrIds[i]))
+		for _, wav := range wavs[i] {
+			slog.Info(fmt.Sprintf("Sound object %d -> Audio Source %d (%s)", soundIds[k], sourceIds[k], filepath.Base(wav)))
+			k += 1
+		}
+	}
+	if actionId != 0 {
+		slog.Info(fmt.Sprintf("Action %d -> Switch container %d", actionId, switchId))
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package automation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/utils"
+	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestSwitchCntrFromFolders(t *testing.T) {
+	withMemoryDB(t)
+
+	if err := utils.InitTmp(); err != nil {
+		t.Fatal(err)
+	}
+	defer utils.CleanTmp()
+
+	dir := t.TempDir()
+	for folder, wavs := range map[string][]string{"grass": {"b.wav", "a.wav"}, "metal": {"a.wav"}} {
+		if err := os.Mkdir(filepath.Join(dir, folder), 0777); err != nil {
+			t.Fatal(err)
+		}
+		for _, wav := range wavs {
+			if err := os.WriteFile(filepath.Join(dir, folder, wav), []byte("RIFF"), 0666); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	script := filepath.Join(dir, "footsteps.json")
+	spec := `{
+		"version": 0,
+		"conversion": "default",
+		"format": 0,
+		"parent": 1,
+		"refContainer": 30,
+		"group": 700,
+		"defaultSwitch": 702,
+		"switches": [{"switch": 701, "folder": "grass"}, {"switch": 702, "folder": "metal"}]
+	}`
+	if err := os.WriteFile(script, []byte(spec), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	bnk, h := newTestBank(141)
+	mixer := &wwise.ActorMixer{Id: 1, BaseParam: &wwise.BaseParameter{}}
+	mixer.Container.Children = []uint32{30}
+	cntr := &wwise.RanSeqCntr{Id: 30, BaseParam: wwise.BaseParameter{DirectParentId: 1}}
+	cntr.Container.Children = []uint32{10}
+	sound := &wwise.Sound{Id: 10, BaseParam: &wwise.BaseParameter{DirectParentId: 30}}
+	h.HircObjs = append(h.HircObjs, sound, cntr, mixer)
+	storeActorMixerHircs(h)
+	bnk.AddChunk(wwise.NewDIDX(1, []byte{'D', 'I', 'D', 'X'}, 0))
+	bnk.AddChunk(&wwise.DATA{I: 2, T: []byte{'D', 'A', 'T', 'A'}, Audios: [][]byte{}, AudiosMap: map[uint32][]byte{}})
+
+	ctx := waapi.WithConverter(context.Background(), &waapi.PlaceholderConverter{})
+	if err := SwitchCntrFromFolders(ctx, bnk, script); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mixer.Container.Children) != 2 {
+		t.Fatalf("Expecting a new switch container under actor mixer, got children %v", mixer.Container.Children)
+	}
+	v, _ := h.ActorMixerHirc.Load(mixer.Container.Children[1])
+	s, ok := v.(*wwise.SwitchCntr)
+	if !ok {
+		t.Fatalf("Expecting a switch container, got %T", v)
+	}
+	if s.GroupID != 700 || s.DefaultSwitch != 702 || len(s.Container.Children) != 2 || len(s.SwitchParams) != 2 {
+		t.Fatalf("Unexpected switch container %+v", s)
+	}
+	for i, sw := range []uint32{701, 702} {
+		g := s.SwitchGroups[i]
+		if g.SwitchID != sw || !slices.Equal(g.NodeList, []uint32{s.Container.Children[i]}) {
+			t.Fatalf("Unexpected assignment of switch %d: %+v", sw, g)
+		}
+	}
+	counts := []int{}
+	for _, id := range s.Container.Children {
+		v, _ := h.ActorMixerHirc.Load(id)
+		r := v.(*wwise.RanSeqCntr)
+		if r.BaseParam.DirectParentId != s.Id || len(r.PlayListItems) != len(r.Container.Children) {
+			t.Fatalf("Unexpected random / sequence container %+v", r)
+		}
+		counts = append(counts, len(r.Container.Children))
+	}
+	if !slices.Equal(counts, []int{2, 1}) || len(bnk.DATA().Audios) != 3 {
+		t.Fatalf("Expecting 2 and 1 sounds with 3 audio sources, got %v and %d", counts, len(bnk.DATA().Audios))
+	}
+	// Children must be encoded before their parent
+	order := map[uint32]int{}
+	for i, o := range h.HircObjs {
+		id, _ := o.HircID()
+		order[id] = i
+	}
+	for _, o := range h.HircObjs {
+		id, _ := o.HircID()
+		if p := o.BaseParameter(); p != nil && p.DirectParentId != 0 && order[id] > order[p.DirectParentId] {
+			t.Fatalf("%d is placed after its parent %d", id, p.DirectParentId)
+		}
+	}
+
+	first := s.Container.Children[0]
+	h.ChangeRoot(first, 1, s.Id, false)
+	if len(s.Container.Children) != 1 || len(s.SwitchParams) != 1 || len(s.SwitchGroups[0].NodeList) != 0 {
+		t.Fatalf("Expecting %d to be removed from switch container, got %+v", first, s)
+	}
+}
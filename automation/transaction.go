@@ -0,0 +1,147 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/parser"
+	"github.com/Dekr0/wwise-teller/utils"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// What to do when a process script fails on a sound bank. In every policy,
+// changes of a failing script never reach the output, including hierarchy
+// IDs and source IDs it allocated in the database.
+type FailurePolicy string
+
+const (
+	// Discard every sound bank of the pipeline and produce no output
+	FailurePolicyFailFast   FailurePolicy = "failFast"
+	// Roll back the failing script and continue with the next script
+	FailurePolicySkipScript FailurePolicy = "skipScript"
+	// Discard the sound bank with the failing script. Other sound banks are
+	// still written. (Default)
+	FailurePolicySkipBank   FailurePolicy = "skipBank"
+)
+
+func (p FailurePolicy) Check() error {
+	switch p {
+	case "", FailurePolicyFailFast, FailurePolicySkipScript, FailurePolicySkipBank:
+		return nil
+	default:
+		return fmt.Errorf(
+			"Unsupported failure policy %s. Use %s, %s or %s",
+			p, FailurePolicyFailFast, FailurePolicySkipScript, FailurePolicySkipBank,
+		)
+	}
+}
+
+func (p *ProcessPipeline) Policy() FailurePolicy {
+	if p.OnFailure == "" {
+		return FailurePolicySkipBank
+	}
+	return p.OnFailure
+}
+
+type ScriptFailure struct {
+	Script string
+	Err    error
+}
+
+// Outcome of running a pipeline on one sound bank
+type BankResult struct {
+	Bank       string
+	Applied  []string
+	Failed   []ScriptFailure
+	// Reason why the sound bank is not written. Empty if it is written.
+	Discarded  string
+	// Hierarchy IDs and source IDs allocated for this sound bank
+	journal    db.Journal
+}
+
+func (r *BankResult) discard(reason string) {
+	if r.Discarded == "" {
+		r.Discarded = reason
+	}
+}
+
+// Take back IDs allocated for a discarded sound bank
+func (r *BankResult) rollback(ctx context.Context) {
+	hids, sids := r.journal.Len()
+	if hids + sids == 0 {
+		return
+	}
+	if err := r.journal.Rollback(ctx); err != nil {
+		slog.Error(fmt.Sprintf("Failed to roll back IDs allocated for sound bank %s", r.Bank), "error", err)
+		return
+	}
+	slog.Info(fmt.Sprintf(
+		"Rolled back %d hierarchy IDs and %d source IDs allocated for sound bank %s", hids, sids, r.Bank,
+	))
+}
+
+// Encode a sound bank into a temporary file so that it can be restored if a
+// script fails half way.
+func SnapshotBank(ctx context.Context, bnk *wwise.Bank) (string, error) {
+	if err := utils.EnsureTmp(); err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Second * 8)
+	defer cancel()
+	data, err := bnk.Encode(ctx, false, false)
+	if err != nil {
+		return "", fmt.Errorf("Failed to encode snapshot of sound bank: %w", err)
+	}
+	f, err := os.CreateTemp(utils.Tmp, "snapshot-*.bnk")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func RestoreBank(ctx context.Context, snapshot string) (*wwise.Bank, error) {
+	bnk, err := parser.ParseBank(snapshot, ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to restore snapshot of sound bank: %w", err)
+	}
+	return bnk, nil
+}
+
+func logPipelineSummary(results []BankResult, scripts int) {
+	written := 0
+	for i := range results {
+		r := &results[i]
+		if r.Discarded == "" {
+			written += 1
+			slog.Info(fmt.Sprintf(
+				"[Written]   %s: applied %d / %d scripts", filepath.Base(r.Bank), len(r.Applied), scripts,
+			))
+		} else {
+			slog.Error(fmt.Sprintf(
+				"[Discarded] %s: %s", filepath.Base(r.Bank), r.Discarded,
+			))
+		}
+		for _, f := range r.Failed {
+			slog.Error(fmt.Sprintf("    failed %s", filepath.Base(f.Script)), "error", f.Err)
+		}
+		if r.Discarded == "" && len(r.Applied) < scripts {
+			applied := make([]string, len(r.Applied))
+			for j, s := range r.Applied {
+				applied[j] = filepath.Base(s)
+			}
+			slog.Warn(fmt.Sprintf("    only applied %s", strings.Join(applied, ", ")))
+		}
+	}
+	slog.Info(fmt.Sprintf("Pipeline summary: %d / %d sound banks written", written, len(results)))
+}
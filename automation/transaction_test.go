@@ -0,0 +1,181 @@
+package automation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/integration"
+	"github.com/Dekr0/wwise-teller/parser"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestFailurePolicy(t *testing.T) {
+	p := ProcessPipeline{}
+	if p.Policy() != FailurePolicySkipBank {
+		t.Fatalf("Expecting default failure policy %s, got %s", FailurePolicySkipBank, p.Policy())
+	}
+	for _, policy := range []FailurePolicy{FailurePolicyFailFast, FailurePolicySkipScript, FailurePolicySkipBank} {
+		if err := policy.Check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := FailurePolicy("retry").Check(); err == nil {
+		t.Fatal("Expecting unsupported failure policy to be rejected")
+	}
+
+	r := BankResult{}
+	r.discard("first")
+	r.discard("second")
+	if r.Discarded != "first" {
+		t.Fatalf("Expecting the first discard reason to be kept, got %s", r.Discarded)
+	}
+}
+
+// Two sound banks share the same script chain. The second script always
+// changes the bank before it fails on a bank with object 2.
+func TestRunProcessScriptsPolicy(t *testing.T) {
+	workspace := t.TempDir()
+	writeBank := func(name string, poisoned bool) {
+		bnk, h := newTestBank(141)
+		// Chunks are encoded in the order of their index
+		h.I = 1
+		ids := []uint32{1}
+		if poisoned {
+			ids = append(ids, 2)
+		}
+		for _, id := range ids {
+			b := &wwise.BaseParameter{}
+			b.StateProp.NumStateProps.Set(0)
+			b.StateGroup.NumStateGroups.Set(0)
+			h.HircObjs = append(h.HircObjs, &wwise.ActorMixer{Id: id, BaseParam: b})
+		}
+		storeActorMixerHircs(h)
+		data, err := bnk.Encode(context.Background(), false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(workspace, name), data, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeBank("a.bnk", true)
+	writeBank("b.bnk", false)
+
+	srcs := []string{
+		"bank.new_id()\nbank.get(1).set_prop(\"Volume\", -6)\n",
+		"bank.new_id()\nbank.get(1).set_prop(\"Volume\", 6)\nif bank.get(2) != None:\n    fail(\"poisoned\")\n",
+		"bank.new_id()\nbank.get(1).set_prop(\"Make Up Gain\", 3)\n",
+	}
+	scripts := make([]ProcessScript, len(srcs))
+	for i, src := range srcs {
+		scripts[i] = ProcessScript{Type: TypeStarlark, Script: fmt.Sprintf("%d.star", i)}
+		if err := os.WriteFile(filepath.Join(workspace, scripts[i].Script), []byte(src), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pipeline := func(policy FailurePolicy) *ProcessPipeline {
+		return &ProcessPipeline{
+			BanksWorkspace: workspace,
+			ScriptsWorkspace: workspace,
+			Banks: []string{"a.bnk", "b.bnk"},
+			Scripts: slices.Clone(scripts),
+			Integration: integration.IntegrationTypeDefault,
+			Output: t.TempDir(),
+			SkipLint: true,
+			OnFailure: policy,
+		}
+	}
+	count := func() (int, int) {
+		var hids, sids int
+		db.WwiseIdDB.QueryRow("SELECT COUNT(*) FROM hierarchy").Scan(&hids)
+		db.WwiseIdDB.QueryRow("SELECT COUNT(*) FROM source").Scan(&sids)
+		return hids, sids
+	}
+	encode := func(bnk *wwise.Bank) []byte {
+		data, err := bnk.Encode(context.Background(), false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	// Expected sound bank after running the given scripts on it
+	expect := func(bank string, scripts ...int) []byte {
+		bnk, err := parser.ParseBank(filepath.Join(workspace, bank), context.Background(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range scripts {
+			if err := RunStarlarkSource(context.Background(), bnk, "expect.star", []byte(srcs[i]), nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return encode(bnk)
+	}
+
+	t.Run(string(FailurePolicySkipScript), func(t *testing.T) {
+		withMemoryDB(t)
+		p := pipeline(FailurePolicySkipScript)
+		cleanup, err := p.resolveScripts()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cleanup()
+		r := BankResult{Bank: "a.bnk"}
+		bnk := RunProcessScripts(context.Background(), "a.bnk", p, &r)
+		if bnk == nil || len(r.Applied) != 2 || len(r.Failed) != 1 || r.Discarded != "" {
+			t.Fatalf("Expecting the second script to be skipped, got applied %v and failed %v", r.Applied, r.Failed)
+		}
+		if hids, sids := r.journal.Len(); hids != 2 || sids != 0 {
+			t.Fatalf("Expecting journal to keep IDs of applied scripts only, got %d and %d", hids, sids)
+		}
+		if hids, sids := count(); hids != 2 || sids != 0 {
+			t.Fatalf("Expecting 2 hierarchy IDs in database, got %d and %d", hids, sids)
+		}
+		r.rollback(context.Background())
+		if hids, sids := count(); hids != 0 || sids != 0 {
+			t.Fatalf("Expecting empty database after rolling back the bank, got %d and %d", hids, sids)
+		}
+		if !bytes.Equal(encode(bnk), expect("a.bnk", 0, 2)) {
+			t.Fatal("Expecting changes of the failing script to be restored from snapshot")
+		}
+	})
+
+	t.Run(string(FailurePolicySkipBank), func(t *testing.T) {
+		withMemoryDB(t)
+		p := pipeline(FailurePolicySkipBank)
+		RunProcessPipeline(context.Background(), p)
+		if _, err := os.Stat(filepath.Join(p.Output, "a.bnk")); !os.IsNotExist(err) {
+			t.Fatal("Expecting failing sound bank to be discarded")
+		}
+		data, err := os.ReadFile(filepath.Join(p.Output, "b.bnk"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hids, sids := count(); hids != 3 || sids != 0 {
+			t.Fatalf("Expecting IDs of the discarded bank to be rolled back, got %d and %d", hids, sids)
+		}
+		if !bytes.Equal(data, expect("b.bnk", 0, 1, 2)) {
+			t.Fatal("Unexpected output of the other sound bank")
+		}
+	})
+
+	t.Run(string(FailurePolicyFailFast), func(t *testing.T) {
+		withMemoryDB(t)
+		p := pipeline(FailurePolicyFailFast)
+		RunProcessPipeline(context.Background(), p)
+		for _, bank := range p.Banks {
+			if _, err := os.Stat(filepath.Join(p.Output, bank)); !os.IsNotExist(err) {
+				t.Fatalf("Expecting sound bank %s to be discarded", bank)
+			}
+		}
+		if hids, sids := count(); hids != 0 || sids != 0 {
+			t.Fatalf("Expecting IDs of every sound bank to be rolled back, got %d and %d", hids, sids)
+		}
+	})
+}
@@ -0,0 +1,383 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Dekr0/wwise-teller/integration"
+	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Changes within this duration are batched into one run
+const WatchDebounce = time.Millisecond * 500
+
+// Sound bank index of a pipeline affected by a file. allBanks means every
+// sound bank of the pipeline (e.g., a process script changed).
+type watchTarget struct {
+	pipeline int
+	bank     int
+}
+
+const allBanks = -1
+
+// Files a processor depends on and what each of them affects
+type watchSet struct {
+	spec    string
+	files   map[string][]watchTarget
+	// Folders whose wave files are all read by a script. Adding or removing a
+	// wave file in them affects the script as well.
+	folders map[string][]watchTarget
+}
+
+func newWatchSet(fspec string, spec *Processor) *watchSet {
+	s := &watchSet{
+		spec: filepath.Clean(fspec),
+		files: make(map[string][]watchTarget),
+		folders: make(map[string][]watchTarget),
+	}
+	s.files[s.spec] = []watchTarget{}
+	if spec == nil {
+		return s
+	}
+	for i := range spec.Pipelines {
+		p := &spec.Pipelines[i]
+		for j, b := range p.Banks {
+			s.add(bankPath(p, b), watchTarget{i, j})
+		}
+		cleanup, err := p.resolveScripts()
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to resolve process scripts of pipeline %d", i), "error", err)
+			continue
+		}
+		for _, script := range p.Scripts {
+			if !script.HasInline() {
+				s.add(script.path, watchTarget{i, allBanks})
+			}
+			for _, input := range scriptInputs(&script) {
+				s.add(input, watchTarget{i, allBanks})
+			}
+			for _, folder := range scriptFolders(&script) {
+				folder = filepath.Clean(folder)
+				if t := (watchTarget{i, allBanks}); !slices.Contains(s.folders[folder], t) {
+					s.folders[folder] = append(s.folders[folder], t)
+				}
+			}
+		}
+		cleanup()
+	}
+	return s
+}
+
+// What a changed file affects. Return false if the file is not watched.
+func (s *watchSet) targets(f string) ([]watchTarget, bool) {
+	f = filepath.Clean(f)
+	if t, in := s.files[f]; in {
+		return t, true
+	}
+	if strings.EqualFold(filepath.Ext(f), ".wav") {
+		t, in := s.folders[filepath.Dir(f)]
+		return t, in
+	}
+	return nil, false
+}
+
+func (s *watchSet) add(path string, t watchTarget) {
+	path = filepath.Clean(path)
+	if !slices.Contains(s.files[path], t) {
+		s.files[path] = append(s.files[path], t)
+	}
+}
+
+// Directories to subscribe. Files are watched through their parent directory
+// so that editors replacing a file on save are still picked up.
+func (s *watchSet) dirs() []string {
+	dirs := []string{}
+	for f := range s.files {
+		if d := filepath.Dir(f); !slices.Contains(dirs, d) {
+			dirs = append(dirs, d)
+		}
+	}
+	for d := range s.folders {
+		if !slices.Contains(dirs, d) {
+			dirs = append(dirs, d)
+		}
+	}
+	slices.Sort(dirs)
+	return dirs
+}
+
+// Audio files read by a process script. Scripts without audio inputs or
+// failing to parse have none.
+func scriptInputs(script *ProcessScript) []string {
+	switch script.Type {
+	case TypeRewireWithNewSources, TypeRewireWithOldSources, TypeReplaceAudioSources:
+		m := WavSoundMap{}
+		if err := DecodeReplaceAudioSourcesScript(&m, script.path); err != nil {
+			slog.Warn(fmt.Sprintf("Failed to read audio inputs of %s", script.Label()), "error", err)
+			return nil
+		}
+		return m.Wavs
+	case TypeImportAsRanSeqCntr, TypeNewSoundToRanSeqCntr:
+		var s ImportAsRanSeqCntrScript
+		inputs, err := ParseImportAsRanSeqCntrScript(&s, script.path)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to read audio inputs of %s", script.Label()), "error", err)
+			return nil
+		}
+		return slices.Sorted(maps.Keys(inputs))
+	case TypeSwitchCntrFromFolders:
+		var s SwitchCntrFromFoldersSpec
+		wavs, err := ParseSwitchCntrFromFoldersSpec(&s, script.path)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to read audio inputs of %s", script.Label()), "error", err)
+			return nil
+		}
+		return slices.Concat(wavs...)
+	case TypeMusicSegmentFromStems:
+		var s MusicSegmentFromStemsSpec
+		wavs, err := ParseMusicSegmentFromStemsSpec(&s, script.path)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to read audio inputs of %s", script.Label()), "error", err)
+			return nil
+		}
+		return wavs
+	}
+	return nil
+}
+
+// Folders read by a process script as a whole
+func scriptFolders(script *ProcessScript) []string {
+	switch script.Type {
+	case TypeSwitchCntrFromFolders:
+		var s SwitchCntrFromFoldersSpec
+		if _, err := ParseSwitchCntrFromFoldersSpec(&s, script.path); err != nil {
+			return nil
+		}
+		folders := make([]string, len(s.Switches))
+		for i, sw := range s.Switches {
+			folders[i] = sw.Folder
+		}
+		return folders
+	}
+	return nil
+}
+
+// Build a processor that only contains pipelines and sound banks affected by
+// the changed files. Sound banks written by an affected pipeline and consumed
+// by another pipeline are affected as well. Helldivers 2 integration packs
+// every sound bank of a pipeline into one patch so the whole pipeline is
+// rerun. Return nil if nothing is affected.
+func (s *watchSet) affected(spec *Processor, changed []string) *Processor {
+	marked := make([][]bool, len(spec.Pipelines))
+	for i := range spec.Pipelines {
+		marked[i] = make([]bool, len(spec.Pipelines[i].Banks))
+	}
+	mark := func(i int, j int) bool {
+		if j == allBanks {
+			if !slices.Contains(marked[i], false) {
+				return false
+			}
+			for j := range marked[i] {
+				marked[i][j] = true
+			}
+			return true
+		}
+		if marked[i][j] {
+			return false
+		}
+		marked[i][j] = true
+		return true
+	}
+	for _, f := range changed {
+		t, _ := s.targets(f)
+		for _, t := range t {
+			mark(t.pipeline, t.bank)
+		}
+	}
+
+	for propagate := true; propagate; {
+		propagate = false
+		for d := range spec.Pipelines {
+			pd := &spec.Pipelines[d]
+			if pd.Integration == integration.IntegrationTypeHelldivers2 && slices.Contains(marked[d], true) {
+				propagate = mark(d, allBanks) || propagate
+			}
+			if pd.Integration != integration.IntegrationTypeDefault {
+				continue
+			}
+			for k, b := range pd.Banks {
+				if !marked[d][k] {
+					continue
+				}
+				out := filepath.Clean(filepath.Join(pd.Output, filepath.Base(b)))
+				for i := range spec.Pipelines {
+					for j, consumed := range spec.Pipelines[i].Banks {
+						if i != d && filepath.Clean(bankPath(&spec.Pipelines[i], consumed)) == out {
+							propagate = mark(i, j) || propagate
+						}
+					}
+				}
+			}
+		}
+	}
+
+	sub := &Processor{Veriosn: spec.Veriosn, Workers: spec.Workers, Pipelines: []ProcessPipeline{}}
+	for i := range spec.Pipelines {
+		if !slices.Contains(marked[i], true) {
+			continue
+		}
+		p := spec.Pipelines[i]
+		p.Banks = []string{}
+		for j, b := range spec.Pipelines[i].Banks {
+			if marked[i][j] {
+				p.Banks = append(p.Banks, b)
+			}
+		}
+		p.Scripts = slices.Clone(p.Scripts)
+		sub.Pipelines = append(sub.Pipelines, p)
+	}
+	if len(sub.Pipelines) == 0 {
+		return nil
+	}
+	return sub
+}
+
+// Sound banks written by a processor. Changes to them right after a run are
+// caused by the run itself.
+func processorOutputs(spec *Processor) []string {
+	outputs := []string{}
+	for i := range spec.Pipelines {
+		p := &spec.Pipelines[i]
+		if p.Integration != integration.IntegrationTypeDefault {
+			continue
+		}
+		for _, b := range p.Banks {
+			outputs = append(outputs, filepath.Clean(filepath.Join(p.Output, filepath.Base(b))))
+		}
+	}
+	return outputs
+}
+
+func subscribe(w *fsnotify.Watcher, s *watchSet) {
+	for _, d := range w.WatchList() {
+		w.Remove(d)
+	}
+	for _, d := range s.dirs() {
+		if err := w.Add(d); err != nil {
+			slog.Warn(fmt.Sprintf("Failed to watch directory %s", d), "error", err)
+		}
+	}
+}
+
+// Run a processor once, then keep running it whenever the processor
+// specification, a process script, a sound bank or an audio input referenced
+// by a script changes. Only affected pipelines and sound banks are rerun.
+// Each run is bound by the given deadline (0 means no deadline). Return once
+// the context is cancelled.
+func Watch(ctx context.Context, fspec string, deadline time.Duration) error {
+	fspec, err := filepath.Abs(fspec)
+	if err != nil {
+		return err
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("Failed to create file watcher: %w", err)
+	}
+	defer w.Close()
+
+	cache, err := waapi.OpenConversionCache()
+	if err != nil {
+		slog.Warn("Conversion cache is disabled", "error", err)
+	} else {
+		ctx = waapi.WithConversionCache(ctx, cache)
+		defer cache.LogStats()
+	}
+
+	run := func(spec *Processor) {
+		rctx := ctx
+		if deadline != 0 {
+			var cancel context.CancelFunc
+			rctx, cancel = context.WithTimeout(ctx, deadline)
+			defer cancel()
+		}
+		runProcessor(rctx, spec)
+	}
+
+	spec, err := ParseProcessor(fspec)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to parse processor %s", fspec), "error", err)
+	}
+	set := newWatchSet(fspec, spec)
+	subscribe(w, set)
+	if spec != nil {
+		run(spec)
+	}
+	slog.Info(fmt.Sprintf("Watching %d files of processor %s", len(set.files), fspec))
+
+	changed := []string{}
+	debounce := time.NewTimer(WatchDebounce)
+	debounce.Stop()
+	// Outputs of the last run and until when their changes are ignored
+	var outputs []string
+	var quiet time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-w.Errors:
+			slog.Error("File watcher error", "error", err)
+		case e := <-w.Events:
+			if !e.Has(fsnotify.Write) && !e.Has(fsnotify.Create) && !e.Has(fsnotify.Rename) && !e.Has(fsnotify.Remove) {
+				continue
+			}
+			f := filepath.Clean(e.Name)
+			if _, in := set.targets(f); !in {
+				continue
+			}
+			if time.Now().Before(quiet) && slices.Contains(outputs, f) {
+				continue
+			}
+			if !slices.Contains(changed, f) {
+				changed = append(changed, f)
+			}
+			debounce.Reset(WatchDebounce)
+		case <-debounce.C:
+			var sub *Processor
+			if slices.Contains(changed, set.spec) {
+				slog.Info(fmt.Sprintf("Processor %s changed", fspec))
+				next, err := ParseProcessor(fspec)
+				if err != nil {
+					slog.Error(fmt.Sprintf("Failed to parse processor %s. Keep watching the previous one.", fspec), "error", err)
+					changed = changed[:0]
+					continue
+				}
+				spec = next
+				set = newWatchSet(fspec, spec)
+				subscribe(w, set)
+				sub = spec
+			} else if spec != nil {
+				for _, f := range changed {
+					slog.Info(fmt.Sprintf("%s changed", f))
+				}
+				sub = set.affected(spec, changed)
+			}
+			changed = changed[:0]
+			if sub == nil {
+				continue
+			}
+			run(sub)
+			// Script changes may add or remove audio inputs
+			set = newWatchSet(fspec, spec)
+			subscribe(w, set)
+			outputs = processorOutputs(sub)
+			quiet = time.Now().Add(WatchDebounce * 2)
+		}
+	}
+}
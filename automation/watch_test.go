@@ -0,0 +1,87 @@
+package automation
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/integration"
+)
+
+func TestWatchAffected(t *testing.T) {
+	dir := t.TempDir()
+	mapping := "conversion,default\nformat,0\ntype,0\nworkspace," + dir + "\nkick,1,100\nsnare.wav,1,101\n"
+	if err := os.WriteFile(filepath.Join(dir, "a.csv"), []byte(mapping), 0666); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"kick.wav", "snare.wav"} {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte{}, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := filepath.Join(dir, "out")
+	spec := &Processor{Pipelines: []ProcessPipeline{
+		{
+			BanksWorkspace: dir,
+			ScriptsWorkspace: dir,
+			Banks: []string{"a.bnk", "b.bnk"},
+			Scripts: []ProcessScript{{Type: TypeReplaceAudioSources, Script: "a.csv"}},
+			Integration: integration.IntegrationTypeDefault,
+			Output: out,
+		},
+		{
+			BanksWorkspace: out,
+			Banks: []string{"b.bnk"},
+			Integration: integration.IntegrationTypeDefault,
+			Output: filepath.Join(dir, "mix"),
+		},
+		{
+			BanksWorkspace: dir,
+			Banks: []string{"c.bnk", "d.bnk"},
+			Integration: integration.IntegrationTypeHelldivers2,
+			Output: filepath.Join(dir, "patch"),
+		},
+	}}
+	fspec := filepath.Join(dir, "proc.json")
+	s := newWatchSet(fspec, spec)
+	for _, f := range []string{"proc.json", "a.csv", "kick.wav", "snare.wav", "a.bnk", "c.bnk"} {
+		if _, in := s.files[filepath.Join(dir, f)]; !in {
+			t.Fatalf("Expecting %s to be watched, got %v", f, s.files)
+		}
+	}
+	if !slices.Equal(s.dirs(), []string{dir, out}) {
+		t.Fatalf("Unexpected watched directories %v", s.dirs())
+	}
+
+	banks := func(sub *Processor) [][]string {
+		if sub == nil {
+			return nil
+		}
+		b := [][]string{}
+		for _, p := range sub.Pipelines {
+			b = append(b, p.Banks)
+		}
+		return b
+	}
+	cases := []struct {
+		changed []string
+		expects [][]string
+	}{
+		{[]string{"a.bnk"}, [][]string{{"a.bnk"}}},
+		{[]string{"b.bnk"}, [][]string{{"b.bnk"}, {"b.bnk"}}},
+		{[]string{"kick.wav"}, [][]string{{"a.bnk", "b.bnk"}, {"b.bnk"}}},
+		{[]string{"d.bnk"}, [][]string{{"c.bnk", "d.bnk"}}},
+		{[]string{"unrelated.wav"}, nil},
+	}
+	for _, c := range cases {
+		changed := []string{}
+		for _, f := range c.changed {
+			changed = append(changed, filepath.Join(dir, f))
+		}
+		got := banks(s.affected(spec, changed))
+		if !slices.EqualFunc(got, c.expects, slices.Equal) || (got == nil) != (c.expects == nil) {
+			t.Fatalf("Changing %v: expecting %v, got %v", c.changed, c.expects, got)
+		}
+	}
+}
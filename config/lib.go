@@ -18,6 +18,8 @@ type Config struct {
 	IdDatabase     string `json:"idDatabase"`
 	Home           string `json:"home"`
 	Bookmark     []string `json:"bookmark"`
+	// Name lists / SoundbanksInfo loaded on startup
+	NameFiles    []string `json:"nameFiles"`
 }
 
 func initHome() (string, error) {
@@ -39,6 +41,7 @@ func New(c *Config) error {
 	}
 	c.Home = home
 	c.Bookmark = []string{}
+	c.NameFiles = []string{}
 	return nil
 }
 
@@ -70,6 +73,23 @@ func Load(c *Config) error {
 	return c.Check()
 }
 
+// Name files listed in the configuration file. Unlike Load, the
+// configuration file is neither created nor checked.
+func NameFiles() ([]string, error) {
+	blob, err := os.ReadFile(DefaultConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(blob, &c); err != nil {
+		return nil, err
+	}
+	return c.NameFiles, nil
+}
+
 func (c *Config) Save() error {
 	var data []byte
 
@@ -101,6 +121,20 @@ func (c *Config) Check() error {
 	}
 	c.Bookmark = clean
 
+	clean = make([]string, 0, len(c.NameFiles))
+	for _, s := range c.NameFiles {
+		_, err = os.Lstat(s)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Name file %s is not accessible. Removing it from config.json...", s))
+			continue
+		}
+		if slices.Contains(clean, s) {
+			continue
+		}
+		clean = append(clean, s)
+	}
+	c.NameFiles = clean
+
 	return nil
 }
 
@@ -30,9 +30,15 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.insertHierarchyStmt, err = db.PrepareContext(ctx, insertHierarchy); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertHierarchy: %w", err)
 	}
+	if q.insertNameStmt, err = db.PrepareContext(ctx, insertName); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertName: %w", err)
+	}
 	if q.insertSourceStmt, err = db.PrepareContext(ctx, insertSource); err != nil {
 		return nil, fmt.Errorf("error preparing query InsertSource: %w", err)
 	}
+	if q.namesStmt, err = db.PrepareContext(ctx, names); err != nil {
+		return nil, fmt.Errorf("error preparing query Names: %w", err)
+	}
 	if q.sourceIdStmt, err = db.PrepareContext(ctx, sourceId); err != nil {
 		return nil, fmt.Errorf("error preparing query SourceId: %w", err)
 	}
@@ -51,11 +57,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing insertHierarchyStmt: %w", cerr)
 		}
 	}
+	if q.insertNameStmt != nil {
+		if cerr := q.insertNameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertNameStmt: %w", cerr)
+		}
+	}
 	if q.insertSourceStmt != nil {
 		if cerr := q.insertSourceStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing insertSourceStmt: %w", cerr)
 		}
 	}
+	if q.namesStmt != nil {
+		if cerr := q.namesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing namesStmt: %w", cerr)
+		}
+	}
 	if q.sourceIdStmt != nil {
 		if cerr := q.sourceIdStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing sourceIdStmt: %w", cerr)
@@ -102,7 +118,9 @@ type Queries struct {
 	tx                  *sql.Tx
 	hierarchyIdStmt     *sql.Stmt
 	insertHierarchyStmt *sql.Stmt
+	insertNameStmt      *sql.Stmt
 	insertSourceStmt    *sql.Stmt
+	namesStmt           *sql.Stmt
 	sourceIdStmt        *sql.Stmt
 }
 
@@ -112,7 +130,9 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		tx:                  tx,
 		hierarchyIdStmt:     q.hierarchyIdStmt,
 		insertHierarchyStmt: q.insertHierarchyStmt,
+		insertNameStmt:      q.insertNameStmt,
 		insertSourceStmt:    q.insertSourceStmt,
+		namesStmt:           q.namesStmt,
 		sourceIdStmt:        q.sourceIdStmt,
 	}
 }
@@ -8,6 +8,11 @@ type Hierarchy struct {
 	Hid int64
 }
 
+type Name struct {
+	ID   int64
+	Name string
+}
+
 type Source struct {
 	Sid int64
 }
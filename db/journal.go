@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Dekr0/wwise-teller/db/id"
+)
+
+const deleteHierarchy = `DELETE FROM hierarchy WHERE hid = ?`
+
+const deleteSource = `DELETE FROM source WHERE sid = ?`
+
+// Record of hierarchy IDs and source IDs allocated by TryHid and TrySid under
+// a context (see WithJournal). IDs are recorded once the transaction that
+// allocates them is committed. Scripts commit their allocations on their own
+// so the journal is used to take them back when the changes that use them are
+// discarded.
+type Journal struct {
+	l       sync.Mutex
+	hids    []uint32
+	sids    []uint32
+	// IDs allocated in database transactions that are yet committed
+	pending map[*id.Queries]*journalTx
+}
+
+type journalTx struct {
+	hids []uint32
+	sids []uint32
+}
+
+// Position in a journal. Allocations after a mark can be rolled back without
+// touching allocations before it.
+type JournalMark struct {
+	hids int
+	sids int
+}
+
+type journalKey struct{}
+
+func WithJournal(ctx context.Context, j *Journal) context.Context {
+	return context.WithValue(ctx, journalKey{}, j)
+}
+
+func journalFrom(ctx context.Context) *Journal {
+	j, _ := ctx.Value(journalKey{}).(*Journal)
+	return j
+}
+
+// Hold back IDs allocated through a transaction query until the transaction
+// is committed. IDs of a rolled back transaction are never recorded.
+func (j *Journal) begin(q *id.Queries) {
+	j.l.Lock()
+	defer j.l.Unlock()
+	if j.pending == nil {
+		j.pending = make(map[*id.Queries]*journalTx)
+	}
+	j.pending[q] = &journalTx{}
+}
+
+func (j *Journal) end(q *id.Queries, committed bool) {
+	j.l.Lock()
+	defer j.l.Unlock()
+	tx, in := j.pending[q]
+	if !in {
+		return
+	}
+	delete(j.pending, q)
+	if committed {
+		j.hids = append(j.hids, tx.hids...)
+		j.sids = append(j.sids, tx.sids...)
+	}
+}
+
+func recordHid(ctx context.Context, q *id.Queries, hid uint32) {
+	if j := journalFrom(ctx); j != nil {
+		j.l.Lock()
+		if tx, in := j.pending[q]; in {
+			tx.hids = append(tx.hids, hid)
+		} else {
+			j.hids = append(j.hids, hid)
+		}
+		j.l.Unlock()
+	}
+}
+
+func recordSid(ctx context.Context, q *id.Queries, sid uint32) {
+	if j := journalFrom(ctx); j != nil {
+		j.l.Lock()
+		if tx, in := j.pending[q]; in {
+			tx.sids = append(tx.sids, sid)
+		} else {
+			j.sids = append(j.sids, sid)
+		}
+		j.l.Unlock()
+	}
+}
+
+func (j *Journal) Mark() JournalMark {
+	j.l.Lock()
+	defer j.l.Unlock()
+	return JournalMark{len(j.hids), len(j.sids)}
+}
+
+// Return # of hierarchy IDs and # of source IDs recorded
+func (j *Journal) Len() (int, int) {
+	j.l.Lock()
+	defer j.l.Unlock()
+	return len(j.hids), len(j.sids)
+}
+
+// Remove all IDs allocated after the mark from the database
+func (j *Journal) RollbackTo(ctx context.Context, m JournalMark) error {
+	j.l.Lock()
+	defer j.l.Unlock()
+	if m.hids >= len(j.hids) && m.sids >= len(j.sids) {
+		return nil
+	}
+	if err := Ping(); err != nil {
+		return err
+	}
+	tx, err := WwiseIdDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Failed begin Wwise sound bank ID database transaction: %w", err)
+	}
+	for _, hid := range j.hids[m.hids:] {
+		if _, err := tx.ExecContext(ctx, deleteHierarchy, int64(hid)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Failed to remove hierarchy ID %d from Wwise sound bank database: %w", hid, err)
+		}
+	}
+	for _, sid := range j.sids[m.sids:] {
+		if _, err := tx.ExecContext(ctx, deleteSource, int64(sid)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Failed to remove source ID %d from Wwise sound bank database: %w", sid, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Failed to commit database transaction: %w", err)
+	}
+	j.hids = j.hids[:m.hids]
+	j.sids = j.sids[:m.sids]
+	return nil
+}
+
+// Remove all recorded IDs from the database
+func (j *Journal) Rollback(ctx context.Context) error {
+	return j.RollbackTo(ctx, JournalMark{})
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestJournal(t *testing.T) {
+	conn, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "id.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"CREATE TABLE hierarchy (hid INTEGER PRIMARY KEY)",
+		"CREATE TABLE source (sid INTEGER PRIMARY KEY)",
+	} {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prev := WwiseIdDB
+	WwiseIdDB = conn
+	defer func() {
+		WwiseIdDB = prev
+		conn.Close()
+	}()
+
+	j := &Journal{}
+	ctx := WithJournal(context.Background(), j)
+	allocate := func() {
+		q, closeConn, commit, rollback, err := CreateConnWithTxQuery(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer closeConn()
+		if _, err := TryHid(ctx, q); err != nil {
+			rollback()
+			t.Fatal(err)
+		}
+		if _, err := TrySid(ctx, q); err != nil {
+			rollback()
+			t.Fatal(err)
+		}
+		if err := commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count := func() (int, int) {
+		var hids, sids int
+		conn.QueryRow("SELECT COUNT(*) FROM hierarchy").Scan(&hids)
+		conn.QueryRow("SELECT COUNT(*) FROM source").Scan(&sids)
+		return hids, sids
+	}
+
+	allocate()
+	m := j.Mark()
+
+	// IDs of a rolled back transaction never reach the journal
+	q, closeConn, _, rollback, err := CreateConnWithTxQuery(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TryHid(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+	rollback()
+	closeConn()
+	if hids, sids := j.Len(); hids != 1 || sids != 1 {
+		t.Fatalf("Expecting 1 hierarchy ID and 1 source ID in journal, got %d and %d", hids, sids)
+	}
+
+	allocate()
+	allocate()
+	if hids, sids := count(); hids != 3 || sids != 3 {
+		t.Fatalf("Expecting 3 hierarchy IDs and 3 source IDs, got %d and %d", hids, sids)
+	}
+
+	if err := j.RollbackTo(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+	if hids, sids := count(); hids != 1 || sids != 1 {
+		t.Fatalf("Expecting 1 hierarchy ID and 1 source ID after rollback to mark, got %d and %d", hids, sids)
+	}
+	if err := j.Rollback(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if hids, sids := count(); hids != 0 || sids != 0 {
+		t.Fatalf("Expecting empty database after rollback, got %d and %d", hids, sids)
+	}
+}
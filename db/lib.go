@@ -87,6 +87,28 @@ func InitDatabase() (err error) {
 	return nil
 }
 
+// Open a throwaway in-memory database in place of the Wwise sound bank ID
+// database. IDs allocated from it are placeholders that never reach the real
+// database (e.g., planning process pipelines).
+func InitMemoryDatabase() (err error) {
+	WwiseIdDB, err = sql.Open("sqlite3", "file::memory:")
+	if err != nil {
+		return fmt.Errorf("Failed to open in-memory database: %w", err)
+	}
+	// Every connection has its own in-memory database
+	WwiseIdDB.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"CREATE TABLE hierarchy (hid INTEGER PRIMARY KEY)",
+		"CREATE TABLE source (sid INTEGER PRIMARY KEY)",
+	} {
+		if _, err := WwiseIdDB.Exec(stmt); err != nil {
+			return fmt.Errorf("Failed to initialize in-memory database: %w", err)
+		}
+	}
+	slog.Info("Opened in-memory Wwise sound bank ID database.")
+	return nil
+}
+
 func CloseDatabase() {
 	if WwiseIdDB != nil {
 		WwiseIdDB.Close()
@@ -127,7 +149,15 @@ func CreateConnWithTxQuery(ctx context.Context) (*id.Queries, func(), func() err
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("Failed begin Wwise sound bank ID database transaction: %w", err)
 	}
+	q := id.New(conn).WithTx(tx)
+	j := journalFrom(ctx)
+	if j != nil {
+		j.begin(q)
+	}
 	closeConn := func() { 
+		if j != nil {
+			j.end(q, false)
+		}
 		if err := conn.Close(); err != nil {
 			slog.Error("Failed to close database connection", "error", err)
 		}
@@ -136,14 +166,20 @@ func CreateConnWithTxQuery(ctx context.Context) (*id.Queries, func(), func() err
 		if err := tx.Commit(); err != nil {
 			return fmt.Errorf("Failed to commit database transaction: %w", err)
 		}
+		if j != nil {
+			j.end(q, true)
+		}
 		return nil
 	}
 	rollback := func() {
+		if j != nil {
+			j.end(q, false)
+		}
 		if err := tx.Rollback(); err != nil {
 			slog.Error("Failed to rollback database transaction. Please manually rollback database by using the backup database", "error", err)
 		}
 	}
-	return id.New(conn).WithTx(tx), closeConn, commit, rollback, nil
+	return q, closeConn, commit, rollback, nil
 }
 
 func TrySid(ctx context.Context, q *id.Queries) (uint32, error) {
@@ -176,6 +212,7 @@ func TrySid(ctx context.Context, q *id.Queries) (uint32, error) {
 	if sid == 0 {
 		return 0, errors.New("Source ID uses invalid value of 0.")
 	}
+	recordSid(ctx, q, sid)
 	return sid, nil
 }
 
@@ -209,6 +246,7 @@ func TryHid(ctx context.Context, q *id.Queries) (uint32, error) {
 	if hid == 0 {
 		return 0, errors.New("Source ID uses invalid value of 0.")
 	}
+	recordHid(ctx, q, hid)
 	return hid, nil
 }
 
@@ -55,3 +55,35 @@ func TestConcurrent(t *testing.T) {
 
 	w.Wait()
 }
+
+func TestMemoryDatabase(t *testing.T) {
+	prev := WwiseIdDB
+	if err := InitMemoryDatabase(); err != nil {
+		t.Fatal(err)
+	}
+	conn := WwiseIdDB
+	defer func() {
+		WwiseIdDB = prev
+		conn.Close()
+	}()
+
+	bg := context.Background()
+	hids := make([]uint32, 16, 16)
+	closeConn, commit, rollback, err := AllocateHids(bg, hids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := commit(); err != nil {
+		rollback()
+		t.Fatal(err)
+	}
+	closeConn()
+
+	var count int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM hierarchy").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != len(hids) {
+		t.Fatalf("Expecting %d hierarchy IDs in in-memory database, got %d", len(hids), count)
+	}
+}
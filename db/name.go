@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dekr0/wwise-teller/db/id"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Older Wwise sound bank databases have no name table. Keep in sync with
+// db/schema.sql.
+const createNameTable = `CREATE TABLE IF NOT EXISTS name (
+	id   INTEGER PRIMARY KEY,
+	name TEXT NOT NULL
+)`
+
+func initNameTable(ctx context.Context) error {
+	if err := Ping(); err != nil {
+		return err
+	}
+	if _, err := WwiseIdDB.ExecContext(ctx, createNameTable); err != nil {
+		return fmt.Errorf("Failed to create name table in Wwise sound bank database: %w", err)
+	}
+	return nil
+}
+
+// Persist a user added name. Return its short ID.
+func InsertName(ctx context.Context, name string) (uint32, error) {
+	if err := initNameTable(ctx); err != nil {
+		return 0, err
+	}
+	q, closeConn, err := createConnWithQuery(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer closeConn()
+	shortID := wwise.ShortID(name)
+	if err := q.InsertName(ctx, id.InsertNameParams{ID: int64(shortID), Name: name}); err != nil {
+		return 0, fmt.Errorf("Failed to insert name %s into Wwise sound bank database: %w", name, err)
+	}
+	return shortID, nil
+}
+
+// Load all user added names into a name database. Return the number of names
+// loaded.
+func LoadNames(ctx context.Context, names *wwise.NameDB) (int, error) {
+	if err := initNameTable(ctx); err != nil {
+		return 0, err
+	}
+	q, closeConn, err := createConnWithQuery(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer closeConn()
+	rows, err := q.Names(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to query names from Wwise sound bank database: %w", err)
+	}
+	for _, row := range rows {
+		names.Set(uint32(row.ID), row.Name)
+	}
+	return len(rows), nil
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestNames(t *testing.T) {
+	prev := WwiseIdDB
+	if err := InitMemoryDatabase(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		WwiseIdDB.Close()
+		WwiseIdDB = prev
+	}()
+
+	ctx := context.Background()
+	for _, name := range []string{"Play_Gun", "Stop_Gun", "Play_Gun"} {
+		if _, err := InsertName(ctx, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := wwise.NewNameDB()
+	count, err := LoadNames(ctx, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("Expecting 2 names, got %d", count)
+	}
+	if name, _ := names.Name(wwise.ShortID("Stop_Gun")); name != "Stop_Gun" {
+		t.Fatalf("Unexpected name %s", name)
+	}
+}
@@ -0,0 +1,5 @@
+-- name: InsertName :exec
+INSERT OR REPLACE INTO name (id, name) VALUES (?, ?);
+
+-- name: Names :many
+SELECT id, name FROM name;
@@ -0,0 +1,8 @@
+CREATE TABLE hierarchy (hid INTEGER PRIMARY KEY);
+
+CREATE TABLE source (sid INTEGER PRIMARY KEY);
+
+CREATE TABLE IF NOT EXISTS name (
+	id   INTEGER PRIMARY KEY,
+	name TEXT NOT NULL
+);
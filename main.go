@@ -2,24 +2,141 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
 	"time"
 
 	"github.com/Dekr0/wwise-teller/automation"
+	"github.com/Dekr0/wwise-teller/config"
 	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/parser"
 	"github.com/Dekr0/wwise-teller/ui"
 	"github.com/Dekr0/wwise-teller/utils"
 	"github.com/Dekr0/wwise-teller/waapi"
+	"github.com/Dekr0/wwise-teller/wwise"
 )
 
 func main() {
 	proc := flag.String("proc", "", "Filepath to sound bank processor pipelines specification")
 	procDeadline := flag.Uint64("deadline", 16, "Deadline in seconds of running sound bank processor pipelines")
+	lint := flag.Bool("lint", false, "Validate sound banks listed after all flags and exit")
+	loudness := flag.Bool("loudness", false, "Print loudness of every embedded audio source of sound banks listed after all flags as JSON and exit")
+	watch := flag.Bool("watch", false, "With -proc, keep running the processor whenever its specification, scripts, sound banks or audio inputs change until interrupted. -deadline applies to each run.")
+	plan := flag.Bool("plan", false, "With -proc, print what the processor would do as JSON without writing sound banks, allocating IDs or converting audio")
+	procSchema := flag.Bool("proc-schema", false, "Print JSON schema of sound bank processor pipelines specification and exit")
+	migrateProc := flag.String("migrate-proc", "", "Print the given version 0 sound bank processor pipelines specification as the current version and exit")
+	pruneCache := flag.Duration("prune-cache", -1, "Remove converted WEM files not used within the given duration (0 removes all) from the conversion cache and exit")
+	nameFiles := []string{}
+	flag.Func("names", "With -plan, label IDs with names from a name list / SoundbanksInfo file in addition to name files in config.json. Can be repeated.", func(path string) error {
+		nameFiles = append(nameFiles, path)
+		return nil
+	})
 
 	flag.Parse()
 
+	if *plan && *proc == "" {
+		usageError("-plan requires -proc")
+	}
+	if *watch && *proc == "" {
+		usageError("-watch requires -proc")
+	}
+
+	if *lint {
+		if !automation.LintBanks(context.Background(), flag.Args()) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *loudness {
+		reports, ok := automation.AnalyzeBanksLoudness(context.Background(), flag.Args())
+		waapi.CleanWEMCache()
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		if err := e.Encode(reports); err != nil {
+			slog.Error("Failed to print loudness", "error", err)
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *procSchema {
+		schema, err := automation.ProcessorSchema()
+		if err != nil {
+			slog.Error("Failed to generate processor schema", "error", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(schema))
+		return
+	}
+
+	if *migrateProc != "" {
+		blob, err := os.ReadFile(*migrateProc)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to open processor %s", *migrateProc), "error", err)
+			os.Exit(1)
+		}
+		migrated, err := automation.MigrateProcessorV0(blob)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to migrate processor %s", *migrateProc), "error", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(migrated))
+		return
+	}
+
+	if *pruneCache >= 0 {
+		cache, err := waapi.OpenConversionCache()
+		if err != nil {
+			slog.Error("Failed to open conversion cache", "error", err)
+			os.Exit(1)
+		}
+		count, size, err := cache.Prune(*pruneCache)
+		slog.Info(fmt.Sprintf("Removed %d entries (%d bytes) from conversion cache %s", count, size, cache.Dir))
+		if err != nil {
+			slog.Error("Failed to prune conversion cache", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *proc != "" && *plan {
+		defer utils.CleanTmp()
+		utils.InitTmp()
+		// Names are read from the Wwise sound bank database before it's replaced
+		// by the in-memory database
+		names := loadNames(nameFiles)
+		if err := db.InitMemoryDatabase(); err != nil {
+			slog.Error("Failed to initialize in-memory database", "error", err)
+			os.Exit(1)
+		}
+		ctx := context.Background()
+		if *procDeadline != 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Second * time.Duration(*procDeadline))
+			defer cancel()
+		}
+		p, err := automation.Plan(ctx, *proc, names)
+		if err != nil {
+			slog.Error("Failed to plan processor", "error", err)
+			os.Exit(1)
+		}
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		if err := e.Encode(p); err != nil {
+			slog.Error("Failed to print plan", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *proc != "" {
 		defer utils.CleanTmp()
 		utils.InitTmp()
@@ -27,7 +144,13 @@ func main() {
 			slog.Error("Failed to initialize Wwise sound bank database", "error", err)
 			os.Exit(1)
 		}
-		if *procDeadline == 0 {
+		if *watch {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			if err := automation.Watch(ctx, *proc, time.Second * time.Duration(*procDeadline)); err != nil {
+				slog.Error("Failed to watch processor", "error", err)
+			}
+		} else if *procDeadline == 0 {
 			automation.Process(context.Background(), *proc)
 		} else {
 			ctx, cancel := context.WithTimeout(context.Background(), time.Second * time.Duration(*procDeadline))
@@ -44,3 +167,39 @@ func main() {
 	utils.CleanTmp()
 	waapi.CleanWEMCache()
 }
+
+// Load names added by users in the Wwise sound bank database (if configured),
+// name files in config.json and the given name files. A source that fails to
+// load is skipped.
+func loadNames(paths []string) *wwise.NameDB {
+	names := wwise.NewNameDB()
+	if os.Getenv(db.DatabaseEnv) != "" {
+		count, err := db.LoadNames(context.Background(), names)
+		if err != nil {
+			slog.Error("Failed to load names from Wwise sound bank database", "error", err)
+		} else {
+			slog.Info(fmt.Sprintf("Loaded %d names from Wwise sound bank database", count))
+		}
+		db.CloseDatabase()
+	}
+	configured, err := config.NameFiles()
+	if err != nil {
+		slog.Warn("Failed to read name files from configuration file", "error", err)
+	}
+	for _, path := range append(configured, paths...) {
+		count, err := parser.ParseNames(path, names)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to load names from %s", path), "error", err)
+			continue
+		}
+		slog.Info(fmt.Sprintf("Loaded %d names from %s", count, path))
+	}
+	return names
+}
+
+// Report misuse of flags the same way the flag package does and exit
+func usageError(msg string) {
+	fmt.Fprintln(flag.CommandLine.Output(), msg)
+	flag.Usage()
+	os.Exit(2)
+}
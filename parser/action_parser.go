@@ -245,7 +245,7 @@ func ParseActionResumeSpecificParam(r *wio.Reader, v int) wwise.ActionSpecificPa
 
 func ParseActionSetPropSpecificParam(r *wio.Reader, v int) wwise.ActionSpecificParam {
 	return &wwise.ActionSetPropSpecificParam{
-		Prop: r.U8Unsafe(),
+		EnumValueMeaning: r.U8Unsafe(),
 		Base: r.F32Unsafe(),
 		Min: r.F32Unsafe(),
 		Max: r.F32Unsafe(),
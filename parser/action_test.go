@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestNewActionEncodeRoundTrip(t *testing.T) {
+	for _, v := range []int{141, 154} {
+		for at := range wwise.ActionTypeName {
+			if at == 0 {
+				continue
+			}
+			a, err := wwise.NewAction(uint32(at) + 100, at, 1, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := roundTrip(t, a, v, ParseAction)
+			if d.Type() != at || d.IdExt != 1 || d.ActionParam.Type() != a.ActionParam.Type() {
+				t.Fatalf("Unexpected action %#x in version %d: %+v", at, v, d)
+			}
+			if d.ActionParam.SpecificParam().Type() != a.ActionParam.SpecificParam().Type() {
+				t.Fatalf("Unexpected specific parameter of action %#x in version %d", at, v)
+			}
+		}
+		if _, err := wwise.NewAction(1, 0x18, 1, v); err == nil {
+			t.Fatal("Expecting error on unknown action type")
+		}
+
+		e := wwise.NewEvent(wwise.ShortID("Play_MyNewSound"))
+		d := roundTrip(t, e, v, ParseEvent)
+		if d.Id != e.Id || len(d.ActionIDs) != 0 {
+			t.Fatalf("Unexpected event %+v", d)
+		}
+		e.NewAction(10)
+		e.NumActionIDs.Set(1)
+		d = roundTrip(t, e, v, ParseEvent)
+		if !slices.Equal(d.ActionIDs, []uint32{10}) {
+			t.Fatalf("Unexpected event actions %v", d.ActionIDs)
+		}
+	}
+}
+
+func TestNewActionValueMeaning(t *testing.T) {
+	for _, v := range []int{141, 154} {
+		for at, expect := range map[uint16]uint8{
+			0x08: wwise.ValueMeaningIndependent,
+			0x09: wwise.ValueMeaningDefault,
+			0x0A: wwise.ValueMeaningIndependent,
+			0x0B: wwise.ValueMeaningDefault,
+			0x0C: wwise.ValueMeaningIndependent,
+			0x0D: wwise.ValueMeaningDefault,
+			0x0E: wwise.ValueMeaningIndependent,
+			0x0F: wwise.ValueMeaningDefault,
+			0x13: wwise.ValueMeaningIndependent,
+			0x14: wwise.ValueMeaningDefault,
+			0x20: wwise.ValueMeaningIndependent,
+			0x30: wwise.ValueMeaningDefault,
+		} {
+			a, err := wwise.NewAction(uint32(at) + 100, at, 1, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := roundTrip(t, a, v, ParseAction)
+			var meaning uint8
+			switch s := d.ActionParam.SpecificParam().(type) {
+			case *wwise.ActionSetPropSpecificParam:
+				meaning = s.EnumValueMeaning
+			case *wwise.ActionSetGameParameterSpecificParam:
+				meaning = s.EnumValueMeaning
+			default:
+				t.Fatalf("Unexpected specific parameter %T of action %#x", s, at)
+			}
+			if meaning != expect {
+				t.Fatalf("Expecting value meaning %d of action %#x in version %d, got %d", expect, at, v, meaning)
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package parser
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wio"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Encode a hierarchy object, decode the encoded data and encode the decoded
+// object again.
+func roundTrip[T wwise.HircObj](t *testing.T, o T, v int, parse func(uint32, *wio.Reader, int) T) T {
+	t.Helper()
+	b := o.Encode(v)
+	data := b[wwise.SizeOfHircObjHeader:]
+	d := parse(uint32(len(data)), wio.NewReader(bytes.NewReader(data), wio.ByteOrder), v)
+	if !bytes.Equal(b, d.Encode(v)) {
+		t.Fatalf("%T is not the same after encoding round trip in version %d", o, v)
+	}
+	return d
+}
@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func newTestBaseParam(parent uint32) wwise.BaseParameter {
+	b := wwise.BaseParameter{DirectParentId: parent}
+	b.StateProp.NumStateProps.Set(0)
+	b.StateGroup.NumStateGroups.Set(0)
+	return b
+}
+
+func TestMusicEncodeRoundTrip(t *testing.T) {
+	for _, v := range []int{141, 154} {
+		seg := &wwise.MusicSegment{Id: 10, BaseParam: newTestBaseParam(1)}
+		seg.Children.Children = []uint32{100, 101}
+		seg.MeterInfo = wwise.MeterInfo{GridPeriod: 1000, Tempo: 120, TimeSigNumBeatsBar: 4, TimeSigBeatVal: 4}
+		seg.Stingers = append(seg.Stingers, wwise.NewStinger(500, 11))
+		seg.Duration = 8000
+		seg.Markers = []wwise.MusicSegmentMarker{
+			{ID: 1, Position: 0, MarkerName: []byte("Entry\x00")},
+			{ID: 2, Position: 8000, MarkerName: []byte("Exit\x00")},
+		}
+		dseg := roundTrip(t, seg, v, ParseMusicSegment)
+		if !slices.Equal(dseg.Children.Children, seg.Children.Children) || !slices.Equal(dseg.Stingers, seg.Stingers) {
+			t.Fatalf("Unexpected music segment %+v", dseg)
+		}
+
+		cntr := &wwise.MusicRanSeqCntr{Id: 1, BaseParam: newTestBaseParam(0), PlayListNode: wwise.NewMusicPlayListGroup(200, wwise.MusicRSTypeContinuousSequence)}
+		cntr.Children.Children = []uint32{10, 11}
+		cntr.Stingers = []wwise.Stinger{wwise.NewStinger(500, 11)}
+		if err := cntr.AddPlayListSegment(200, 201, 10); err != nil {
+			t.Fatal(err)
+		}
+		if err := cntr.AddPlayListGroup(200, 202, wwise.MusicRSTypeStepRandom); err != nil {
+			t.Fatal(err)
+		}
+		if err := cntr.AddPlayListSegment(202, 203, 11); err != nil {
+			t.Fatal(err)
+		}
+		g := cntr.PlayListNode.Node(202)
+		g.Loop, g.Weight, g.AvoidRepeatCount, g.Shuffle = 0, 25000, 1, 1
+		if err := cntr.AddTransitionRule(wwise.NewMusicTransitionRule(
+			[]uint32{wwise.MusicTransitionAny}, []uint32{wwise.MusicTransitionAny},
+		)); err != nil {
+			t.Fatal(err)
+		}
+		rule := wwise.NewMusicTransitionRule([]uint32{10}, []uint32{11})
+		rule.TransitionSourceRule.SyncType = 2
+		rule.TransitionSourceRule.TransitionTime = 500
+		rule.TransitionDestRule.FadeCurve = uint32(wwise.InterpCurveTypeSCurve)
+		rule.TransitionDestRule.JumpToType = wwise.JumpToTypeSpecificItem
+		rule.TransitionDestRule.JumpToID = 203
+		rule.SetTransitionSegment(11)
+		rule.TransitionObj.FadeInParam.TransitionTime = 250
+		if err := cntr.AddTransitionRule(rule); err != nil {
+			t.Fatal(err)
+		}
+		dcntr := roundTrip(t, cntr, v, ParseMusicRanSeqCntr)
+		if !reflect.DeepEqual(dcntr.PlayListNode, cntr.PlayListNode) || !reflect.DeepEqual(dcntr.TransitionRules, cntr.TransitionRules) {
+			t.Fatalf("Unexpected music random / sequence container %+v", dcntr)
+		}
+		if err := cntr.RemoveTransitionRule(1); err != nil {
+			t.Fatal(err)
+		}
+		cntr.PlayListNode.RemoveNode(202)
+		roundTrip(t, cntr, v, ParseMusicRanSeqCntr)
+
+		sw := &wwise.MusicSwitchCntr{Id: 2, BaseParam: newTestBaseParam(0), DecisionTreeData: []byte{1, 0, 0, 0, 2, 0, 0, 0}}
+		sw.Children.Children = []uint32{1}
+		sw.TransitionRules = append(sw.TransitionRules, wwise.NewMusicTransitionRule(
+			[]uint32{wwise.MusicTransitionNothing}, []uint32{1},
+		))
+		dsw := roundTrip(t, sw, v, ParseMusicSwitchCntr)
+		if !reflect.DeepEqual(dsw.TransitionRules, sw.TransitionRules) {
+			t.Fatalf("Unexpected music switch container %+v", dsw)
+		}
+	}
+}
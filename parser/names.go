@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Load names from a file into a name database. Files ending with .xml or
+// .json are treated as SoundbanksInfo generated by Wwise. Anything else (e.g.
+// wwnames.txt) is treated as a name list with one name per line.
+// Return the number of names loaded.
+func ParseNames(path string, names *wwise.NameDB) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to open name file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var count int
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		count, err = ParseSoundbanksInfoXML(f, names)
+	case ".json":
+		count, err = ParseSoundbanksInfoJSON(f, names)
+	default:
+		count, err = ParseNameList(f, names)
+	}
+	if err != nil {
+		return count, fmt.Errorf("Failed to parse name file %s: %w", path, err)
+	}
+	return count, nil
+}
+
+// One candidate name per line. Empty lines and lines starting with # are
+// skipped.
+func ParseNameList(r io.Reader, names *wwise.NameDB) (int, error) {
+	count := 0
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 4096), 1 << 20)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names.Add(line)
+		count += 1
+	}
+	return count, s.Err()
+}
+
+// Every element with an Id attribute is named either by its Name attribute
+// (events, buses, game syncs, ...) or by its ShortName child element (sound
+// banks, media files).
+func ParseSoundbanksInfoXML(r io.Reader, names *wwise.NameDB) (int, error) {
+	count := 0
+	d := xml.NewDecoder(r)
+	// ID of each open element. 0 if the element does not have an ID
+	ids := []uint32{}
+	inShortName := false
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return count, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			var id uint64
+			var name string
+			for _, attr := range t.Attr {
+				switch attr.Name.Local {
+				case "Id":
+					id, _ = strconv.ParseUint(attr.Value, 10, 32)
+				case "Name":
+					name = attr.Value
+				}
+			}
+			if id != 0 && name != "" {
+				names.Set(uint32(id), name)
+				count += 1
+			}
+			inShortName = t.Name.Local == "ShortName"
+			ids = append(ids, uint32(id))
+		case xml.CharData:
+			if !inShortName || len(ids) < 2 {
+				continue
+			}
+			if owner := ids[len(ids) - 2]; owner != 0 {
+				if name := strings.TrimSpace(string(t)); name != "" {
+					names.Set(owner, name)
+					count += 1
+				}
+			}
+		case xml.EndElement:
+			inShortName = false
+			if len(ids) > 0 {
+				ids = ids[:len(ids) - 1]
+			}
+		}
+	}
+	return count, nil
+}
+
+// Every object with an Id field is named either by its Name or ShortName
+// field.
+func ParseSoundbanksInfoJSON(r io.Reader, names *wwise.NameDB) (int, error) {
+	var root any
+	if err := json.NewDecoder(r).Decode(&root); err != nil {
+		return 0, err
+	}
+	count := 0
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			var id uint64
+			switch i := v["Id"].(type) {
+			case string:
+				id, _ = strconv.ParseUint(i, 10, 32)
+			case float64:
+				id = uint64(i)
+			}
+			if id != 0 {
+				name, _ := v["Name"].(string)
+				if name == "" {
+					name, _ = v["ShortName"].(string)
+				}
+				if name != "" {
+					names.Set(uint32(id), name)
+					count += 1
+				}
+			}
+			for _, c := range v {
+				walk(c)
+			}
+		case []any:
+			for _, c := range v {
+				walk(c)
+			}
+		}
+	}
+	walk(root)
+	return count, nil
+}
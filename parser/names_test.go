@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestParseNames(t *testing.T) {
+	names := wwise.NewNameDB()
+
+	list := "# Buses\n\nMaster Audio Bus\n  play_footstep  \n"
+	count, err := ParseNameList(strings.NewReader(list), names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("Expect 2 names from name list, got %d", count)
+	}
+	if id := wwise.ShortID("master audio bus"); id != 3803692087 {
+		t.Fatalf("Expect short ID of Master Audio Bus to be 3803692087, got %d", id)
+	}
+	if name, in := names.Name(3803692087); !in || name != "Master Audio Bus" {
+		t.Fatalf("Expect Master Audio Bus, got %q", name)
+	}
+	if wwise.ShortID("PLAY_FOOTSTEP") != wwise.ShortID("play_footstep") {
+		t.Fatal("Short ID should be case insensitive")
+	}
+
+	xml := `<?xml version="1.0" encoding="utf-8"?>
+<SoundBanksInfo>
+	<SoundBanks>
+		<SoundBank Id="1355168291" Language="SFX">
+			<ShortName>Init</ShortName>
+			<IncludedEvents>
+				<Event Id="1000" Name="Play_Gun"/>
+			</IncludedEvents>
+			<Media>
+				<File Id="2000" Language="SFX">
+					<ShortName>gun_01.wav</ShortName>
+				</File>
+			</Media>
+		</SoundBank>
+	</SoundBanks>
+</SoundBanksInfo>`
+	if count, err = ParseSoundbanksInfoXML(strings.NewReader(xml), names); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Fatalf("Expect 3 names from SoundbanksInfo.xml, got %d", count)
+	}
+	for id, expect := range map[uint32]string{1355168291: "Init", 1000: "Play_Gun", 2000: "gun_01.wav"} {
+		if name, _ := names.Name(id); name != expect {
+			t.Fatalf("Expect %d to be %s, got %q", id, expect, name)
+		}
+	}
+
+	json := `{"SoundBanksInfo": {"SoundBanks": [{"Id": "3000", "ShortName": "Weapons", "Events": [{"Id": "4000", "Name": "Stop_Gun"}]}]}}`
+	if count, err = ParseSoundbanksInfoJSON(strings.NewReader(json), names); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("Expect 2 names from SoundbanksInfo.json, got %d", count)
+	}
+	if label := names.Label(4000); label != "Stop_Gun (4000)" {
+		t.Fatalf("Expect label Stop_Gun (4000), got %s", label)
+	}
+	if label := names.Label(5000); label != "5000" {
+		t.Fatalf("Expect label 5000, got %s", label)
+	}
+}
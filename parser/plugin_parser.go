@@ -1,14 +1,36 @@
 package parser
 
 import (
+	"fmt"
+	"log/slog"
+
 	"github.com/Dekr0/wwise-teller/assert"
 	"github.com/Dekr0/wwise-teller/wio"
 	"github.com/Dekr0/wwise-teller/wwise"
 )
 
+// Parameter block of a built-in FX that is shorter than the decoded layout is
+// kept as is.
+var pluginParamMinSize map[uint32]uint32 = map[uint32]uint32{
+	0x006A0003: wwise.SizeOfDelayFX,
+	0x00730003: wwise.SizeOfMatrixReverb,
+	0x00760003: wwise.SizeOfRoomVerb,
+	0x007D0003: wwise.SizeOfFlanger,
+	0x007E0003: wwise.SizeOfGuitarDistortion,
+	0x007F0003: wwise.SizeOfConvolutionReverb,
+	0x00820003: wwise.SizeOfTimeStretch,
+	0x00830003: wwise.SizeOfTremolo,
+	0x00870003: wwise.SizeOfStereoDelay,
+	0x00880003: wwise.SizeOfPitchShifter,
+	0x008A0003: wwise.SizeOfHarmonizer,
+}
+
 func ParsePluginParam(r *wio.Reader, p *wwise.PluginParam, pluginId uint32, v int) {
 	p.PluginParamSize = r.U32Unsafe()
 	p.PluginParamData = &wwise.FxPlaceholder{Data: []byte{}}
+	if size, in := pluginParamMinSize[pluginId]; in && p.PluginParamSize < size {
+		pluginId = 0
+	}
 	if p.PluginParamSize > 0 {
 		switch pluginId {
 		case 0x00690003:
@@ -29,6 +51,46 @@ func ParsePluginParam(r *wio.Reader, p *wwise.PluginParam, pluginId uint32, v in
 		case 0x008B0003:
 			p.PluginParamData = &wwise.GainFX{}
 		 	ParseGainFX(r, p.PluginParamSize, p.PluginParamData.(*wwise.GainFX))
+		case 0x006A0003:
+			p.PluginParamData = &wwise.DelayFX{}
+			ParseDelayFX(r, p.PluginParamSize, p.PluginParamData.(*wwise.DelayFX))
+		case 0x00730003:
+			p.PluginParamData = &wwise.MatrixReverb{}
+			ParseMatrixReverb(r, p.PluginParamSize, p.PluginParamData.(*wwise.MatrixReverb))
+		case 0x00760003:
+			p.PluginParamData = &wwise.RoomVerb{}
+			ParseRoomVerb(r, p.PluginParamSize, p.PluginParamData.(*wwise.RoomVerb))
+		case 0x007D0003:
+			p.PluginParamData = &wwise.Flanger{}
+			ParseFlanger(r, p.PluginParamSize, p.PluginParamData.(*wwise.Flanger))
+		case 0x007E0003:
+			p.PluginParamData = &wwise.GuitarDistortion{}
+			ParseGuitarDistortion(r, p.PluginParamSize, p.PluginParamData.(*wwise.GuitarDistortion))
+		case 0x007F0003:
+			c := &wwise.ConvolutionReverb{}
+			if err := ParseConvolutionReverb(r, p.PluginParamSize, c, v); err != nil {
+				slog.Warn("Convolution reverb parameters are kept as is", "error", err)
+				p.PluginParamData = &wwise.FxPlaceholder{
+					Data: r.ReadNUnsafe(uint64(p.PluginParamSize), 0),
+				}
+			} else {
+				p.PluginParamData = c
+			}
+		case 0x00820003:
+			p.PluginParamData = &wwise.TimeStretch{}
+			ParseTimeStretch(r, p.PluginParamSize, p.PluginParamData.(*wwise.TimeStretch))
+		case 0x00830003:
+			p.PluginParamData = &wwise.Tremolo{}
+			ParseTremolo(r, p.PluginParamSize, p.PluginParamData.(*wwise.Tremolo))
+		case 0x00870003:
+			p.PluginParamData = &wwise.StereoDelay{}
+			ParseStereoDelay(r, p.PluginParamSize, p.PluginParamData.(*wwise.StereoDelay))
+		case 0x00880003:
+			p.PluginParamData = &wwise.PitchShifter{}
+			ParsePitchShifter(r, p.PluginParamSize, p.PluginParamData.(*wwise.PitchShifter))
+		case 0x008A0003:
+			p.PluginParamData = &wwise.Harmonizer{}
+			ParseHarmonizer(r, p.PluginParamSize, p.PluginParamData.(*wwise.Harmonizer))
 		default:
 			p.PluginParamData = &wwise.FxPlaceholder{
 				Data: r.ReadNUnsafe(uint64(p.PluginParamSize), 0),
@@ -42,11 +104,7 @@ func ParseParametricEQ(r *wio.Reader, size uint32, p *wwise.ParametricEQ) {
 	expectedEnd := begin + uint64(size)
 	p.EQBand = [3]wwise.ParametricEQBand(make([]wwise.ParametricEQBand, 3, 3))
 	for i := range p.EQBand {
-		p.EQBand[i].FilterType = wwise.EQFilterType(r.U32Unsafe())
-		p.EQBand[i].Gain = r.F32Unsafe()
-		p.EQBand[i].Frequency = r.F32Unsafe()
-		p.EQBand[i].QFactor = r.F32Unsafe()
-		p.EQBand[i].OnOff = r.U8Unsafe()
+		ParseEQBand(r, &p.EQBand[i])
 	}
 	p.OutputLevel = r.F32Unsafe()
 	p.ProcessLFE = r.U8Unsafe()
@@ -172,3 +230,297 @@ func ParseExpander(r *wio.Reader, size uint32, p *wwise.Expander) {
 		"source plugin parameter header",
 	)
 }
+
+func ParseEQBand(r *wio.Reader, b *wwise.ParametricEQBand) {
+	b.FilterType = wwise.EQFilterType(r.U32Unsafe())
+	b.Gain = r.F32Unsafe()
+	b.Frequency = r.F32Unsafe()
+	b.QFactor = r.F32Unsafe()
+	b.OnOff = r.U8Unsafe()
+}
+
+func ParseFxFilter(r *wio.Reader, f *wwise.FxFilter) {
+	f.FilterType = wwise.EQFilterType(r.U32Unsafe())
+	f.Gain = r.F32Unsafe()
+	f.Frequency = r.F32Unsafe()
+	f.QFactor = r.F32Unsafe()
+}
+
+func ParseFxLFO(r *wio.Reader, l *wwise.FxLFO) {
+	l.Frequency = r.F32Unsafe()
+	l.Waveform = wwise.LFOWaveform(r.U32Unsafe())
+	l.Smoothing = r.F32Unsafe()
+	l.PWM = r.F32Unsafe()
+	l.PhaseOffset = r.F32Unsafe()
+	l.PhaseMode = wwise.LFOPhaseMode(r.U32Unsafe())
+	l.PhaseSpread = r.F32Unsafe()
+}
+
+func ParseDelayFX(r *wio.Reader, size uint32, p *wwise.DelayFX) {
+	begin := r.Pos()
+	expectedEnd := begin + uint64(size)
+	p.DelayTime = r.F32Unsafe()
+	p.Feedback = r.F32Unsafe()
+	p.WetDryMix = r.F32Unsafe()
+	p.OutputLevel = r.F32Unsafe()
+	p.FeedbackEnabled = r.U8Unsafe()
+	p.ProcessLFE = r.U8Unsafe()
+	p.Data = r.ReadNUnsafe(expectedEnd - r.Pos(), 0)
+	end := r.Pos()
+	if begin >= end {
+		panic("Reader consume zero byte.")
+	}
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+}
+
+func ParseMatrixReverb(r *wio.Reader, size uint32, p *wwise.MatrixReverb) {
+	begin := r.Pos()
+	expectedEnd := begin + uint64(size)
+	p.ReverbTime = r.F32Unsafe()
+	p.HFRatio = r.F32Unsafe()
+	p.NumberOfDelays = r.U32Unsafe()
+	p.DryLevel = r.F32Unsafe()
+	p.WetLevel = r.F32Unsafe()
+	p.PreDelay = r.F32Unsafe()
+	p.ProcessLFE = r.U8Unsafe()
+	p.DelayLengthsMode = r.U32Unsafe()
+	p.Data = r.ReadNUnsafe(expectedEnd - r.Pos(), 0)
+	end := r.Pos()
+	if begin >= end {
+		panic("Reader consume zero byte.")
+	}
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+}
+
+func ParseRoomVerb(r *wio.Reader, size uint32, p *wwise.RoomVerb) {
+	begin := r.Pos()
+	expectedEnd := begin + uint64(size)
+	p.DecayTime = r.F32Unsafe()
+	p.HFDamping = r.F32Unsafe()
+	p.Diffusion = r.F32Unsafe()
+	p.StereoWidth = r.F32Unsafe()
+	for i := range p.Filters {
+		p.Filters[i].Gain = r.F32Unsafe()
+		p.Filters[i].Frequency = r.F32Unsafe()
+		p.Filters[i].QFactor = r.F32Unsafe()
+	}
+	p.FrontLevel = r.F32Unsafe()
+	p.RearLevel = r.F32Unsafe()
+	p.CenterLevel = r.F32Unsafe()
+	p.LFELevel = r.F32Unsafe()
+	p.DryLevel = r.F32Unsafe()
+	p.ERLevel = r.F32Unsafe()
+	p.ReverbLevel = r.F32Unsafe()
+	p.Data = r.ReadNUnsafe(expectedEnd - r.Pos(), 0)
+	end := r.Pos()
+	if begin >= end {
+		panic("Reader consume zero byte.")
+	}
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+}
+
+func ParseFlanger(r *wio.Reader, size uint32, p *wwise.Flanger) {
+	begin := r.Pos()
+	expectedEnd := begin + uint64(size)
+	p.DelayTime = r.F32Unsafe()
+	p.DryLevel = r.F32Unsafe()
+	p.FfwdLevel = r.F32Unsafe()
+	p.FbackLevel = r.F32Unsafe()
+	p.ModDepth = r.F32Unsafe()
+	ParseFxLFO(r, &p.LFO)
+	p.OutputLevel = r.F32Unsafe()
+	p.WetDryMix = r.F32Unsafe()
+	p.EnableLFO = r.U8Unsafe()
+	p.ProcessCenter = r.U8Unsafe()
+	p.ProcessLFE = r.U8Unsafe()
+	p.Data = r.ReadNUnsafe(expectedEnd - r.Pos(), 0)
+	end := r.Pos()
+	if begin >= end {
+		panic("Reader consume zero byte.")
+	}
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+}
+
+func ParseGuitarDistortion(r *wio.Reader, size uint32, p *wwise.GuitarDistortion) {
+	begin := r.Pos()
+	expectedEnd := begin + uint64(size)
+	for i := range p.PreEQ {
+		ParseEQBand(r, &p.PreEQ[i])
+	}
+	for i := range p.PostEQ {
+		ParseEQBand(r, &p.PostEQ[i])
+	}
+	p.DistortionType = wwise.DistortionType(r.U32Unsafe())
+	p.Drive = r.F32Unsafe()
+	p.Tone = r.F32Unsafe()
+	p.Rectification = r.F32Unsafe()
+	p.OutputLevel = r.F32Unsafe()
+	p.WetDryMix = r.F32Unsafe()
+	p.Data = r.ReadNUnsafe(expectedEnd - r.Pos(), 0)
+	end := r.Pos()
+	if begin >= end {
+		panic("Reader consume zero byte.")
+	}
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+}
+
+// Parameter block must match the layout of the given version exactly. Nothing
+// is consumed if it does not.
+func ParseConvolutionReverb(r *wio.Reader, size uint32, p *wwise.ConvolutionReverb, v int) error {
+	if expected := wwise.ConvolutionReverbSize(v); size != expected {
+		return fmt.Errorf(
+			"Convolution reverb parameter block of version %d has %d bytes, expecting %d bytes",
+			v, size, expected,
+		)
+	}
+	begin := r.Pos()
+	p.PreDelay = r.F32Unsafe()
+	p.FrontRearDelay = r.F32Unsafe()
+	p.StereoWidth = r.F32Unsafe()
+	p.InputCenterLevel = r.F32Unsafe()
+	p.InputLFELevel = r.F32Unsafe()
+	if v >= 150 {
+		w := r.F32Unsafe()
+		p.InputStereoWidth = &w
+	}
+	p.FrontLevel = r.F32Unsafe()
+	p.RearLevel = r.F32Unsafe()
+	p.CenterLevel = r.F32Unsafe()
+	p.LFELevel = r.F32Unsafe()
+	p.DryLevel = r.F32Unsafe()
+	p.WetLevel = r.F32Unsafe()
+	p.AlgoType = r.U32Unsafe()
+	end := r.Pos()
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+	return nil
+}
+
+func ParseTimeStretch(r *wio.Reader, size uint32, p *wwise.TimeStretch) {
+	begin := r.Pos()
+	expectedEnd := begin + uint64(size)
+	p.WindowSize = r.U32Unsafe()
+	p.TimeStretch = r.F32Unsafe()
+	p.TimeStretchRandom = r.F32Unsafe()
+	p.OutputGain = r.F32Unsafe()
+	p.Data = r.ReadNUnsafe(expectedEnd - r.Pos(), 0)
+	end := r.Pos()
+	if begin >= end {
+		panic("Reader consume zero byte.")
+	}
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+}
+
+func ParseTremolo(r *wio.Reader, size uint32, p *wwise.Tremolo) {
+	begin := r.Pos()
+	expectedEnd := begin + uint64(size)
+	p.ModDepth = r.F32Unsafe()
+	ParseFxLFO(r, &p.LFO)
+	p.OutputGain = r.F32Unsafe()
+	p.ProcessCenter = r.U8Unsafe()
+	p.ProcessLFE = r.U8Unsafe()
+	p.Data = r.ReadNUnsafe(expectedEnd - r.Pos(), 0)
+	end := r.Pos()
+	if begin >= end {
+		panic("Reader consume zero byte.")
+	}
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+}
+
+func ParseStereoDelay(r *wio.Reader, size uint32, p *wwise.StereoDelay) {
+	begin := r.Pos()
+	expectedEnd := begin + uint64(size)
+	for i := range p.Channels {
+		p.Channels[i].InputType = r.U32Unsafe()
+		p.Channels[i].DelayTime = r.F32Unsafe()
+		p.Channels[i].Feedback = r.F32Unsafe()
+		p.Channels[i].CrossFeed = r.F32Unsafe()
+	}
+	ParseFxFilter(r, &p.Filter)
+	p.DryLevel = r.F32Unsafe()
+	p.WetLevel = r.F32Unsafe()
+	p.FrontRearBalance = r.F32Unsafe()
+	p.EnableFeedback = r.U8Unsafe()
+	p.EnableCrossFeed = r.U8Unsafe()
+	p.Data = r.ReadNUnsafe(expectedEnd - r.Pos(), 0)
+	end := r.Pos()
+	if begin >= end {
+		panic("Reader consume zero byte.")
+	}
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+}
+
+func ParsePitchShifter(r *wio.Reader, size uint32, p *wwise.PitchShifter) {
+	begin := r.Pos()
+	expectedEnd := begin + uint64(size)
+	p.InputType = r.U32Unsafe()
+	p.ProcessLFE = r.U8Unsafe()
+	p.SyncDry = r.U8Unsafe()
+	p.DryLevel = r.F32Unsafe()
+	p.WetLevel = r.F32Unsafe()
+	p.DelayTime = r.F32Unsafe()
+	p.Pitch = r.F32Unsafe()
+	ParseFxFilter(r, &p.Filter)
+	p.Data = r.ReadNUnsafe(expectedEnd - r.Pos(), 0)
+	end := r.Pos()
+	if begin >= end {
+		panic("Reader consume zero byte.")
+	}
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+}
+
+func ParseHarmonizer(r *wio.Reader, size uint32, p *wwise.Harmonizer) {
+	begin := r.Pos()
+	expectedEnd := begin + uint64(size)
+	p.InputType = r.U32Unsafe()
+	p.ProcessLFE = r.U8Unsafe()
+	p.SyncDry = r.U8Unsafe()
+	p.DryLevel = r.F32Unsafe()
+	p.WetLevel = r.F32Unsafe()
+	p.WindowSize = r.U32Unsafe()
+	for i := range p.Voices {
+		voice := &p.Voices[i]
+		voice.Enable = r.U8Unsafe()
+		voice.Pitch = r.F32Unsafe()
+		voice.Gain = r.F32Unsafe()
+		ParseFxFilter(r, &voice.Filter)
+	}
+	p.Data = r.ReadNUnsafe(expectedEnd - r.Pos(), 0)
+	end := r.Pos()
+	if begin >= end {
+		panic("Reader consume zero byte.")
+	}
+	assert.Equal(size, uint32(end - begin),
+		"The amount of bytes reader consume doesn't equal size in " + 
+		"source plugin parameter header",
+	)
+}
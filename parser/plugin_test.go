@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wio"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func TestPluginParamRoundTrip(t *testing.T) {
+	width := float32(0.5)
+	tail := []byte{1, 2, 3}
+	for pluginId, f := range map[uint32]wwise.FxParam{
+		0x006A0003: &wwise.DelayFX{DelayTime: 0.5, Feedback: 30, WetDryMix: 50, OutputLevel: -3, FeedbackEnabled: 1, Data: tail},
+		0x00730003: &wwise.MatrixReverb{ReverbTime: 4, HFRatio: 2, NumberOfDelays: 8, DryLevel: -96, WetLevel: -12, Data: tail},
+		0x00760003: &wwise.RoomVerb{DecayTime: 1.2, Filters: [3]wwise.RoomVerbFilter{{Gain: 3, Frequency: 100, QFactor: 1}}, ReverbLevel: -20, Data: tail},
+		0x007D0003: &wwise.Flanger{DelayTime: 2, ModDepth: 50, LFO: wwise.FxLFO{Frequency: 1, Waveform: wwise.LFOWaveformTriangle}, ProcessLFE: 1, Data: []byte{}},
+		0x007E0003: &wwise.GuitarDistortion{PreEQ: [3]wwise.ParametricEQBand{{FilterType: wwise.EQFilterTypeHiPass, Frequency: 300, OnOff: 1}}, DistortionType: wwise.DistortionTypeFuzz, Drive: 80, Data: tail},
+		0x007F0003: &wwise.ConvolutionReverb{PreDelay: 10, WetLevel: -6, AlgoType: 1},
+		0x00820003: &wwise.TimeStretch{WindowSize: 2, TimeStretch: 150, OutputGain: -1, Data: []byte{}},
+		0x00830003: &wwise.Tremolo{ModDepth: 100, LFO: wwise.FxLFO{Frequency: 4, PhaseMode: wwise.LFOPhaseModeCircular}, Data: []byte{}},
+		0x00870003: &wwise.StereoDelay{Channels: [2]wwise.StereoDelayChannel{{DelayTime: 0.25}, {DelayTime: 0.5}}, EnableFeedback: 1, Data: tail},
+		0x00880003: &wwise.PitchShifter{Pitch: -1200, Filter: wwise.FxFilter{FilterType: wwise.EQFilterTypeLowPass, Frequency: 8000}, Data: []byte{}},
+		0x008A0003: &wwise.Harmonizer{
+			DryLevel: -3, WetLevel: -6, WindowSize: 3,
+			Voices: [2]wwise.HarmonizerVoice{
+				{Enable: 1, Pitch: 700, Gain: -3, Filter: wwise.FxFilter{FilterType: wwise.EQFilterTypeLowPass, Frequency: 8000, QFactor: 1}},
+				{Pitch: -1200},
+			},
+			Data: tail,
+		},
+	} {
+		p := wwise.PluginParam{PluginParamSize: f.Size(141), PluginParamData: f}
+		b := p.Encode(141)
+		var d wwise.PluginParam
+		ParsePluginParam(wio.NewReader(bytes.NewReader(b), wio.ByteOrder), &d, pluginId, 141)
+		if reflect.TypeOf(d.PluginParamData) != reflect.TypeOf(f) {
+			t.Fatalf("Expecting plugin 0x%08X to be decoded as %T, got %T", pluginId, f, d.PluginParamData)
+		}
+		if !reflect.DeepEqual(d.PluginParamData, f) {
+			t.Fatalf("Expecting %+v, got %+v", f, d.PluginParamData)
+		}
+		if !bytes.Equal(b, d.Encode(141)) {
+			t.Fatalf("%T is not the same after encoding round trip", f)
+		}
+	}
+
+	// Parameter block shorter than the decoded layout is kept as is
+	short := wwise.PluginParam{PluginParamSize: 4, PluginParamData: &wwise.FxPlaceholder{Data: []byte{1, 2, 3, 4}}}
+	var d wwise.PluginParam
+	ParsePluginParam(wio.NewReader(bytes.NewReader(short.Encode(141)), wio.ByteOrder), &d, 0x00830003, 141)
+	if _, ok := d.PluginParamData.(*wwise.FxPlaceholder); !ok {
+		t.Fatalf("Expecting a placeholder for short parameter block, got %T", d.PluginParamData)
+	}
+
+	// Input stereo width of convolution reverb only exists since version 150
+	c := &wwise.ConvolutionReverb{DryLevel: -3, InputStereoWidth: &width}
+	p := wwise.PluginParam{PluginParamSize: c.Size(150), PluginParamData: c}
+	b := p.Encode(150)
+	ParsePluginParam(wio.NewReader(bytes.NewReader(b), wio.ByteOrder), &d, 0x007F0003, 150)
+	if !reflect.DeepEqual(d.PluginParamData, c) || !bytes.Equal(b, d.Encode(150)) {
+		t.Fatalf("Expecting %+v, got %+v", c, d.PluginParamData)
+	}
+	ParsePluginParam(wio.NewReader(bytes.NewReader(b), wio.ByteOrder), &d, 0x007F0003, 141)
+	if f, ok := d.PluginParamData.(*wwise.FxPlaceholder); !ok || len(f.Data) != int(c.Size(150)) {
+		t.Fatalf("Expecting a placeholder for version 141 parameter block of %d bytes, got %+v", c.Size(150), d.PluginParamData)
+	}
+	if !bytes.Equal(b, d.Encode(141)) {
+		t.Fatal("Placeholder of convolution reverb is not the same after encoding round trip")
+	}
+
+	p = wwise.PluginParam{PluginParamSize: c.Size(141), PluginParamData: c}
+	b = p.Encode(141)
+	ParsePluginParam(wio.NewReader(bytes.NewReader(b), wio.ByteOrder), &d, 0x007F0003, 141)
+	if dc := d.PluginParamData.(*wwise.ConvolutionReverb); dc.InputStereoWidth != nil || dc.DryLevel != -3 {
+		t.Fatalf("Unexpected convolution reverb %+v", dc)
+	}
+	ParsePluginParam(wio.NewReader(bytes.NewReader(b), wio.ByteOrder), &d, 0x007F0003, 150)
+	if _, ok := d.PluginParamData.(*wwise.FxPlaceholder); !ok {
+		t.Fatalf("Expecting a placeholder for version 150 parameter block of %d bytes, got %T", c.Size(141), d.PluginParamData)
+	}
+}
@@ -71,6 +71,14 @@ type BankTab struct {
 	GameSyncViewer    GameSyncViewer
 	ModulatorViewer   ModulatorViewer
 	MusicHircViewer   MusicHircViewer
+	XRefViewer        XRefViewer
+	LintViewer        LintViewer
+	ScriptConsole     ScriptConsole
+
+	// Input
+	NewRTPC           NewRTPCInput
+	NewState          NewStateInput
+	NewEvent          NewEventInput
 
 	// Sync
 	Focus             BankTabEnum 
@@ -114,6 +122,8 @@ func (b *BankTab) Version() int {
 func (b *BankTab) ChangeRoot(hid, np, op uint32) {
 	b.Bank.HIRC().ChangeRoot(hid, np, op, true)
 	b.FilterActorMixerHircs()
+	b.FilterMusicHircs()
+	b.FilterMusicHircRoots()
 	b.ActorMixerViewer.CntrStorage.Clear()
 	b.ActorMixerViewer.RanSeqPlaylistStorage.Clear()
 }
@@ -121,6 +131,8 @@ func (b *BankTab) ChangeRoot(hid, np, op uint32) {
 func (b *BankTab) RemoveRoot(hid, op uint32) {
 	b.Bank.HIRC().RemoveRoot(hid, op, true)
 	b.FilterActorMixerHircs()
+	b.FilterMusicHircs()
+	b.FilterMusicHircRoots()
 	b.ActorMixerViewer.CntrStorage.Clear()
 	b.ActorMixerViewer.RanSeqPlaylistStorage.Clear()
 }
@@ -263,6 +275,22 @@ func (b *BankTab) SetActiveActorMixerHirc(id uint32) {
 	b.ActorMixerViewer.SetSelected(id, true)
 }
 
+func (b *BankTab) SetActiveMusicHirc(id uint32) bool {
+	hirc := b.Bank.HIRC()
+	if hirc == nil {
+		return false
+	}
+	v, ok := hirc.MusicHirc.Load(id)
+	if !ok {
+		return false
+	}
+	b.MusicHircViewer.ActiveMusicHirc = v.(wwise.HircObj)
+	b.MusicHircViewer.CntrStorage.Clear()
+	b.MusicHircViewer.LinearStorage.Clear()
+	b.MusicHircViewer.LinearStorage.SetItemSelected(imgui.ID(id), true)
+	return true
+}
+
 func (b *BankTab) OpenActorMixerHircNode(id uint32) {
 	h := b.Bank.HIRC()
 	if h == nil {
@@ -540,6 +568,9 @@ func (b *BankManager) OpenBank(ctx context.Context, path string) error {
 			LinearStorage: imgui.NewSelectionBasicStorage(),
 			CntrStorage: imgui.NewSelectionBasicStorage(),
 		},
+		NewRTPC: NewRTPCInputDefault(),
+		NewState: NewStateInput{Param: wwise.RTPCParameterTypeVolume},
+		NewEvent: NewEventInput{ActionType: 0x04},
 		Focus: BankTabNone,
 		SounBankLock: atomic.Bool{},
 		WEMExportLock: atomic.Bool{},
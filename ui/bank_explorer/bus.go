@@ -1,9 +1,13 @@
 package bank_explorer
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"slices"
 	"strconv"
 
+	"github.com/Dekr0/wwise-teller/db"
 	"github.com/Dekr0/wwise-teller/wwise"
 	"github.com/lithammer/fuzzysearch/fuzzy"
 )
@@ -50,3 +54,43 @@ type BusViewer struct {
 	Filter    BusFilter
 	ActiveBus wwise.HircObj
 }
+
+// Create a bus or an auxiliary bus with default settings under a parent bus.
+// The bus ID is allocated from Wwise sound bank ID database.
+func (b *BankTab) NewBus(ctx context.Context, parentID uint32, t wwise.HircType) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	h := b.Bank.HIRC()
+	ids := []uint32{0}
+	closeConn, commit, rollback, err := db.AllocateHids(ctx, ids)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to allocate ID for a new %s", wwise.HircTypeName[t]), "error", err)
+		return
+	}
+	defer closeConn()
+
+	var o wwise.HircObj
+	switch t {
+	case wwise.HircTypeBus:
+		o = wwise.NewBus(ids[0], parentID)
+	case wwise.HircTypeAuxBus:
+		o = wwise.NewAuxBus(ids[0], parentID)
+	default:
+		rollback()
+		slog.Error(fmt.Sprintf("%s is not a bus type", wwise.HircTypeName[t]))
+		return
+	}
+	if err := h.AppendNewBus(o); err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to add %s %d", wwise.HircTypeName[t], ids[0]), "error", err)
+		return
+	}
+	if err := commit(); err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to allocate ID for a new %s", wwise.HircTypeName[t]), "error", err)
+		return
+	}
+	b.FilterBuses()
+	b.SetActiveBus(ids[0])
+}
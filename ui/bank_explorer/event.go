@@ -1,10 +1,13 @@
 package bank_explorer
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"slices"
 	"strconv"
 
+	"github.com/Dekr0/wwise-teller/db"
 	"github.com/Dekr0/wwise-teller/wwise"
 	"github.com/lithammer/fuzzysearch/fuzzy"
 )
@@ -53,6 +56,68 @@ type EventViewer struct {
 	ActiveAction *wwise.Action   
 }
 
+// Input of creating events and adding actions to an event
+type NewEventInput struct {
+	// Event name. ID is the short ID of the name if it is not empty.
+	Name       string
+	ID         uint32
+	ActionType uint16
+	Target     uint32
+}
+
+// Create an event with no action. The event ID should be the short ID of its
+// name so that the game can post it by name.
+func (b *BankTab) CreateEvent(id uint32) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	if err := b.Bank.CheckEventID(id); err != nil {
+		slog.Error(fmt.Sprintf("Failed to create event %d", id), "error", err)
+		return
+	}
+	e := wwise.NewEvent(id)
+	if err := b.Bank.HIRC().AppendNewEvent(e); err != nil {
+		slog.Error(fmt.Sprintf("Failed to create event %d", id), "error", err)
+		return
+	}
+	b.FilterEvents()
+	b.EventViewer.ActiveEvent = e
+	b.EventViewer.ActiveAction = nil
+}
+
+// Add an action with default parameters to an event. The action ID is
+// allocated from Wwise sound bank ID database.
+func (b *BankTab) AddAction(ctx context.Context, eventID uint32, t uint16, target uint32) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	ids := []uint32{0}
+	closeConn, commit, rollback, err := db.AllocateHids(ctx, ids)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to allocate action ID for event %d", eventID), "error", err)
+		return
+	}
+	defer closeConn()
+
+	a, err := b.Bank.NewAction(ids[0], t, target)
+	if err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to create action for event %d", eventID), "error", err)
+		return
+	}
+	if err := b.Bank.HIRC().AppendNewActionToEvent(a, eventID); err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to add action %d to event %d", a.Id, eventID), "error", err)
+		return
+	}
+	if err := commit(); err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to allocate action ID for event %d", eventID), "error", err)
+		return
+	}
+	b.EventViewer.ActiveAction = a
+}
+
 type StateFilter struct {
 	Id        uint32
 	States []*wwise.State
@@ -97,6 +97,9 @@ func (f *FxFilter) Filter(objs []wwise.HircObj) {
 type FxViewer struct {
 	Filter   FxFilter
 	ActiveFx wwise.HircObj
+	// Flags of FX created in FX slots
+	NewFxRender bool
+	NewFxBypass bool
 }
 
 type ModulatorFilter struct {
@@ -144,4 +147,13 @@ func (f *ModulatorFilter) Filter(objs []wwise.HircObj) {
 type ModulatorViewer struct {
 	Filter          ModulatorFilter
 	ActiveModulator wwise.HircObj
+	Binding         ModulatorBinding
+}
+
+// Input of binding a modulator to a parameter of a hierarchy object
+type ModulatorBinding struct {
+	ModulatorId uint32
+	Param       wwise.RTPCParameterType
+	Min         float32
+	Max         float32
 }
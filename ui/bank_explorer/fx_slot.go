@@ -0,0 +1,98 @@
+package bank_explorer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Create a FX share set or a FX custom of a built-in FX plugin with default
+// parameters, and insert it into a FX slot of a hierarchy object or a bus. The
+// FX ID is allocated from Wwise sound bank ID database.
+func (b *BankTab) NewFx(ctx context.Context, id uint32, slot uint8, pluginID uint32, shareSet bool, render bool, bypass bool) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	h := b.Bank.HIRC()
+	ids := []uint32{0}
+	closeConn, commit, rollback, err := db.AllocateHids(ctx, ids)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to allocate ID for a new FX of %d", id), "error", err)
+		return
+	}
+	defer closeConn()
+
+	var fx wwise.HircObj
+	if shareSet {
+		fx, err = wwise.NewFxShareSet(ids[0], pluginID)
+	} else {
+		fx, err = wwise.NewFxCustom(ids[0], pluginID)
+	}
+	if err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to create a new FX for %d", id), "error", err)
+		return
+	}
+	if err := h.AppendNewFx(fx); err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to add FX %d", ids[0]), "error", err)
+		return
+	}
+	if err := h.AttachFx(id, slot, ids[0], b.Version()); err != nil {
+		rollback()
+		h.RemoveFx(ids[0])
+		slog.Error(fmt.Sprintf("Failed to insert FX %d into slot %d of %d", ids[0], slot, id), "error", err)
+		return
+	}
+	f, err := h.FxChunkOf(id)
+	if err == nil {
+		err = f.SetSlotFlags(slot, render, bypass, b.Version())
+	}
+	if err != nil {
+		rollback()
+		h.DetachFx(id, slot, b.Version())
+		slog.Error(fmt.Sprintf("Failed to set flags of FX %d in slot %d of %d", ids[0], slot, id), "error", err)
+		return
+	}
+	if err := commit(); err != nil {
+		rollback()
+		h.DetachFx(id, slot, b.Version())
+		slog.Error(fmt.Sprintf("Failed to allocate ID for a new FX of %d", id), "error", err)
+		return
+	}
+	b.FilterFxS()
+}
+
+// Insert an existing FX share set into a FX slot of a hierarchy object or a bus
+func (b *BankTab) AttachFx(id uint32, slot uint8, fxID uint32) {
+	if err := b.Bank.HIRC().AttachFx(id, slot, fxID, b.Version()); err != nil {
+		slog.Error(fmt.Sprintf("Failed to insert FX %d into slot %d of %d", fxID, slot, id), "error", err)
+	}
+}
+
+// Remove a FX from a FX slot of a hierarchy object or a bus. The FX is removed
+// from the sound bank if nothing else references it.
+func (b *BankTab) DetachFx(id uint32, slot uint8) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	h := b.Bank.HIRC()
+	fxID, err := h.DetachFx(id, slot, b.Version())
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to remove FX in slot %d of %d", slot, id), "error", err)
+		return
+	}
+	if b.FxViewer.ActiveFx != nil {
+		if activeID, _ := b.FxViewer.ActiveFx.HircID(); activeID == fxID {
+			_, shareSet := h.FxShareSets.Load(fxID)
+			_, custom := h.FxCustoms.Load(fxID)
+			if !shareSet && !custom {
+				b.FxViewer.ActiveFx = nil
+			}
+		}
+	}
+	b.FilterFxS()
+}
@@ -0,0 +1,31 @@
+package bank_explorer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Add a layer to a layer container with an ID allocated from Wwise sound bank
+// ID database.
+func (b *BankTab) NewLayer(ctx context.Context, l *wwise.LayerCntr) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	ids := []uint32{0}
+	closeConn, commit, rollback, err := db.AllocateHids(ctx, ids)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to allocate ID for a new layer in layer container %d", l.Id), "error", err)
+		return
+	}
+	defer closeConn()
+	if err := commit(); err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to allocate ID for a new layer in layer container %d", l.Id), "error", err)
+		return
+	}
+	l.NewLayer(ids[0])
+}
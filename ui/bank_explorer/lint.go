@@ -0,0 +1,16 @@
+package bank_explorer
+
+import (
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+type LintViewer struct {
+	Ran          bool
+	HideWarning  bool
+	Diagnostics  []wwise.Diagnostic
+}
+
+func (b *BankTab) Lint() {
+	b.LintViewer.Diagnostics = b.Bank.Lint()
+	b.LintViewer.Ran = true
+}
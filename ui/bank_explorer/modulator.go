@@ -0,0 +1,86 @@
+package bank_explorer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Create a LFO, envelope or time modulator with default properties. The
+// modulator ID is allocated from Wwise sound bank ID database.
+func (b *BankTab) NewModulator(ctx context.Context, t wwise.HircType) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	h := b.Bank.HIRC()
+	ids := []uint32{0}
+	closeConn, commit, rollback, err := db.AllocateHids(ctx, ids)
+	if err != nil {
+		slog.Error("Failed to allocate ID for a new modulator", "error", err)
+		return
+	}
+	defer closeConn()
+
+	m, err := wwise.NewModulator(ids[0], t)
+	if err != nil {
+		rollback()
+		slog.Error("Failed to create a new modulator", "error", err)
+		return
+	}
+	if err := h.AppendNewModulator(m); err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to add modulator %d", ids[0]), "error", err)
+		return
+	}
+	if err := commit(); err != nil {
+		rollback()
+		slog.Error("Failed to allocate ID for a new modulator", "error", err)
+		return
+	}
+	b.FilterModulator()
+	b.ModulatorViewer.ActiveModulator = m
+}
+
+// Drive a parameter of a hierarchy object or a bus with a modulator. The RTPC
+// curve ID is allocated from Wwise sound bank ID database.
+func (b *BankTab) BindModulator(
+	ctx context.Context,
+	id uint32,
+	modulatorID uint32,
+	p wwise.RTPCParameterType,
+	min float32,
+	max float32,
+) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	ids := []uint32{0}
+	closeConn, commit, rollback, err := db.AllocateHids(ctx, ids)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to allocate RTPC curve ID for modulator %d", modulatorID), "error", err)
+		return
+	}
+	defer closeConn()
+
+	r, err := b.Bank.HIRC().RTPCOf(id)
+	if err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to bind modulator %d to %d", modulatorID, id), "error", err)
+		return
+	}
+	prev := len(r.RTPCItems)
+	if err := b.Bank.HIRC().BindModulator(id, modulatorID, ids[0], p, min, max, b.Version()); err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to bind modulator %d to %d", modulatorID, id), "error", err)
+		return
+	}
+	if err := commit(); err != nil {
+		rollback()
+		r.RemoveRTPCItem(prev)
+		slog.Error(fmt.Sprintf("Failed to allocate RTPC curve ID for modulator %d", modulatorID), "error", err)
+		return
+	}
+}
@@ -0,0 +1,43 @@
+package bank_explorer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Add a group node or a segment node (segmentID is non zero) under group node
+// groupItemID of a music random / sequence container. The play list item ID is
+// allocated from Wwise sound bank ID database.
+func (b *BankTab) NewMusicPlayListNode(
+	ctx context.Context, m *wwise.MusicRanSeqCntr, groupItemID uint32, segmentID uint32,
+) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	ids := []uint32{0}
+	closeConn, commit, rollback, err := db.AllocateHids(ctx, ids)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to allocate ID for a new play list item in music random / sequence container %d", m.Id), "error", err)
+		return
+	}
+	defer closeConn()
+	if segmentID == 0 {
+		err = m.AddPlayListGroup(groupItemID, ids[0], wwise.MusicRSTypeContinuousSequence)
+	} else {
+		err = m.AddPlayListSegment(groupItemID, ids[0], segmentID)
+	}
+	if err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to add play list item to music random / sequence container %d", m.Id), "error", err)
+		return
+	}
+	if err := commit(); err != nil {
+		rollback()
+		m.PlayListNode.RemoveNode(ids[0])
+		slog.Error(fmt.Sprintf("Failed to allocate ID for a new play list item in music random / sequence container %d", m.Id), "error", err)
+	}
+}
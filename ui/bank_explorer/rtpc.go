@@ -0,0 +1,74 @@
+package bank_explorer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Input of adding a RTPC item to a hierarchy object, a bus or an attenuation
+type NewRTPCInput struct {
+	// Game parameter name. RTPCID is the short ID of the name if it is not
+	// empty.
+	Name     string
+	RTPCID   uint32
+	RTPCType uint8
+	Param    wwise.RTPCParameterType
+	Accum    wwise.RTPCAccumType
+	Scaling  wwise.CurveScalingType
+	// Initial curve is a straight line from (MinX, MinY) to (MaxX, MaxY)
+	MinX     float32
+	MaxX     float32
+	MinY     float32
+	MaxY     float32
+}
+
+func NewRTPCInputDefault() NewRTPCInput {
+	return NewRTPCInput{
+		RTPCType: wwise.RTPCTypeGameParameter,
+		Accum: wwise.DefaultRTPCAccum(wwise.RTPCParameterTypeVolume),
+		Scaling: wwise.DefaultCurveScaling(wwise.RTPCParameterTypeVolume),
+		MaxX: 100,
+	}
+}
+
+// Add a RTPC item to a hierarchy object, a bus or an attenuation. The RTPC
+// curve ID is allocated from Wwise sound bank ID database.
+func (b *BankTab) AddRTPC(ctx context.Context, id uint32, in NewRTPCInput) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	ids := []uint32{0}
+	closeConn, commit, rollback, err := db.AllocateHids(ctx, ids)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to allocate RTPC curve ID for %d", id), "error", err)
+		return
+	}
+	defer closeConn()
+
+	h := b.Bank.HIRC()
+	item, err := wwise.NewRTPCItem(in.RTPCID, in.RTPCType, ids[0], in.Param)
+	if err == nil {
+		item.RTPCAccum = in.Accum
+		item.Scaling = in.Scaling
+		err = item.SetCurve([]float32{in.MinX, in.MaxX}, []float32{in.MinY, in.MaxY}, nil)
+	}
+	if err == nil {
+		err = h.AddRTPC(id, item, b.Version())
+	}
+	if err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to add RTPC %d to %d", in.RTPCID, id), "error", err)
+		return
+	}
+	if err := commit(); err != nil {
+		rollback()
+		r, _ := h.RTPCOf(id)
+		r.RemoveRTPCItem(len(r.RTPCItems) - 1)
+		slog.Error(fmt.Sprintf("Failed to allocate RTPC curve ID for %d", id), "error", err)
+		return
+	}
+}
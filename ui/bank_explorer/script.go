@@ -0,0 +1,116 @@
+package bank_explorer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Dekr0/wwise-teller/automation"
+	"github.com/Dekr0/wwise-teller/db"
+)
+
+// Starlark console of a sound bank tab (see automation.RunStarlarkSource)
+type ScriptConsole struct {
+	Source    string
+	Running   atomic.Bool
+	l         sync.Mutex
+	output  []string
+}
+
+func (c *ScriptConsole) Output() []string {
+	c.l.Lock()
+	defer c.l.Unlock()
+	return c.output
+}
+
+func (c *ScriptConsole) Print(line string) {
+	c.l.Lock()
+	c.output = append(c.output, line)
+	c.l.Unlock()
+}
+
+func (c *ScriptConsole) Clear() {
+	c.l.Lock()
+	c.output = nil
+	c.l.Unlock()
+}
+
+// Run the console source on the sound bank. Changes made by a script are not
+// recorded in the undo history. A failing script leaves the sound bank as it
+// was before the run.
+func (b *BankTab) RunScript(ctx context.Context) {
+	c := &b.ScriptConsole
+	if !c.Running.CompareAndSwap(false, true) {
+		return
+	}
+	defer c.Running.Store(false)
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	if err := db.Ping(); err != nil {
+		slog.Warn("Scripts cannot allocate IDs without Wwise sound bank ID database", "error", err)
+	}
+	snapshot, err := automation.SnapshotBank(ctx, b.Bank)
+	if err != nil {
+		c.Print(fmt.Sprintf("Failed to take snapshot of sound bank: %s", err.Error()))
+		return
+	}
+	defer os.Remove(snapshot)
+
+	err = automation.RunStarlarkSource(ctx, b.Bank, "console", []byte(c.Source), c.Print)
+	if err == nil {
+		if h := b.Bank.HIRC(); h != nil {
+			h.BuildTree()
+		}
+		return
+	}
+	c.Print(err.Error())
+
+	// IDs allocated by the script are already rolled back. Partial edits are
+	// discarded by restoring the snapshot.
+	restored, rerr := automation.RestoreBank(context.Background(), snapshot)
+	if rerr != nil {
+		slog.Error("Failed to roll back sound bank after a failing script", "error", rerr)
+		c.Print(fmt.Sprintf("Failed to roll back sound bank: %s", rerr.Error()))
+		return
+	}
+	*b.Bank = *restored
+	b.resetViewers()
+	c.Print("Rolled back changes made by the script")
+}
+
+// Drop every reference to hierarchy objects of the sound bank before it is
+// restored, and filter again against the restored hierarchy.
+func (b *BankTab) resetViewers() {
+	b.ActorMixerViewer.ActiveHirc = nil
+	b.ActorMixerViewer.LinearStorage.Clear()
+	b.ActorMixerViewer.CntrStorage.Clear()
+	b.ActorMixerViewer.RanSeqPlaylistStorage.Clear()
+	b.AttenuationViewer.ActiveAttenuation = nil
+	b.BusViewer.ActiveBus = nil
+	b.EventViewer.ActiveEvent = nil
+	b.EventViewer.ActiveAction = nil
+	b.FxViewer.ActiveFx = nil
+	b.GameSyncViewer.ActiveState = nil
+	b.ModulatorViewer.ActiveModulator = nil
+	b.MusicHircViewer.ActiveMusicHirc = nil
+	b.MusicHircViewer.LinearStorage.Clear()
+	b.MusicHircViewer.CntrStorage.Clear()
+
+	b.FilterActorMixerHircs()
+	b.FilterActorMixerRoots()
+	b.FilterAttenuations()
+	b.FilterBuses()
+	b.FilterEvents()
+	b.FilterFxS()
+	b.FilterModulator()
+	b.FilterMusicHircs()
+	b.FilterMusicHircRoots()
+	b.FilterMediaIndices()
+	if h := b.Bank.HIRC(); h != nil {
+		b.GameSyncViewer.Filter.Filter(h.HircObjs)
+	}
+}
@@ -0,0 +1,60 @@
+package bank_explorer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// Input of adding state groups, states and state properties to a hierarchy
+// object, a bus or an auxiliary bus
+type NewStateInput struct {
+	// State group name. GroupID is the short ID of the name if it is not
+	// empty.
+	GroupName string
+	GroupID   uint32
+	SyncType  uint8
+	// State name. StateID is the short ID of the name if it is not empty.
+	StateName string
+	StateID   uint32
+	Param     wwise.RTPCParameterType
+}
+
+// Add a state to a state group of a hierarchy object, a bus or an auxiliary
+// bus. For version <= 145, the state instance ID is allocated from Wwise sound
+// bank ID database.
+func (b *BankTab) AddState(ctx context.Context, id uint32, groupID uint32, stateID uint32) {
+	b.SounBankLock.Store(true)
+	defer b.SounBankLock.Store(false)
+
+	h := b.Bank.HIRC()
+	v := b.Version()
+	if v > 145 {
+		if err := h.AddState(id, groupID, stateID, 0, v); err != nil {
+			slog.Error(fmt.Sprintf("Failed to add state %d to %d", stateID, id), "error", err)
+		}
+		return
+	}
+
+	ids := []uint32{0}
+	closeConn, commit, rollback, err := db.AllocateHids(ctx, ids)
+	if err != nil {
+		slog.Error(fmt.Sprintf("Failed to allocate state instance ID for %d", id), "error", err)
+		return
+	}
+	defer closeConn()
+
+	if err := h.AddState(id, groupID, stateID, ids[0], v); err != nil {
+		rollback()
+		slog.Error(fmt.Sprintf("Failed to add state %d to %d", stateID, id), "error", err)
+		return
+	}
+	if err := commit(); err != nil {
+		rollback()
+		h.RemoveState(id, groupID, stateID)
+		slog.Error(fmt.Sprintf("Failed to allocate state instance ID for %d", id), "error", err)
+	}
+}
@@ -0,0 +1,37 @@
+package bank_explorer
+
+import (
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+type XRefViewer struct {
+	Target   uint32
+	Refs   []wwise.Ref
+}
+
+// Rebuild the reverse index every time since HIRC might be modified in 
+// between.
+func (b *BankTab) FindReferences(id uint32) {
+	h := b.Bank.HIRC()
+	if h == nil {
+		return
+	}
+	x := h.BuildXRef(b.Version())
+	b.XRefViewer.Target = id
+	b.XRefViewer.Refs = x.References(id)
+}
+
+func (b *BankTab) SetActiveEvent(id uint32) bool {
+	h := b.Bank.HIRC()
+	if h == nil {
+		return false
+	}
+	v, ok := h.Events.Load(id)
+	if !ok {
+		return false
+	}
+	b.EventViewer.ActiveEvent = v.(*wwise.Event)
+	b.EventViewer.ActiveAction = nil
+	b.Focus = BankTabEvents
+	return true
+}
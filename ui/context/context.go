@@ -10,6 +10,7 @@ import (
 	dockmanager "github.com/Dekr0/wwise-teller/ui/dock_manager"
 	"github.com/Dekr0/wwise-teller/ui/modal"
 	"github.com/Dekr0/wwise-teller/ui/processor"
+	"github.com/Dekr0/wwise-teller/wwise"
 )
 
 type Context struct {
@@ -20,6 +21,7 @@ type Context struct {
 	BankMngr   bank_explorer.BankManager
 	DockMngr   dockmanager.DockManager
 	Editor     processor.ProcessorEditor
+	Names     *wwise.NameDB
 	CopyEnable bool
 }
 
@@ -31,6 +33,7 @@ func Init() {
 	GCtx.ModalQ = modal.NewModalQ()
 	GCtx.Config = config.Config{}
 	GCtx.Editor = processor.New()
+	GCtx.Names = wwise.NewNameDB()
 	GCtx.CopyEnable = false
 	GCtx.BankMngr = bank_explorer.BankManager{WriteLock: atomic.Bool{}}
 	GCtx.BankMngr.WriteLock.Store(false)
@@ -41,6 +41,9 @@ const (
 	NotificationTag
 	TransportControlTag
 	ProcessorEditorTag           
+	ReferencesTag
+	DiagnosticsTag
+	ScriptConsoleTag
 	DockWindowTagCount
 )
 
@@ -62,6 +65,9 @@ var DockWindowNames []string = []string{
 	"Notifications",
 	"Transport Control",
 	"Processor Editor",
+	"References",
+	"Diagnostics",
+	"Script Console",
 }
 
 type DockManager struct {
@@ -172,6 +178,9 @@ func (d *DockManager) BuildDockSpace() imgui.ID {
 		imgui.InternalDockBuilderDockWindow(DockWindowNames[TransportControlTag], transportDock)
 		imgui.InternalDockBuilderDockWindow(DockWindowNames[EventsTag], eventDock)
 		imgui.InternalDockBuilderDockWindow(DockWindowNames[GameSyncTag], eventDock)
+		imgui.InternalDockBuilderDockWindow(DockWindowNames[ReferencesTag], eventDock)
+		imgui.InternalDockBuilderDockWindow(DockWindowNames[DiagnosticsTag], eventDock)
+		imgui.InternalDockBuilderDockWindow(DockWindowNames[ScriptConsoleTag], eventDock)
 
 		imgui.InternalDockBuilderFinish(eventDock)
 		d.Rebuild = false
@@ -86,6 +86,8 @@ func Run() error {
 	}
 	slog.Info("Loaded configuration file")
 
+	loadNames(GCtx.Ctx)
+
 	fileExplorer, err := fs.NewFileExplorer(fileExplorerCallback, GCtx.Config.Home)
 	if err != nil {
 		return err
@@ -214,6 +216,9 @@ func createLoop(
 		renderFXViewer(&DockMngr.Opens[dockmanager.FXTag])
 		renderEventsViewer(&DockMngr.Opens[dockmanager.EventsTag])
 		renderAttenuationViewer(&DockMngr.Opens[dockmanager.AttenuationsTag])
+		renderReferences(&DockMngr.Opens[dockmanager.ReferencesTag])
+		renderDiagnostics(&DockMngr.Opens[dockmanager.DiagnosticsTag])
+		renderScriptConsole(&DockMngr.Opens[dockmanager.ScriptConsoleTag])
 		RenderTransportControl(&DockMngr.Opens[dockmanager.TransportControlTag])
 		// processor.RenderProcessorEditor(&GCtx.Editor, &DockMngr.Opens[dockmanager.ProcessorEditorTag])
 
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"log/slog"
 	"strconv"
 
 	"github.com/AllenDang/cimgui-go/imgui"
@@ -72,6 +73,10 @@ func renderAttenuationTable(t *be.BankTab) {
 				) {
 					t.AttenuationViewer.ActiveAttenuation = attenuation
 				}
+				if imgui.BeginPopupContextItem() {
+					renderAttenuationTableCtx(t, attenuation, idA)
+					imgui.EndPopup()
+				}
 			}
 		}
 		imgui.EndTable()
@@ -84,6 +89,9 @@ func renderAttenuationTableCtx(t *be.BankTab, o wwise.HircObj, id uint32) {
 			clipboard.Write(clipboard.FmtText, []byte(strconv.FormatUint(uint64(id), 10)))
 		}
 	})
+	if imgui.SelectableBool("Find References") {
+		findReferences(t, id)
+	}
 }
 
 func renderAttenuationViewer(open *bool) {
@@ -102,11 +110,11 @@ func renderAttenuationViewer(open *bool) {
 		return
 	}
 	if activeBank.AttenuationViewer.ActiveAttenuation != nil {
-		renderAttenuation(activeBank.AttenuationViewer.ActiveAttenuation)
+		renderAttenuation(activeBank, activeBank.AttenuationViewer.ActiveAttenuation)
 	}
 }
 
-func renderAttenuation(a *wwise.Attenuation) {
+func renderAttenuation(t *be.BankTab, a *wwise.Attenuation) {
 	imgui.Text(fmt.Sprintf("Attenuation ID %d", a.Id))
 
 	heightSpreadEnabled := a.HeightSpreadEnabled()
@@ -130,6 +138,18 @@ func renderAttenuation(a *wwise.Attenuation) {
 	imgui.EndDisabled()
 
 	imgui.SeparatorText("Attenuation Settings")
+	maxDistance := a.MaxDistance()
+	imgui.SetNextItemWidth(160.0)
+	if imgui.InputFloatV("Max Distance", &maxDistance, 0, 0, "%.2f", imgui.InputTextFlagsEnterReturnsTrue) {
+		if maxDistance > 0 {
+			if err := a.SetMaxDistance(maxDistance); err != nil {
+				slog.Error(
+					fmt.Sprintf("Failed to set max distance of attenuation %d", a.Id),
+					"error", err,
+				)
+			}
+		}
+	}
 	RenderAttenuationSettingsGE141(a)
 
 	imgui.SeparatorText("Attenuation Conversion Table")
@@ -247,6 +267,7 @@ func renderAttenuation(a *wwise.Attenuation) {
 	}
 
 	renderRTPC(a.Id, &a.RTPC, "Attenuation RTPC Initial")
+	renderNewRTPC(t, a.Id)
 }
 
 func RenderAttenuationSettingsGE141(a *wwise.Attenuation)  {
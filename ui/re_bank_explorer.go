@@ -427,7 +427,7 @@ func renderActorMixerHircTable(t *be.BankTab) {
 				flags := imgui.SelectableFlagsSpanAllColumns | 
 					     imgui.SelectableFlagsAllowOverlap
 				size := imgui.NewVec2(0, 0)
-				imgui.SelectableBoolPtrV(strconv.FormatUint(uint64(id), 10), &selected, flags, size)
+				imgui.SelectableBoolPtrV(idLabel(id), &selected, flags, size)
 
 				if imgui.BeginPopupContextItem() {
 					renderActorMixerHircTableCtx(t, o, id)
@@ -472,6 +472,10 @@ func renderActorMixerHircTableCtx(t *be.BankTab, o wwise.HircObj, id uint32) {
 		})
 	}
 
+	if imgui.SelectableBool("Find References") {
+		findReferences(t, id)
+	}
+
 	leafs := o.Leafs()
 	if len(leafs) <= 0 {
 		return
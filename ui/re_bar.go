@@ -61,6 +61,8 @@ func renderMainMenuBar(
 			imgui.EndMenu()
 		}
 
+		renderNamesMenu()
+
 		if imgui.BeginMenu("Views") {
 			for tag, open := range dockMngr.Opens {
 				if imgui.MenuItemBoolV(dockmanager.DockWindowNames[tag], "", open, true) {
@@ -1,9 +1,12 @@
 package ui
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
+	"log/slog"
 	"strconv"
+	"time"
 
 	"github.com/AllenDang/cimgui-go/imgui"
 	"github.com/AllenDang/cimgui-go/utils"
@@ -87,7 +90,7 @@ func renderBusTable(t *be.BankTab) {
 				if err != nil { panic(err) }
 				selected := idA == idB
 				if imgui.SelectableBoolPtrV(
-					strconv.FormatUint(uint64(idA), 10),
+					idLabel(idA),
 					&selected,
 					DefaultSelectableFlags,
 					DefaultSize,
@@ -139,7 +142,7 @@ func renderMasterMixerHierarchy(open *bool) {
 		return
 	}
 	activeBank, valid := BnkMngr.ActiveBankV()
-	if !valid {
+	if !valid || activeBank.SounBankLock.Load() {
 		return
 	}
 	renderMasterMixerHierarchyTreeTable(activeBank)
@@ -166,7 +169,7 @@ func renderMasterMixerHierarchyNode(t *be.BankTab, node *wwise.BusHircNode) {
 	selected := false
 	id, err := o.HircID()
 	if err != nil { panic(err) }
-	sid = strconv.FormatUint(uint64(id), 10) 
+	sid = idLabel(id)
 	selected = t.BusViewer.ActiveBus == o
 
 	imgui.TableNextRow()
@@ -187,6 +190,20 @@ func renderMasterMixerHierarchyNode(t *be.BankTab, node *wwise.BusHircNode) {
 				clipboard.Write(clipboard.FmtText, []byte(strconv.FormatUint(uint64(id), 10)))
 			}
 		})
+		if imgui.SelectableBool("Find References") {
+			findReferences(t, id)
+		}
+		imgui.Separator()
+		if imgui.SelectableBool("New Child Bus") {
+			BG(time.Second * 8, "Creating bus", "Created bus", func(ctx context.Context) {
+				t.NewBus(ctx, id, wwise.HircTypeBus)
+			})
+		}
+		if imgui.SelectableBool("New Child Auxiliary Bus") {
+			BG(time.Second * 8, "Creating auxiliary bus", "Created auxiliary bus", func(ctx context.Context) {
+				t.NewBus(ctx, id, wwise.HircTypeAuxBus)
+			})
+		}
 		imgui.EndPopup()
 	}
 
@@ -253,13 +270,20 @@ func renderBus(t *be.BankTab, b *wwise.Bus) {
 			t.SetActiveBus(b.OverrideBusId)
 			imgui.SetWindowFocusStr("Buses")
 		}
+		imgui.SameLine()
+		renderBusParent(t, b.Id, b.OverrideBusId)
 	}
 	renderBusAuxParam(t, &b.AuxParam, &b.PropBundle)
 	renderBusEarlyReflection(t, &b.AuxParam, &b.PropBundle)
-	renderHDR(b)
+	renderHDR(t, b)
 	renderBusAdvanceSetting(b)
+	renderDucking(t, b.Id, &b.MaxDuckVolume, &b.RecoveryTime, b.DuckInfoList)
 	renderAllProp(&b.PropBundle, nil, t.Version())
-	renderBusFxParam(t, &b.BusFxParam)
+	renderBusFxParam(t, b.Id, &b.BusFxParam)
+	renderRTPC(b.Id, &b.BusRTPC, "RTPC (Property)")
+	renderNewRTPC(t, b.Id)
+	renderBindModulator(t, b.Id)
+	renderStateGroup(t, b.Id)
 }
 
 func renderAuxBus(t *be.BankTab, b *wwise.AuxBus) {
@@ -271,12 +295,19 @@ func renderAuxBus(t *be.BankTab, b *wwise.AuxBus) {
 			t.SetActiveBus(b.OverrideBusId)
 			imgui.SetWindowFocusStr("Buses")
 		}
+		imgui.SameLine()
+		renderBusParent(t, b.Id, b.OverrideBusId)
 	}
 	renderBusAuxParam(t, &b.AuxParam, &b.PropBundle)
 	renderBusEarlyReflection(t, &b.AuxParam, &b.PropBundle)
 	renderAuxBusAdvanceSetting(b)
+	renderDucking(t, b.Id, &b.MaxDuckVolume, &b.RecoveryTime, b.DuckInfoList)
 	renderAllProp(&b.PropBundle, nil, t.Version())
-	renderBusFxParam(t, &b.BusFxParam)
+	renderBusFxParam(t, b.Id, &b.BusFxParam)
+	renderRTPC(b.Id, &b.BusRTPC, "RTPC (Property)")
+	renderNewRTPC(t, b.Id)
+	renderBindModulator(t, b.Id)
+	renderStateGroup(t, b.Id)
 }
 
 func renderBusAuxParam(t *be.BankTab, a *wwise.AuxParam, p *wwise.PropBundle) {
@@ -654,7 +685,7 @@ func renderBusAdvanceSetting(b *wwise.Bus) {
 	}
 }
 
-func renderHDR(b *wwise.Bus) {
+func renderHDR(t *be.BankTab, b *wwise.Bus) {
 	if imgui.TreeNodeExStr("HDR") {
 		if b.CanSetHDR == -1 {
 			panic(fmt.Sprintf("HDR Availability isn't resolve for Bus %d", b.Id))
@@ -666,11 +697,11 @@ func renderHDR(b *wwise.Bus) {
 				imgui.Text("HDR is enabled in a parent bus.")
 			} else {
 				HDREnable := b.IsHDRBus()
-				imgui.BeginDisabled()
 				if imgui.Checkbox("Enable HDR", &HDREnable) {
-					b.SetHDRBus(HDREnable)
+					if err := t.Bank.HIRC().SetHDRBus(b.Id, HDREnable); err != nil {
+						slog.Error(fmt.Sprintf("Failed to set HDR of bus %d", b.Id), "error", err)
+					}
 				}
-				imgui.EndDisabled()
 			}
 			{
 				DefaultSize.Y = 128
@@ -779,69 +810,13 @@ func renderAuxBusAdvanceSetting(b *wwise.AuxBus) {
 	}
 }
 
-func renderBusFxParam(t *be.BankTab, b *wwise.BusFxParam) {
+func renderBusFxParam(t *be.BankTab, id uint32, b *wwise.BusFxParam) {
 	if imgui.TreeNodeStr("FX") {
-		byPassAllFx := b.FxChunk.BitsFxByPass != 0
-		{
-			if imgui.Checkbox("By Passing FX", &byPassAllFx) {
-				b.FxChunk.BypassFx(byPassAllFx)
-			}
-		}
-
-		if imgui.BeginTableV("FXChunkTable", 3, DefaultTableFlags, DefaultSize, 0) {
-			imgui.TableSetupColumn("Unique FX Index")
-			imgui.TableSetupColumn("FX ID")
-			if t.Version() <= 145 {
-				imgui.TableSetupColumn("Is Share Set")
-			}
-			if t.Version() > 145 {
-				imgui.TableSetupColumn("Bypass FX")
-			}
-			imgui.TableSetupScrollFreeze(0, 1)
-			imgui.TableHeadersRow()
-			for i := range b.FxChunk.FxChunkItems {
-				fi := &b.FxChunk.FxChunkItems[i]
-
-				imgui.TableNextRow()
-
-				imgui.TableSetColumnIndex(0)
-				imgui.Text(strconv.FormatUint(uint64(fi.UniqueFxIndex), 10))
-
-				imgui.TableSetColumnIndex(1)
-				{
-					imgui.Text(strconv.FormatUint(uint64(fi.FxId), 10))
-					imgui.SameLine()
-
-					imgui.BeginDisabled()
-					imgui.ArrowButton(fmt.Sprintf("##SetFXID%d", i), imgui.DirDown)
-					imgui.EndDisabled()
-
-					imgui.SameLine()
-					if imgui.ArrowButton(fmt.Sprintf("##GoToFXID%d", i), imgui.DirRight) {
-						t.SetActiveFX(fi.FxId)
-					}
-				}
+		renderFxChunk(t, id, &b.FxChunk)
 
-				imgui.TableSetColumnIndex(2)
-				if t.Version() <= 145 {
-					Disabled(true, func() {
-						isSharedSet := fi.BitIsShareSet != 0
-						imgui.Checkbox(fmt.Sprintf("##IsShareSet%d", i), &isSharedSet)
-					})
-				}
-				if t.Version() > 145 {
-					bypassFx := fi.BitVector != 0
-					Disabled(byPassAllFx, func() {
-						if imgui.Checkbox(fmt.Sprintf("##Bypass%d", i), &bypassFx) {
-							if bypassFx && !byPassAllFx {
-								fi.Bypass(bypassFx)
-							}
-						}
-					})
-				}
-			}
-
-			imgui.EndTable()
+		if t.Version() > 145 {
+			imgui.TreePop()
+			return
 		}
 
 		imgui.Text(fmt.Sprintf("FX ID: %d", b.FxID_0))
@@ -864,3 +839,119 @@ func renderBusFxParam(t *be.BankTab, b *wwise.BusFxParam) {
 		imgui.TreePop()
 	}
 }
+
+func renderBusParent(t *be.BankTab, id uint32, parentID uint32) {
+	h := t.Bank.HIRC()
+	imgui.SetNextItemWidth(200)
+	if imgui.BeginCombo(fmt.Sprintf("##BusParent%d", id), "Move Under") {
+		for _, o := range t.BusViewer.Filter.Buses {
+			optionID, err := o.HircID()
+			if err != nil { panic(err) }
+			if optionID == id || optionID == parentID {
+				continue
+			}
+			if imgui.SelectableBool(idLabel(optionID) + "##BusParent") {
+				if err := h.SetBusParent(id, optionID); err != nil {
+					slog.Error(fmt.Sprintf("Failed to move bus %d under %d", id, optionID), "error", err)
+				}
+			}
+		}
+		imgui.EndCombo()
+	}
+}
+
+func renderDucking(t *be.BankTab, id uint32, maxDuckVolume *float32, recoveryTime *int32, ducks []wwise.DuckInfo) {
+	if !imgui.TreeNodeExStr("Auto-ducking") {
+		return
+	}
+	h := t.Bank.HIRC()
+	v := t.Version()
+
+	imgui.SetNextItemWidth(96)
+	if imgui.InputFloat("Maximum Ducking Volume", maxDuckVolume) {
+		*maxDuckVolume = max(min(*maxDuckVolume, 0), -96)
+	}
+	recovery := *recoveryTime
+	imgui.SetNextItemWidth(96)
+	if imgui.InputInt("Recovery Time (ms)", &recovery) && recovery >= 0 {
+		*recoveryTime = recovery
+	}
+
+	var edit func() error = nil
+	const flags = DefaultTableFlags
+	if imgui.BeginTableV(fmt.Sprintf("%dDucks", id), 6, flags, imgui.NewVec2(0, 0), 0) {
+		imgui.TableSetupColumnV("", imgui.TableColumnFlagsWidthFixed, 0, 0)
+		imgui.TableSetupColumn("Ducked Bus")
+		imgui.TableSetupColumn("Volume")
+		imgui.TableSetupColumn("Fade Out (ms)")
+		imgui.TableSetupColumn("Fade In (ms)")
+		imgui.TableSetupColumn("Curve")
+		imgui.TableHeadersRow()
+		for i := range ducks {
+			d := &ducks[i]
+			stackID := fmt.Sprintf("%dDuck%d", id, d.BusID)
+			imgui.TableNextRow()
+
+			imgui.TableSetColumnIndex(0)
+			imgui.PushIDStr(stackID + "Rm")
+			if imgui.Button("X") {
+				busID := d.BusID
+				edit = func() error { return h.RemoveDuck(id, busID) }
+			}
+			imgui.PopID()
+
+			imgui.TableSetColumnIndex(1)
+			imgui.Text(idLabel(d.BusID))
+
+			imgui.TableSetColumnIndex(2)
+			imgui.SetNextItemWidth(96)
+			if imgui.InputFloat("##" + stackID + "Volume", &d.DuckVolume) {
+				d.DuckVolume = max(min(d.DuckVolume, 0), -96)
+			}
+
+			imgui.TableSetColumnIndex(3)
+			fadeOut := d.FadeOutTime
+			imgui.SetNextItemWidth(96)
+			if imgui.InputInt("##" + stackID + "FadeOut", &fadeOut) && fadeOut >= 0 {
+				d.FadeOutTime = fadeOut
+			}
+
+			imgui.TableSetColumnIndex(4)
+			fadeIn := d.FadeInTime
+			imgui.SetNextItemWidth(96)
+			if imgui.InputInt("##" + stackID + "FadeIn", &fadeIn) && fadeIn >= 0 {
+				d.FadeInTime = fadeIn
+			}
+
+			imgui.TableSetColumnIndex(5)
+			curve := int32(d.EnumFadeCurve)
+			imgui.SetNextItemWidth(128)
+			if imgui.ComboStrarr("##" + stackID + "Curve", &curve, wwise.InterpCurveTypeName, int32(wwise.InterpCurveTypeCount)) {
+				d.EnumFadeCurve = wwise.InterpCurveType(curve)
+			}
+		}
+		imgui.EndTable()
+	}
+
+	imgui.SetNextItemWidth(200)
+	if imgui.BeginCombo(fmt.Sprintf("##%dNewDuck", id), "Duck Bus") {
+		for _, o := range t.BusViewer.Filter.Buses {
+			optionID, err := o.HircID()
+			if err != nil { panic(err) }
+			if optionID == id {
+				continue
+			}
+			if imgui.SelectableBool(idLabel(optionID) + "##NewDuck") {
+				edit = func() error { return h.AddDuck(id, wwise.NewDuckInfo(optionID, v)) }
+			}
+		}
+		imgui.EndCombo()
+	}
+
+	imgui.TreePop()
+	if edit != nil {
+		if err := edit(); err != nil {
+			slog.Error(fmt.Sprintf("Failed to edit auto-ducking of bus %d", id), "error", err)
+		}
+	}
+}
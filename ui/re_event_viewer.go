@@ -5,8 +5,10 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/AllenDang/cimgui-go/imgui"
 	"github.com/AllenDang/cimgui-go/utils"
@@ -32,6 +34,7 @@ func renderEventsViewer(open *bool) {
 	if activeBank.EventViewer.ActiveEvent != nil {
 		size := imgui.NewVec2(0, 0)
 
+		renderNewAction(activeBank)
 		size.Y = imgui.ContentRegionAvail().Y * 0.5
 		imgui.BeginChildStrV("EventActionTableChild", size, imgui.ChildFlagsBorders, imgui.WindowFlagsNone)
 		renderActionsTable(activeBank)
@@ -46,6 +49,36 @@ func renderEventsViewer(open *bool) {
 	}
 }
 
+func renderNewAction(t *be.BankTab) {
+	in := &t.NewEvent
+	eventID := t.EventViewer.ActiveEvent.Id
+	names := wwise.ActionTypeNames(t.Version())
+	imgui.SetNextItemWidth(200)
+	if imgui.BeginCombo("##NewActionType", fmt.Sprintf("%s (%#x)", names[in.ActionType], in.ActionType)) {
+		for at, name := range names {
+			if name == "" {
+				continue
+			}
+			if imgui.SelectableBool(fmt.Sprintf("%s (%#x)", name, at)) {
+				in.ActionType = uint16(at)
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.SameLine()
+	imgui.SetNextItemWidth(128)
+	imgui.InputScalar("Target ID##NewAction", imgui.DataTypeU32, uintptr(utils.Ptr(&in.Target)))
+	imgui.SameLine()
+	imgui.BeginDisabledV(t.SounBankLock.Load())
+	if imgui.Button("Add Action##NewAction") {
+		at, target := in.ActionType, in.Target
+		BG(time.Second * 8, "Adding action", "Added action", func(ctx context.Context) {
+			t.AddAction(ctx, eventID, at, target)
+		})
+	}
+	imgui.EndDisabled()
+}
+
 func renderActionsTable(t *be.BankTab) {
 	const flags = DefaultTableFlags | imgui.TableFlagsScrollY
 	if imgui.BeginTableV("EventActionTable", 3, flags, DefaultSize, 0) {
@@ -71,7 +104,7 @@ func renderActionsTable(t *be.BankTab) {
 				t.EventViewer.ActiveAction = action
 			}
 			selected := actionID == t.EventViewer.ActiveAction.Id
-			label := strconv.FormatUint(uint64(actionID), 10)
+			label := idLabel(actionID)
 			if imgui.SelectableBoolPtrV(label, &selected, flags, DefaultSize) {
 				t.EventViewer.ActiveAction = action
 			}
@@ -90,7 +123,7 @@ func renderActionsTable(t *be.BankTab) {
 			}
 
 			imgui.TableSetColumnIndex(2)
-			imgui.Text(strconv.FormatUint(uint64(action.IdExt), 10))
+			imgui.Text(idLabel(action.IdExt))
 		}
 		imgui.EndTable()
 	}
@@ -143,6 +176,9 @@ func renderActionCtxMenu(t *be.BankTab, action *wwise.Action, actionID uint32) {
 			clipboard.Write(clipboard.FmtText, []byte(strconv.FormatUint(uint64(action.IdExt), 10)))
 		}
 	})
+	if imgui.SelectableBool("Find References") {
+		findReferences(t, actionID)
+	}
 }
 
 func renderEventCtxMenu(t *be.BankTab, event *wwise.Event) {
@@ -156,6 +192,9 @@ func renderEventCtxMenu(t *be.BankTab, event *wwise.Event) {
 
 		}
 	})
+	if imgui.SelectableBool("Find References") {
+		findReferences(t, event.Id)
+	}
 }
 
 func renderEventsTable(t *be.BankTab) {
@@ -164,6 +203,7 @@ func renderEventsTable(t *be.BankTab) {
 	if imgui.InputScalar("By event ID", imgui.DataTypeU32, uintptr(utils.Ptr(&t.EventViewer.Filter.Id))) {
 		t.FilterEvents()
 	}
+	renderNewEvent(t)
 	imgui.SeparatorText("")
 
 	if imgui.BeginTableV("EventsTable", 1, DefaultTableFlagsY, DefaultSize, 0) {
@@ -183,7 +223,7 @@ func renderEventsTable(t *be.BankTab) {
 					t.EventViewer.ActiveEvent = event
 				}
 				selected := event.Id == t.EventViewer.ActiveEvent.Id
-				label := strconv.FormatUint(uint64(event.Id), 10)
+				label := idLabel(event.Id)
 				if imgui.SelectableBoolPtr(label, &selected) {
 					t.EventViewer.ActiveEvent = event
 					t.EventViewer.ActiveAction = nil
@@ -199,6 +239,29 @@ func renderEventsTable(t *be.BankTab) {
 	}
 }
 
+func renderNewEvent(t *be.BankTab) {
+	in := &t.NewEvent
+	imgui.SeparatorText("New Event")
+	imgui.SetNextItemWidth(160)
+	if imgui.InputTextWithHint("Event Name##NewEvent", "Play_...", &in.Name, 0, nil) && in.Name != "" {
+		in.ID = wwise.ShortID(in.Name)
+	}
+	imgui.SetNextItemWidth(160)
+	imgui.InputScalar("Event ID##NewEvent", imgui.DataTypeU32, uintptr(utils.Ptr(&in.ID)))
+	err := t.Bank.CheckEventID(in.ID)
+	if err != nil && in.ID != 0 {
+		imgui.Text(err.Error())
+	}
+	imgui.BeginDisabledV(err != nil)
+	if imgui.Button("Create##NewEvent") {
+		if in.Name != "" && wwise.ShortID(in.Name) == in.ID {
+			GCtx.Names.Add(in.Name)
+		}
+		t.CreateEvent(in.ID)
+	}
+	imgui.EndDisabled()
+}
+
 func renderActionParam(a *wwise.Action) {
 	if imgui.TreeNodeStr("Action Parameter") {
 		switch t := a.ActionParam.(type) {
@@ -214,31 +277,31 @@ func renderActionParam(a *wwise.Action) {
 			t.EnumFadeCurve = renderFadeInCurveCombo(t.EnumFadeCurve)
 			renderActionExceptParamTable(t.ExceptParams)
 		case *wwise.ActionSetStateParam:
-			imgui.Text(fmt.Sprintf("State Group ID: %d", t.StateGroupID))
+			imgui.Text("State Group ID: " + idLabel(t.StateGroupID))
 			imgui.SameLine()
 			imgui.BeginDisabled()
 			imgui.ArrowButton(fmt.Sprintf("GoToStateGroup%d", t.StateGroupID), imgui.DirRight)
 			imgui.EndDisabled()
 
-			imgui.Text(fmt.Sprintf("Target State ID: %d", t.TargetStateID))
+			imgui.Text("Target State ID: " + idLabel(t.TargetStateID))
 			imgui.SameLine()
 			imgui.BeginDisabled()
 			imgui.ArrowButton(fmt.Sprintf("GoToTargetState%d", t.TargetStateID), imgui.DirRight)
 			imgui.EndDisabled()
 		case *wwise.ActionSetSwitchParam:
-			imgui.Text(fmt.Sprintf("Switch Group ID: %d", t.SwitchGroupID))
+			imgui.Text("Switch Group ID: " + idLabel(t.SwitchGroupID))
 			imgui.SameLine()
 			imgui.BeginDisabled()
 			imgui.ArrowButton(fmt.Sprintf("GoToSwitchGroup%d", t.SwitchGroupID), imgui.DirRight)
 			imgui.EndDisabled()
 
-			imgui.Text(fmt.Sprintf("Switch State ID: %d", t.SwitchStateID))
+			imgui.Text("Switch State ID: " + idLabel(t.SwitchStateID))
 			imgui.SameLine()
 			imgui.BeginDisabled()
 			imgui.ArrowButton(fmt.Sprintf("GoToSwitchState%d", t.SwitchStateID), imgui.DirRight)
 			imgui.EndDisabled()
 		case *wwise.ActionSetRTPCParam:
-			imgui.Text(fmt.Sprintf("RTPC ID: %d", t.RTPCID))
+			imgui.Text("RTPC ID: " + idLabel(t.RTPCID))
 			imgui.SameLine()
 			imgui.BeginDisabled()
 			imgui.ArrowButton(fmt.Sprintf("GoToRTPC%d", t.RTPCID), imgui.DirRight)
@@ -339,7 +402,7 @@ func renderActionExceptParamTable(params []wwise.ExceptParam) {
 		for _, param := range params {
 			imgui.TableNextRow()
 			imgui.TableSetColumnIndex(0)
-			imgui.Text(strconv.FormatUint(uint64(param.ID), 10))
+			imgui.Text(idLabel(param.ID))
 			imgui.SameLine()
 			imgui.BeginDisabled()
 			if imgui.ArrowButton(fmt.Sprintf("GoToExceptionTarget%d", param.ID), imgui.DirRight) {
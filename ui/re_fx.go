@@ -1,13 +1,16 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/AllenDang/cimgui-go/imgui"
 	"github.com/AllenDang/cimgui-go/utils"
 	be "github.com/Dekr0/wwise-teller/ui/bank_explorer"
 	dockmanager "github.com/Dekr0/wwise-teller/ui/dock_manager"
+	"github.com/Dekr0/wwise-teller/wio"
 	"github.com/Dekr0/wwise-teller/wwise"
 )
 
@@ -119,6 +122,144 @@ func renderFxTable(t *be.BankTab) {
 	}
 }
 
+// FX slots of a hierarchy object or a bus
+func renderFxChunk(t *be.BankTab, id uint32, f *wwise.FxChunk) {
+	v := t.Version()
+	var edit func() = nil
+
+	bypassAll := f.BitsFxByPass != 0
+	if imgui.Checkbox("Bypass All FX", &bypassAll) {
+		f.BypassFx(bypassAll)
+	}
+
+	if imgui.BeginTableV("FXChunkTable", 5, DefaultTableFlags, DefaultSize, 0) {
+		imgui.TableSetupColumn("Slot")
+		imgui.TableSetupColumn("FX ID")
+		imgui.TableSetupColumn("Plugin")
+		imgui.TableSetupColumn("Is Share Set")
+		imgui.TableSetupColumn("Bypass FX")
+		imgui.TableSetupScrollFreeze(0, 1)
+		imgui.TableHeadersRow()
+		for i := range f.FxChunkItems {
+			fi := &f.FxChunkItems[i]
+
+			imgui.TableNextRow()
+
+			imgui.TableSetColumnIndex(0)
+			imgui.BeginDisabledV(t.SounBankLock.Load())
+			if imgui.Button(fmt.Sprintf("X##RemoveFX%d", i)) {
+				edit = bindDetachFx(t, id, fi.UniqueFxIndex)
+			}
+			imgui.EndDisabled()
+			imgui.SameLine()
+			imgui.Text(strconv.FormatUint(uint64(fi.UniqueFxIndex), 10))
+
+			imgui.TableSetColumnIndex(1)
+			imgui.Text(strconv.FormatUint(uint64(fi.FxId), 10))
+			imgui.SameLine()
+			if imgui.ArrowButton(fmt.Sprintf("##GoToFXID%d", i), imgui.DirRight) {
+				t.SetActiveFX(fi.FxId)
+			}
+
+			imgui.TableSetColumnIndex(2)
+			imgui.Text(fxPluginName(t, fi.FxId))
+
+			imgui.TableSetColumnIndex(3)
+			imgui.BeginDisabled()
+			isShareSet := fi.IsShareSet(v)
+			imgui.Checkbox(fmt.Sprintf("##IsShareSet%d", i), &isShareSet)
+			imgui.EndDisabled()
+
+			imgui.TableSetColumnIndex(4)
+			if v > 145 {
+				bypass := wio.GetBit(fi.BitVector, 0)
+				imgui.BeginDisabledV(bypassAll)
+				if imgui.Checkbox(fmt.Sprintf("##Bypass%d", i), &bypass) {
+					fi.Bypass(bypass)
+				}
+				imgui.EndDisabled()
+			}
+		}
+		imgui.EndTable()
+	}
+
+	slot := uint8(wwise.FxChunkMaxSlot)
+	for s := range uint8(wwise.FxChunkMaxSlot) {
+		if f.Slot(s) == nil {
+			slot = s
+			break
+		}
+	}
+	imgui.BeginDisabledV(slot >= wwise.FxChunkMaxSlot || t.SounBankLock.Load())
+	for _, shareSet := range []bool{false, true} {
+		label := "New FX Custom"
+		if shareSet {
+			label = "New FX Share Set"
+			imgui.SameLine()
+		}
+		imgui.SetNextItemWidth(160)
+		if imgui.BeginCombo(fmt.Sprintf("##%s", label), label) {
+			for _, pluginID := range wwise.NewFxPluginIDs {
+				if imgui.SelectableBool(fmt.Sprintf("%s##%s%d", wwise.PluginNameLUT[int32(pluginID)], label, pluginID)) {
+					edit = bindNewFx(t, id, slot, pluginID, shareSet, t.FxViewer.NewFxRender, t.FxViewer.NewFxBypass && v > 145)
+				}
+			}
+			imgui.EndCombo()
+		}
+	}
+	imgui.SameLine()
+	imgui.Checkbox("Render##NewFx", &t.FxViewer.NewFxRender)
+	if v > 145 {
+		imgui.SameLine()
+		imgui.Checkbox("Bypass##NewFx", &t.FxViewer.NewFxBypass)
+	}
+	imgui.SameLine()
+	imgui.SetNextItemWidth(160)
+	if imgui.BeginCombo("##ExistingFxShareSet", "Existing FX Share Set") {
+		t.Bank.HIRC().FxShareSets.Range(func(key, value any) bool {
+			fxID := key.(uint32)
+			if imgui.SelectableBool(fmt.Sprintf("%d (%s)##ExistingFxShareSet", fxID, fxPluginName(t, fxID))) {
+				edit = func() { t.AttachFx(id, slot, fxID) }
+			}
+			return true
+		})
+		imgui.EndCombo()
+	}
+	imgui.EndDisabled()
+
+	if edit != nil {
+		edit()
+	}
+}
+
+func fxPluginName(t *be.BankTab, fxID uint32) string {
+	var pluginID uint32
+	h := t.Bank.HIRC()
+	if v, in := h.FxShareSets.Load(fxID); in {
+		pluginID = v.(*wwise.FxShareSet).PluginTypeId
+	} else if v, in := h.FxCustoms.Load(fxID); in {
+		pluginID = v.(*wwise.FxCustom).PluginTypeId
+	} else {
+		return "Unknown FX"
+	}
+	if name, in := wwise.PluginNameLUT[int32(pluginID)]; in {
+		return name
+	}
+	return fmt.Sprintf("Plugin ID %d", pluginID)
+}
+
+func bindNewFx(t *be.BankTab, id uint32, slot uint8, pluginID uint32, shareSet bool, render bool, bypass bool) func() {
+	return func() {
+		BG(time.Second * 8, "Creating FX", "Created FX", func(ctx context.Context) {
+			t.NewFx(ctx, id, slot, pluginID, shareSet, render, bypass)
+		})
+	}
+}
+
+func bindDetachFx(t *be.BankTab, id uint32, slot uint8) func() {
+	return func() { t.DetachFx(id, slot) }
+}
+
 func renderFXViewer(open *bool) {
 	if !*open {
 		return
@@ -189,82 +330,113 @@ func renderFxParam(f wwise.FxParam) {
 		renderGainFX(f)
 	case *wwise.Compressor:
 		renderCompressor(f)
+	case *wwise.Expander:
+		renderExpander(f)
+	case *wwise.DelayFX:
+		renderDelayFX(f)
+	case *wwise.MatrixReverb:
+		renderMatrixReverb(f)
+	case *wwise.RoomVerb:
+		renderRoomVerb(f)
+	case *wwise.Flanger:
+		renderFlanger(f)
+	case *wwise.GuitarDistortion:
+		renderGuitarDistortion(f)
+	case *wwise.ConvolutionReverb:
+		renderConvolutionReverb(f)
+	case *wwise.TimeStretch:
+		renderTimeStretch(f)
+	case *wwise.Tremolo:
+		renderTremolo(f)
+	case *wwise.StereoDelay:
+		renderStereoDelay(f)
+	case *wwise.PitchShifter:
+		renderPitchShifter(f)
+	case *wwise.Harmonizer:
+		renderHarmonizer(f)
+	case *wwise.FxPlaceholder:
+		imgui.Text(fmt.Sprintf("Parameters of this plugin are not decoded (%d bytes).", len(f.Data)))
 	}
 }
 
 func renderParametricEQ(f *wwise.ParametricEQ) {
-	size := imgui.NewVec2(160, 160)
 	for i := range f.EQBand {
-		stack := fmt.Sprintf("Band %d", i + 1)
-
-		imgui.BeginChildStrV(stack, size, imgui.ChildFlagsBorders, imgui.WindowFlagsNone)
-
-		imgui.SeparatorText(fmt.Sprintf("Band %d", i + 1))
-
-		b := &f.EQBand[i]
-
-		enabled := b.OnOff != 0
-		imgui.PushIDStr(fmt.Sprintf("%sEnable", stack))
-		if imgui.Checkbox("Enable", &enabled) {
-			if enabled {
-				b.OnOff = 1
-			} else {
-				b.OnOff = 0
-			}
+		renderEQBand(fmt.Sprintf("Band %d", i + 1), &f.EQBand[i])
+		if i < 2 {
+			imgui.SameLine()
 		}
-		imgui.PopID()
+	}
 
-		imgui.BeginDisabledV(!enabled)
+	imgui.SetNextItemWidth(96)
+	imgui.SliderFloat("Output Gain", &f.OutputLevel, -24, 24)
 
-		filterType := int32(b.FilterType)
-		imgui.PushIDStr(fmt.Sprintf("%sCurve", stack))
-		if imgui.ComboStrarrV("Curve", &filterType, wwise.EQFilterNames, int32(wwise.EQFilterTypeCount), 0) {
-			b.FilterType = wwise.EQFilterType(filterType)
-		}
-		imgui.PopID()
+	renderFxFlag("Process LFE", &f.ProcessLFE)
+}
 
-		imgui.BeginDisabledV(
-			b.FilterType == wwise.EQFilterTypeLowPass  || 
-			b.FilterType == wwise.EQFilterTypeHiPass   || 
-			b.FilterType == wwise.EQFilterTypeBandPass ||
-			b.FilterType == wwise.EQFilterTypeNotch,
-		)
-		imgui.PushIDStr(fmt.Sprintf("%sGain", stack))
-		imgui.SliderFloat("Gain", &b.Gain, -24, 24)
-		imgui.PopID()
-		imgui.EndDisabled()
+func renderEQBand(stack string, b *wwise.ParametricEQBand) {
+	size := imgui.NewVec2(160, 160)
 
-		imgui.PushIDStr(fmt.Sprintf("%sFreq.", stack))
-		imgui.SliderFloat("Freq.", &b.Frequency, 20, 20000)
-		imgui.PopID()
+	imgui.BeginChildStrV(stack, size, imgui.ChildFlagsBorders, imgui.WindowFlagsNone)
 
-		imgui.BeginDisabledV(
-			b.FilterType == wwise.EQFilterTypeLowPass  || 
-			b.FilterType == wwise.EQFilterTypeHiPass   || 
-			b.FilterType == wwise.EQFilterTypeLowShelf ||
-			b.FilterType == wwise.EQFilterTypeHiShelf,
-		)
-		imgui.PushIDStr(fmt.Sprintf("%sQ", stack))
-		imgui.SliderFloat("Q", &b.QFactor, 0.5, 100)
-		imgui.PopID()
-		imgui.EndDisabled()
+	imgui.SeparatorText(stack)
 
-		imgui.EndDisabled()
-		imgui.EndChild()
-		if i < 2 {
-			imgui.SameLine()
+	enabled := b.OnOff != 0
+	imgui.PushIDStr(fmt.Sprintf("%sEnable", stack))
+	if imgui.Checkbox("Enable", &enabled) {
+		if enabled {
+			b.OnOff = 1
+		} else {
+			b.OnOff = 0
 		}
 	}
+	imgui.PopID()
 
-	imgui.SetNextItemWidth(96)
-	imgui.SliderFloat("Output Gain", &f.OutputLevel, -24, 24)
+	imgui.BeginDisabledV(!enabled)
 
-	lfe := f.ProcessLFE != 0
-	if imgui.Checkbox("Process LFE", &lfe) {
-		if lfe {
-			f.ProcessLFE = 1
+	filterType := int32(b.FilterType)
+	imgui.PushIDStr(fmt.Sprintf("%sCurve", stack))
+	if imgui.ComboStrarrV("Curve", &filterType, wwise.EQFilterNames, int32(wwise.EQFilterTypeCount), 0) {
+		b.FilterType = wwise.EQFilterType(filterType)
+	}
+	imgui.PopID()
+
+	imgui.BeginDisabledV(
+		b.FilterType == wwise.EQFilterTypeLowPass  || 
+		b.FilterType == wwise.EQFilterTypeHiPass   || 
+		b.FilterType == wwise.EQFilterTypeBandPass ||
+		b.FilterType == wwise.EQFilterTypeNotch,
+	)
+	imgui.PushIDStr(fmt.Sprintf("%sGain", stack))
+	imgui.SliderFloat("Gain", &b.Gain, -24, 24)
+	imgui.PopID()
+	imgui.EndDisabled()
+
+	imgui.PushIDStr(fmt.Sprintf("%sFreq.", stack))
+	imgui.SliderFloat("Freq.", &b.Frequency, 20, 20000)
+	imgui.PopID()
+
+	imgui.BeginDisabledV(
+		b.FilterType == wwise.EQFilterTypeLowPass  || 
+		b.FilterType == wwise.EQFilterTypeHiPass   || 
+		b.FilterType == wwise.EQFilterTypeLowShelf ||
+		b.FilterType == wwise.EQFilterTypeHiShelf,
+	)
+	imgui.PushIDStr(fmt.Sprintf("%sQ", stack))
+	imgui.SliderFloat("Q", &b.QFactor, 0.5, 100)
+	imgui.PopID()
+	imgui.EndDisabled()
+
+	imgui.EndDisabled()
+	imgui.EndChild()
+}
+
+func renderFxFlag(label string, flag *uint8) {
+	set := *flag != 0
+	if imgui.Checkbox(label, &set) {
+		if set {
+			*flag = 1
 		} else {
-			f.ProcessLFE = 0
+			*flag = 0
 		}
 	}
 }
@@ -397,3 +569,256 @@ func renderPeakLimiter(f *wwise.PeakLimiter) {
 		}
 	}
 }
+
+func renderExpander(f *wwise.Expander) {
+	imgui.PushItemWidth(96.0)
+	imgui.SliderFloat("Threshold", &f.Threshold, -96.3, 0.0)
+	imgui.SliderFloat("Ratio", &f.Ratio, 1, 50)
+	imgui.SliderFloat("Attack", &f.Attack, 0, 2)
+	imgui.SliderFloat("Release", &f.Release, 0, 2)
+	imgui.SliderFloat("Output Gain", &f.OutputGain, -24, 24)
+	imgui.PopItemWidth()
+
+	renderFxFlag("Process LFE", &f.ProcessLFE)
+	renderFxFlag("Channel link", &f.ChannelLink)
+}
+
+func renderDelayFX(f *wwise.DelayFX) {
+	imgui.PushItemWidth(96.0)
+	imgui.SliderFloat("Delay Time", &f.DelayTime, 0.001, 10)
+	imgui.SliderFloat("Feedback", &f.Feedback, 0, 100)
+	imgui.SliderFloat("Wet / Dry Mix", &f.WetDryMix, 0, 100)
+	imgui.SliderFloat("Output Level", &f.OutputLevel, -96.3, 0)
+	imgui.PopItemWidth()
+
+	renderFxFlag("Enable Feedback", &f.FeedbackEnabled)
+	renderFxFlag("Process LFE", &f.ProcessLFE)
+}
+
+func renderMatrixReverb(f *wwise.MatrixReverb) {
+	imgui.PushItemWidth(96.0)
+	imgui.SliderFloat("Reverb Time", &f.ReverbTime, 0.1, 10)
+	imgui.SliderFloat("HF Ratio", &f.HFRatio, 0.5, 10)
+	imgui.SliderFloat("Pre-Delay", &f.PreDelay, 0, 1000)
+	imgui.SliderFloat("Dry Level", &f.DryLevel, -96.3, 0)
+	imgui.SliderFloat("Wet Level", &f.WetLevel, -96.3, 0)
+	imgui.PopItemWidth()
+
+	renderFxFlag("Process LFE", &f.ProcessLFE)
+	imgui.Text(fmt.Sprintf("# of Delays: %d", f.NumberOfDelays))
+}
+
+func renderRoomVerb(f *wwise.RoomVerb) {
+	imgui.PushItemWidth(96.0)
+	imgui.SeparatorText("Reverb")
+	imgui.SliderFloat("Decay Time", &f.DecayTime, 0.1, 10)
+	imgui.SliderFloat("HF Damping", &f.HFDamping, 0.5, 10)
+	imgui.SliderFloat("Diffusion", &f.Diffusion, 0, 100)
+	imgui.SliderFloat("Stereo Width", &f.StereoWidth, 0, 180)
+
+	imgui.SeparatorText("Tone Controls")
+	for i := range f.Filters {
+		filter := &f.Filters[i]
+		imgui.PushIDStr(fmt.Sprintf("RoomVerbFilter%d", i))
+		imgui.Text(fmt.Sprintf("Filter %d", i + 1))
+		imgui.SameLine()
+		imgui.SliderFloat("Gain", &filter.Gain, -24, 24)
+		imgui.SameLine()
+		imgui.SliderFloat("Freq.", &filter.Frequency, 20, 20000)
+		imgui.SameLine()
+		imgui.SliderFloat("Q", &filter.QFactor, 0.1, 20)
+		imgui.PopID()
+	}
+
+	imgui.SeparatorText("Output Levels")
+	imgui.SliderFloat("Front", &f.FrontLevel, -96.3, 0)
+	imgui.SliderFloat("Rear", &f.RearLevel, -96.3, 0)
+	imgui.SliderFloat("Center", &f.CenterLevel, -96.3, 0)
+	imgui.SliderFloat("LFE", &f.LFELevel, -96.3, 0)
+	imgui.SliderFloat("Dry", &f.DryLevel, -96.3, 0)
+	imgui.SliderFloat("Early Reflections", &f.ERLevel, -96.3, 0)
+	imgui.SliderFloat("Reverb", &f.ReverbLevel, -96.3, 0)
+	imgui.PopItemWidth()
+}
+
+func renderFxLFO(l *wwise.FxLFO) {
+	imgui.SeparatorText("LFO")
+	imgui.SliderFloat("Frequency", &l.Frequency, 0, 20)
+	waveform := int32(l.Waveform)
+	if imgui.ComboStrarrV("Waveform", &waveform, wwise.LFOWaveformNames, int32(wwise.LFOWaveformCount), 0) {
+		l.Waveform = wwise.LFOWaveform(waveform)
+	}
+	imgui.SliderFloat("Smoothing", &l.Smoothing, 0, 100)
+	imgui.SliderFloat("PWM", &l.PWM, 10, 90)
+	imgui.SliderFloat("Phase Offset", &l.PhaseOffset, 0, 360)
+	phaseMode := int32(l.PhaseMode)
+	if imgui.ComboStrarrV("Phase Mode", &phaseMode, wwise.LFOPhaseModeNames, int32(wwise.LFOPhaseModeCount), 0) {
+		l.PhaseMode = wwise.LFOPhaseMode(phaseMode)
+	}
+	imgui.SliderFloat("Phase Spread", &l.PhaseSpread, 0, 360)
+}
+
+func renderFxFilter(f *wwise.FxFilter) {
+	imgui.SeparatorText("Filter")
+	filterType := int32(f.FilterType)
+	if imgui.ComboStrarrV("Curve", &filterType, wwise.EQFilterNames, int32(wwise.EQFilterTypeCount), 0) {
+		f.FilterType = wwise.EQFilterType(filterType)
+	}
+	imgui.SliderFloat("Gain", &f.Gain, -24, 24)
+	imgui.SliderFloat("Freq.", &f.Frequency, 20, 20000)
+	imgui.SliderFloat("Q", &f.QFactor, 0.5, 100)
+}
+
+func renderFlanger(f *wwise.Flanger) {
+	imgui.PushItemWidth(96.0)
+	imgui.SliderFloat("Delay Time", &f.DelayTime, 0, 100)
+	imgui.SliderFloat("Mod. Depth", &f.ModDepth, 0, 100)
+	imgui.SliderFloat("Dry Level", &f.DryLevel, 0, 1)
+	imgui.SliderFloat("Feedforward Level", &f.FfwdLevel, 0, 1)
+	imgui.SliderFloat("Feedback Level", &f.FbackLevel, 0, 1)
+	imgui.SliderFloat("Output Level", &f.OutputLevel, -96.3, 0)
+	imgui.SliderFloat("Wet / Dry Mix", &f.WetDryMix, 0, 100)
+	renderFxFlag("Enable LFO", &f.EnableLFO)
+	imgui.BeginDisabledV(f.EnableLFO == 0)
+	renderFxLFO(&f.LFO)
+	imgui.EndDisabled()
+	imgui.PopItemWidth()
+
+	renderFxFlag("Process Center", &f.ProcessCenter)
+	renderFxFlag("Process LFE", &f.ProcessLFE)
+}
+
+func renderGuitarDistortion(f *wwise.GuitarDistortion) {
+	for i := range f.PreEQ {
+		renderEQBand(fmt.Sprintf("Pre EQ Band %d", i + 1), &f.PreEQ[i])
+		if i < 2 {
+			imgui.SameLine()
+		}
+	}
+	for i := range f.PostEQ {
+		renderEQBand(fmt.Sprintf("Post EQ Band %d", i + 1), &f.PostEQ[i])
+		if i < 2 {
+			imgui.SameLine()
+		}
+	}
+
+	imgui.PushItemWidth(96.0)
+	distortionType := int32(f.DistortionType)
+	if imgui.ComboStrarrV("Distortion", &distortionType, wwise.DistortionTypeNames, int32(wwise.DistortionTypeCount), 0) {
+		f.DistortionType = wwise.DistortionType(distortionType)
+	}
+	imgui.SliderFloat("Drive", &f.Drive, 0, 100)
+	imgui.SliderFloat("Tone", &f.Tone, 0, 100)
+	imgui.SliderFloat("Rectification", &f.Rectification, 0, 100)
+	imgui.SliderFloat("Output Level", &f.OutputLevel, -96.3, 24)
+	imgui.SliderFloat("Wet / Dry Mix", &f.WetDryMix, 0, 100)
+	imgui.PopItemWidth()
+}
+
+func renderConvolutionReverb(f *wwise.ConvolutionReverb) {
+	imgui.PushItemWidth(96.0)
+	imgui.SliderFloat("Pre-Delay", &f.PreDelay, 0, 1000)
+	imgui.SliderFloat("Front / Rear Delay", &f.FrontRearDelay, 0, 1000)
+	imgui.SliderFloat("Stereo Width", &f.StereoWidth, 0, 180)
+
+	imgui.SeparatorText("Input Levels")
+	imgui.SliderFloat("Center##Input", &f.InputCenterLevel, -96.3, 0)
+	imgui.SliderFloat("LFE##Input", &f.InputLFELevel, -96.3, 0)
+	if f.InputStereoWidth != nil {
+		imgui.SliderFloat("Stereo Width##Input", f.InputStereoWidth, 0, 180)
+	}
+
+	imgui.SeparatorText("Output Levels")
+	imgui.SliderFloat("Front", &f.FrontLevel, -96.3, 0)
+	imgui.SliderFloat("Rear", &f.RearLevel, -96.3, 0)
+	imgui.SliderFloat("Center", &f.CenterLevel, -96.3, 0)
+	imgui.SliderFloat("LFE", &f.LFELevel, -96.3, 0)
+	imgui.SliderFloat("Dry", &f.DryLevel, -96.3, 0)
+	imgui.SliderFloat("Wet", &f.WetLevel, -96.3, 0)
+	imgui.PopItemWidth()
+}
+
+func renderTimeStretch(f *wwise.TimeStretch) {
+	imgui.PushItemWidth(96.0)
+	imgui.InputScalar("Window Size", imgui.DataTypeU32, uintptr(utils.Ptr(&f.WindowSize)))
+	imgui.SliderFloat("Time Stretch (%)", &f.TimeStretch, 25, 1600)
+	imgui.SliderFloat("Time Stretch Random (%)", &f.TimeStretchRandom, 0, 100)
+	imgui.SliderFloat("Output Gain", &f.OutputGain, -24, 24)
+	imgui.PopItemWidth()
+}
+
+func renderTremolo(f *wwise.Tremolo) {
+	imgui.PushItemWidth(96.0)
+	imgui.SliderFloat("Mod. Depth", &f.ModDepth, 0, 100)
+	imgui.SliderFloat("Output Gain", &f.OutputGain, -24, 24)
+	renderFxLFO(&f.LFO)
+	imgui.PopItemWidth()
+
+	renderFxFlag("Process Center", &f.ProcessCenter)
+	renderFxFlag("Process LFE", &f.ProcessLFE)
+}
+
+func renderStereoDelay(f *wwise.StereoDelay) {
+	imgui.PushItemWidth(96.0)
+	for i, channel := range []string{"Left", "Right"} {
+		c := &f.Channels[i]
+		imgui.SeparatorText(channel)
+		imgui.PushIDStr(channel)
+		imgui.InputScalar("Input", imgui.DataTypeU32, uintptr(utils.Ptr(&c.InputType)))
+		imgui.SliderFloat("Delay Time", &c.DelayTime, 0, 5)
+		imgui.SliderFloat("Feedback", &c.Feedback, -96.3, 0)
+		imgui.SliderFloat("Cross-feed", &c.CrossFeed, -96.3, 0)
+		imgui.PopID()
+	}
+	renderFxFilter(&f.Filter)
+
+	imgui.SeparatorText("Output")
+	imgui.SliderFloat("Dry Level", &f.DryLevel, -96.3, 0)
+	imgui.SliderFloat("Wet Level", &f.WetLevel, -96.3, 0)
+	imgui.SliderFloat("Front / Rear Balance", &f.FrontRearBalance, -100, 100)
+	imgui.PopItemWidth()
+
+	renderFxFlag("Enable Feedback", &f.EnableFeedback)
+	renderFxFlag("Enable Cross-feed", &f.EnableCrossFeed)
+}
+
+func renderPitchShifter(f *wwise.PitchShifter) {
+	imgui.PushItemWidth(96.0)
+	imgui.InputScalar("Input", imgui.DataTypeU32, uintptr(utils.Ptr(&f.InputType)))
+	imgui.SliderFloat("Pitch (cents)", &f.Pitch, -2400, 2400)
+	imgui.SliderFloat("Delay Time", &f.DelayTime, 10, 100)
+	imgui.SliderFloat("Dry Level", &f.DryLevel, -96.3, 0)
+	imgui.SliderFloat("Wet Level", &f.WetLevel, -96.3, 0)
+	renderFxFilter(&f.Filter)
+	imgui.PopItemWidth()
+
+	renderFxFlag("Sync Dry", &f.SyncDry)
+	renderFxFlag("Process LFE", &f.ProcessLFE)
+}
+
+func renderHarmonizer(f *wwise.Harmonizer) {
+	imgui.PushItemWidth(96.0)
+	imgui.InputScalar("Input", imgui.DataTypeU32, uintptr(utils.Ptr(&f.InputType)))
+	imgui.InputScalar("Window Size", imgui.DataTypeU32, uintptr(utils.Ptr(&f.WindowSize)))
+	imgui.SliderFloat("Dry Level", &f.DryLevel, -96.3, 0)
+	imgui.SliderFloat("Wet Level", &f.WetLevel, -96.3, 0)
+	imgui.PopItemWidth()
+
+	renderFxFlag("Sync Dry", &f.SyncDry)
+	renderFxFlag("Process LFE", &f.ProcessLFE)
+
+	for i := range f.Voices {
+		voice := &f.Voices[i]
+		imgui.PushIDStr(fmt.Sprintf("Voice%d", i))
+		imgui.SeparatorText(fmt.Sprintf("Voice %d", i + 1))
+		renderFxFlag("Enable", &voice.Enable)
+		imgui.BeginDisabledV(voice.Enable == 0)
+		imgui.PushItemWidth(96.0)
+		imgui.SliderFloat("Pitch (cents)", &voice.Pitch, -3600, 3600)
+		imgui.SliderFloat("Gain", &voice.Gain, -96.3, 24)
+		renderFxFilter(&voice.Filter)
+		imgui.PopItemWidth()
+		imgui.EndDisabled()
+		imgui.PopID()
+	}
+}
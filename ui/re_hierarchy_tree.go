@@ -55,7 +55,7 @@ func renderActorMixerHircNode(t *be.BankTab, node *wwise.ActorMixerHircNode) {
 	id, err := o.HircID()
 	if err != nil { panic("Panic Trap") }
 
-	sid = strconv.FormatUint(uint64(id), 10)
+	sid = idLabel(id)
 	selected = t.ActorMixerViewer.Selected(id)
 
 	imgui.TableNextRow()
@@ -119,6 +119,10 @@ func renderActorMixerHircCtx(
 		})
 	}
 
+	if imgui.SelectableBool("Find References") {
+		findReferences(t, id)
+	}
+
 	if len(node.Leafs) <= 0 {
 		return
 	}
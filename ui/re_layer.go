@@ -1,22 +1,223 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/AllenDang/cimgui-go/imgui"
+	"github.com/AllenDang/cimgui-go/implot"
+	"github.com/AllenDang/cimgui-go/utils"
+	be "github.com/Dekr0/wwise-teller/ui/bank_explorer"
 	"github.com/Dekr0/wwise-teller/wwise"
 )
 
-func renderLayer(layerCntr *wwise.LayerCntr) {
+func renderLayer(t *be.BankTab, layerCntr *wwise.LayerCntr) {
 	if imgui.TreeNodeExStr("Layer / Blend Tracks") {
+		imgui.BeginDisabledV(t.SounBankLock.Load())
+		if imgui.Button("New Layer / Blend Track") {
+			BG(time.Second * 8, "Adding layer", "Added layer", func(ctx context.Context) {
+				t.NewLayer(ctx, layerCntr)
+			})
+		}
+		imgui.EndDisabled()
+
+		var removeLayer func() = nil
 		if imgui.BeginTabBarV("LayerTabBar", DefaultTabFlags) {
-			for _, layer := range layerCntr.Layers  {
+			for i := range layerCntr.Layers {
+				layer := &layerCntr.Layers[i]
 				if imgui.BeginTabItem(fmt.Sprintf("Layer / Blend Track %d", layer.Id)) {
+					if imgui.Button("Remove Layer / Blend Track") {
+						removeLayer = bindRemoveLayer(layerCntr, layer.Id)
+					}
+					renderLayerRTPC(layer)
+					renderLayerAssociations(layerCntr, layer)
+					renderRTPC(layer.Id, &layer.InitialRTPC, "Initial RTPC")
 					imgui.EndTabItem()
 				}
 			}
 			imgui.EndTabBar()
 		}
+		if removeLayer != nil {
+			removeLayer()
+		}
 		imgui.TreePop()
 	}
 }
+
+func bindRemoveLayer(l *wwise.LayerCntr, id uint32) func() {
+	return func() { l.RemoveLayer(id) }
+}
+
+func renderLayerRTPC(layer *wwise.Layer) {
+	imgui.SetNextItemWidth(128)
+	imgui.InputScalar(
+		fmt.Sprintf("Crossfade Parameter ID##%dRTPCId", layer.Id),
+		imgui.DataTypeU32,
+		uintptr(utils.Ptr(&layer.RTPCId)),
+	)
+	rtpcType := int32(layer.RTPCType)
+	imgui.SetNextItemWidth(128)
+	if imgui.ComboStrarr(
+		fmt.Sprintf("Crossfade Parameter Type##%dRTPCType", layer.Id),
+		&rtpcType,
+		wwise.RTPCTypeName,
+		wwise.RTPCTypeCount,
+	) {
+		layer.RTPCType = uint8(rtpcType)
+	}
+}
+
+func renderLayerAssociations(l *wwise.LayerCntr, layer *wwise.Layer) {
+	if !imgui.TreeNodeExStr(fmt.Sprintf("Associated Children##%dAssoc", layer.Id)) {
+		return
+	}
+	defer imgui.TreePop()
+
+	var associate func() = nil
+	imgui.SetNextItemWidth(128)
+	if imgui.BeginCombo(fmt.Sprintf("Associate Child##%dAssocCombo", layer.Id), "") {
+		for _, child := range l.Container.Children {
+			if layer.Association(child) != nil {
+				continue
+			}
+			if imgui.SelectableBool(strconv.FormatUint(uint64(child), 10)) {
+				associate = bindAssociateLayer(l, layer.Id, child)
+			}
+		}
+		imgui.EndCombo()
+	}
+	if associate != nil {
+		associate()
+	}
+
+	var unassociate func() = nil
+	for i := range layer.LayerRTPCs {
+		a := &layer.LayerRTPCs[i]
+		imgui.PushIDStr(fmt.Sprintf("%dUnassoc%d", layer.Id, a.AssociatedChildID))
+		if imgui.Button("X") {
+			unassociate = bindUnassociateLayer(layer, a.AssociatedChildID)
+		}
+		imgui.PopID()
+		imgui.SameLine()
+		if imgui.TreeNodeExStrStr(
+			fmt.Sprintf("%dAssoc%d", layer.Id, a.AssociatedChildID), 0,
+			"Child " + idLabel(a.AssociatedChildID),
+		) {
+			renderLayerCurve(fmt.Sprintf("%d%d", layer.Id, a.AssociatedChildID), a)
+			imgui.TreePop()
+		}
+	}
+	if unassociate != nil {
+		unassociate()
+	}
+}
+
+func bindAssociateLayer(l *wwise.LayerCntr, layerID uint32, child uint32) func() {
+	return func() {
+		// Play at full volume over the whole game parameter range by default
+		l.Associate(layerID, child, wwise.LayerCrossfadeCurve(0, 100, 0, 0))
+	}
+}
+
+func bindUnassociateLayer(layer *wwise.Layer, child uint32) func() {
+	return func() { layer.Unassociate(child) }
+}
+
+// Crossfade curve of a child. X is the game parameter value and Y is the volume
+// (dB) of the child.
+func renderLayerCurve(stackID string, a *wwise.LayerRTPC) {
+	points := a.RTPCGraphPoints
+	var edit func() = nil
+	const flags = DefaultTableFlags | imgui.TableFlagsScrollY
+	if imgui.BeginTableV(stackID + "Points", 5, flags, imgui.NewVec2(0, 160), 0) {
+		imgui.TableSetupColumnV("", imgui.TableColumnFlagsWidthFixed, 0, 0)
+		imgui.TableSetupColumnV("", imgui.TableColumnFlagsWidthFixed, 0, 0)
+		imgui.TableSetupColumnV("X", imgui.TableColumnFlagsWidthFixed, 0, 0)
+		imgui.TableSetupColumnV("Volume", imgui.TableColumnFlagsWidthFixed, 0, 0)
+		imgui.TableSetupColumn("Curve Interpolation")
+		imgui.TableSetupScrollFreeze(0, 1)
+		imgui.TableHeadersRow()
+
+		for i := range points {
+			p := &points[i]
+			imgui.TableNextRow()
+
+			imgui.TableSetColumnIndex(0)
+			imgui.BeginDisabledV(len(points) <= 2)
+			imgui.PushIDStr(fmt.Sprintf("%sRmPoint%d", stackID, i))
+			if imgui.Button("X") {
+				edit = bindRemoveLayerCurvePoint(a, i)
+			}
+			imgui.PopID()
+			imgui.EndDisabled()
+
+			imgui.TableSetColumnIndex(1)
+			imgui.BeginDisabledV(i >= len(points) - 1)
+			imgui.PushIDStr(fmt.Sprintf("%sAppendPoint%d", stackID, i))
+			if imgui.Button("+") {
+				edit = bindInsertLayerCurvePoint(a, i)
+			}
+			imgui.PopID()
+			imgui.EndDisabled()
+
+			imgui.TableSetColumnIndex(2)
+			imgui.SetNextItemWidth(128)
+			lo, hi := float32(-8192.0), float32(8192.0)
+			if i > 0 {
+				lo = points[i - 1].From + 1e-4
+			}
+			if i < len(points) - 1 {
+				hi = points[i + 1].From - 1e-4
+			}
+			imgui.SliderFloat(fmt.Sprintf("##%sX%d", stackID, i), &p.From, lo, hi)
+
+			imgui.TableSetColumnIndex(3)
+			imgui.SetNextItemWidth(128)
+			imgui.SliderFloat(fmt.Sprintf("##%sY%d", stackID, i), &p.To, wwise.LayerCurveSilence, 0)
+
+			imgui.TableSetColumnIndex(4)
+			imgui.SetNextItemWidth(-1)
+			curve := int32(p.Interp)
+			if imgui.ComboStrarr(
+				fmt.Sprintf("##%sCurve%d", stackID, i),
+				&curve, wwise.InterpCurveTypeName, int32(wwise.InterpCurveTypeCount),
+			) {
+				p.Interp = uint32(curve)
+			}
+		}
+		imgui.EndTable()
+	}
+	if edit != nil {
+		edit()
+	}
+
+	const plotFlags = implot.FlagsNoLegend | implot.FlagsNoTitle | implot.FlagsNoFrame | implot.FlagsCanvasOnly
+	const axisFlags = implot.AxisFlagsNoLabel | implot.AxisFlagsAutoFit
+	xs := make([]float32, len(a.RTPCGraphPoints))
+	ys := make([]float32, len(a.RTPCGraphPoints))
+	for i, p := range a.RTPCGraphPoints {
+		xs[i], ys[i] = p.From, p.To
+	}
+	if implot.BeginPlotV(stackID + "Plot", imgui.Vec2{X: -1, Y: 128}, plotFlags) {
+		implot.SetupAxesV("", "", axisFlags, axisFlags)
+		implot.PlotLineFloatPtrFloatPtr(stackID + "Line", utils.SliceToPtr(xs), utils.SliceToPtr(ys), int32(len(xs)))
+		implot.EndPlot()
+	}
+}
+
+func bindRemoveLayerCurvePoint(a *wwise.LayerRTPC, i int) func() {
+	return func() { a.RTPCGraphPoints = slices.Delete(a.RTPCGraphPoints, i, i + 1) }
+}
+
+// Insert a point half way between point i and i + 1
+func bindInsertLayerCurvePoint(a *wwise.LayerRTPC, i int) func() {
+	return func() {
+		p, n := a.RTPCGraphPoints[i], a.RTPCGraphPoints[i + 1]
+		a.RTPCGraphPoints = slices.Insert(a.RTPCGraphPoints, i + 1, wwise.RTPCGraphPoint{
+			From: (p.From + n.From) / 2, To: (p.To + n.To) / 2, Interp: p.Interp,
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+	dockmanager "github.com/Dekr0/wwise-teller/ui/dock_manager"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func renderDiagnostics(open *bool) {
+	if !*open {
+		return
+	}
+	imgui.BeginV(dockmanager.DockWindowNames[dockmanager.DiagnosticsTag], open, imgui.WindowFlagsNone)
+	defer imgui.End()
+	if !*open {
+		return
+	}
+	activeBank, valid := BnkMngr.ActiveBankV()
+	if !valid || activeBank.SounBankLock.Load() {
+		return
+	}
+	viewer := &activeBank.LintViewer
+
+	if imgui.Button("Run Lint") {
+		activeBank.Lint()
+	}
+	imgui.SameLine()
+	imgui.Checkbox("Hide Warning", &viewer.HideWarning)
+	if !viewer.Ran {
+		return
+	}
+
+	numErr, numWarn := 0, 0
+	for _, d := range viewer.Diagnostics {
+		switch d.Severity {
+		case wwise.SeverityError:
+			numErr += 1
+		case wwise.SeverityWarning:
+			numWarn += 1
+		}
+	}
+	imgui.SameLine()
+	imgui.Text(fmt.Sprintf("%d error(s), %d warning(s)", numErr, numWarn))
+
+	const flags = DefaultTableFlags | imgui.TableFlagsScrollY
+	if imgui.BeginTableV("DiagnosticsTable", 4, flags, DefaultSize, 0) {
+		imgui.TableSetupColumnV("Severity", imgui.TableColumnFlagsWidthFixed, 0, 0)
+		imgui.TableSetupColumnV("ID", imgui.TableColumnFlagsWidthFixed, 0, 0)
+		imgui.TableSetupColumnV("Diagnostic", imgui.TableColumnFlagsWidthFixed, 0, 0)
+		imgui.TableSetupColumn("Message")
+		imgui.TableSetupScrollFreeze(0, 1)
+		imgui.TableHeadersRow()
+
+		const selectableFlags = imgui.SelectableFlagsSpanAllColumns |
+			                    imgui.SelectableFlagsAllowOverlap
+		for i, d := range viewer.Diagnostics {
+			if viewer.HideWarning && d.Severity != wwise.SeverityError {
+				continue
+			}
+			imgui.TableNextRow()
+			imgui.TableSetColumnIndex(0)
+			imgui.PushIDInt(int32(i))
+			if imgui.SelectableBoolV(wwise.SeverityName[d.Severity], false, selectableFlags, DefaultSize) {
+				goToHircObj(activeBank, d.ID, d.HircType)
+			}
+			if imgui.BeginPopupContextItem() {
+				if imgui.SelectableBool("Find References") {
+					findReferences(activeBank, d.ID)
+				}
+				imgui.EndPopup()
+			}
+			imgui.PopID()
+
+			imgui.TableSetColumnIndex(1)
+			imgui.Text(idLabel(d.ID))
+			imgui.TableSetColumnIndex(2)
+			imgui.Text(wwise.LintCodeName[d.Code])
+			imgui.TableSetColumnIndex(3)
+			imgui.Text(d.Message)
+		}
+		imgui.EndTable()
+	}
+}
@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"context"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/AllenDang/cimgui-go/imgui"
 	"github.com/AllenDang/cimgui-go/utils"
@@ -17,6 +20,8 @@ func renderModulatorTable(t *be.BankTab) {
 	useViDown()
 	useViShiftDown()
 
+	renderNewModulator(t)
+
 	imgui.SeparatorText("Filter")
 
 	filterState := &t.ModulatorViewer.Filter
@@ -51,6 +56,10 @@ func renderModulatorTable(t *be.BankTab) {
 	}
 	imgui.SeparatorText("")
 
+	if t.ModulatorViewer.ActiveModulator != nil {
+		renderModulator(t.ModulatorViewer.ActiveModulator.(*wwise.Modulator))
+	}
+
 	if imgui.Shortcut(UnFocusQuerySC) {
 		focusTable = true
 		imgui.SetKeyboardFocusHere()
@@ -97,3 +106,97 @@ func renderModulatorTable(t *be.BankTab) {
 		imgui.EndTable()
 	}
 }
+
+func renderNewModulator(t *be.BankTab) {
+	imgui.BeginDisabledV(t.SounBankLock.Load())
+	imgui.SetNextItemWidth(160)
+	if imgui.BeginCombo("##NewModulator", "New Modulator") {
+		var create func() = nil
+		for _, _type := range wwise.ModulatorTypes[1:] {
+			if imgui.SelectableBool(wwise.HircTypeName[_type]) {
+				create = bindNewModulator(t, _type)
+			}
+		}
+		imgui.EndCombo()
+		if create != nil {
+			create()
+		}
+	}
+	imgui.EndDisabled()
+}
+
+func bindNewModulator(t *be.BankTab, _type wwise.HircType) func() {
+	return func() {
+		BG(time.Second * 8, "Creating modulator", "Created modulator", func(ctx context.Context) {
+			t.NewModulator(ctx, _type)
+		})
+	}
+}
+
+var ModulatorScopeNames []string = []string{
+	"Voice", "Note / Event", "Game Object", "Global",
+}
+
+var ModulatorLFOWaveformNames []string = []string{
+	"Sine", "Triangle", "Square", "Saw Up", "Saw Down", "Random",
+}
+
+var ModulatorTriggerOnNames []string = []string{
+	"Play", "Note Off",
+}
+
+func renderModulator(m *wwise.Modulator) {
+	if !imgui.TreeNodeExStr(fmt.Sprintf("%s %d", wwise.HircTypeName[m.ModulatorType], m.Id)) {
+		return
+	}
+	for _, p := range wwise.ModulatorProps[m.ModulatorType] {
+		val, _ := m.Prop(p)
+		label := fmt.Sprintf("%s##%dModulatorProp", wwise.ModulatorPropTypeName[p], m.Id)
+		imgui.SetNextItemWidth(160)
+		switch p {
+		case wwise.ModulatorPropTypeScope:
+			renderModulatorEnumProp(m, p, label, ModulatorScopeNames, val)
+		case wwise.ModulatorPropTypeLFOWaveform:
+			renderModulatorEnumProp(m, p, label, ModulatorLFOWaveformNames, val)
+		case wwise.ModulatorPropTypeEnvelopeTriggerOn:
+			renderModulatorEnumProp(m, p, label, ModulatorTriggerOnNames, val)
+		case wwise.ModulatorPropTypeEnvelopeStopPlayback:
+			stop := val != 0
+			if imgui.Checkbox(label, &stop) {
+				if stop {
+					m.SetProp(p, 1)
+				} else {
+					m.SetProp(p, 0)
+				}
+			}
+		case wwise.ModulatorPropTypeTimeLoops:
+			loops := int32(val)
+			if imgui.InputInt(label, &loops) && loops >= 0 {
+				m.SetProp(p, float32(loops))
+			}
+		default:
+			if imgui.InputFloat(label, &val) {
+				m.SetProp(p, val)
+			}
+		}
+	}
+	renderRTPC(m.Id, &m.RTPC, "Modulator RTPC")
+	imgui.TreePop()
+}
+
+func renderModulatorEnumProp(m *wwise.Modulator, p wwise.ModulatorPropType, label string, names []string, val float32) {
+	i := int32(val)
+	preview := strconv.FormatInt(int64(i), 10)
+	if i >= 0 && int(i) < len(names) {
+		preview = names[i]
+	}
+	if imgui.BeginCombo(label, preview) {
+		for j, name := range names {
+			selected := int32(j) == i
+			if imgui.SelectableBoolPtr(name, &selected) {
+				m.SetProp(p, float32(j))
+			}
+		}
+		imgui.EndCombo()
+	}
+}
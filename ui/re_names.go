@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+	"github.com/Dekr0/wwise-teller/db"
+	"github.com/Dekr0/wwise-teller/parser"
+	"github.com/Dekr0/wwise-teller/wwise"
+	"golang.design/x/clipboard"
+)
+
+// Display label of an ID. Use the resolved name if there's any.
+func idLabel(id uint32) string {
+	return GCtx.Names.Label(id)
+}
+
+// Load all name files in config.json and all user added names in the
+// database.
+func loadNames(ctx context.Context) {
+	for _, path := range GCtx.Config.NameFiles {
+		count, err := parser.ParseNames(path, GCtx.Names)
+		if err != nil {
+			slog.Error(fmt.Sprintf("Failed to load names from %s", path), "error", err)
+			continue
+		}
+		slog.Info(fmt.Sprintf("Loaded %d names from %s", count, path))
+	}
+	count, err := db.LoadNames(ctx, GCtx.Names)
+	if err != nil {
+		slog.Warn("Failed to load user added names from database", "error", err)
+		return
+	}
+	slog.Info(fmt.Sprintf("Loaded %d user added names from database", count))
+}
+
+func renderNamesMenu() {
+	if imgui.BeginMenu("Names") {
+		imgui.Text(fmt.Sprintf("%d known names", GCtx.Names.Len()))
+		imgui.Separator()
+		if imgui.MenuItemBool("Load Name List / SoundbanksInfo") {
+			pushLoadNameFileModal()
+		}
+		if imgui.MenuItemBool("Add Name") {
+			pushAddNameModal()
+		}
+		imgui.EndMenu()
+	}
+}
+
+func pushLoadNameFileModal() {
+	renderF, done, err := openFileDialogFunc(
+		onLoadNameFiles, false, GCtx.Config.Home, []string{".txt", ".xml", ".json"},
+	)
+	if err != nil {
+		slog.Error("Failed to create open file dialog for loading name files", "error", err)
+		return
+	}
+	Modal(done, 0, "Load name list / SoundbanksInfo", renderF, nil)
+}
+
+func onLoadNameFiles(paths []string) {
+	for _, path := range paths {
+		onProcMsg := fmt.Sprintf("Loading names from %s", path)
+		onDoneMsg := fmt.Sprintf("Loaded names from %s", path)
+		BG(time.Second * 8, onProcMsg, onDoneMsg, func(context.Context) {
+			count, err := parser.ParseNames(path, GCtx.Names)
+			if err != nil {
+				slog.Error(fmt.Sprintf("Failed to load names from %s", path), "error", err)
+				return
+			}
+			slog.Info(fmt.Sprintf("Loaded %d names from %s", count, path))
+		})
+		if !slices.Contains(GCtx.Config.NameFiles, path) {
+			GCtx.Config.NameFiles = append(GCtx.Config.NameFiles, path)
+		}
+	}
+	if err := GCtx.Config.Save(); err != nil {
+		slog.Error("Failed to save configuration", "error", err)
+	}
+}
+
+func addNameModalFunc() (func(), *bool) {
+	done := false
+	name := ""
+	return func() {
+		imgui.InputTextWithHint("Name", "", &name, 0, nil)
+		id := wwise.ShortID(name)
+		imgui.Text(fmt.Sprintf("Short ID: %d", id))
+		if existing, in := GCtx.Names.Name(id); in {
+			imgui.Text(fmt.Sprintf("Already known as %s", existing))
+		}
+		Disabled(!GCtx.CopyEnable, func() {
+			imgui.SameLine()
+			if imgui.Button("Copy ID") {
+				clipboard.Write(clipboard.FmtText, []byte(fmt.Sprintf("%d", id)))
+			}
+		})
+		Disabled(name == "", func() {
+			if imgui.Button("Add") {
+				GCtx.Names.Add(name)
+				if _, err := db.InsertName(GCtx.Ctx, name); err != nil {
+					slog.Error(fmt.Sprintf("Failed to persist name %s", name), "error", err)
+				} else {
+					slog.Info(fmt.Sprintf("Added name %s (%d)", name, id))
+				}
+				done = true
+			}
+		})
+		imgui.SameLine()
+		if imgui.Button("Cancel") {
+			done = true
+		}
+	}, &done
+}
+
+func pushAddNameModal() {
+	renderF, done := addNameModalFunc()
+	Modal(done, imgui.WindowFlagsAlwaysAutoResize, "Add Name", renderF, nil)
+}
@@ -170,7 +170,7 @@ func renderActorMixer(t *be.BankTab, o *wwise.ActorMixer) {
 func renderLayerCntr(t *be.BankTab, o *wwise.LayerCntr) {
 	renderBaseParam(t, o)
 	renderContainer(t, o.Id, &o.Container, wwise.ActorMixerHircType(o))
-	renderLayer(o)
+	renderLayer(t, o)
 }
 
 func renderRanSeqCntr(t *be.BankTab, o *wwise.RanSeqCntr) {
@@ -190,15 +190,24 @@ func renderSound(t *be.BankTab, o *wwise.Sound) {
 }
 
 func renderMusicSegment(t *be.BankTab, o *wwise.MusicSegment) {
-	imgui.Text("Under construction")
+	renderBaseParam(t, o)
+	renderContainer(t, o.Id, &o.Children, false)
+	renderStingers(o.Id, &o.Stingers)
 }
 
 func renderMusicSwitchCntr(t *be.BankTab, o *wwise.MusicSwitchCntr) {
-	imgui.Text("Under construction")
+	renderBaseParam(t, o)
+	renderContainer(t, o.Id, &o.Children, false)
+	renderStingers(o.Id, &o.Stingers)
+	renderTransitionRules(o.Id, o.Children.Children, &o.TransitionRules, nil)
 }
 
 func renderMusicRanSeqCntr(t *be.BankTab, o *wwise.MusicRanSeqCntr) {
-	imgui.Text("Under construction")
+	renderBaseParam(t, o)
+	renderContainer(t, o.Id, &o.Children, false)
+	renderStingers(o.Id, &o.Stingers)
+	renderTransitionRules(o.Id, o.Children.Children, &o.TransitionRules, &o.PlayListNode)
+	renderMusicPlayList(t, o)
 }
 
 func renderContainer(t *be.BankTab, id uint32, cntr *wwise.Container, actorMixer bool) {
@@ -255,6 +264,9 @@ func renderContainer(t *be.BankTab, id uint32, cntr *wwise.Container, actorMixer
 				imgui.TableSetColumnIndex(2)
 				imgui.SetNextItemWidth(-1)
 				value, ok := hirc.ActorMixerHirc.Load(i)
+				if !actorMixer {
+					value, ok = hirc.MusicHirc.Load(i)
+				}
 				if !ok {
 					imgui.Text("-")
 				} else {
@@ -4,6 +4,7 @@ package ui
 import (
 	"encoding/binary"
 	"fmt"
+	"log/slog"
 	"slices"
 	"strconv"
 
@@ -29,7 +30,8 @@ func renderBaseParam(t *be.BankTab, o wwise.HircObj) {
 		imgui.Text("Override Bus ID: ")
 		imgui.SameLine()
 		imgui.SetNextItemWidth(128)
-		if imgui.BeginCombo("##OverrideBusId", strconv.FormatUint(uint64(b.OverrideBusId), 10)) {
+		if imgui.BeginCombo("##OverrideBusId", idLabel(b.OverrideBusId)) {
+			renderOutputBusOptions(t, hid, b)
 			imgui.EndCombo()
 		}
 		imgui.SameLine()
@@ -45,6 +47,7 @@ func renderBaseParam(t *be.BankTab, o wwise.HircObj) {
 		imgui.SameLine()
 		renderChangeParentListing(t, wwise.ActorMixerHircType(o))
 		renderByBitVec(b, t.Version())
+		renderBaseFx(t, hid, b)
 		renderAuxParam(o, v)
 		RenderPositioningParam(t, b, v)
 		renderEarlyReflection(o, v)
@@ -53,6 +56,60 @@ func renderBaseParam(t *be.BankTab, o wwise.HircObj) {
 		renderAllProp(&b.PropBundle, &b.RangePropBundle, v)
 		renderAdvSetting(b, &b.AdvanceSetting, t.Version())
 		renderRTPC(hid, &b.RTPC, "RTPC (Property)")
+		renderNewRTPC(t, hid)
+		renderBindModulator(t, hid)
+		renderStateGroup(t, hid)
+		imgui.TreePop()
+	}
+}
+
+// Buses in this sound bank and Init.bnk
+func renderOutputBusOptions(t *be.BankTab, hid uint32, b *wwise.BaseParameter) {
+	h := t.Bank.HIRC()
+	if b.DirectParentId != 0 {
+		if imgui.SelectableBool("Use Parent Output Bus") {
+			if err := h.SetOutputBus(hid, 0); err != nil {
+				slog.Error(fmt.Sprintf("Failed to reset output bus of %d", hid), "error", err)
+			}
+		}
+	}
+	banks := []*be.BankTab{t}
+	if BnkMngr.InitBank != nil && BnkMngr.InitBank != t {
+		banks = append(banks, BnkMngr.InitBank)
+	}
+	for _, bank := range banks {
+		if bank.Bank.HIRC() == nil {
+			continue
+		}
+		for _, o := range bank.Bank.HIRC().HircObjs {
+			if !wwise.BusHircType(o) {
+				continue
+			}
+			id, err := o.HircID()
+			if err != nil { panic(err) }
+			selected := id == b.OverrideBusId
+			if imgui.SelectableBoolPtr(idLabel(id) + "##OutputBus", &selected) {
+				if err := h.SetOutputBus(hid, id); err != nil {
+					slog.Error(fmt.Sprintf("Failed to route %d to bus %d", hid, id), "error", err)
+				}
+			}
+		}
+	}
+}
+
+func renderBaseFx(t *be.BankTab, hid uint32, b *wwise.BaseParameter) {
+	if imgui.TreeNodeStr("FX") {
+		imgui.BeginDisabledV(b.DirectParentId == 0)
+		overrideParentFx := b.BitIsOverrideParentFx != 0
+		if imgui.Checkbox("Override Parent FX", &overrideParentFx) {
+			if overrideParentFx {
+				b.BitIsOverrideParentFx = 1
+			} else {
+				b.BitIsOverrideParentFx = 0
+			}
+		}
+		imgui.EndDisabled()
+		renderFxChunk(t, hid, &b.FxChunk)
 		imgui.TreePop()
 	}
 }
@@ -130,7 +187,7 @@ func renderChangeParentQuery(
 		filter()
 	}
 
-	preview = strconv.FormatUint(uint64(b.DirectParentId), 10)
+	preview = idLabel(b.DirectParentId)
 	imgui.BeginDisabledV(disable)
 	imgui.Text("Direct Parent ID")
 	if imgui.BeginComboV("##Direct Parent ID", preview, 0) {
@@ -0,0 +1,435 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+	"github.com/AllenDang/cimgui-go/utils"
+	be "github.com/Dekr0/wwise-teller/ui/bank_explorer"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func renderStingers(id uint32, stingers *[]wwise.Stinger) {
+	if !imgui.TreeNodeExStr(fmt.Sprintf("Stingers##%dStingers", id)) {
+		return
+	}
+	defer imgui.TreePop()
+
+	if imgui.Button("Add Stinger") {
+		*stingers = append(*stingers, wwise.NewStinger(0, 0))
+	}
+
+	var remove func() = nil
+	const flags = DefaultTableFlags
+	if imgui.BeginTableV(fmt.Sprintf("%dStingerTable", id), 6, flags, imgui.NewVec2(0, 0), 0) {
+		imgui.TableSetupColumnV("", imgui.TableColumnFlagsWidthFixed, 0, 0)
+		imgui.TableSetupColumn("Trigger ID")
+		imgui.TableSetupColumn("Segment ID")
+		imgui.TableSetupColumn("Play At")
+		imgui.TableSetupColumn("Don't Repeat Time")
+		imgui.TableSetupColumn("Segment Look Ahead")
+		imgui.TableHeadersRow()
+
+		for i := range *stingers {
+			s := &(*stingers)[i]
+			imgui.TableNextRow()
+
+			imgui.TableSetColumnIndex(0)
+			imgui.PushIDStr(fmt.Sprintf("%dRmStinger%d", id, i))
+			if imgui.Button("X") {
+				remove = bindRemoveStinger(stingers, i)
+			}
+			imgui.PopID()
+
+			imgui.TableSetColumnIndex(1)
+			imgui.SetNextItemWidth(-1)
+			imgui.InputScalar(fmt.Sprintf("##%dStingerTrigger%d", id, i), imgui.DataTypeU32, uintptr(utils.Ptr(&s.TriggerID)))
+
+			imgui.TableSetColumnIndex(2)
+			imgui.SetNextItemWidth(-1)
+			imgui.InputScalar(fmt.Sprintf("##%dStingerSegment%d", id, i), imgui.DataTypeU32, uintptr(utils.Ptr(&s.SegmentID)))
+
+			imgui.TableSetColumnIndex(3)
+			imgui.SetNextItemWidth(-1)
+			syncPlayAt := int32(s.SyncPlayAt)
+			if imgui.ComboStrarr(fmt.Sprintf("##%dStingerPlayAt%d", id, i), &syncPlayAt, wwise.SyncTypeName, wwise.NumSyncType) {
+				s.SyncPlayAt = uint32(syncPlayAt)
+			}
+
+			imgui.TableSetColumnIndex(4)
+			imgui.SetNextItemWidth(-1)
+			imgui.InputInt(fmt.Sprintf("##%dStingerDontRepeat%d", id, i), &s.DontRepeatTime)
+
+			imgui.TableSetColumnIndex(5)
+			imgui.SetNextItemWidth(-1)
+			imgui.InputScalar(fmt.Sprintf("##%dStingerLookAhead%d", id, i), imgui.DataTypeU32, uintptr(utils.Ptr(&s.NumSegmentLookAhead)))
+		}
+		imgui.EndTable()
+	}
+	if remove != nil {
+		remove()
+	}
+}
+
+func bindRemoveStinger(stingers *[]wwise.Stinger, i int) func() {
+	return func() { *stingers = slices.Delete(*stingers, i, i + 1) }
+}
+
+func transitionNodeLabel(id uint32) string {
+	switch id {
+	case wwise.MusicTransitionAny:
+		return "Any"
+	case wwise.MusicTransitionNothing:
+		return "Nothing"
+	}
+	return idLabel(id)
+}
+
+func transitionNodesLabel(ids []uint32) string {
+	if len(ids) == 1 {
+		return transitionNodeLabel(ids[0])
+	}
+	return fmt.Sprintf("%d objects", len(ids))
+}
+
+// playList is nil if the container does not have a play list (i.e., music
+// switch container).
+func renderTransitionRules(
+	id uint32, children []uint32, rules *[]wwise.MusicTransitionRule, playList *wwise.MusicPlayListNode,
+) {
+	if !imgui.TreeNodeExStr(fmt.Sprintf("Transition Rules##%dRules", id)) {
+		return
+	}
+	defer imgui.TreePop()
+
+	if imgui.Button("Add Transition Rule") {
+		*rules = append(*rules, wwise.NewMusicTransitionRule(
+			[]uint32{wwise.MusicTransitionAny}, []uint32{wwise.MusicTransitionAny},
+		))
+	}
+
+	var remove func() = nil
+	for i := range *rules {
+		r := &(*rules)[i]
+		// The first rule is the default rule of a container
+		imgui.BeginDisabledV(i == 0)
+		imgui.PushIDStr(fmt.Sprintf("%dRmRule%d", id, i))
+		if imgui.Button("X") {
+			remove = bindRemoveTransitionRule(rules, i)
+		}
+		imgui.PopID()
+		imgui.EndDisabled()
+		imgui.SameLine()
+		label := fmt.Sprintf("%s -> %s", transitionNodesLabel(r.SrcIDs), transitionNodesLabel(r.DestIDs))
+		if imgui.TreeNodeExStrStr(fmt.Sprintf("%dRule%d", id, i), 0, label) {
+			stackID := fmt.Sprintf("%dRule%d", id, i)
+			renderTransitionNodes(stackID + "Src", "Source", children, &r.SrcIDs)
+			renderTransitionNodes(stackID + "Dest", "Destination", children, &r.DestIDs)
+			renderTransitionSrcRule(stackID, r)
+			renderTransitionDestRule(stackID, r, playList)
+			renderTransitionObj(stackID, children, r)
+			imgui.TreePop()
+		}
+	}
+	if remove != nil {
+		remove()
+	}
+}
+
+func bindRemoveTransitionRule(rules *[]wwise.MusicTransitionRule, i int) func() {
+	return func() { *rules = slices.Delete(*rules, i, i + 1) }
+}
+
+func renderTransitionNodes(stackID string, label string, children []uint32, ids *[]uint32) {
+	imgui.Text(label)
+	candidates := append([]uint32{wwise.MusicTransitionAny, wwise.MusicTransitionNothing}, children...)
+
+	var edit func() = nil
+	imgui.SetNextItemWidth(160)
+	if imgui.BeginCombo(fmt.Sprintf("##%sAdd", stackID), "Add " + label) {
+		for _, c := range candidates {
+			if slices.Contains(*ids, c) {
+				continue
+			}
+			if imgui.SelectableBool(fmt.Sprintf("%s##%s%d", transitionNodeLabel(c), stackID, c)) {
+				edit = bindAddTransitionNode(ids, c)
+			}
+		}
+		imgui.EndCombo()
+	}
+	for _, c := range *ids {
+		imgui.BeginDisabledV(len(*ids) <= 1)
+		imgui.PushIDStr(fmt.Sprintf("%sRm%d", stackID, c))
+		if imgui.Button("X") {
+			edit = bindRemoveTransitionNode(ids, c)
+		}
+		imgui.PopID()
+		imgui.EndDisabled()
+		imgui.SameLine()
+		imgui.Text(transitionNodeLabel(c))
+	}
+	if edit != nil {
+		edit()
+	}
+}
+
+func bindAddTransitionNode(ids *[]uint32, id uint32) func() {
+	return func() { *ids = append(*ids, id) }
+}
+
+func bindRemoveTransitionNode(ids *[]uint32, id uint32) func() {
+	return func() { *ids = slices.DeleteFunc(*ids, func(i uint32) bool { return i == id }) }
+}
+
+func renderFadeCurve(label string, curve *uint32) {
+	c := int32(*curve)
+	imgui.SetNextItemWidth(160)
+	if imgui.ComboStrarr(label, &c, wwise.InterpCurveTypeName, int32(wwise.InterpCurveTypeCount)) {
+		*curve = uint32(c)
+	}
+}
+
+func renderFlag(label string, flag *uint8) {
+	b := *flag != 0
+	if imgui.Checkbox(label, &b) {
+		*flag = 0
+		if b {
+			*flag = 1
+		}
+	}
+}
+
+func renderTransitionSrcRule(stackID string, r *wwise.MusicTransitionRule) {
+	s := &r.TransitionSourceRule
+	imgui.SeparatorText("Source")
+
+	syncType := int32(s.SyncType)
+	imgui.SetNextItemWidth(160)
+	if imgui.ComboStrarr("Exit Source At##" + stackID + "Sync", &syncType, wwise.SyncTypeName, wwise.NumSyncType) {
+		s.SyncType = uint32(syncType)
+	}
+	renderFlag("Play Post Exit##" + stackID + "PostExit", &s.PlayPostExit)
+
+	imgui.SetNextItemWidth(128)
+	imgui.InputInt("Fade Out Time (ms)##" + stackID + "SrcTime", &s.TransitionTime)
+	renderFadeCurve("Fade Out Curve##" + stackID + "SrcCurve", &s.FadeCurve)
+	imgui.SetNextItemWidth(128)
+	imgui.InputScalar("Fade Out Offset (ms)##" + stackID + "SrcOffset", imgui.DataTypeU32, uintptr(utils.Ptr(&s.FadeOffset)))
+}
+
+func renderTransitionDestRule(stackID string, r *wwise.MusicTransitionRule, playList *wwise.MusicPlayListNode) {
+	d := &r.TransitionDestRule
+	imgui.SeparatorText("Destination")
+
+	entryType := int32(d.EntryType)
+	imgui.SetNextItemWidth(160)
+	if imgui.ComboStrarr("Sync To##" + stackID + "Entry", &entryType, wwise.EntryTypeName, wwise.EntryTypeCount) {
+		d.EntryType = uint16(entryType)
+	}
+	renderFlag("Play Pre Entry##" + stackID + "PreEntry", &d.PlayPreEntry)
+	renderFlag("Match Source Cue Name##" + stackID + "MatchCue", &d.DestMatchSourceCueName)
+
+	if playList != nil {
+		jumpToType := int32(d.JumpToType)
+		imgui.SetNextItemWidth(160)
+		if imgui.ComboStrarr("Jump To##" + stackID + "JumpTo", &jumpToType, wwise.JumpToTypeName, wwise.JumpToTypeCount) {
+			d.JumpToType = uint16(jumpToType)
+			if d.JumpToType != wwise.JumpToTypeSpecificItem {
+				d.JumpToID = 0
+			}
+		}
+		if d.JumpToType == wwise.JumpToTypeSpecificItem {
+			imgui.SetNextItemWidth(160)
+			if imgui.BeginCombo("Play List Item##" + stackID + "JumpToID", strconv.FormatUint(uint64(d.JumpToID), 10)) {
+				renderJumpToItems(playList, &d.JumpToID)
+				imgui.EndCombo()
+			}
+		}
+	}
+
+	imgui.SetNextItemWidth(128)
+	imgui.InputInt("Fade In Time (ms)##" + stackID + "DestTime", &d.TransitionTime)
+	renderFadeCurve("Fade In Curve##" + stackID + "DestCurve", &d.FadeCurve)
+	imgui.SetNextItemWidth(128)
+	imgui.InputScalar("Fade In Offset (ms)##" + stackID + "DestOffset", imgui.DataTypeU32, uintptr(utils.Ptr(&d.FadeOffset)))
+}
+
+func renderJumpToItems(n *wwise.MusicPlayListNode, jumpToID *uint32) {
+	if !n.IsGroup() {
+		label := fmt.Sprintf("%d (Segment %s)", n.PlayListItemID, idLabel(n.SegmentID))
+		if imgui.SelectableBoolV(label, *jumpToID == n.PlayListItemID, 0, imgui.NewVec2(0, 0)) {
+			*jumpToID = n.PlayListItemID
+		}
+		return
+	}
+	for i := range n.PlayListLeafs {
+		renderJumpToItems(&n.PlayListLeafs[i], jumpToID)
+	}
+}
+
+func renderTransitionObj(stackID string, children []uint32, r *wwise.MusicTransitionRule) {
+	imgui.SeparatorText("Transition Segment")
+
+	use := r.HasTransitionObj()
+	imgui.BeginDisabledV(len(children) == 0)
+	if imgui.Checkbox("Use Transition Segment##" + stackID + "UseObj", &use) {
+		if use {
+			r.SetTransitionSegment(children[0])
+		} else {
+			r.RemoveTransitionSegment()
+		}
+	}
+	imgui.EndDisabled()
+	if !r.HasTransitionObj() {
+		return
+	}
+
+	o := &r.TransitionObj
+	imgui.SetNextItemWidth(160)
+	if imgui.BeginCombo("Segment##" + stackID + "ObjSegment", idLabel(o.SegmentID)) {
+		for _, c := range children {
+			if imgui.SelectableBoolV(fmt.Sprintf("%s##%sObj%d", idLabel(c), stackID, c), o.SegmentID == c, 0, imgui.NewVec2(0, 0)) {
+				o.SegmentID = c
+			}
+		}
+		imgui.EndCombo()
+	}
+	renderFlag("Play Pre Entry##" + stackID + "ObjPreEntry", &o.PlayPreEntry)
+	renderFlag("Play Post Exit##" + stackID + "ObjPostExit", &o.PlayPostExit)
+
+	imgui.SetNextItemWidth(128)
+	imgui.InputInt("Fade In Time (ms)##" + stackID + "ObjInTime", &o.FadeInParam.TransitionTime)
+	renderFadeCurve("Fade In Curve##" + stackID + "ObjInCurve", &o.FadeInParam.FadeCurve)
+	imgui.SetNextItemWidth(128)
+	imgui.InputInt("Fade In Offset (ms)##" + stackID + "ObjInOffset", &o.FadeInParam.FadeOffset)
+
+	imgui.SetNextItemWidth(128)
+	imgui.InputInt("Fade Out Time (ms)##" + stackID + "ObjOutTime", &o.FadeOutParam.TransitionTime)
+	renderFadeCurve("Fade Out Curve##" + stackID + "ObjOutCurve", &o.FadeOutParam.FadeCurve)
+	imgui.SetNextItemWidth(128)
+	imgui.InputInt("Fade Out Offset (ms)##" + stackID + "ObjOutOffset", &o.FadeOutParam.FadeOffset)
+}
+
+func renderMusicPlayList(t *be.BankTab, m *wwise.MusicRanSeqCntr) {
+	if !imgui.TreeNodeExStr("Music Play List") {
+		return
+	}
+	defer imgui.TreePop()
+
+	var edit func() = nil
+	renderMusicPlayListNode(t, m, nil, 0, &m.PlayListNode, &edit)
+	if edit != nil {
+		edit()
+	}
+}
+
+// parent is nil for the root node. Edits on the tree are deferred to edit so
+// that the tree is not modified while it is being rendered.
+func renderMusicPlayListNode(
+	t *be.BankTab,
+	m *wwise.MusicRanSeqCntr,
+	parent *wwise.MusicPlayListNode,
+	i int,
+	n *wwise.MusicPlayListNode,
+	edit *func(),
+) {
+	stackID := fmt.Sprintf("%dPL%d", m.Id, n.PlayListItemID)
+	if parent != nil {
+		imgui.PushIDStr(stackID + "Rm")
+		if imgui.Button("X") {
+			*edit = bindRemoveMusicPlayListNode(m, n.PlayListItemID)
+		}
+		imgui.PopID()
+		imgui.SameLine()
+		imgui.BeginDisabledV(i == 0)
+		if imgui.ArrowButton(stackID + "Up", imgui.DirUp) {
+			*edit = bindMoveMusicPlayListNode(parent, i, i - 1)
+		}
+		imgui.EndDisabled()
+		imgui.SameLine()
+		imgui.BeginDisabledV(i >= len(parent.PlayListLeafs) - 1)
+		if imgui.ArrowButton(stackID + "Down", imgui.DirDown) {
+			*edit = bindMoveMusicPlayListNode(parent, i, i + 1)
+		}
+		imgui.EndDisabled()
+		imgui.SameLine()
+	}
+
+	var label string
+	if n.IsGroup() {
+		rsType := "Unknown"
+		if n.RSType < wwise.MusicRSTypeCount {
+			rsType = wwise.MusicRSTypeName[n.RSType]
+		}
+		label = fmt.Sprintf("Group %d (%s)", n.PlayListItemID, rsType)
+	} else {
+		label = fmt.Sprintf("Segment %s", idLabel(n.SegmentID))
+	}
+	if !imgui.TreeNodeExStrStr(stackID, imgui.TreeNodeFlagsDefaultOpen, label) {
+		return
+	}
+	defer imgui.TreePop()
+
+	imgui.PushItemWidth(128)
+	imgui.InputScalar("Loop Count (0 = Infinite)##" + stackID + "Loop", imgui.DataTypeS16, uintptr(utils.Ptr(&n.Loop)))
+	if parent != nil && parent.IsRandom() {
+		imgui.InputScalar("Weight##" + stackID + "Weight", imgui.DataTypeU32, uintptr(utils.Ptr(&n.Weight)))
+	}
+	if n.IsGroup() {
+		rsType := int32(n.RSType)
+		imgui.SetNextItemWidth(160)
+		if imgui.ComboStrarr("Play Type##" + stackID + "RSType", &rsType, wwise.MusicRSTypeName, int32(wwise.MusicRSTypeCount)) {
+			n.RSType = uint32(rsType)
+		}
+		if n.IsRandom() {
+			renderFlag("Shuffle##" + stackID + "Shuffle", &n.Shuffle)
+			renderFlag("Using Weight##" + stackID + "UsingWeight", &n.UsingWeight)
+			imgui.InputScalar("Avoid Repeat Count##" + stackID + "Avoid", imgui.DataTypeU16, uintptr(utils.Ptr(&n.AvoidRepeatCount)))
+		}
+	}
+	imgui.PopItemWidth()
+
+	if !n.IsGroup() {
+		return
+	}
+
+	imgui.BeginDisabledV(t.SounBankLock.Load())
+	if imgui.Button("Add Group##" + stackID + "AddGroup") {
+		*edit = bindNewMusicPlayListNode(t, m, n.PlayListItemID, 0)
+	}
+	imgui.SameLine()
+	imgui.SetNextItemWidth(160)
+	if imgui.BeginCombo("##" + stackID + "AddSegment", "Add Segment") {
+		for _, c := range m.Children.Children {
+			if imgui.SelectableBool(fmt.Sprintf("%s##%sAdd%d", idLabel(c), stackID, c)) {
+				*edit = bindNewMusicPlayListNode(t, m, n.PlayListItemID, c)
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.EndDisabled()
+
+	for j := range n.PlayListLeafs {
+		renderMusicPlayListNode(t, m, n, j, &n.PlayListLeafs[j], edit)
+	}
+}
+
+func bindRemoveMusicPlayListNode(m *wwise.MusicRanSeqCntr, itemID uint32) func() {
+	return func() { m.PlayListNode.RemoveNode(itemID) }
+}
+
+func bindMoveMusicPlayListNode(parent *wwise.MusicPlayListNode, a int, b int) func() {
+	return func() { parent.MoveLeaf(a, b) }
+}
+
+func bindNewMusicPlayListNode(t *be.BankTab, m *wwise.MusicRanSeqCntr, groupItemID uint32, segmentID uint32) func() {
+	return func() {
+		BG(time.Second * 8, "Adding play list item", "Added play list item", func(ctx context.Context) {
+			t.NewMusicPlayListNode(ctx, m, groupItemID, segmentID)
+		})
+	}
+}
@@ -68,7 +68,7 @@ func renderMusicTrackPlayList(t *be.BankTab, o *wwise.MusicTrack) {
 
 				imgui.TableSetColumnIndex(2)
 				imgui.SetNextItemWidth(88)
-				imgui.Text(strconv.FormatUint(uint64(p.EventID), 10))
+				imgui.Text(idLabel(p.EventID))
 
 				imgui.TableSetColumnIndex(3)
 				imgui.SetNextItemWidth(-1)
@@ -295,7 +295,7 @@ func renderTransitionParam(p *wwise.MusicTrackTransitionParam) {
 func renderSwitchParam(t *be.BankTab, m *wwise.MusicTrack) {
 	if imgui.TreeNodeStr("Music Track Switch Parameter") {
 		imgui.Text("Group Type: " + wwise.GroupTypeName[m.SwitchParam.GroupType])
-		imgui.Text(fmt.Sprintf("Group ID: %d", m.SwitchParam.GroupID))
+		imgui.Text("Group ID: " + idLabel(m.SwitchParam.GroupID))
 		imgui.BeginDisabledV(!ModifiyEverything)
 		imgui.Text("Default Switch ID")
 		imgui.SetNextItemWidth(64)
@@ -1,11 +1,14 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/AllenDang/cimgui-go/imgui"
 	"github.com/AllenDang/cimgui-go/implot"
 	"github.com/AllenDang/cimgui-go/utils"
+	be "github.com/Dekr0/wwise-teller/ui/bank_explorer"
 	"github.com/Dekr0/wwise-teller/wwise"
 )
 
@@ -20,7 +23,7 @@ func renderRTPC(hid uint32, r *wwise.RTPC, title string) {
 			}
 			imgui.PopID()
 			imgui.SameLine()
-			if imgui.TreeNodeExStrStr(fmt.Sprintf("RTPC%d%d", ri.RTPCID, i), 0, fmt.Sprintf("RTPC %d", ri.RTPCID)) {
+			if imgui.TreeNodeExStrStr(fmt.Sprintf("RTPC%d%d", ri.RTPCID, i), 0, "RTPC " + idLabel(ri.RTPCID)) {
 				imgui.BeginDisabledV(!ModifiyEverything)
 				rtpcType := int32(ri.RTPCType)
 				imgui.SetNextItemWidth(128)
@@ -111,6 +114,139 @@ func renderRTPC(hid uint32, r *wwise.RTPC, title string) {
 	}
 }
 
+func renderNewRTPC(t *be.BankTab, hid uint32) {
+	if !imgui.TreeNodeExStr("New RTPC") {
+		return
+	}
+	in := &t.NewRTPC
+
+	rtpcType := int32(in.RTPCType)
+	imgui.SetNextItemWidth(160)
+	if imgui.ComboStrarr("RTPC Type##NewRTPC", &rtpcType, wwise.RTPCTypeName, 2) {
+		in.RTPCType = uint8(rtpcType)
+	}
+
+	imgui.SetNextItemWidth(160)
+	if imgui.InputTextWithHint("Game Parameter Name##NewRTPC", "", &in.Name, 0, nil) && in.Name != "" {
+		in.RTPCID = wwise.ShortID(in.Name)
+	}
+	imgui.SetNextItemWidth(160)
+	imgui.InputScalar("RTPC ID##NewRTPC", imgui.DataTypeU32, uintptr(utils.Ptr(&in.RTPCID)))
+	imgui.SameLine()
+	imgui.SetNextItemWidth(200)
+	if imgui.BeginCombo("##ExistingGameParameter", "Existing Game Parameter") {
+		for _, id := range t.Bank.HIRC().GameParameterIDs(t.Version()) {
+			if imgui.SelectableBool(idLabel(id) + "##ExistingGameParameter") {
+				in.Name = ""
+				in.RTPCID = id
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.Text("RTPC: " + idLabel(in.RTPCID))
+
+	param := int32(in.Param)
+	imgui.SetNextItemWidth(200)
+	if imgui.ComboStrarr(
+		"RTPC Parameter##NewRTPC",
+		&param,
+		wwise.RTPCParameterIDName,
+		int32(wwise.RTPCParameterTypeCount),
+	) {
+		in.Param = wwise.RTPCParameterType(param)
+		in.Accum = wwise.DefaultRTPCAccum(in.Param)
+		in.Scaling = wwise.DefaultCurveScaling(in.Param)
+	}
+
+	accum := int32(in.Accum)
+	imgui.SetNextItemWidth(96)
+	if imgui.ComboStrarr("Accumulation Type##NewRTPC", &accum, wwise.RTPCAccumTypeName, int32(wwise.RTPCAccumTypeCount)) {
+		in.Accum = wwise.RTPCAccumType(accum)
+	}
+	imgui.SameLine()
+	scaling := int32(in.Scaling)
+	imgui.SetNextItemWidth(96)
+	if imgui.ComboStrarr("Scaling##NewRTPC", &scaling, wwise.CurveScalingTypeName, int32(wwise.CurveScalingTypeCount)) {
+		in.Scaling = wwise.CurveScalingType(scaling)
+	}
+
+	imgui.Text("Initial Curve")
+	imgui.SetNextItemWidth(96)
+	imgui.InputFloat("Min X##NewRTPC", &in.MinX)
+	imgui.SameLine()
+	imgui.SetNextItemWidth(96)
+	imgui.InputFloat("Max X##NewRTPC", &in.MaxX)
+	imgui.SetNextItemWidth(96)
+	imgui.InputFloat("Min Y##NewRTPC", &in.MinY)
+	imgui.SameLine()
+	imgui.SetNextItemWidth(96)
+	imgui.InputFloat("Max Y##NewRTPC", &in.MaxY)
+
+	imgui.BeginDisabledV(in.RTPCID == 0 || in.MinX >= in.MaxX || t.SounBankLock.Load())
+	if imgui.Button("Add##NewRTPC") {
+		input := *in
+		BG(time.Second * 8, "Adding RTPC", "Added RTPC", func(ctx context.Context) {
+			t.AddRTPC(ctx, hid, input)
+		})
+	}
+	imgui.EndDisabled()
+	imgui.TreePop()
+}
+
+func renderBindModulator(t *be.BankTab, hid uint32) {
+	if !imgui.TreeNodeExStr("Bind Modulator") {
+		return
+	}
+	binding := &t.ModulatorViewer.Binding
+	h := t.Bank.HIRC()
+
+	preview := "None"
+	if m, in := h.Modulator(binding.ModulatorId); in {
+		preview = fmt.Sprintf("%s %d", wwise.HircTypeName[m.ModulatorType], m.Id)
+	}
+	imgui.SetNextItemWidth(200)
+	if imgui.BeginCombo("Modulator", preview) {
+		for _, o := range h.HircObjs {
+			m, ok := o.(*wwise.Modulator)
+			if !ok {
+				continue
+			}
+			selected := binding.ModulatorId == m.Id
+			if imgui.SelectableBoolPtr(fmt.Sprintf("%s %d", wwise.HircTypeName[m.ModulatorType], m.Id), &selected) {
+				binding.ModulatorId = m.Id
+			}
+		}
+		imgui.EndCombo()
+	}
+
+	param := int32(binding.Param)
+	imgui.SetNextItemWidth(200)
+	if imgui.ComboStrarr(
+		"RTPC Parameter##BindModulator",
+		&param,
+		wwise.RTPCParameterIDName,
+		int32(wwise.RTPCParameterTypeCount),
+	) {
+		binding.Param = wwise.RTPCParameterType(param)
+	}
+
+	imgui.SetNextItemWidth(96)
+	imgui.InputFloat("Min##BindModulator", &binding.Min)
+	imgui.SameLine()
+	imgui.SetNextItemWidth(96)
+	imgui.InputFloat("Max##BindModulator", &binding.Max)
+
+	imgui.BeginDisabledV(binding.ModulatorId == 0 || t.SounBankLock.Load())
+	if imgui.Button("Bind") {
+		b := *binding
+		BG(time.Second * 8, "Binding modulator", "Bound modulator", func(ctx context.Context) {
+			t.BindModulator(ctx, hid, b.ModulatorId, b.Param, b.Min, b.Max)
+		})
+	}
+	imgui.EndDisabled()
+	imgui.TreePop()
+}
+
 func bindRTPCRemove(r *wwise.RTPC, i int) func() {
 	return func() { r.RemoveRTPCItem(i) }
 }
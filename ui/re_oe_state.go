@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+	"github.com/AllenDang/cimgui-go/utils"
+	be "github.com/Dekr0/wwise-teller/ui/bank_explorer"
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func renderStateGroup(t *be.BankTab, hid uint32) {
+	if !imgui.TreeNodeExStr("State Group") {
+		return
+	}
+	h := t.Bank.HIRC()
+	v := t.Version()
+	sp, sg, err := h.StateGroupOf(hid)
+	if err != nil {
+		imgui.Text(err.Error())
+		imgui.TreePop()
+		return
+	}
+	in := &t.NewState
+
+	// Mutation is delayed until every widget of this frame is rendered
+	var edit func() error = nil
+
+	imgui.SeparatorText("State Properties")
+	for _, item := range sp.StatePropItems {
+		p := wwise.RTPCParameterType(item.PropertyId.Value)
+		imgui.PushIDStr(fmt.Sprintf("%dRmStateProp%d", hid, p))
+		if imgui.Button("X") {
+			edit = func() error { return h.RemoveStateProp(hid, p, v) }
+		}
+		imgui.PopID()
+		imgui.SameLine()
+		imgui.Text(fmt.Sprintf("%s (%s)", wwise.RTPCParameterIDName[p], wwise.RTPCAccumTypeName[item.AccumType]))
+	}
+	param := int32(in.Param)
+	imgui.SetNextItemWidth(200)
+	if imgui.ComboStrarr(
+		"##NewStateProp",
+		&param,
+		wwise.RTPCParameterIDName,
+		int32(wwise.RTPCParameterTypeCount),
+	) {
+		in.Param = wwise.RTPCParameterType(param)
+	}
+	imgui.SameLine()
+	if imgui.Button("Add Property##NewStateProp") {
+		p := in.Param
+		edit = func() error { return h.AddStateProp(hid, p, v) }
+	}
+
+	imgui.SeparatorText("State Groups")
+	for i := range sg.StateGroupItems {
+		g := &sg.StateGroupItems[i]
+		imgui.PushIDStr(fmt.Sprintf("%dRmStateGroup%d", hid, g.StateGroupID))
+		if imgui.Button("X") {
+			groupID := g.StateGroupID
+			edit = func() error { return h.RemoveStateGroup(hid, groupID) }
+		}
+		imgui.PopID()
+		imgui.SameLine()
+		if !imgui.TreeNodeExStrStr(
+			fmt.Sprintf("%dStateGroup%d", hid, g.StateGroupID), 0,
+			"State Group " + idLabel(g.StateGroupID),
+		) {
+			continue
+		}
+		syncType := int32(g.StateSyncType)
+		imgui.SetNextItemWidth(128)
+		if imgui.ComboStrarr(
+			fmt.Sprintf("Sync Type##%dStateSync%d", hid, g.StateGroupID),
+			&syncType,
+			wwise.SyncTypeName,
+			wwise.NumSyncType,
+		) {
+			g.StateSyncType = uint8(syncType)
+		}
+		edit = renderStates(t, hid, g, sp, edit)
+		imgui.TreePop()
+	}
+
+	imgui.SeparatorText("New State Group")
+	imgui.SetNextItemWidth(160)
+	if imgui.InputTextWithHint("State Group Name##NewStateGroup", "", &in.GroupName, 0, nil) && in.GroupName != "" {
+		in.GroupID = wwise.ShortID(in.GroupName)
+	}
+	imgui.SetNextItemWidth(160)
+	imgui.InputScalar("State Group ID##NewStateGroup", imgui.DataTypeU32, uintptr(utils.Ptr(&in.GroupID)))
+	imgui.SameLine()
+	imgui.SetNextItemWidth(200)
+	if imgui.BeginCombo("##ExistingStateGroup", "Existing State Group") {
+		for _, id := range h.StateGroupIDs(v) {
+			if imgui.SelectableBool(idLabel(id) + "##ExistingStateGroup") {
+				in.GroupName = ""
+				in.GroupID = id
+			}
+		}
+		imgui.EndCombo()
+	}
+	syncType := int32(in.SyncType)
+	imgui.SetNextItemWidth(128)
+	if imgui.ComboStrarr("Sync Type##NewStateGroup", &syncType, wwise.SyncTypeName, wwise.NumSyncType) {
+		in.SyncType = uint8(syncType)
+	}
+	imgui.BeginDisabledV(in.GroupID == 0 || sg.Group(in.GroupID) != nil)
+	if imgui.Button("Add##NewStateGroup") {
+		groupID, syncType := in.GroupID, in.SyncType
+		edit = func() error { return h.AddStateGroup(hid, groupID, syncType) }
+	}
+	imgui.EndDisabled()
+
+	imgui.TreePop()
+	if edit != nil {
+		if err := edit(); err != nil {
+			slog.Error(fmt.Sprintf("Failed to edit state group of %d", hid), "error", err)
+		}
+	}
+}
+
+func renderStates(
+	t *be.BankTab,
+	hid uint32,
+	g *wwise.StateGroupItem,
+	sp *wwise.StateProp,
+	edit func() error,
+) func() error {
+	h := t.Bank.HIRC()
+	v := t.Version()
+	in := &t.NewState
+	stackID := fmt.Sprintf("%dStates%d", hid, g.StateGroupID)
+
+	const flags = DefaultTableFlags
+	if imgui.BeginTableV(stackID, int32(2 + len(sp.StatePropItems)), flags, imgui.NewVec2(0, 0), 0) {
+		imgui.TableSetupColumnV("", imgui.TableColumnFlagsWidthFixed, 0, 0)
+		imgui.TableSetupColumn("State")
+		for _, item := range sp.StatePropItems {
+			imgui.TableSetupColumn(wwise.RTPCParameterIDName[item.PropertyId.Value])
+		}
+		imgui.TableHeadersRow()
+
+		for i := range g.States {
+			s := &g.States[i]
+			imgui.TableNextRow()
+
+			imgui.TableSetColumnIndex(0)
+			imgui.PushIDStr(fmt.Sprintf("%sRm%d", stackID, s.StateID))
+			if imgui.Button("X") {
+				groupID, stateID := g.StateGroupID, s.StateID
+				edit = func() error { return h.RemoveState(hid, groupID, stateID) }
+			}
+			imgui.PopID()
+
+			imgui.TableSetColumnIndex(1)
+			imgui.Text(idLabel(s.StateID))
+
+			for j, item := range sp.StatePropItems {
+				p := wwise.RTPCParameterType(item.PropertyId.Value)
+				val, _ := h.StateValue(s, p, v)
+				imgui.TableSetColumnIndex(int32(2 + j))
+				imgui.SetNextItemWidth(96)
+				if imgui.InputFloat(fmt.Sprintf("##%s%dProp%d", stackID, s.StateID, p), &val) {
+					groupID, stateID, newVal := g.StateGroupID, s.StateID, val
+					edit = func() error { return h.SetStateProp(hid, groupID, stateID, p, newVal, v) }
+				}
+			}
+		}
+		imgui.EndTable()
+	}
+
+	imgui.SetNextItemWidth(160)
+	if imgui.InputTextWithHint("State Name##" + stackID, "", &in.StateName, 0, nil) && in.StateName != "" {
+		in.StateID = wwise.ShortID(in.StateName)
+	}
+	imgui.SetNextItemWidth(160)
+	imgui.InputScalar("State ID##" + stackID, imgui.DataTypeU32, uintptr(utils.Ptr(&in.StateID)))
+	imgui.SameLine()
+	imgui.SetNextItemWidth(200)
+	if imgui.BeginCombo("##ExistingState" + stackID, "Existing State") {
+		for _, id := range h.StateIDsOf(g.StateGroupID) {
+			if imgui.SelectableBool(idLabel(id) + "##ExistingState" + stackID) {
+				in.StateName = ""
+				in.StateID = id
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.BeginDisabledV(in.StateID == 0 || g.State(in.StateID) != nil || t.SounBankLock.Load())
+	if imgui.Button("Add State##" + stackID) {
+		groupID, stateID := g.StateGroupID, in.StateID
+		BG(time.Second * 8, "Adding state", "Added state", func(ctx context.Context) {
+			t.AddState(ctx, hid, groupID, stateID)
+		})
+	}
+	imgui.EndDisabled()
+	return edit
+}
@@ -1,7 +1,6 @@
 package ui
 
 import (
-	"fmt"
 
 	"github.com/AllenDang/cimgui-go/imgui"
 	"github.com/Dekr0/wwise-teller/ui/bank_explorer"
@@ -29,6 +28,6 @@ func RenderSwitchSetting(t *bank_explorer.BankTab, o *wwise.SwitchCntr) {
 		}
 		imgui.EndDisabled()
 
-		imgui.Text(fmt.Sprintf("Group ID: %d", o.GroupID))
+		imgui.Text("Group ID: " + idLabel(o.GroupID))
 	}
 }
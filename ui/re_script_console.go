@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+	dockmanager "github.com/Dekr0/wwise-teller/ui/dock_manager"
+)
+
+func renderScriptConsole(open *bool) {
+	if !*open {
+		return
+	}
+	imgui.BeginV(dockmanager.DockWindowNames[dockmanager.ScriptConsoleTag], open, imgui.WindowFlagsNone)
+	defer imgui.End()
+	if !*open {
+		return
+	}
+	activeBank, valid := BnkMngr.ActiveBankV()
+	if !valid {
+		return
+	}
+	console := &activeBank.ScriptConsole
+	running := console.Running.Load()
+
+	size := imgui.NewVec2(-1, imgui.ContentRegionAvail().Y * 0.5)
+	imgui.BeginDisabledV(running)
+	imgui.InputTextMultiline("##ScriptSource", &console.Source, size, imgui.InputTextFlagsAllowTabInput, nil)
+	imgui.EndDisabled()
+
+	imgui.BeginDisabledV(running || activeBank.SounBankLock.Load())
+	if imgui.Button("Run") {
+		BG(time.Second * 30, "Running script", "Ran script", func(ctx context.Context) {
+			activeBank.RunScript(ctx)
+		})
+	}
+	imgui.EndDisabled()
+	imgui.SameLine()
+	if imgui.Button("Clear Output") {
+		console.Clear()
+	}
+	imgui.SameLine()
+	imgui.TextDisabled("Changes made by scripts cannot be undone")
+
+	imgui.BeginChildStrV("ScriptOutput", DefaultSize, imgui.ChildFlagsBorders, imgui.WindowFlagsNone)
+	for _, line := range console.Output() {
+		imgui.TextUnformatted(line)
+	}
+	imgui.EndChild()
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+	be "github.com/Dekr0/wwise-teller/ui/bank_explorer"
+	dockmanager "github.com/Dekr0/wwise-teller/ui/dock_manager"
+	"github.com/Dekr0/wwise-teller/wwise"
+	"golang.design/x/clipboard"
+)
+
+func findReferences(t *be.BankTab, id uint32) {
+	t.FindReferences(id)
+	DockMngr.Opens[dockmanager.ReferencesTag] = true
+	imgui.SetWindowFocusStr(dockmanager.DockWindowNames[dockmanager.ReferencesTag])
+}
+
+func renderReferences(open *bool) {
+	if !*open {
+		return
+	}
+	imgui.BeginV(dockmanager.DockWindowNames[dockmanager.ReferencesTag], open, imgui.WindowFlagsNone)
+	defer imgui.End()
+	if !*open {
+		return
+	}
+	activeBank, valid := BnkMngr.ActiveBankV()
+	if !valid || activeBank.SounBankLock.Load() {
+		return
+	}
+	viewer := &activeBank.XRefViewer
+	if viewer.Target == 0 {
+		imgui.Text("Use \"Find References\" in a context menu to search references of an object.")
+		return
+	}
+
+	imgui.Text(fmt.Sprintf("%d reference(s) to %s", len(viewer.Refs), idLabel(viewer.Target)))
+	imgui.SameLine()
+	if imgui.Button("Refresh") {
+		activeBank.FindReferences(viewer.Target)
+	}
+
+	const flags = DefaultTableFlags | imgui.TableFlagsScrollY
+	if imgui.BeginTableV("ReferencesTable", 3, flags, DefaultSize, 0) {
+		imgui.TableSetupColumn("Referenced By")
+		imgui.TableSetupColumn("Hierarchy Type")
+		imgui.TableSetupColumn("Reference Type")
+		imgui.TableSetupScrollFreeze(0, 1)
+		imgui.TableHeadersRow()
+
+		const selectableFlags = imgui.SelectableFlagsSpanAllColumns |
+			                    imgui.SelectableFlagsAllowOverlap
+		for i, r := range viewer.Refs {
+			imgui.TableNextRow()
+			imgui.TableSetColumnIndex(0)
+			imgui.PushIDInt(int32(i))
+			label := strconv.FormatUint(uint64(r.From), 10)
+			if imgui.SelectableBoolV(idLabel(r.From), false, selectableFlags, DefaultSize) {
+				goToHircObj(activeBank, r.From, r.FromType)
+			}
+			if imgui.BeginPopupContextItem() {
+				Disabled(!GCtx.CopyEnable, func() {
+					if imgui.SelectableBool("Copy ID") {
+						clipboard.Write(clipboard.FmtText, []byte(label))
+					}
+				})
+				if imgui.SelectableBool("Find References") {
+					activeBank.FindReferences(r.From)
+				}
+				imgui.EndPopup()
+			}
+			imgui.PopID()
+
+			imgui.TableSetColumnIndex(1)
+			imgui.Text(wwise.HircTypeName[r.FromType])
+			imgui.TableSetColumnIndex(2)
+			imgui.Text(wwise.RefTypeName[r.Type])
+		}
+		imgui.EndTable()
+	}
+}
+
+func goToHircObj(t *be.BankTab, id uint32, ht wwise.HircType) {
+	switch {
+	case ht == wwise.HircTypeSound      ||
+	     ht == wwise.HircTypeRanSeqCntr ||
+	     ht == wwise.HircTypeSwitchCntr ||
+	     ht == wwise.HircTypeActorMixer ||
+	     ht == wwise.HircTypeLayerCntr:
+		t.SetActiveActorMixerHirc(id)
+		t.OpenActorMixerHircNode(id)
+		imgui.SetWindowFocusStr("Actor Mixer Hierarchy")
+		imgui.SetWindowFocusStr("Bank Explorer")
+		t.Focus = be.BankTabActorMixer
+	case ht == wwise.HircTypeMusicSegment    ||
+	     ht == wwise.HircTypeMusicTrack      ||
+	     ht == wwise.HircTypeMusicSwitchCntr ||
+	     ht == wwise.HircTypeMusicRanSeqCntr:
+		if t.SetActiveMusicHirc(id) {
+			imgui.SetWindowFocusStr("Music Hierarchy")
+			imgui.SetWindowFocusStr("Bank Explorer")
+			t.Focus = be.BankTabMusic
+		}
+	case ht == wwise.HircTypeEvent:
+		if t.SetActiveEvent(id) {
+			imgui.SetWindowFocusStr("Events")
+		}
+	case ht == wwise.HircTypeAction:
+		if t.SearchNearestEventAction(id) {
+			t.Focus = be.BankTabEvents
+			imgui.SetWindowFocusStr("Events")
+		}
+	case ht == wwise.HircTypeBus || ht == wwise.HircTypeAuxBus:
+		if t.SetActiveBus(id) {
+			t.Focus = be.BankTabBuses
+			imgui.SetWindowFocusStr("Buses")
+		}
+	case ht == wwise.HircTypeAttenuation:
+		t.SetActiveAttenuation(id)
+		imgui.SetWindowFocusStr("Attenuations")
+	case ht == wwise.HircTypeFxCustom || ht == wwise.HircTypeFxShareSet:
+		t.SetActiveFX(id)
+		imgui.SetWindowFocusStr("FX")
+	}
+}
@@ -15,8 +15,22 @@ func InitTmp() error {
 	return nil
 }
 
-func CleanTmp() error {
-	return os.RemoveAll(Tmp)
+// Create the temporary directory if it is not initialized or it is removed
+// by a previous clean up
+func EnsureTmp() error {
+	if Tmp != "" {
+		if _, err := os.Stat(Tmp); err == nil {
+			return nil
+		}
+	}
+	return InitTmp()
 }
 
-
+func CleanTmp() error {
+	if Tmp == "" {
+		return nil
+	}
+	err := os.RemoveAll(Tmp)
+	Tmp = ""
+	return err
+}
@@ -0,0 +1,252 @@
+package waapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Overwrite location of the conversion cache. Default to wwise-teller/wem
+// under the user cache directory.
+const ConversionCacheEnv = "WWISETELLER_CONVERSION_CACHE"
+
+// Temporary files younger than this are assumed to be entries being stored by
+// a running conversion and are never pruned
+const ConversionCacheTmpGrace = time.Hour
+
+// Persistent content addressed store of converted WEM files. An entry is
+// keyed by the content of the wave file, the conversion setting, the format,
+// and the converter version. Safe for concurrent use by multiple pipelines
+// and multiple processes.
+type ConversionCache struct {
+	Dir    string
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func OpenConversionCache() (*ConversionCache, error) {
+	dir := os.Getenv(ConversionCacheEnv)
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to locate user cache directory: %w", err)
+		}
+		dir = filepath.Join(cacheDir, "wwise-teller", "wem")
+	} else if !filepath.IsAbs(dir) {
+		return nil, fmt.Errorf("Enviromental variable %s is not in absolute path.", ConversionCacheEnv)
+	}
+	return NewConversionCache(dir)
+}
+
+func NewConversionCache(dir string) (*ConversionCache, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, fmt.Errorf("Failed to create conversion cache %s: %w", dir, err)
+	}
+	return &ConversionCache{Dir: dir}, nil
+}
+
+// Hash the content of a wave file together with conversion parameters
+func (c *ConversionCache) Key(wav string, conversion string, format ConversionFormatType, version string) (string, error) {
+	f, err := os.Open(wav)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	content := sha256.New()
+	if _, err := io.Copy(content, f); err != nil {
+		return "", fmt.Errorf("Failed to hash wave file %s: %w", wav, err)
+	}
+	h := sha256.New()
+	h.Write(content.Sum(nil))
+	fmt.Fprintf(h, "\x00%s\x00%d\x00%s", conversion, format, version)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *ConversionCache) path(key string) string {
+	return filepath.Join(c.Dir, key[:2], key + ".wem")
+}
+
+// Copy a cached WEM file into dest. Return false on a miss.
+func (c *ConversionCache) Lookup(key string, dest string) bool {
+	entry := c.path(key)
+	if err := copyFile(entry, dest); err != nil {
+		c.misses.Add(1)
+		return false
+	}
+	// Modification time tracks last use so that pruning keeps hot entries
+	now := time.Now()
+	os.Chtimes(entry, now, now)
+	c.hits.Add(1)
+	return true
+}
+
+// Store a converted WEM file. The entry becomes visible atomically.
+func (c *ConversionCache) Store(key string, wem string) error {
+	entry := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(entry), 0777); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(entry), key + ".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	tmp.Close()
+	if err := copyFile(wem, tmpName); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, entry); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+func (c *ConversionCache) Stats() (uint64, uint64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+func (c *ConversionCache) LogStats() {
+	hits, misses := c.Stats()
+	if hits + misses == 0 {
+		return
+	}
+	slog.Info(fmt.Sprintf(
+		"Conversion cache: %d hits, %d misses (%.1f%% hit rate)",
+		hits, misses, float64(hits) * 100 / float64(hits + misses),
+	))
+}
+
+// Remove entries that are not used within maxAge. Zero maxAge removes every
+// entry. Temporary files left by interrupted stores are removed once they're
+// older than both maxAge and ConversionCacheTmpGrace. Return the number of
+// entries and bytes removed.
+func (c *ConversionCache) Prune(maxAge time.Duration) (int, int64, error) {
+	count := 0
+	var size int64 = 0
+	now := time.Now()
+	err := filepath.WalkDir(c.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !strings.HasSuffix(path, ".wem") && !strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		age := maxAge
+		if strings.HasSuffix(path, ".tmp") {
+			age = max(maxAge, ConversionCacheTmpGrace)
+		}
+		if age > 0 && info.ModTime().After(now.Add(-age)) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		count += 1
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return count, size, fmt.Errorf("Failed to prune conversion cache %s: %w", c.Dir, err)
+	}
+	return count, size, nil
+}
+
+func copyFile(src string, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+type conversionCacheKey struct{}
+
+// Attach a conversion cache to a context. Converters created down the line
+// are wrapped with it (see CachedConverter).
+func WithConversionCache(ctx context.Context, c *ConversionCache) context.Context {
+	return context.WithValue(ctx, conversionCacheKey{}, c)
+}
+
+func ConversionCacheFrom(ctx context.Context) *ConversionCache {
+	c, _ := ctx.Value(conversionCacheKey{}).(*ConversionCache)
+	return c
+}
+
+// Serve jobs from the conversion cache and only hand cache misses to the
+// underlying converter.
+type CachedConverter struct {
+	Converter Converter
+	Cache    *ConversionCache
+}
+
+func (c *CachedConverter) Version() string {
+	return c.Converter.Version()
+}
+
+func (c *CachedConverter) Convert(
+	ctx        context.Context,
+	jobs     []ConversionJob,
+	conversion string,
+	format     ConversionFormatType,
+) error {
+	version := c.Converter.Version()
+	if version == UnknownConverterVersion {
+		slog.Warn("Conversion cache is disabled because version of the converter is unknown")
+		return c.Converter.Convert(ctx, jobs, conversion, format)
+	}
+	misses := make([]ConversionJob, 0, len(jobs))
+	keys := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		key, err := c.Cache.Key(job.Wav, conversion, format, version)
+		if err != nil {
+			return err
+		}
+		if c.Cache.Lookup(key, job.Wem) {
+			continue
+		}
+		misses = append(misses, job)
+		keys = append(keys, key)
+	}
+	if len(jobs) > 0 {
+		slog.Info(fmt.Sprintf("Conversion cache: %d of %d wave files are cached", len(jobs) - len(misses), len(jobs)))
+	}
+	if len(misses) <= 0 {
+		return nil
+	}
+	if err := c.Converter.Convert(ctx, misses, conversion, format); err != nil {
+		return err
+	}
+	for i, job := range misses {
+		if err := c.Cache.Store(keys[i], job.Wem); err != nil {
+			slog.Warn(fmt.Sprintf("Failed to store converted %s into conversion cache", job.Wav), "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package waapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type countingConverter struct {
+	version   string
+	converted int
+}
+
+func (c *countingConverter) Convert(ctx context.Context, jobs []ConversionJob, conversion string, format ConversionFormatType) error {
+	for _, job := range jobs {
+		wav, err := os.ReadFile(job.Wav)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(job.Wem, append([]byte(conversion), wav...), 0777); err != nil {
+			return err
+		}
+		c.converted += 1
+	}
+	return nil
+}
+
+func (c *countingConverter) Version() string {
+	return c.version
+}
+
+func TestConversionCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewConversionCache(filepath.Join(dir, "cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := filepath.Join(dir, "a.wav")
+	b := filepath.Join(dir, "b.wav")
+	os.WriteFile(a, []byte("a"), 0777)
+	os.WriteFile(b, []byte("b"), 0777)
+
+	inner := &countingConverter{version: "Counting|1"}
+	c := &CachedConverter{Converter: inner, Cache: cache}
+	jobs := func(suffix string) []ConversionJob {
+		return []ConversionJob{
+			{Wav: a, Wem: filepath.Join(dir, "a" + suffix + ".wem")},
+			{Wav: b, Wem: filepath.Join(dir, "b" + suffix + ".wem")},
+		}
+	}
+	ctx := context.Background()
+
+	if err := c.Convert(ctx, jobs("0"), "High", ConversionFormatTypeVORBIS); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Convert(ctx, jobs("1"), "High", ConversionFormatTypeVORBIS); err != nil {
+		t.Fatal(err)
+	}
+	if inner.converted != 2 {
+		t.Fatalf("Expecting 2 conversions, got %d", inner.converted)
+	}
+	if wem, _ := os.ReadFile(filepath.Join(dir, "b1.wem")); string(wem) != "Highb" {
+		t.Fatalf("Unexpected cached WEM content %q", wem)
+	}
+
+	// Different conversion setting or content is a miss
+	if err := c.Convert(ctx, jobs("2"), "Low", ConversionFormatTypeVORBIS); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(a, []byte("a2"), 0777)
+	if err := c.Convert(ctx, jobs("3"), "High", ConversionFormatTypeVORBIS); err != nil {
+		t.Fatal(err)
+	}
+	if inner.converted != 5 {
+		t.Fatalf("Expecting 5 conversions, got %d", inner.converted)
+	}
+	if hits, misses := cache.Stats(); hits != 3 || misses != 5 {
+		t.Fatalf("Expecting 3 hits and 5 misses, got %d and %d", hits, misses)
+	}
+
+	// Temporary file of an entry being stored by another process
+	tmp := filepath.Join(cache.Dir, "ab", "ab.123.tmp")
+	if err := os.MkdirAll(filepath.Dir(tmp), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tmp, []byte{0}, 0666); err != nil {
+		t.Fatal(err)
+	}
+	count, _, err := cache.Prune(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Fatalf("Expecting 5 pruned entries, got %d", count)
+	}
+	if _, err := os.Stat(tmp); err != nil {
+		t.Fatalf("Expecting in-flight temporary file is kept: %v", err)
+	}
+	stale := time.Now().Add(-ConversionCacheTmpGrace * 2)
+	if err := os.Chtimes(tmp, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	if count, _, err := cache.Prune(0); err != nil || count != 1 {
+		t.Fatalf("Expecting stale temporary file is pruned, got %d removed (%v)", count, err)
+	}
+
+	// Outputs of a converter with unknown version are never cached
+	inner.version = UnknownConverterVersion
+	for _, suffix := range []string{"4", "5"} {
+		if err := c.Convert(ctx, jobs(suffix), "High", ConversionFormatTypeVORBIS); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inner.converted != 9 {
+		t.Fatalf("Expecting 9 conversions, got %d", inner.converted)
+	}
+	if hits, misses := cache.Stats(); hits != 3 || misses != 5 {
+		t.Fatalf("Expecting cache to be untouched, got %d hits and %d misses", hits, misses)
+	}
+}
@@ -0,0 +1,464 @@
+package waapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Dekr0/wwise-teller/utils"
+)
+
+type ConverterType uint8
+
+const (
+	ConverterTypeWwiseConsole ConverterType = 0 // WwiseConsole on Windows
+	ConverterTypeWine         ConverterType = 1 // WwiseConsole under Wine
+	ConverterTypeNative       ConverterType = 2 // Built-in PCM / ADPCM encoder
+	ConverterTypeExternal     ConverterType = 3 // User provided command template
+	ConverterTypeCount        ConverterType = 4
+)
+
+var ConverterTypeName []string = []string{
+	"WwiseConsole",
+	"WwiseConsole (Wine)",
+	"Native",
+	"External Command",
+}
+
+var ConversionFormatTypeName []string = []string{
+	"pcm",
+	"adpcm",
+	"vorbis",
+	"opus",
+}
+
+// Placeholders recognized in the command template of the external command
+// converter
+const (
+	ConverterInput      = "{input}"      // Absolute path of the wave file
+	ConverterOutput     = "{output}"     // Absolute path of the WEM file to produce
+	ConverterConversion = "{conversion}" // Conversion setting of the script
+	ConverterFormat     = "{format}"     // One of ConversionFormatTypeName
+)
+
+// Selection of conversion backend in a process pipeline. Zero value is
+// WwiseConsole with WWISETELLER_WPROJ.
+type ConverterSpec struct {
+	Type      ConverterType `json:"type"`
+	// Wwise project used by WwiseConsole. Default to WWISETELLER_WPROJ.
+	Project   string        `json:"project"`
+	// Wine binary. Default to wine.
+	Wine      string        `json:"wine"`
+	// Command template of external command converter. First element is the
+	// executable. Each argument is expanded separately so no shell quoting is
+	// needed.
+	Command []string        `json:"command"`
+	// Always convert instead of reusing WEM files in the conversion cache
+	NoCache   bool          `json:"noCache"`
+}
+
+func (s *ConverterSpec) Check() error {
+	if s.Type >= ConverterTypeCount {
+		return fmt.Errorf("Unsupported converter type %d", s.Type)
+	}
+	if s.Project != "" && !filepath.IsAbs(s.Project) {
+		return fmt.Errorf("File path of Wwise project %s is not in aboslute path.", s.Project)
+	}
+	if s.Type == ConverterTypeExternal {
+		if len(s.Command) <= 0 {
+			return fmt.Errorf("External command converter requires a command template")
+		}
+		joined := strings.Join(s.Command, " ")
+		if !strings.Contains(joined, ConverterInput) || !strings.Contains(joined, ConverterOutput) {
+			return fmt.Errorf(
+				"Command template of external command converter must contain %s and %s",
+				ConverterInput, ConverterOutput,
+			)
+		}
+	}
+	return nil
+}
+
+func NewConverter(s *ConverterSpec) (Converter, error) {
+	if err := s.Check(); err != nil {
+		return nil, err
+	}
+	switch s.Type {
+	case ConverterTypeWwiseConsole:
+		return &WwiseConsoleConverter{Project: s.Project}, nil
+	case ConverterTypeWine:
+		return &WineConverter{Project: s.Project, Wine: s.Wine}, nil
+	case ConverterTypeNative:
+		return &NativeConverter{}, nil
+	case ConverterTypeExternal:
+		return &ExternalConverter{Command: s.Command}, nil
+	default:
+		panic("Panic Trap")
+	}
+}
+
+// Convert one wave file into one WEM file. Both are in absolute path.
+type ConversionJob struct {
+	Wav string
+	Wem string
+}
+
+type Converter interface {
+	// Produce a WEM file for every job. Conversion is the name of Wwise
+	// conversion setting (e.g., Vorbis Quality High). Backends that do not
+	// use Wwise rely on format instead.
+	Convert(ctx context.Context, jobs []ConversionJob, conversion string, format ConversionFormatType) error
+	// Identify the backend and its version. Outputs of two converters with
+	// the same version are interchangeable. UnknownConverterVersion if it
+	// cannot be determined.
+	Version() string
+}
+
+// Version of a converter whose outputs cannot be told apart from outputs of
+// another installation. They are never cached.
+const UnknownConverterVersion = ""
+
+type converterKey struct{}
+
+// Attach a converter to a context so that process scripts down the line use
+// it.
+func WithConverter(ctx context.Context, c Converter) context.Context {
+	return context.WithValue(ctx, converterKey{}, c)
+}
+
+// Converter attached to the context. Default to WwiseConsole with
+// WWISETELLER_WPROJ.
+func ConverterFrom(ctx context.Context) Converter {
+	if c, ok := ctx.Value(converterKey{}).(Converter); ok {
+		return c
+	}
+	return &WwiseConsoleConverter{}
+}
+
+// Create a staging folder and assign each wave file a WEM file in it. The
+// order of jobs follows the order of wave files. Caller is responsible to
+// remove the staging folder.
+// Assume there's no duplicate wave files.
+// Assume all wave files are in full path.
+func StageConversion(wavs []string) (string, []ConversionJob, error) {
+	if utils.Tmp == "" {
+		if err := utils.InitTmp(); err != nil {
+			return "", nil, err
+		}
+	}
+	staging, err := os.MkdirTemp(utils.Tmp, "staging-")
+	if err != nil {
+		return "", nil, err
+	}
+
+	jobs := make([]ConversionJob, len(wavs))
+	suffixing := make(map[string]uint8, len(wavs))
+	for i, wav := range wavs {
+		basename := strings.Split(filepath.Base(wav), ".")[0]
+		dest := basename + ".wem"
+		if suffix, in := suffixing[basename]; in {
+			dest = fmt.Sprintf("%s_%d.wem", basename, suffix)
+		}
+		suffixing[basename] += 1
+		jobs[i] = ConversionJob{Wav: wav, Wem: filepath.Join(staging, dest)}
+	}
+	return staging, jobs, nil
+}
+
+// Convert through WwiseConsole on Windows.
+type WwiseConsoleConverter struct {
+	Project string
+}
+
+func (c *WwiseConsoleConverter) Convert(
+	ctx        context.Context,
+	jobs     []ConversionJob,
+	conversion string,
+	format     ConversionFormatType,
+) error {
+	if runtime.GOOS != "windows" {
+		return fmt.Errorf("Wwise External Sources Conversion is only available on Windows")
+	}
+	proj, err := resolveProject(c.Project)
+	if err != nil {
+		return err
+	}
+	cli, err := GetWwiseCLI()
+	if err != nil {
+		return err
+	}
+	return convertExternalSources(ctx, jobs, conversion, func(p string) string { return p },
+		func(wsource string, output string) *exec.Cmd {
+			return exec.CommandContext(
+				ctx,
+				cli,
+				"convert-external-source", proj,
+				"--platform", "Windows",
+				"--source-file", wsource,
+				"--output", output,
+			)
+		},
+	)
+}
+
+func (c *WwiseConsoleConverter) Version() string {
+	return wwiseVersion(c.Project)
+}
+
+// Convert through WwiseConsole under Wine. WWISEROOT points to the Wwise
+// installation inside the Wine prefix using host path. All paths handed to
+// WwiseConsole are mapped to drive Z:.
+type WineConverter struct {
+	Project string
+	Wine    string
+}
+
+func (c *WineConverter) Convert(
+	ctx        context.Context,
+	jobs     []ConversionJob,
+	conversion string,
+	format     ConversionFormatType,
+) error {
+	proj, err := resolveProject(c.Project)
+	if err != nil {
+		return err
+	}
+	cli, err := GetWwiseCLI()
+	if err != nil {
+		return err
+	}
+	wine := c.Wine
+	if wine == "" {
+		wine = "wine"
+	}
+	return convertExternalSources(ctx, jobs, conversion, WinePath,
+		func(wsource string, output string) *exec.Cmd {
+			return exec.CommandContext(
+				ctx,
+				wine, cli,
+				"convert-external-source", WinePath(proj),
+				"--platform", "Windows",
+				"--source-file", WinePath(wsource),
+				"--output", WinePath(output),
+			)
+		},
+	)
+}
+
+func (c *WineConverter) Version() string {
+	return wwiseVersion(c.Project)
+}
+
+// Map a host absolute path to the path seen by Wine through drive Z:
+func WinePath(p string) string {
+	if !strings.HasPrefix(p, "/") {
+		return p
+	}
+	return "Z:" + strings.ReplaceAll(p, "/", "\\")
+}
+
+// Wwise installation folder is named after its version (e.g.,
+// Wwise2024.1.0.8669). Conversion settings live in the project so it is part
+// of the version as well. Unknown if either of them is missing.
+func wwiseVersion(proj string) string {
+	if proj == "" {
+		proj = os.Getenv("WWISETELLER_WPROJ")
+	}
+	wwiseRoot := os.Getenv("WWISEROOT")
+	if wwiseRoot == "" {
+		return UnknownConverterVersion
+	}
+	digest := projectDigest(proj)
+	if digest == "" {
+		return UnknownConverterVersion
+	}
+	version := filepath.Base(filepath.Clean(wwiseRoot))
+	return "WwiseConsole|" + version + "|" + proj + "|" + digest
+}
+
+// Hash of the content of a Wwise project file so that WEM files converted
+// before the project changes (e.g., conversion settings) are not reused.
+// Empty if the project file cannot be read.
+func projectDigest(proj string) string {
+	blob, err := os.ReadFile(proj)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(blob))
+}
+
+func resolveProject(proj string) (string, error) {
+	if proj == "" {
+		return GetProject()
+	}
+	stat, err := os.Lstat(proj)
+	if err != nil {
+		return "", err
+	}
+	if stat.IsDir() {
+		return "", fmt.Errorf("File path of Wwise project %s is a directory.", proj)
+	}
+	return proj, nil
+}
+
+// Write an external sources list into a private output folder, run
+// WwiseConsole, and move each output into its job destination.
+func convertExternalSources(
+	ctx          context.Context,
+	jobs       []ConversionJob,
+	conversion   string,
+	path         func(string) string,
+	command      func(wsource string, output string) *exec.Cmd,
+) error {
+	if len(jobs) <= 0 {
+		return nil
+	}
+	if utils.Tmp == "" {
+		if err := utils.InitTmp(); err != nil {
+			return err
+		}
+	}
+	output, err := os.MkdirTemp(utils.Tmp, "wwise-console-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(output)
+
+	list := ExternalSourcesList{
+		SchemaVersion: 1,
+		Root: path(output),
+		Sources: make([]ExternalSource, len(jobs)),
+	}
+	for i, job := range jobs {
+		list.Sources[i] = ExternalSource{
+			Path: path(job.Wav),
+			Conversion: &conversion,
+			Destination: fmt.Sprintf("%d.wem", i),
+		}
+	}
+	x, err := xml.MarshalIndent(&list, "", "    ")
+	if err != nil {
+		return err
+	}
+	wsource := filepath.Join(output, "external_sources.wsources")
+	if err := os.WriteFile(wsource, []byte(xml.Header + string(x)), 0777); err != nil {
+		return err
+	}
+
+	res, err := command(wsource, output).CombinedOutput()
+	logCommandOutput(res)
+	if err != nil {
+		return fmt.Errorf("WwiseConsole failed to convert external sources: %w", err)
+	}
+
+	for i, job := range jobs {
+		converted := filepath.Join(output, "Windows", fmt.Sprintf("%d.wem", i))
+		if err := os.Rename(converted, job.Wem); err != nil {
+			return fmt.Errorf("WwiseConsole did not produce WEM file for %s: %w", job.Wav, err)
+		}
+	}
+	return nil
+}
+
+// Convert through a user provided command. The command is run once per job
+// after placeholders are expanded.
+type ExternalConverter struct {
+	Command []string
+}
+
+func (c *ExternalConverter) Expand(job ConversionJob, conversion string, format ConversionFormatType) []string {
+	formatName := ""
+	if int(format) < len(ConversionFormatTypeName) {
+		formatName = ConversionFormatTypeName[format]
+	}
+	r := strings.NewReplacer(
+		ConverterInput, job.Wav,
+		ConverterOutput, job.Wem,
+		ConverterConversion, conversion,
+		ConverterFormat, formatName,
+	)
+	args := make([]string, len(c.Command))
+	for i, arg := range c.Command {
+		args[i] = r.Replace(arg)
+	}
+	return args
+}
+
+func (c *ExternalConverter) Version() string {
+	return "External|" + strings.Join(c.Command, " ")
+}
+
+func (c *ExternalConverter) Convert(
+	ctx        context.Context,
+	jobs     []ConversionJob,
+	conversion string,
+	format     ConversionFormatType,
+) error {
+	if len(c.Command) <= 0 {
+		return fmt.Errorf("External command converter requires a command template")
+	}
+	for _, job := range jobs {
+		args := c.Expand(job, conversion, format)
+		res, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+		logCommandOutput(res)
+		if err != nil {
+			return fmt.Errorf("External command failed to convert %s: %w", job.Wav, err)
+		}
+		if _, err := os.Lstat(job.Wem); err != nil {
+			return fmt.Errorf("External command did not produce WEM file for %s: %w", job.Wav, err)
+		}
+	}
+	return nil
+}
+
+// Stand-in converter for planning. Instead of converting, it writes a zero
+// filled WEM file with the size of the wave file as an estimate.
+type PlaceholderConverter struct {
+	converted atomic.Uint64
+}
+
+func (c *PlaceholderConverter) Convert(
+	ctx        context.Context,
+	jobs     []ConversionJob,
+	conversion string,
+	format     ConversionFormatType,
+) error {
+	for _, job := range jobs {
+		stat, err := os.Stat(job.Wav)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(job.Wem, make([]byte, stat.Size()), 0777); err != nil {
+			return err
+		}
+		c.converted.Add(1)
+	}
+	return nil
+}
+
+func (c *PlaceholderConverter) Version() string {
+	return "Placeholder"
+}
+
+// # of wave files that would be converted
+func (c *PlaceholderConverter) Converted() uint64 {
+	return c.converted.Load()
+}
+
+func logCommandOutput(res []byte) {
+	for line := range strings.SplitSeq(string(res), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		slog.Info(line)
+	}
+}
@@ -0,0 +1,210 @@
+package waapi
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+	"github.com/go-audio/audio"
+	"github.com/go-audio/wav"
+)
+
+func TestNativeConverter(t *testing.T) {
+	dir := t.TempDir()
+	wavPath := filepath.Join(dir, "sine.wav")
+	f, err := os.Create(wavPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const channels = 2
+	const frames = 1000
+	data := make([]int, frames * channels)
+	for i := range frames {
+		v := int(8000 * math.Sin(2 * math.Pi * 440 * float64(i) / 48000))
+		data[i * channels] = v
+		data[i * channels + 1] = -v
+	}
+	e := wav.NewEncoder(f, 48000, 16, channels, 1)
+	err = e.Write(&audio.IntBuffer{
+		Format: &audio.Format{NumChannels: channels, SampleRate: 48000},
+		Data: data,
+		SourceBitDepth: 16,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	c := &NativeConverter{}
+	for _, format := range []ConversionFormatType{ConversionFormatTypePCM, ConversionFormatTypeADPCM} {
+		jobs := []ConversionJob{{Wav: wavPath, Wem: filepath.Join(dir, "sine.wem")}}
+		if err := c.Convert(context.Background(), jobs, "", format); err != nil {
+			t.Fatal(err)
+		}
+		wem, err := os.ReadFile(jobs[0].Wem)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pluginID, err := WEMPluginID(wem)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pluginID != format.PluginID() {
+			t.Fatalf("Expecting codec %s, got %s", wwise.PluginTypeCodecFmt[format.PluginID()], wwise.PluginTypeCodecFmt[pluginID])
+		}
+		if string(wem[44:48]) != "data" {
+			t.Fatalf("Expecting data chunk after fmt chunk")
+		}
+		payload := wem[52:52 + binary.LittleEndian.Uint32(wem[48:52])]
+
+		if format == ConversionFormatTypeADPCM {
+			blockAlign := binary.LittleEndian.Uint16(wem[32:34])
+			blockSamples := binary.LittleEndian.Uint16(wem[38:40])
+			if blockAlign != WEMADPCMBlockSize * channels || blockSamples != WEMADPCMBlockSamples {
+				t.Fatalf("Unexpected block align %d and samples per block %d", blockAlign, blockSamples)
+			}
+			if len(payload) % int(blockAlign) != 0 || len(payload) / int(blockAlign) * WEMADPCMBlockSamples < len(data) / channels {
+				t.Fatalf("Unexpected ADPCM data size %d", len(payload))
+			}
+			continue
+		}
+		decoded := make([]int16, len(payload) / 2)
+		for i := range decoded {
+			decoded[i] = int16(binary.LittleEndian.Uint16(payload[i * 2:]))
+		}
+		if len(decoded) < len(data) {
+			t.Fatalf("Expecting at least %d samples, got %d", len(data), len(decoded))
+		}
+		for i, s := range data {
+			if int(decoded[i]) != s {
+				t.Fatalf("Unexpected PCM sample %d: %d != %d", i, decoded[i], s)
+			}
+		}
+	}
+
+	err = c.Convert(context.Background(), nil, "", ConversionFormatTypeVORBIS)
+	if err == nil {
+		t.Fatal("Expecting native converter to reject Vorbis")
+	}
+}
+
+func TestStageConversion(t *testing.T) {
+	staging, jobs, err := StageConversion([]string{"/a/core.wav", "/b/core.wav", "/c/tail.wav"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(staging)
+	wems := []string{}
+	for _, job := range jobs {
+		wems = append(wems, filepath.Base(job.Wem))
+	}
+	if !slices.Equal(wems, []string{"core.wem", "core_1.wem", "tail.wem"}) {
+		t.Fatalf("Unexpected staged WEM files %v", wems)
+	}
+}
+
+func TestExternalConverterExpand(t *testing.T) {
+	spec := ConverterSpec{Type: ConverterTypeExternal, Command: []string{"enc", "-q", "{conversion}"}}
+	if err := spec.Check(); err == nil {
+		t.Fatal("Expecting command template without input / output to be rejected")
+	}
+	spec.Command = []string{"enc", "--codec={format}", "-q", "{conversion}", "{input}", "{output}"}
+	c, err := NewConverter(&spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := c.(*ExternalConverter).Expand(
+		ConversionJob{Wav: "/in/a b.wav", Wem: "/out/a b.wem"}, "Vorbis Quality High", ConversionFormatTypeVORBIS,
+	)
+	expect := []string{"enc", "--codec=vorbis", "-q", "Vorbis Quality High", "/in/a b.wav", "/out/a b.wem"}
+	if !slices.Equal(args, expect) {
+		t.Fatalf("Expecting %v, got %v", expect, args)
+	}
+	if WinePath("/home/user/a.wav") != `Z:\home\user\a.wav` {
+		t.Fatalf("Unexpected Wine path %s", WinePath("/home/user/a.wav"))
+	}
+}
+
+func TestWwiseConsoleConverterVersion(t *testing.T) {
+	proj := filepath.Join(t.TempDir(), "Project.wproj")
+	if err := os.WriteFile(proj, []byte("<Project/>"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	c := &WwiseConsoleConverter{Project: proj}
+	t.Setenv("WWISEROOT", "")
+	if c.Version() != UnknownConverterVersion {
+		t.Fatalf("Expecting unknown version without WWISEROOT, got %s", c.Version())
+	}
+	t.Setenv("WWISEROOT", "/opt/Wwise2024.1.0.8669")
+	before := c.Version()
+	if !strings.Contains(before, "Wwise2024.1.0.8669") {
+		t.Fatalf("Expecting Wwise version in %s", before)
+	}
+	if before != c.Version() {
+		t.Fatal("Expecting version to be stable while the project is unchanged")
+	}
+	if err := os.WriteFile(proj, []byte("<Project Conversion=\"ADPCM\"/>"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if before == c.Version() {
+		t.Fatal("Expecting version to change with the content of the project")
+	}
+}
+
+// One stereo frame in the Wwise IMA layout read by vgmstream: each channel
+// has its own 0x24 bytes block of header and nibbles. Expected samples are
+// taken from vgmstream's decode_wwise_ima.
+func TestDecodeADPCMWEMData(t *testing.T) {
+	left := []byte{0x00, 0x00, 0x00, 0x00}
+	for i := range 32 {
+		switch {
+		case i < 4:
+			left = append(left, 0x77)
+		case i < 8:
+			left = append(left, 0xFF)
+		case i < 31:
+			left = append(left, 0x00)
+		default:
+			// High nibble is the unused 64th nibble
+			left = append(left, 0x70)
+		}
+	}
+	right := []byte{0xE8, 0x03, 0x0A, 0x00}
+	for range 32 {
+		right = append(right, 0x18)
+	}
+	data := append(left, right...)
+
+	samples := DecodeADPCMWEMData(data, 2)
+	if len(samples) != 64 * 2 {
+		t.Fatalf("Expecting 64 samples per channel, got %d", len(samples) / 2)
+	}
+	expectLeft := []int16{0, 11, 41, 104, 240, 533, 1164, 2521, 5431, -805, -14177, -32768}
+	expectRight := []int16{1000, 998, 1004, 1002, 1006, 1005, 1009, 1008, 1011, 1010, 1013, 1013}
+	for i := range expectLeft {
+		if samples[i * 2] != expectLeft[i] || samples[i * 2 + 1] != expectRight[i] {
+			t.Fatalf("Unexpected sample %d: %d %d", i, samples[i * 2], samples[i * 2 + 1])
+		}
+	}
+	if samples[63 * 2 + 1] != 1039 {
+		t.Fatalf("Unexpected last sample of right channel %d", samples[63 * 2 + 1])
+	}
+
+	wem := EncodeADPCMWEM([]int16{0, 1000, 0, 1000}, 2, 48000)
+	payload := wem[52:52 + binary.LittleEndian.Uint32(wem[48:52])]
+	if len(payload) != WEMADPCMBlockSize * 2 {
+		t.Fatalf("Expecting one stereo frame, got %d bytes", len(payload))
+	}
+	if int16(binary.LittleEndian.Uint16(payload[0:])) != 0 || int16(binary.LittleEndian.Uint16(payload[WEMADPCMBlockSize:])) != 1000 {
+		t.Fatal("Expecting the header of each channel at the start of its own block")
+	}
+}
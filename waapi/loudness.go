@@ -0,0 +1,269 @@
+package waapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/go-audio/wav"
+)
+
+// Absolute silence floor used when a measurement has no energy at all
+const LoudnessFloor = -144.0
+
+const (
+	loudnessBlockSec   = 0.4
+	loudnessStepSec    = 0.1
+	loudnessAbsGate    = -70.0
+	loudnessRelGate    = -10.0
+	truePeakOverSample = 4
+	truePeakTaps       = 12
+)
+
+// Integrated loudness in LUFS (ITU-R BS.1770-4), true peak in dBTP and RMS
+// in dBFS.
+type Loudness struct {
+	Integrated float64 `json:"integrated"`
+	TruePeak   float64 `json:"truePeak"`
+	RMS        float64 `json:"rms"`
+}
+
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	z1, z2             float64
+}
+
+func (b *biquad) process(x float64) float64 {
+	y := b.b0*x + b.z1
+	b.z1 = b.b1*x - b.a1*y + b.z2
+	b.z2 = b.b2*x - b.a2*y
+	return y
+}
+
+// K-weighting pre-filter (high shelf) and RLB filter (high pass) derived for
+// arbitrary sample rate. Same derivation as libebur128.
+func kWeighting(sampleRate int) (biquad, biquad) {
+	fs := float64(sampleRate)
+
+	const shelfGain = 3.999843853973347
+	const shelfQ    = 0.7071752369554196
+	const shelfFc   = 1681.974450955533
+	K := math.Tan(math.Pi * shelfFc / fs)
+	Vh := math.Pow(10, shelfGain / 20)
+	Vb := math.Pow(Vh, 0.4996667741545416)
+	a0 := 1 + K / shelfQ + K * K
+	shelf := biquad{
+		b0: (Vh + Vb * K / shelfQ + K * K) / a0,
+		b1: 2 * (K * K - Vh) / a0,
+		b2: (Vh - Vb * K / shelfQ + K * K) / a0,
+		a1: 2 * (K * K - 1) / a0,
+		a2: (1 - K / shelfQ + K * K) / a0,
+	}
+
+	const passQ  = 0.5003270373238773
+	const passFc = 38.13547087602444
+	K = math.Tan(math.Pi * passFc / fs)
+	a0 = 1 + K / passQ + K * K
+	pass := biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (K * K - 1) / a0,
+		a2: (1 - K / passQ + K * K) / a0,
+	}
+
+	return shelf, pass
+}
+
+// Channel weighting according to BS.1770. Assume WAVE channel order. LFE is
+// excluded.
+func channelWeight(channel int, numChannels int) float64 {
+	if numChannels >= 6 && channel == 3 {
+		return 0.0
+	}
+	if channel < 3 {
+		return 1.0
+	}
+	return 1.41
+}
+
+func toDB(v float64) float64 {
+	if v <= 0 {
+		return LoudnessFloor
+	}
+	return 10 * math.Log10(v)
+}
+
+// Measure interleaved samples in [-1.0, 1.0]
+func MeasureLoudness(samples []float64, numChannels int, sampleRate int) (Loudness, error) {
+	if numChannels <= 0 {
+		return Loudness{}, fmt.Errorf("Invalid number of channels %d", numChannels)
+	}
+	if sampleRate <= 0 {
+		return Loudness{}, fmt.Errorf("Invalid sample rate %d", sampleRate)
+	}
+	if len(samples) % numChannels != 0 {
+		return Loudness{}, fmt.Errorf("Number of samples is not a multiple of number of channels")
+	}
+	numFrames := len(samples) / numChannels
+	if numFrames == 0 {
+		return Loudness{LoudnessFloor, LoudnessFloor, LoudnessFloor}, nil
+	}
+
+	l := Loudness{}
+
+	// RMS
+	sum := 0.0
+	for _, s := range samples {
+		sum += s * s
+	}
+	l.RMS = toDB(sum / float64(len(samples)))
+
+	// K-weighted squared signal per channel
+	weighted := make([][]float64, numChannels)
+	for c := range numChannels {
+		shelf, pass := kWeighting(sampleRate)
+		weighted[c] = make([]float64, numFrames)
+		for i := range numFrames {
+			y := pass.process(shelf.process(samples[i * numChannels + c]))
+			weighted[c][i] = y * y
+		}
+	}
+
+	integrated, err := integratedLoudness(weighted, numFrames, sampleRate)
+	if err != nil {
+		return Loudness{}, err
+	}
+	l.Integrated = integrated
+	l.TruePeak = toDB(truePeak(samples, numChannels, numFrames))
+
+	return l, nil
+}
+
+func integratedLoudness(weighted [][]float64, numFrames int, sampleRate int) (float64, error) {
+	blockSize := int(loudnessBlockSec * float64(sampleRate))
+	stepSize := int(loudnessStepSec * float64(sampleRate))
+	if stepSize <= 0 {
+		return 0, fmt.Errorf("Sample rate %d is too low to measure loudness", sampleRate)
+	}
+	if blockSize > numFrames {
+		blockSize = numFrames
+	}
+
+	numChannels := len(weighted)
+	blocks := make([]float64, 0, numFrames / stepSize + 1)
+	for begin := 0; begin + blockSize <= numFrames; begin += stepSize {
+		power := 0.0
+		for c := range numChannels {
+			g := channelWeight(c, numChannels)
+			if g == 0 {
+				continue
+			}
+			sum := 0.0
+			for _, z := range weighted[c][begin:begin + blockSize] {
+				sum += z
+			}
+			power += g * sum / float64(blockSize)
+		}
+		blocks = append(blocks, power)
+	}
+
+	gated := func(threshold float64) (float64, int) {
+		sum, count := 0.0, 0
+		for _, power := range blocks {
+			if -0.691 + toDB(power) > threshold {
+				sum += power
+				count += 1
+			}
+		}
+		return sum, count
+	}
+
+	sum, count := gated(loudnessAbsGate)
+	if count == 0 {
+		return LoudnessFloor, nil
+	}
+	relative := -0.691 + toDB(sum / float64(count)) + loudnessRelGate
+	sum, count = gated(max(relative, loudnessAbsGate))
+	if count == 0 {
+		return LoudnessFloor, nil
+	}
+	return -0.691 + toDB(sum / float64(count)), nil
+}
+
+// Return the linear squared peak value of the oversampled signal. Use Hann
+// windowed sinc interpolation.
+func truePeak(samples []float64, numChannels int, numFrames int) float64 {
+	kernel := make([][]float64, truePeakOverSample)
+	for phase := range truePeakOverSample {
+		kernel[phase] = make([]float64, 2 * truePeakTaps)
+		frac := float64(phase) / truePeakOverSample
+		for k := range 2 * truePeakTaps {
+			t := float64(k - truePeakTaps + 1) - frac
+			sinc := 1.0
+			if t != 0 {
+				sinc = math.Sin(math.Pi * t) / (math.Pi * t)
+			}
+			window := 0.5 + 0.5 * math.Cos(math.Pi * t / truePeakTaps)
+			kernel[phase][k] = sinc * window
+		}
+	}
+
+	peak := 0.0
+	for c := range numChannels {
+		for i := range numFrames {
+			s := samples[i * numChannels + c]
+			peak = max(peak, s * s)
+			for phase := 1; phase < truePeakOverSample; phase++ {
+				v := 0.0
+				for k := range 2 * truePeakTaps {
+					j := i + k - truePeakTaps + 1
+					if j < 0 || j >= numFrames {
+						continue
+					}
+					v += samples[j * numChannels + c] * kernel[phase][k]
+				}
+				peak = max(peak, v * v)
+			}
+		}
+	}
+	return peak
+}
+
+func MeasureLoudnessWAVFile(path string) (Loudness, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Loudness{}, err
+	}
+	defer f.Close()
+
+	d := wav.NewDecoder(f)
+	if !d.IsValidFile() {
+		return Loudness{}, fmt.Errorf("%s is not a valid WAVE file.", path)
+	}
+	buf, err := d.FullPCMBuffer()
+	if err != nil {
+		return Loudness{}, fmt.Errorf("Failed to decode WAVE file %s: %w", path, err)
+	}
+	if buf.SourceBitDepth <= 0 {
+		return Loudness{}, fmt.Errorf("WAVE file %s has invalid bit depth %d", path, buf.SourceBitDepth)
+	}
+
+	scale := 1.0 / float64(int64(1) << (buf.SourceBitDepth - 1))
+	samples := make([]float64, len(buf.Data))
+	for i, s := range buf.Data {
+		samples[i] = float64(s) * scale
+	}
+	return MeasureLoudness(samples, buf.Format.NumChannels, buf.Format.SampleRate)
+}
+
+// Decode WEM using vgmstream, and then measure the decoded WAVE
+func MeasureLoudnessWEMByte(ctx context.Context, wem []byte) (Loudness, error) {
+	wave, err := ExportWEMByte(ctx, wem, true)
+	if err != nil {
+		return Loudness{}, err
+	}
+	defer os.Remove(wave)
+	return MeasureLoudnessWAVFile(wave)
+}
@@ -0,0 +1,67 @@
+package waapi
+
+import (
+	"math"
+	"testing"
+)
+
+func sine(freq float64, amp float64, sampleRate int, numChannels int, sec float64) []float64 {
+	numFrames := int(float64(sampleRate) * sec)
+	samples := make([]float64, numFrames * numChannels)
+	for i := range numFrames {
+		v := amp * math.Sin(2 * math.Pi * freq * float64(i) / float64(sampleRate))
+		for c := range numChannels {
+			samples[i * numChannels + c] = v
+		}
+	}
+	return samples
+}
+
+func TestMeasureLoudness(t *testing.T) {
+	// BS.1770: 0 dBFS 997 Hz sine on one channel reads -3.01 LUFS
+	l, err := MeasureLoudness(sine(997, 1.0, 48000, 1, 5), 1, 48000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(l.Integrated - -3.01) > 0.05 {
+		t.Fatalf("Expected -3.01 LUFS, got %f", l.Integrated)
+	}
+	if math.Abs(l.RMS - -3.01) > 0.05 {
+		t.Fatalf("Expected -3.01 dBFS RMS, got %f", l.RMS)
+	}
+	if math.Abs(l.TruePeak) > 0.1 {
+		t.Fatalf("Expected 0 dBTP, got %f", l.TruePeak)
+	}
+
+	// -20 dBFS stereo
+	amp := math.Pow(10, -20.0 / 20.0)
+	l, err = MeasureLoudness(sine(997, amp, 44100, 2, 5), 2, 44100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(l.Integrated - -20.0) > 0.05 {
+		t.Fatalf("Expected -20 LUFS, got %f", l.Integrated)
+	}
+
+	// -6 dBFS
+	amp = math.Pow(10, -6.0 / 20.0)
+	l, err = MeasureLoudness(sine(997, amp, 48000, 1, 5), 1, 48000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(l.TruePeak - -6.0) > 0.1 {
+		t.Fatalf("Expected -6 dBTP, got %f", l.TruePeak)
+	}
+
+	l, err = MeasureLoudness(make([]float64, 48000), 1, 48000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Integrated != LoudnessFloor {
+		t.Fatalf("Expected silence to be gated, got %f", l.Integrated)
+	}
+
+	if _, err := MeasureLoudness(sine(1, 0.5, 5, 1, 2), 1, 5); err == nil {
+		t.Fatal("Expected sample rate below 10 Hz to be rejected")
+	}
+}
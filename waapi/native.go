@@ -0,0 +1,358 @@
+package waapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-audio/wav"
+)
+
+// Number of samples per channel in one Wwise IMA ADPCM block. One sample is
+// stored in the block header and 63 samples are stored as 4 bit nibbles. The
+// last nibble of a block is unused.
+const WEMADPCMBlockSamples = 64
+
+// Size of one Wwise IMA ADPCM block of one channel
+const WEMADPCMBlockSize = 0x24
+
+var imaStepTable = [89]int{
+	7, 8, 9, 10, 11, 12, 13, 14, 16, 17,
+	19, 21, 23, 25, 28, 31, 34, 37, 41, 45,
+	50, 55, 60, 66, 73, 80, 88, 97, 107, 118,
+	130, 143, 157, 173, 190, 209, 230, 253, 279, 307,
+	337, 371, 408, 449, 494, 544, 598, 658, 724, 796,
+	876, 963, 1060, 1166, 1282, 1411, 1552, 1707, 1878, 2066,
+	2272, 2499, 2749, 3024, 3327, 3660, 4026, 4428, 4871, 5358,
+	5894, 6484, 7132, 7845, 8630, 9493, 10442, 11487, 12635, 13899,
+	15289, 16818, 18500, 20350, 22385, 24623, 27086, 29794, 32767,
+}
+
+var imaIndexTable = [8]int{-1, -1, -1, -1, 2, 4, 6, 8}
+
+// Encode PCM and ADPCM WEM without Wwise. Conversion setting is ignored
+// except the format. Only integer PCM wave files are supported. Samples are
+// reduced to 16 bit.
+type NativeConverter struct{}
+
+// Bump when encoder output changes
+func (c *NativeConverter) Version() string {
+	return "Native|2"
+}
+
+func (c *NativeConverter) Convert(
+	ctx        context.Context,
+	jobs     []ConversionJob,
+	conversion string,
+	format     ConversionFormatType,
+) error {
+	if format != ConversionFormatTypePCM && format != ConversionFormatTypeADPCM {
+		return fmt.Errorf("Native converter only supports PCM and ADPCM format")
+	}
+	for _, job := range jobs {
+		select {
+		case <- ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		samples, channels, sampleRate, err := readWAV16(job.Wav)
+		if err != nil {
+			return err
+		}
+		var wem []byte
+		if format == ConversionFormatTypePCM {
+			wem = EncodePCMWEM(samples, channels, sampleRate)
+		} else {
+			wem = EncodeADPCMWEM(samples, channels, sampleRate)
+		}
+		if err := os.WriteFile(job.Wem, wem, 0777); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Return play duration of a PCM WAVE file
+func WAVDuration(path string) (time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	d := wav.NewDecoder(f)
+	if !d.IsValidFile() {
+		return 0, fmt.Errorf("%s is not a valid WAVE file.", path)
+	}
+	// Decoder.Duration counts chunk headers as samples
+	if err := d.FwdToPCM(); err != nil {
+		return 0, fmt.Errorf("Failed to read PCM chunk of WAVE file %s: %w", path, err)
+	}
+	frameSize := int64(d.NumChans) * int64(d.BitDepth / 8)
+	if frameSize <= 0 || d.SampleRate == 0 {
+		return 0, fmt.Errorf("WAVE file %s has invalid format.", path)
+	}
+	frames := int64(d.PCMSize) / frameSize
+	return time.Duration(frames) * time.Second / time.Duration(d.SampleRate), nil
+}
+
+// Return interleaved 16 bit samples, # of channels, and sample rate
+func readWAV16(path string) ([]int16, int, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer f.Close()
+
+	d := wav.NewDecoder(f)
+	if !d.IsValidFile() {
+		return nil, 0, 0, fmt.Errorf("%s is not a valid WAVE file.", path)
+	}
+	buf, err := d.FullPCMBuffer()
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("Failed to decode WAVE file %s: %w", path, err)
+	}
+	if d.WavAudioFormat != WEMFormatTagPCM && d.WavAudioFormat != WEMFormatTagExtensible {
+		return nil, 0, 0, fmt.Errorf("WAVE file %s is not in integer PCM (format tag 0x%04X)", path, d.WavAudioFormat)
+	}
+	if buf.Format.NumChannels <= 0 || buf.Format.NumChannels > len(ChannelLUT) {
+		return nil, 0, 0, fmt.Errorf("WAVE file %s has unsupported # of channels %d", path, buf.Format.NumChannels)
+	}
+
+	samples := make([]int16, len(buf.Data))
+	switch buf.SourceBitDepth {
+	case 8:
+		for i, s := range buf.Data {
+			samples[i] = int16((s - 128) << 8)
+		}
+	case 16:
+		for i, s := range buf.Data {
+			samples[i] = int16(s)
+		}
+	case 24:
+		for i, s := range buf.Data {
+			samples[i] = int16(s >> 8)
+		}
+	case 32:
+		for i, s := range buf.Data {
+			samples[i] = int16(s >> 16)
+		}
+	default:
+		return nil, 0, 0, fmt.Errorf("WAVE file %s has unsupported bit depth %d", path, buf.SourceBitDepth)
+	}
+	return samples, buf.Format.NumChannels, buf.Format.SampleRate, nil
+}
+
+// Default speaker layout used by Wwise for a given # of channels
+func channelMask(channels int) uint32 {
+	switch channels {
+	case 1:
+		return 0x4
+	case 2:
+		return 0x3
+	case 3:
+		return 0x7
+	case 4:
+		return 0x33
+	case 6:
+		return 0x3F
+	case 8:
+		return 0x63F
+	default:
+		return 0
+	}
+}
+
+// fmt chunk (0x18 bytes) used by Wwise: WAVEFORMATEX followed by 2 bytes of
+// extra data (valid bits or samples per block) and channel mask.
+func writeWEM(
+	tag           uint16,
+	channels      int,
+	sampleRate    int,
+	avgBytes      int,
+	blockAlign    int,
+	bitsPerSample int,
+	extra         uint16,
+	data        []byte,
+) []byte {
+	var b bytes.Buffer
+	b.Grow(12 + 8 + 0x18 + 8 + len(data))
+	le := binary.LittleEndian
+
+	b.WriteString("RIFF")
+	binary.Write(&b, le, uint32(4 + 8 + 0x18 + 8 + len(data) + len(data) & 1))
+	b.WriteString("WAVE")
+
+	b.WriteString("fmt ")
+	binary.Write(&b, le, uint32(0x18))
+	binary.Write(&b, le, tag)
+	binary.Write(&b, le, uint16(channels))
+	binary.Write(&b, le, uint32(sampleRate))
+	binary.Write(&b, le, uint32(avgBytes))
+	binary.Write(&b, le, uint16(blockAlign))
+	binary.Write(&b, le, uint16(bitsPerSample))
+	binary.Write(&b, le, uint16(6))
+	binary.Write(&b, le, extra)
+	binary.Write(&b, le, channelMask(channels))
+
+	b.WriteString("data")
+	binary.Write(&b, le, uint32(len(data)))
+	b.Write(data)
+	if len(data) & 1 == 1 {
+		b.WriteByte(0)
+	}
+	return b.Bytes()
+}
+
+// Encode interleaved 16 bit samples into a PCM WEM
+func EncodePCMWEM(samples []int16, channels int, sampleRate int) []byte {
+	data := make([]byte, len(samples) * 2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[i * 2:], uint16(s))
+	}
+	return writeWEM(
+		WEMFormatTagExtensible, channels, sampleRate,
+		sampleRate * channels * 2, channels * 2, 16, 16, data,
+	)
+}
+
+type imaState struct {
+	predictor int
+	index     int
+}
+
+func (s *imaState) encode(sample int) byte {
+	step := imaStepTable[s.index]
+	diff := sample - s.predictor
+	var nibble byte = 0
+	if diff < 0 {
+		nibble = 8
+		diff = -diff
+	}
+	delta := step >> 3
+	if diff >= step {
+		nibble |= 4
+		diff -= step
+		delta += step
+	}
+	step >>= 1
+	if diff >= step {
+		nibble |= 2
+		diff -= step
+		delta += step
+	}
+	step >>= 1
+	if diff >= step {
+		nibble |= 1
+		delta += step
+	}
+	if nibble & 8 != 0 {
+		s.predictor -= delta
+	} else {
+		s.predictor += delta
+	}
+	s.predictor = min(max(s.predictor, -32768), 32767)
+	s.index = min(max(s.index + imaIndexTable[nibble & 7], 0), 88)
+	return nibble
+}
+
+func (s *imaState) decode(nibble byte) int16 {
+	step := imaStepTable[s.index]
+	delta := step >> 3
+	if nibble & 1 != 0 {
+		delta += step >> 2
+	}
+	if nibble & 2 != 0 {
+		delta += step >> 1
+	}
+	if nibble & 4 != 0 {
+		delta += step
+	}
+	if nibble & 8 != 0 {
+		s.predictor -= delta
+	} else {
+		s.predictor += delta
+	}
+	s.predictor = min(max(s.predictor, -32768), 32767)
+	s.index = min(max(s.index + imaIndexTable[nibble & 7], 0), 88)
+	return int16(s.predictor)
+}
+
+// Encode interleaved 16 bit samples into a Wwise IMA ADPCM WEM. Each frame
+// holds one 0x24 bytes block per channel, stored one after another. A block
+// starts with a 4 bytes header (first sample, step index, reserved), followed
+// by 32 bytes of nibbles (low nibble first). The last frame is padded with
+// silence.
+func EncodeADPCMWEM(samples []int16, channels int, sampleRate int) []byte {
+	frames := len(samples) / channels
+	blocks := (frames + WEMADPCMBlockSamples - 1) / WEMADPCMBlockSamples
+	blockAlign := WEMADPCMBlockSize * channels
+	data := make([]byte, blocks * blockAlign)
+
+	sample := func(frame int, c int) int {
+		if frame >= frames {
+			return 0
+		}
+		return int(samples[frame * channels + c])
+	}
+
+	states := make([]imaState, channels)
+	for b := range blocks {
+		first := b * WEMADPCMBlockSamples
+		for c := range channels {
+			block := data[b * blockAlign + c * WEMADPCMBlockSize:][:WEMADPCMBlockSize]
+			s := &states[c]
+			s.predictor = sample(first, c)
+			binary.LittleEndian.PutUint16(block, uint16(int16(s.predictor)))
+			block[2] = byte(s.index)
+			block[3] = 0
+
+			nibbles := block[4:]
+			for i := 1; i < WEMADPCMBlockSamples; i++ {
+				nibble := s.encode(sample(first + i, c))
+				if (i - 1) & 1 == 0 {
+					nibbles[(i - 1) / 2] = nibble
+				} else {
+					nibbles[(i - 1) / 2] |= nibble << 4
+				}
+			}
+		}
+	}
+
+	avgBytes := sampleRate * blockAlign / WEMADPCMBlockSamples
+	return writeWEM(
+		WEMFormatTagADPCM, channels, sampleRate,
+		avgBytes, blockAlign, 4, WEMADPCMBlockSamples, data,
+	)
+}
+
+// Decode the data chunk of a Wwise IMA ADPCM WEM into interleaved 16 bit
+// samples.
+func DecodeADPCMWEMData(data []byte, channels int) []int16 {
+	blockAlign := WEMADPCMBlockSize * channels
+	blocks := len(data) / blockAlign
+	samples := make([]int16, blocks * WEMADPCMBlockSamples * channels)
+	for b := range blocks {
+		first := b * WEMADPCMBlockSamples
+		for c := range channels {
+			block := data[b * blockAlign + c * WEMADPCMBlockSize:][:WEMADPCMBlockSize]
+			s := imaState{
+				predictor: int(int16(binary.LittleEndian.Uint16(block))),
+				index: min(int(block[2]), 88),
+			}
+			samples[first * channels + c] = int16(s.predictor)
+			nibbles := block[4:]
+			for i := 1; i < WEMADPCMBlockSamples; i++ {
+				nibble := nibbles[(i - 1) / 2]
+				if (i - 1) & 1 == 1 {
+					nibble >>= 4
+				}
+				samples[(first + i) * channels + c] = s.decode(nibble & 0xF)
+			}
+		}
+	}
+	return samples
+}
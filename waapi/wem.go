@@ -0,0 +1,84 @@
+package waapi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+// WAVEFORMATEX format tags used by Wwise encoded media
+const (
+	WEMFormatTagPCM        = 0x0001
+	WEMFormatTagADPCM      = 0x0002
+	WEMFormatTagExtensible = 0xFFFE // PCM
+	WEMFormatTagVorbis     = 0xFFFF
+	WEMFormatTagOpus       = 0x3041
+)
+
+func (f ConversionFormatType) PluginID() uint32 {
+	switch f {
+	case ConversionFormatTypePCM:
+		return wwise.PCM
+	case ConversionFormatTypeADPCM:
+		return wwise.ADPCM
+	case ConversionFormatTypeVORBIS:
+		return wwise.VORBIS
+	case ConversionFormatTypeWEMOpus:
+		return wwise.WEM_OPUS
+	default:
+		panic(fmt.Sprintf("Unsupported conversion format %d", f))
+	}
+}
+
+// Validate the RIFF header of a WEM file and return the codec plugin ID
+// (see BankSourceData.PluginID) that matches its format tag.
+func WEMPluginID(wem []byte) (uint32, error) {
+	if len(wem) < 12 {
+		return 0, fmt.Errorf("WEM data is too short (%d bytes) to contain a RIFF header", len(wem))
+	}
+	var order binary.ByteOrder
+	switch {
+	case bytes.Equal(wem[0:4], []byte("RIFF")):
+		order = binary.LittleEndian
+	case bytes.Equal(wem[0:4], []byte("RIFX")):
+		order = binary.BigEndian
+	default:
+		return 0, fmt.Errorf("WEM data does not start with RIFF / RIFX")
+	}
+	if !bytes.Equal(wem[8:12], []byte("WAVE")) {
+		return 0, fmt.Errorf("WEM data is not in WAVE form")
+	}
+	riffSize := order.Uint32(wem[4:8])
+	if uint64(riffSize) + 8 > uint64(len(wem)) {
+		return 0, fmt.Errorf("RIFF size %d exceeds WEM data size %d", riffSize, len(wem))
+	}
+
+	offset := 12
+	for offset + 8 <= len(wem) {
+		id := wem[offset:offset + 4]
+		size := int(order.Uint32(wem[offset + 4:offset + 8]))
+		offset += 8
+		if !bytes.Equal(id, []byte("fmt ")) {
+			offset += size + size & 1
+			continue
+		}
+		if size < 2 || offset + 2 > len(wem) {
+			return 0, fmt.Errorf("WEM fmt chunk is truncated")
+		}
+		switch tag := order.Uint16(wem[offset:offset + 2]); tag {
+		case WEMFormatTagPCM, WEMFormatTagExtensible:
+			return wwise.PCM, nil
+		case WEMFormatTagADPCM:
+			return wwise.ADPCM, nil
+		case WEMFormatTagVorbis:
+			return wwise.VORBIS, nil
+		case WEMFormatTagOpus:
+			return wwise.WEM_OPUS, nil
+		default:
+			return 0, fmt.Errorf("Unsupported WEM format tag 0x%04X", tag)
+		}
+	}
+	return 0, fmt.Errorf("WEM data does not have fmt chunk")
+}
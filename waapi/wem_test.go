@@ -0,0 +1,48 @@
+package waapi
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/Dekr0/wwise-teller/wwise"
+)
+
+func wemHeader(tag uint16) []byte {
+	b := []byte("RIFF\x00\x00\x00\x00WAVE")
+	// A chunk before fmt to make sure chunks are walked
+	b = append(b, []byte("JUNK\x03\x00\x00\x00\x00\x00\x00\x00")...)
+	b = append(b, []byte("fmt \x10\x00\x00\x00")...)
+	fmt := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmt, tag)
+	b = append(b, fmt...)
+	binary.LittleEndian.PutUint32(b[4:8], uint32(len(b) - 8))
+	return b
+}
+
+func TestWEMPluginID(t *testing.T) {
+	for tag, expect := range map[uint16]uint32{
+		WEMFormatTagExtensible: wwise.PCM,
+		WEMFormatTagADPCM: wwise.ADPCM,
+		WEMFormatTagVorbis: wwise.VORBIS,
+		WEMFormatTagOpus: wwise.WEM_OPUS,
+	} {
+		pluginID, err := WEMPluginID(wemHeader(tag))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pluginID != expect {
+			t.Fatalf("Expect plugin ID 0x%08X for format tag 0x%04X, got 0x%08X", expect, tag, pluginID)
+		}
+	}
+
+	if _, err := WEMPluginID(wemHeader(0x0166)); err == nil {
+		t.Fatal("Expect error on unsupported format tag")
+	}
+	if _, err := WEMPluginID([]byte("OggS\x00\x00\x00\x00WAVE")); err == nil {
+		t.Fatal("Expect error on non RIFF data")
+	}
+	truncated := wemHeader(WEMFormatTagVorbis)
+	if _, err := WEMPluginID(truncated[:len(truncated) - 8]); err == nil {
+		t.Fatal("Expect error on truncated data")
+	}
+}
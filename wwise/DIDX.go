@@ -74,6 +74,7 @@ func (d *DIDX) GuessAlignment() {
 func (d *DIDX) Append(sid uint32, size uint32) error {
 	if len(d.MediaIndexs) == 0 {
 		d.MediaIndexs = append(d.MediaIndexs, MediaIndex{sid, 0, size})
+		d.MediaIndexsMap[sid] = &d.MediaIndexs[0]
 		return nil
 	}
 	last := d.MediaIndexs[len(d.MediaIndexs) - 1]
@@ -3,6 +3,7 @@
 package wwise
 
 import (
+	"fmt"
 	"slices"
 
 	"github.com/Dekr0/wwise-teller/wio"
@@ -36,6 +37,14 @@ var InterpCurveTypeName []string = []string{
   	"Constant",
 }
 
+const (
+	RTPCTypeGameParameter uint8 = 0
+	RTPCTypeMIDIParameter uint8 = 1
+	RTPCTypeSwitch        uint8 = 2
+	RTPCTypeState         uint8 = 3
+	RTPCTypeModulator     uint8 = 4
+)
+
 // Target toward v144 but HD2 is using 141???
 const RTPCTypeCount = 5
 var RTPCTypeName []string = []string{
@@ -240,6 +249,20 @@ func (r *RTPC) Clone() RTPC {
 	return cr
 }
 
+// Append a RTPC item. A parameter can only be driven once by the same RTPC.
+func (r *RTPC) AddRTPCItem(item RTPCItem) error {
+	if slices.ContainsFunc(r.RTPCItems, func(i RTPCItem) bool {
+		return i.RTPCID == item.RTPCID && i.RTPCType == item.RTPCType && i.ParamID.Value == item.ParamID.Value
+	}) {
+		return fmt.Errorf(
+			"%s is already driven by %s %d",
+			RTPCParameterIDName[item.ParamID.Value], RTPCTypeName[item.RTPCType], item.RTPCID,
+		)
+	}
+	r.RTPCItems = append(r.RTPCItems, item)
+	return nil
+}
+
 func (r *RTPC) RemoveRTPCItem(i int) {
 	r.RTPCItems = slices.Delete(r.RTPCItems, i, i + 1)
 }
@@ -311,6 +334,173 @@ func (r *RTPCItem) Size(int) uint32 {
 	return uint32(4 + 1 + 1 + len(r.ParamID.Bytes) + 4 + 1 + 2 + len(r.RTPCGraphPointsX) * SizeOfRTPCGraphPoint)
 }
 
+// Create a RTPC item with default accumulation and scaling of the parameter,
+// and a flat curve at 0 over game parameter range 0 to 100
+func NewRTPCItem(rtpcID uint32, rtpcType uint8, curveID uint32, p RTPCParameterType) (RTPCItem, error) {
+	if rtpcType >= RTPCTypeCount {
+		return RTPCItem{}, fmt.Errorf("Invalid RTPC type %d", rtpcType)
+	}
+	if p >= RTPCParameterTypeCount {
+		return RTPCItem{}, fmt.Errorf("Invalid RTPC parameter ID %d", p)
+	}
+	item := RTPCItem{
+		RTPCID: rtpcID,
+		RTPCType: rtpcType,
+		RTPCAccum: DefaultRTPCAccum(p),
+		RTPCCurveID: curveID,
+		Scaling: DefaultCurveScaling(p),
+		RTPCGraphPointsX: []float32{0, 100},
+		RTPCGraphPointsY: []float32{0, 0},
+		RTPCGraphPointsInterp: []uint32{uint32(InterpCurveTypeLinear), uint32(InterpCurveTypeLinear)},
+	}
+	if err := item.ParamID.Set(uint64(p)); err != nil {
+		return RTPCItem{}, err
+	}
+	return item, nil
+}
+
+// Create a RTPC item that maps the output of a modulator (0 to 1) linearly
+// onto a parameter from min to max
+func NewModulatorRTPCItem(modulatorID uint32, curveID uint32, p RTPCParameterType, min float32, max float32) (RTPCItem, error) {
+	item, err := NewRTPCItem(modulatorID, RTPCTypeModulator, curveID, p)
+	if err != nil {
+		return RTPCItem{}, err
+	}
+	item.RTPCGraphPointsX = []float32{0, 1}
+	item.RTPCGraphPointsY = []float32{min, max}
+	return item, nil
+}
+
+// Replace the curve. X values must be in ascending order. Interpolation of
+// each point defaults to linear if interps is nil.
+func (r *RTPCItem) SetCurve(xs []float32, ys []float32, interps []uint32) error {
+	if len(xs) < 2 {
+		return fmt.Errorf("RTPC curve needs at least 2 points")
+	}
+	if len(xs) != len(ys) {
+		return fmt.Errorf("RTPC curve has %d X values but %d Y values", len(xs), len(ys))
+	}
+	if interps == nil {
+		interps = make([]uint32, len(xs))
+		for i := range interps {
+			interps[i] = uint32(InterpCurveTypeLinear)
+		}
+	}
+	if len(interps) != len(xs) {
+		return fmt.Errorf("RTPC curve has %d points but %d interpolations", len(xs), len(interps))
+	}
+	for i := range xs {
+		if i > 0 && xs[i] <= xs[i - 1] {
+			return fmt.Errorf("X value %f of point %d is not greater than X value of the previous point", xs[i], i)
+		}
+		if interps[i] >= uint32(InterpCurveTypeCount) {
+			return fmt.Errorf("Invalid curve interpolation %d of point %d", interps[i], i)
+		}
+	}
+	r.RTPCGraphPointsX = slices.Clone(xs)
+	r.RTPCGraphPointsY = slices.Clone(ys)
+	r.RTPCGraphPointsInterp = slices.Clone(interps)
+	return nil
+}
+
+// RTPC parameters only available on buses
+var BusRTPCParameterTypes []RTPCParameterType = []RTPCParameterType{
+	RTPCParameterTypeBusVolume,
+	RTPCParameterTypeHDRBusThreshold,
+	RTPCParameterTypeHDRBusReleaseTime,
+	RTPCParameterTypeHDRBusRatio,
+}
+
+// RTPC parameters only available on actor-mixer and interactive music
+// hierarchy objects
+var HircRTPCParameterTypes []RTPCParameterType = []RTPCParameterType{
+	RTPCParameterTypeInitialDelay,
+	RTPCParameterTypeMidiTransposition,
+	RTPCParameterTypeMidiVelocityOffset,
+	RTPCParameterTypePlaybackSpeed,
+	RTPCParameterTypePlayMechanismSpecialTransitionsValue,
+	RTPCParameterTypeHDRActiveRange,
+}
+
+// RTPC parameters available on attenuations
+var AttenuationRTPCParameterTypes []RTPCParameterType = []RTPCParameterType{
+	RTPCParameterTypePositioningConeAttenuationONOFF,
+	RTPCParameterTypePositioningConeAttenuation,
+	RTPCParameterTypePositioningConeLPF,
+	RTPCParameterTypePositioningConeHPF,
+}
+
+// Check whether a RTPC parameter can be driven on this kind of object in this
+// bank version
+func CheckRTPCParameter(o HircObj, p RTPCParameterType, v int) error {
+	if p >= RTPCParameterTypeCount {
+		return fmt.Errorf("Invalid RTPC parameter ID %d", p)
+	}
+	name := RTPCParameterIDName[p]
+	if v >= 140 && (p == RTPCParameterTypeDeprecatedFeedbackVolume  ||
+		            p == RTPCParameterTypeDeprecatedFeedbackLowpass ||
+		            p == RTPCParameterTypeDeprecatedFeedbackPitch) {
+		return fmt.Errorf("%s is deprecated in bank version %d", name, v)
+	}
+	t := o.HircType()
+	switch t {
+	case HircTypeAttenuation:
+		if !slices.Contains(AttenuationRTPCParameterTypes, p) {
+			return fmt.Errorf("%s is not available on %s", name, HircTypeName[t])
+		}
+		return nil
+	case HircTypeBus, HircTypeAuxBus:
+		if slices.Contains(HircRTPCParameterTypes, p) {
+			return fmt.Errorf("%s is not available on %s", name, HircTypeName[t])
+		}
+		return nil
+	}
+	if o.BaseParameter() == nil {
+		return fmt.Errorf("%s does not have RTPC", HircTypeName[t])
+	}
+	if slices.Contains(BusRTPCParameterTypes, p) {
+		return fmt.Errorf("%s is only available on buses", name)
+	}
+	if p == RTPCParameterTypePlaybackSpeed && !MusicHircType(o) {
+		return fmt.Errorf("%s is only available on interactive music hierarchy objects", name)
+	}
+	return nil
+}
+
+// Accumulation Wwise uses when a parameter is driven by multiple RTPCs
+func DefaultRTPCAccum(p RTPCParameterType) RTPCAccumType {
+	switch p {
+	case RTPCParameterTypeBypassFX0,
+	     RTPCParameterTypeBypassFX1,
+	     RTPCParameterTypeBypassFX2,
+	     RTPCParameterTypeBypassFX3,
+	     RTPCParameterTypeBypassAllFX,
+	     RTPCParameterTypePositioningConeAttenuationONOFF,
+	     RTPCParameterTypePositioningEnableAttenuation,
+	     RTPCParameterTypeBypassAllMetadata:
+		return RTPCAccumTypeBoolean
+	}
+	return RTPCAccumTypeAdditive
+}
+
+// Volume curves are authored in dB
+func DefaultCurveScaling(p RTPCParameterType) CurveScalingType {
+	switch p {
+	case RTPCParameterTypeVolume,
+	     RTPCParameterTypeBusVolume,
+	     RTPCParameterTypeMakeUpGain,
+	     RTPCParameterTypeGameAuxSendVolume,
+	     RTPCParameterTypeUserAuxSendVolume0,
+	     RTPCParameterTypeUserAuxSendVolume1,
+	     RTPCParameterTypeUserAuxSendVolume2,
+	     RTPCParameterTypeUserAuxSendVolume3,
+	     RTPCParameterTypeOutputBusVolume,
+	     RTPCParameterTypeReflectionsVolume:
+		return CurveScalingTypeDb
+	}
+	return CurveScalingTypeNone
+}
+
 const RTPCInterpSampleRate = 64
 const SizeOfRTPCGraphPoint = 12
 type RTPCGraphPoint struct {
@@ -0,0 +1,83 @@
+package wwise
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAddRTPC(t *testing.T) {
+	const v = 141
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	s := &Sound{Id: 10, BaseParam: &BaseParameter{}}
+	bus := &Bus{Id: 20}
+	a := &Attenuation{Id: 30}
+	h.HircObjs = []HircObj{a, s, bus}
+	h.ActorMixerHirc.Store(s.Id, s)
+	h.Buses.Store(bus.Id, bus)
+	h.Attenuations.Store(a.Id, a)
+
+	rpm := ShortID("RPM")
+	newItem := func(p RTPCParameterType) RTPCItem {
+		item, err := NewRTPCItem(rpm, RTPCTypeGameParameter, uint32(p) + 1000, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return item
+	}
+
+	item := newItem(RTPCParameterTypeVolume)
+	if err := item.SetCurve([]float32{0, 50, 100}, []float32{-12, -3, 0}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := item.SetCurve([]float32{0, 100, 50}, []float32{0, 0, 0}, nil); err == nil {
+		t.Fatal("Expecting error on unsorted curve")
+	}
+	if err := item.SetCurve([]float32{0}, []float32{0}, nil); err == nil {
+		t.Fatal("Expecting error on a curve with a single point")
+	}
+	if len(item.RTPCGraphPointsX) != 3 || item.Scaling != CurveScalingTypeDb {
+		t.Fatalf("Unexpected RTPC item %+v", item)
+	}
+	if err := h.AddRTPC(10, item, v); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.AddRTPC(10, item, v); err == nil {
+		t.Fatal("Expecting error on adding the same RTPC twice")
+	}
+
+	for _, c := range []struct {
+		id uint32
+		p  RTPCParameterType
+		ok bool
+	}{
+		{10, RTPCParameterTypePitch, true},
+		{10, RTPCParameterTypeBusVolume, false},
+		{10, RTPCParameterTypePlaybackSpeed, false},
+		{10, RTPCParameterTypeDeprecatedFeedbackVolume, false},
+		{20, RTPCParameterTypeHDRBusThreshold, true},
+		{20, RTPCParameterTypeInitialDelay, false},
+		{30, RTPCParameterTypePositioningConeLPF, true},
+		{30, RTPCParameterTypeVolume, false},
+	} {
+		err := h.AddRTPC(c.id, newItem(c.p), v)
+		if c.ok && err != nil {
+			t.Fatalf("Expecting %s is available on %d: %v", RTPCParameterIDName[c.p], c.id, err)
+		}
+		if !c.ok && err == nil {
+			t.Fatalf("Expecting %s is not available on %d", RTPCParameterIDName[c.p], c.id)
+		}
+	}
+	if _, err := NewRTPCItem(rpm, RTPCTypeCount, 0, RTPCParameterTypeVolume); err == nil {
+		t.Fatal("Expecting error on invalid RTPC type")
+	}
+	if err := h.AddRTPC(40, newItem(RTPCParameterTypeVolume), v); err == nil {
+		t.Fatal("Expecting error on missing object")
+	}
+
+	if ids := h.GameParameterIDs(v); !slices.Equal(ids, []uint32{rpm}) {
+		t.Fatalf("Expecting game parameter %d, got %v", rpm, ids)
+	}
+	if len(s.BaseParam.RTPC.RTPCItems) != 2 || len(bus.BusRTPC.RTPCItems) != 1 || len(a.RTPC.RTPCItems) != 1 {
+		t.Fatal("Unexpected number of RTPC items")
+	}
+}
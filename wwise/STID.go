@@ -3,6 +3,7 @@ package wwise
 import (
 	"context"
 	"encoding/binary"
+	"fmt"
 
 	"github.com/Dekr0/wwise-teller/wio"
 )
@@ -17,6 +18,35 @@ func NewSTID(I uint8, T []byte, b []byte) *STID {
 	return &STID{I, T, b}
 }
 
+type STIDEntry struct {
+	BankID uint32
+	Name   string
+}
+
+// Decode the sound bank ID and name pairs stored in STID.
+func (s *STID) Entries() ([]STIDEntry, error) {
+	if len(s.B) < 8 {
+		return nil, fmt.Errorf("STID is too short (%d bytes)", len(s.B))
+	}
+	numStrings := wio.ByteOrder.Uint32(s.B[4:])
+	entries := make([]STIDEntry, 0, numStrings)
+	i := 8
+	for range numStrings {
+		if i + 5 > len(s.B) {
+			return nil, fmt.Errorf("STID entry %d is truncated", len(entries))
+		}
+		id := wio.ByteOrder.Uint32(s.B[i:])
+		l := int(s.B[i + 4])
+		i += 5
+		if i + l > len(s.B) {
+			return nil, fmt.Errorf("STID entry %d is truncated", len(entries))
+		}
+		entries = append(entries, STIDEntry{id, string(s.B[i:i + l])})
+		i += l
+	}
+	return entries, nil
+}
+
 func (s *STID) Encode(ctx context.Context, v int) ([]byte, error) {
 	encoded := s.T 
 	encoded, err := binary.Append(encoded, wio.ByteOrder, uint32(len(s.B)))
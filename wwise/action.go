@@ -3,6 +3,8 @@ package wwise
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
+	"maps"
 	"slices"
 
 	"github.com/Dekr0/wwise-teller/wio"
@@ -58,6 +60,21 @@ var ActionTypeName map[uint16]string = map[uint16]string{
 	0x37: "Bypass FX",
 }
 
+// Action type names indexed by action type code in a given version. Codes
+// without an action type have an empty name. Since version 150, 0x1A and 0x1B
+// are Break and Trigger instead of Bypass FX.
+func ActionTypeNames(v int) []string {
+	names := make([]string, slices.Max(slices.Collect(maps.Keys(ActionTypeName))) + 1)
+	for t, name := range ActionTypeName {
+		names[t] = name
+	}
+	if v >= 150 {
+		names[0x1A] = "Break"
+		names[0x1B] = "Trigger"
+	}
+	return names
+}
+
 const (
 	TypeActionNoParam        ActionParamType = 0
 	TypeActionActiveParam    ActionParamType = 1
@@ -172,6 +189,135 @@ func (a *Action) IsBus() bool {
 	return wio.GetBit(a.IdExt4, 0)
 }
 
+// Scope (low byte of the action type) of an action created from scratch. Set
+// State is global, Set Switch is applied on a game object, and the rest target
+// the element on the game object posting the event.
+func DefaultActionScope(t uint16) uint8 {
+	switch t {
+	case 0x12:
+		return 0x04
+	case 0x19:
+		return 0x01
+	}
+	return 0x03
+}
+
+// Create an action of a given action type (high byte of the action type, e.g.
+// 0x04 for Play) with default parameters. The bank ID of a play action is left
+// to the caller.
+func NewAction(id uint32, t uint16, target uint32, v int) (*Action, error) {
+	p, err := NewActionParam(t, v)
+	if err != nil {
+		return nil, err
+	}
+	return &Action{
+		Id: id,
+		ActionType: ActionType((t << 8) | uint16(DefaultActionScope(t))),
+		IdExt: target,
+		PropBundle: PropBundle{PropValues: []PropValue{}},
+		RangePropBundle: RangePropBundle{RangeValues: []RangeValue{}},
+		ActionParam: p,
+	}, nil
+}
+
+// Default action parameter and action specific parameter of an action type.
+// It follows the same dispatch as the action parser.
+func NewActionParam(t uint16, v int) (ActionParam, error) {
+	var e wio.Var
+	e.Set(0)
+	// Action type code already tells which property is set. Set actions use
+	// the value as is. Reset actions go back to the default value.
+	meaning := ValueMeaningIndependent
+	switch t {
+	case 0x09, 0x0B, 0x0D, 0x0F, 0x14, 0x30:
+		meaning = ValueMeaningDefault
+	}
+	setProp := func() ActionParam {
+		return &ActionSetValueParam{
+			EnumFadeCurve: InterpCurveTypeLinear,
+			AkSpecificParam: &ActionSetPropSpecificParam{EnumValueMeaning: meaning},
+			ExceptionListSize: e,
+			ExceptParams: []ExceptParam{},
+		}
+	}
+	switch t {
+	case 0x01, 0x02, 0x03, 0x22:
+		var s ActionSpecificParam
+		switch t {
+		case 0x01:
+			s = &ActionStopSpecificParam{}
+		case 0x02:
+			s = &ActionPauseSpecificParam{}
+		case 0x03:
+			s = &ActionResumeSpecificParam{}
+		default:
+			s = &ActionResetPlayListSpecificParam{}
+		}
+		return &ActionActiveParam{
+			EnumFadeCurve: InterpCurveTypeLinear,
+			AkSpecificParam: s,
+			ExceptionListSize: e,
+			ExceptParams: []ExceptParam{},
+		}, nil
+	case 0x04, 0x05, 0x23:
+		return &ActionPlayParam{EnumFadeCurve: InterpCurveTypeLinear}, nil
+	case 0x06, 0x07:
+		return &ActionSetValueParam{
+			EnumFadeCurve: InterpCurveTypeLinear,
+			AkSpecificParam: &ActionNoSpecificParam{},
+			ExceptionListSize: e,
+			ExceptParams: []ExceptParam{},
+		}, nil
+	case 0x08, 0x09:
+		return setProp(), nil
+	case 0x0A, 0x0B:
+		return setProp(), nil
+	case 0x0C, 0x0D:
+		return setProp(), nil
+	case 0x0E, 0x0F:
+		return setProp(), nil
+	case 0x20, 0x30:
+		return setProp(), nil
+	case 0x10, 0x11, 0x15, 0x16, 0x17, 0x1C, 0x1D:
+		return &ActionNoParam{}, nil
+	case 0x12:
+		return &ActionSetStateParam{}, nil
+	case 0x13, 0x14:
+		return &ActionSetValueParam{
+			EnumFadeCurve: InterpCurveTypeLinear,
+			AkSpecificParam: &ActionSetGameParameterSpecificParam{EnumValueMeaning: meaning},
+			ExceptionListSize: e,
+			ExceptParams: []ExceptParam{},
+		}, nil
+	case 0x19:
+		return &ActionSetSwitchParam{}, nil
+	case 0x1A, 0x1B, 0x33, 0x34, 0x35, 0x36, 0x37:
+		// Break and Trigger since version 150
+		if v >= 150 && (t == 0x1A || t == 0x1B) {
+			return &ActionNoParam{}, nil
+		}
+		return &ActionByPassFXParam{
+			ExceptionListSize: e,
+			ExceptParams: []ExceptParam{},
+		}, nil
+	case 0x1E:
+		return &ActionSeekParam{
+			ExceptionListSize: e,
+			ExceptParams: []ExceptParam{},
+		}, nil
+	case 0x1F:
+		return &ActionReleaseParam{}, nil
+	case 0x21:
+		return &ActionPlayEventParam{}, nil
+	case 0x31, 0x32:
+		return &ActionSetFXParam{
+			ExceptionListSize: e,
+			ExceptParams: []ExceptParam{},
+		}, nil
+	}
+	return nil, fmt.Errorf("Unknown action type %#x", t)
+}
+
 // Action Specific Param
 
 type ActionNoSpecificParam struct {}
@@ -266,11 +412,20 @@ func (p *ActionResumeSpecificParam) ApplyToDynamicSequence() bool {
 	return wio.GetBit(p.BitVector, 2)
 }
 
+// Meaning of the value of Set Prop and Set Game Parameter actions
+const (
+	ValueMeaningDefault     uint8 = 0
+	ValueMeaningIndependent uint8 = 1
+	ValueMeaningOffset      uint8 = 2
+)
+
+var ValueMeaningNames []string = []string{"Default", "Independent", "Offset"}
+
 type ActionSetPropSpecificParam struct {
-	Prop uint8
-	Base float32
-	Min  float32
-	Max  float32
+	EnumValueMeaning uint8
+	Base             float32
+	Min              float32
+	Max              float32
 }
 
 func (p *ActionSetPropSpecificParam) Type() ActionSpecificParamType { 
@@ -284,7 +439,7 @@ func (p *ActionSetPropSpecificParam) Encode(int) []byte {
 
 func (p *ActionSetPropSpecificParam) Clone() ActionSpecificParam {
 	return &ActionSetPropSpecificParam{
-		p.Prop, p.Base, p.Min, p.Max,
+		p.EnumValueMeaning, p.Base, p.Min, p.Max,
 	}
 }
 
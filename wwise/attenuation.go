@@ -1,6 +1,8 @@
 package wwise
 
 import (
+	"fmt"
+	"math"
 	"slices"
 
 	"github.com/Dekr0/wwise-teller/wio"
@@ -66,7 +68,10 @@ func (h *Attenuation) Clone(id uint32, out *Attenuation) {
 	out.LoPass = h.LoPass
 	out.HiPass = h.HiPass
 	out.Curves = slices.Clone(h.Curves)
-	out.AttenuationConversionTables = slices.Clone(h.AttenuationConversionTables)
+	out.AttenuationConversionTables = make([]AttenuationConversionTable, len(h.AttenuationConversionTables))
+	for i := range h.AttenuationConversionTables {
+		out.AttenuationConversionTables[i] = h.AttenuationConversionTables[i].Clone()
+	}
 	out.RTPC = h.RTPC.Clone()
 }
 
@@ -172,3 +177,228 @@ func (a *AttenuationConversionTable) Encode(v int) []byte {
 	}
 	return w.BytesAssert(int(size))
 }
+
+func (a *AttenuationConversionTable) Clone() AttenuationConversionTable {
+	return AttenuationConversionTable{
+		a.EnumScaling,
+		slices.Clone(a.RTPCGraphPointsX),
+		slices.Clone(a.RTPCGraphPointsY),
+		slices.Clone(a.RTPCGraphPointsInterp),
+	}
+}
+
+// Shape a normalized position t in [0, 1] between two graph points.
+func InterpCurve(interp InterpCurveType, t float64) float64 {
+	switch interp {
+	case InterpCurveTypeLog3:
+		return 1 - math.Pow(1 - t, 3)
+	case InterpCurveTypeSine:
+		return math.Sin(t * math.Pi / 2)
+	case InterpCurveTypeLog1:
+		return 1 - math.Pow(1 - t, 1.41)
+	case InterpCurveTypeInvSCurve:
+		return math.Acos(1 - 2 * t) / math.Pi
+	case InterpCurveTypeSCurve:
+		return 0.5 - 0.5 * math.Cos(math.Pi * t)
+	case InterpCurveTypeExp1:
+		return math.Pow(t, 1.41)
+	case InterpCurveTypInvSine:
+		return 1 - math.Cos(t * math.Pi / 2)
+	case InterpCurveTypeExp3:
+		return math.Pow(t, 3)
+	case InterpCurveTypeConst:
+		return 0
+	default:
+		return t
+	}
+}
+
+// Evaluate the curve at x. Values outside of the graph are clamped to the 
+// first and the last point.
+func (a *AttenuationConversionTable) Evaluate(x float32) float32 {
+	n := len(a.RTPCGraphPointsX)
+	if n == 0 {
+		return 0
+	}
+	if x <= a.RTPCGraphPointsX[0] {
+		return a.RTPCGraphPointsY[0]
+	}
+	if x >= a.RTPCGraphPointsX[n - 1] {
+		return a.RTPCGraphPointsY[n - 1]
+	}
+	i := 0
+	for i < n - 1 && a.RTPCGraphPointsX[i + 1] < x {
+		i += 1
+	}
+	x0, x1 := a.RTPCGraphPointsX[i], a.RTPCGraphPointsX[i + 1]
+	y0, y1 := a.RTPCGraphPointsY[i], a.RTPCGraphPointsY[i + 1]
+	if x1 <= x0 {
+		return y1
+	}
+	t := float64(x - x0) / float64(x1 - x0)
+	t = InterpCurve(InterpCurveType(a.RTPCGraphPointsInterp[i]), t)
+	return y0 + float32(t) * (y1 - y0)
+}
+
+func (a *AttenuationConversionTable) MaxX() float32 {
+	if len(a.RTPCGraphPointsX) == 0 {
+		return 0
+	}
+	return a.RTPCGraphPointsX[len(a.RTPCGraphPointsX) - 1]
+}
+
+func (a *AttenuationConversionTable) ScaleX(factor float32) {
+	for i := range a.RTPCGraphPointsX {
+		a.RTPCGraphPointsX[i] *= factor
+	}
+}
+
+// Return the conversion table used by a distance property (index of Curves).
+// Return nil if the property does not use any curve.
+func (h *Attenuation) Curve(property int) *AttenuationConversionTable {
+	if property < 0 || property >= len(h.Curves) {
+		return nil
+	}
+	idx := int(h.Curves[property])
+	if idx < 0 || idx >= len(h.AttenuationConversionTables) {
+		return nil
+	}
+	return &h.AttenuationConversionTables[idx]
+}
+
+// Evaluate a distance property at a given distance. The second return value
+// is false if the property does not use any curve.
+func (h *Attenuation) Evaluate(property int, distance float32) (float32, bool) {
+	c := h.Curve(property)
+	if c == nil {
+		return 0, false
+	}
+	return c.Evaluate(distance), true
+}
+
+// Largest distance among all curves
+func (h *Attenuation) MaxDistance() float32 {
+	var d float32 = 0
+	for i := range h.AttenuationConversionTables {
+		d = max(d, h.AttenuationConversionTables[i].MaxX())
+	}
+	return d
+}
+
+// Scale distance (X axis) of all curves
+func (h *Attenuation) ScaleMaxDistance(factor float32) error {
+	if factor <= 0 {
+		return fmt.Errorf("Distance scaling factor must be positive")
+	}
+	for i := range h.AttenuationConversionTables {
+		h.AttenuationConversionTables[i].ScaleX(factor)
+	}
+	return nil
+}
+
+func (h *Attenuation) SetMaxDistance(distance float32) error {
+	curr := h.MaxDistance()
+	if curr <= 0 {
+		return fmt.Errorf("Attenuation %d does not have any curve with distance", h.Id)
+	}
+	return h.ScaleMaxDistance(distance / curr)
+}
+
+// Assign a conversion table to a distance property. A new table is appended 
+// if the property does not use any curve or its current table is shared with
+// other properties.
+func (h *Attenuation) SetCurve(property int, t AttenuationConversionTable) error {
+	if property < 0 || property >= len(h.Curves) {
+		return fmt.Errorf("Attenuation %d does not have distance property %d", h.Id, property)
+	}
+	if len(t.RTPCGraphPointsX) != len(t.RTPCGraphPointsY) ||
+	   len(t.RTPCGraphPointsX) != len(t.RTPCGraphPointsInterp) {
+		return fmt.Errorf("Number of X, Y and interpolation of curve points do not match")
+	}
+	if !slices.IsSorted(t.RTPCGraphPointsX) {
+		return fmt.Errorf("Curve points are not sorted by distance")
+	}
+	idx := h.Curves[property]
+	shared := false
+	for i, c := range h.Curves {
+		if i != property && c == idx {
+			shared = true
+			break
+		}
+	}
+	if idx < 0 || shared {
+		if len(h.AttenuationConversionTables) >= math.MaxInt8 {
+			return fmt.Errorf("Attenuation %d has too many curves", h.Id)
+		}
+		h.AttenuationConversionTables = append(h.AttenuationConversionTables, t)
+		h.Curves[property] = int8(len(h.AttenuationConversionTables) - 1)
+		return nil
+	}
+	h.AttenuationConversionTables[idx] = t
+	return nil
+}
+
+type AttenuationPresetType uint8
+
+const (
+	AttenuationPresetLinear        AttenuationPresetType = 0
+	AttenuationPresetInverseSquare AttenuationPresetType = 1
+	AttenuationPresetCustom        AttenuationPresetType = 2
+	AttenuationPresetCount         AttenuationPresetType = 3
+)
+
+var AttenuationPresetName []string = []string{
+	"Linear",
+	"Inverse Square",
+	"Custom",
+}
+
+// Generate a volume (dB) attenuation curve.
+// - Linear: straight line from 0 dB at distance 0 to floor dB at max distance.
+// - Inverse square: -6 dB per doubling of distance starting from reference 
+// distance. Clamped at floor.
+func NewAttenuationPresetCurve(
+	preset AttenuationPresetType, maxDistance float32, reference float32, floor float32,
+) (AttenuationConversionTable, error) {
+	t := AttenuationConversionTable{EnumScaling: uint8(CurveScalingTypeDb)}
+	if maxDistance <= 0 {
+		return t, fmt.Errorf("Max distance must be positive")
+	}
+	if floor >= 0 {
+		return t, fmt.Errorf("Floor volume must be negative")
+	}
+	switch preset {
+	case AttenuationPresetLinear:
+		t.RTPCGraphPointsX = []float32{0, maxDistance}
+		t.RTPCGraphPointsY = []float32{0, floor}
+		t.RTPCGraphPointsInterp = []uint32{
+			uint32(InterpCurveTypeLinear), uint32(InterpCurveTypeLinear),
+		}
+	case AttenuationPresetInverseSquare:
+		if reference <= 0 || reference >= maxDistance {
+			return t, fmt.Errorf("Reference distance must be positive and less than max distance")
+		}
+		t.RTPCGraphPointsX = []float32{0, reference}
+		t.RTPCGraphPointsY = []float32{0, 0}
+		for d := reference * 2; d < maxDistance; d *= 2 {
+			y := float32(-20 * math.Log10(float64(d / reference)))
+			if y <= floor {
+				break
+			}
+			t.RTPCGraphPointsX = append(t.RTPCGraphPointsX, d)
+			t.RTPCGraphPointsY = append(t.RTPCGraphPointsY, y)
+		}
+		t.RTPCGraphPointsX = append(t.RTPCGraphPointsX, maxDistance)
+		t.RTPCGraphPointsY = append(t.RTPCGraphPointsY, max(
+			floor, float32(-20 * math.Log10(float64(maxDistance / reference))),
+		))
+		t.RTPCGraphPointsInterp = make([]uint32, len(t.RTPCGraphPointsX))
+		for i := range t.RTPCGraphPointsInterp {
+			t.RTPCGraphPointsInterp[i] = uint32(InterpCurveTypeLog1)
+		}
+		t.RTPCGraphPointsInterp[0] = uint32(InterpCurveTypeLinear)
+	default:
+		return t, fmt.Errorf("Preset %d does not generate curve. Use custom points instead.", preset)
+	}
+	return t, nil
+}
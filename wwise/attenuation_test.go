@@ -0,0 +1,73 @@
+package wwise
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAttenuationCurve(t *testing.T) {
+	a := Attenuation{
+		Id: 1,
+		Curves: []int8{0, 0, -1, -1, -1, -1, -1},
+		AttenuationConversionTables: []AttenuationConversionTable{
+			{
+				EnumScaling: uint8(CurveScalingTypeDb),
+				RTPCGraphPointsX: []float32{0, 10, 20},
+				RTPCGraphPointsY: []float32{0, -10, -40},
+				RTPCGraphPointsInterp: []uint32{
+					uint32(InterpCurveTypeLinear),
+					uint32(InterpCurveTypeConst),
+					uint32(InterpCurveTypeLinear),
+				},
+			},
+		},
+	}
+	cases := []struct{ d float32; v float32 }{
+		{-1, 0}, {5, -5}, {10, -10}, {15, -10}, {20, -40}, {100, -40},
+	}
+	for _, c := range cases {
+		v, ok := a.Evaluate(0, c.d)
+		if !ok {
+			t.Fatal("Volume curve should exist")
+		}
+		if v != c.v {
+			t.Fatalf("Expected %f at distance %f, got %f", c.v, c.d, v)
+		}
+	}
+	if _, ok := a.Evaluate(3, 5); ok {
+		t.Fatal("LPF curve should not exist")
+	}
+
+	var clone Attenuation
+	a.Clone(2, &clone)
+	if err := clone.SetMaxDistance(40); err != nil {
+		t.Fatal(err)
+	}
+	if clone.MaxDistance() != 40 || a.MaxDistance() != 20 {
+		t.Fatalf("Expected max distance 40 and 20, got %f and %f", clone.MaxDistance(), a.MaxDistance())
+	}
+
+	// Volume and Aux Game share the same table. Replacing one of them should
+	// not affect the other one.
+	curve, err := NewAttenuationPresetCurve(AttenuationPresetInverseSquare, 100, 1, -96)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := clone.SetCurve(0, curve); err != nil {
+		t.Fatal(err)
+	}
+	if clone.Curves[0] == clone.Curves[1] {
+		t.Fatal("Shared curve should be split")
+	}
+	v, _ := clone.Evaluate(0, 2)
+	if math.Abs(float64(v) - -6.0206) > 0.001 {
+		t.Fatalf("Expected -6.02 dB at double reference distance, got %f", v)
+	}
+	v, _ = clone.Evaluate(1, 20)
+	if v != -10 {
+		t.Fatalf("Expected -10 dB, got %f", v)
+	}
+	if clone.Size(141) != uint32(len(clone.Encode(141))) - SizeOfHircObjHeader {
+		t.Fatal("Encoded size does not match calculated size")
+	}
+}
@@ -29,6 +29,24 @@ type AuxBus struct {
 	StateGroup                StateGroup
 }
 
+// Auxiliary bus with default settings under parent bus parentID
+func NewAuxBus(id uint32, parentID uint32) *AuxBus {
+	b := &AuxBus{
+		Id: id,
+		OverrideBusId: parentID,
+		PropBundle: PropBundle{PropValues: []PropValue{}},
+		AuxParam: NewAuxParam(),
+		RecoveryTime: 500,
+		MaxDuckVolume: -96,
+		DuckInfoList: []DuckInfo{},
+		BusFxMetadataParam: BusFxMetadataParam{[]FxChunkMetadataItem{}},
+		BusRTPC: RTPC{RTPCItems: []RTPCItem{}},
+	}
+	b.StateProp.NumStateProps.Set(0)
+	b.StateGroup.NumStateGroups.Set(0)
+	return b
+}
+
 func (h *AuxBus) KillNewest() bool {
 	return wio.GetBit(h.VirtualBehaviorBitVector, 0)
 }
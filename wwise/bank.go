@@ -11,6 +11,7 @@ import (
 	"slices"
 )
 
+var NoBKHD = errors.New("This sound bank does not have BKHD chunk.")
 var NoHIRC = errors.New("This sound bank does not have HIRC chunk.")
 var NoDIDX = errors.New("This sound bank does not have DIDX chunk.")
 var NoDATA = errors.New("This sound bank does not have DATA chunk.")
@@ -94,6 +95,15 @@ func (b *Bank) HIRC() *HIRC {
 	return nil
 }
 
+func (b *Bank) STID() *STID {
+	for _, chunk := range b.Chunks {
+		if bytes.Compare(chunk.Tag(), []byte{'S', 'T', 'I', 'D'}) == 0 {
+			return chunk.(*STID)
+		}
+	}
+	return nil
+}
+
 func (b *Bank) META() *META {
 	for _, chunk := range b.Chunks {
 		if bytes.Compare(chunk.Tag(), []byte{'M', 'E', 'T', 'A'}) == 0 {
@@ -117,6 +117,38 @@ type DuckInfo struct {
 	TargetProp    PropType
 }
 
+// Default ducking between buses in Wwise authoring tool: bus volume of the
+// ducked bus drops by 6 dB, and fades out and in within 1 second
+func NewDuckInfo(busID uint32, v int) DuckInfo {
+	return DuckInfo{
+		BusID: busID,
+		DuckVolume: -6,
+		FadeOutTime: 1000,
+		FadeInTime: 1000,
+		EnumFadeCurve: InterpCurveTypeLinear,
+		TargetProp: PropType(ForwardTranslateProp(TBusVolume, v)),
+	}
+}
+
+// Bus with default settings under parent bus parentID
+func NewBus(id uint32, parentID uint32) *Bus {
+	b := &Bus{
+		Id: id,
+		OverrideBusId: parentID,
+		PropBundle: PropBundle{PropValues: []PropValue{}},
+		AuxParam: NewAuxParam(),
+		CanSetHDR: -1,
+		RecoveryTime: 500,
+		MaxDuckVolume: -96,
+		DuckInfoList: []DuckInfo{},
+		BusFxMetadataParam: BusFxMetadataParam{[]FxChunkMetadataItem{}},
+		BusRTPC: RTPC{RTPCItems: []RTPCItem{}},
+	}
+	b.StateProp.NumStateProps.Set(0)
+	b.StateGroup.NumStateGroups.Set(0)
+	return b
+}
+
 func (h *Bus) KillNewest() bool {
 	return wio.GetBit(h.VirtualBehaviorBitVector, 0)
 }
@@ -0,0 +1,99 @@
+package wwise
+
+import "testing"
+
+func TestBusAuthoring(t *testing.T) {
+	const v = 141
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	master := NewBus(1, 0)
+	s := &Sound{Id: 10, BaseParam: &BaseParameter{DirectParentId: 5}}
+	h.HircObjs = []HircObj{master, s}
+	h.Buses.Store(master.Id, master)
+	h.ActorMixerHirc.Store(s.Id, s)
+	h.BuildTree()
+	h.HDRAvailability()
+
+	weapons := NewBus(ShortID("Weapons"), 1)
+	weapons.SetHDRBus(true)
+	reverb := NewAuxBus(ShortID("Weapons Reverb"), weapons.Id)
+	music := NewBus(ShortID("Music"), 1)
+	for _, o := range []HircObj{weapons, reverb, music} {
+		if err := h.AppendNewBus(o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.AppendNewBus(NewBus(100, 99)); err == nil {
+		t.Fatal("Expecting error on missing parent bus")
+	}
+	if err := h.AppendNewBus(NewBus(music.Id, 1)); err == nil {
+		t.Fatal("Expecting error on duplicate bus")
+	}
+	if _, ok := h.HircObjs[3].(*Bus); !ok || len(h.BusRoots) != 1 || len(h.BusHircNodesMap[1].Leafs) != 2 {
+		t.Fatal("Expecting new buses are placed after existing buses and under master bus")
+	}
+	master.Encode(v)
+	weapons.Encode(v)
+	reverb.Encode(v)
+
+	if music.CanSetHDR != 1 {
+		t.Fatal("Expecting HDR is available in music bus")
+	}
+	if err := h.SetHDRBus(1, true); err == nil {
+		t.Fatal("Expecting error on HDR master bus above a HDR bus")
+	}
+	if err := h.SetBusParent(music.Id, reverb.Id); err != nil {
+		t.Fatal(err)
+	}
+	if music.CanSetHDR != 0 {
+		t.Fatal("Expecting HDR is not available under a HDR bus")
+	}
+	if err := h.SetBusParent(weapons.Id, music.Id); err == nil {
+		t.Fatal("Expecting error on moving a bus under its descendant")
+	}
+	if err := h.SetHDRBus(weapons.Id, false); err != nil {
+		t.Fatal(err)
+	}
+	if music.CanSetHDR != 1 {
+		t.Fatal("Expecting HDR is available after disabling HDR in parent bus")
+	}
+
+	if err := h.SetOutputBus(10, weapons.Id); err != nil || s.BaseParam.OverrideBusId != weapons.Id {
+		t.Fatalf("Expecting sound is routed to weapons bus: %v", err)
+	}
+	if err := h.SetOutputBus(10, 10); err == nil {
+		t.Fatal("Expecting error on routing to a non bus object")
+	}
+	if err := h.SetAuxSend(10, 1, reverb.Id, -3, v); err != nil {
+		t.Fatal(err)
+	}
+	a := &s.BaseParam.AuxParam
+	if !a.HasAux() || !a.OverrideAuxSends() || a.AuxIds[1] != reverb.Id {
+		t.Fatalf("Unexpected auxiliary param %+v", a)
+	}
+	if i, _ := s.BaseParam.PropBundle.Prop(TUserAuxSendVolume1, v); i == -1 {
+		t.Fatal("Expecting user-defined auxiliary send volume")
+	}
+	if err := h.SetAuxSend(10, 4, reverb.Id, 0, v); err == nil {
+		t.Fatal("Expecting error on out of range slot")
+	}
+	if err := h.SetAuxSend(10, 0, music.Id, 0, v); err == nil {
+		t.Fatal("Expecting error on sending to a non auxiliary bus")
+	}
+
+	if err := h.AddDuck(weapons.Id, NewDuckInfo(music.Id, v)); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.AddDuck(weapons.Id, NewDuckInfo(music.Id, v)); err == nil {
+		t.Fatal("Expecting error on ducking the same bus twice")
+	}
+	if err := h.AddDuck(weapons.Id, NewDuckInfo(weapons.Id, v)); err == nil {
+		t.Fatal("Expecting error on ducking itself")
+	}
+	if len(h.BuildXRef(v).ReferencesOf(music.Id, RefTypeDuckBus)) != 1 {
+		t.Fatal("Expecting ducked bus is referenced")
+	}
+	weapons.Encode(v)
+	if err := h.RemoveDuck(weapons.Id, music.Id); err != nil || len(weapons.DuckInfoList) != 0 {
+		t.Fatalf("Expecting duck is removed: %v", err)
+	}
+}
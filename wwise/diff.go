@@ -0,0 +1,268 @@
+package wwise
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/wio"
+)
+
+type ChangeKind uint8
+
+const (
+	ChangeCreated  ChangeKind = 0
+	ChangeRemoved  ChangeKind = 1
+	ChangeModified ChangeKind = 2
+)
+
+var ChangeKindName []string = []string{
+	"created",
+	"removed",
+	"modified",
+}
+
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(ChangeKindName[k]), nil
+}
+
+// Old or New is empty if the property is absent on that side
+type PropChange struct {
+	Prop string `json:"prop"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+type ObjectChange struct {
+	Kind       ChangeKind   `json:"kind"`
+	ID         uint32       `json:"id"`
+	// Known name of the ID. Empty if there's none.
+	Name       string       `json:"name,omitempty"`
+	Type       string       `json:"type"`
+	Props    []PropChange   `json:"props,omitempty"`
+	// Modified in a way that is not a property change (e.g., children,
+	// play list, RTPC curves)
+	Structural bool         `json:"structural,omitempty"`
+}
+
+type MediaChange struct {
+	Kind    ChangeKind `json:"kind"`
+	SID     uint32     `json:"sid"`
+	Name    string     `json:"name,omitempty"`
+	OldSize uint32     `json:"oldSize"`
+	NewSize uint32     `json:"newSize"`
+}
+
+type BankDiff struct {
+	Objects []ObjectChange `json:"objects"`
+	Media   []MediaChange  `json:"media"`
+}
+
+func (d *BankDiff) Empty() bool {
+	return len(d.Objects) == 0 && len(d.Media) == 0
+}
+
+// Compare two revisions of the same sound bank. Hierarchy objects are
+// matched by ID and compared by their encoded form. Media are matched by
+// source ID. IDs are labeled with names in the name database if it's not nil.
+func DiffBank(before *Bank, after *Bank, names *NameDB) BankDiff {
+	d := BankDiff{Objects: []ObjectChange{}, Media: []MediaChange{}}
+	v := 0
+	if bkhd := after.BKHD(); bkhd != nil {
+		v = int(bkhd.BankGenerationVersion)
+	}
+
+	objs := func(b *Bank) (map[uint32]HircObj, []uint32) {
+		m := make(map[uint32]HircObj)
+		ids := []uint32{}
+		if h := b.HIRC(); h != nil {
+			for _, o := range h.HircObjs {
+				id, err := o.HircID()
+				if err != nil {
+					continue
+				}
+				m[id] = o
+				ids = append(ids, id)
+			}
+		}
+		return m, ids
+	}
+	beforeObjs, beforeIDs := objs(before)
+	afterObjs, afterIDs := objs(after)
+
+	for _, id := range beforeIDs {
+		o := beforeObjs[id]
+		n, in := afterObjs[id]
+		if !in {
+			d.Objects = append(d.Objects, ObjectChange{
+				Kind: ChangeRemoved, ID: id, Name: names.nameOf(id), Type: HircTypeName[o.HircType()],
+			})
+			continue
+		}
+		if bytes.Equal(o.Encode(v), n.Encode(v)) {
+			continue
+		}
+		c := ObjectChange{Kind: ChangeModified, ID: id, Name: names.nameOf(id), Type: HircTypeName[n.HircType()]}
+		c.Props = diffBaseParameter(o.BaseParameter(), n.BaseParameter(), names, v)
+		c.Structural = len(c.Props) == 0 || structuralChange(o, n, v)
+		d.Objects = append(d.Objects, c)
+	}
+	for _, id := range afterIDs {
+		if _, in := beforeObjs[id]; in {
+			continue
+		}
+		n := afterObjs[id]
+		d.Objects = append(d.Objects, ObjectChange{
+			Kind: ChangeCreated, ID: id, Name: names.nameOf(id), Type: HircTypeName[n.HircType()],
+			Props: diffBaseParameter(nil, n.BaseParameter(), names, v),
+		})
+	}
+
+	media := func(b *Bank) map[uint32]uint32 {
+		m := make(map[uint32]uint32)
+		if didx := b.DIDX(); didx != nil {
+			for _, i := range didx.MediaIndexs {
+				m[i.Sid] = i.Size
+			}
+		}
+		return m
+	}
+	beforeMedia := media(before)
+	afterMedia := media(after)
+	for sid, size := range beforeMedia {
+		newSize, in := afterMedia[sid]
+		if !in {
+			d.Media = append(d.Media, MediaChange{Kind: ChangeRemoved, SID: sid, Name: names.nameOf(sid), OldSize: size})
+			continue
+		}
+		oldData, _ := before.Audio(sid)
+		newData, _ := after.Audio(sid)
+		if size != newSize || !bytes.Equal(oldData, newData) {
+			d.Media = append(d.Media, MediaChange{Kind: ChangeModified, SID: sid, Name: names.nameOf(sid), OldSize: size, NewSize: newSize})
+		}
+	}
+	for sid, size := range afterMedia {
+		if _, in := beforeMedia[sid]; !in {
+			d.Media = append(d.Media, MediaChange{Kind: ChangeCreated, SID: sid, Name: names.nameOf(sid), NewSize: size})
+		}
+	}
+	slices.SortFunc(d.Media, func(a MediaChange, b MediaChange) int {
+		if a.Kind != b.Kind {
+			return int(a.Kind) - int(b.Kind)
+		}
+		if a.SID < b.SID {
+			return -1
+		} else if a.SID > b.SID {
+			return 1
+		}
+		return 0
+	})
+	return d
+}
+
+// Whether two revisions of a hierarchy object with property changes are
+// still different once the properties listed by diffBaseParameter are taken
+// from the new revision. The old revision is restored before returning.
+func structuralChange(o HircObj, n HircObj, v int) bool {
+	ob, nb := o.BaseParameter(), n.BaseParameter()
+	if ob == nil || nb == nil {
+		return true
+	}
+	props, ranges := ob.PropBundle.PropValues, ob.RangePropBundle.RangeValues
+	parent, bus := ob.DirectParentId, ob.OverrideBusId
+	ob.PropBundle.PropValues, ob.RangePropBundle.RangeValues = nb.PropBundle.PropValues, nb.RangePropBundle.RangeValues
+	ob.DirectParentId, ob.OverrideBusId = nb.DirectParentId, nb.OverrideBusId
+	defer func() {
+		ob.PropBundle.PropValues, ob.RangePropBundle.RangeValues = props, ranges
+		ob.DirectParentId, ob.OverrideBusId = parent, bus
+	}()
+	return !bytes.Equal(o.Encode(v), n.Encode(v))
+}
+
+// Property changes between two base parameters. Either side can be nil.
+func diffBaseParameter(o *BaseParameter, n *BaseParameter, names *NameDB, v int) []PropChange {
+	changes := []PropChange{}
+	var oProps, nProps []PropValue
+	var oRange, nRange []RangeValue
+	var oParent, nParent, oBus, nBus uint32
+	if o != nil {
+		oProps, oRange = o.PropBundle.PropValues, o.RangePropBundle.RangeValues
+		oParent, oBus = o.DirectParentId, o.OverrideBusId
+	}
+	if n != nil {
+		nProps, nRange = n.PropBundle.PropValues, n.RangePropBundle.RangeValues
+		nParent, nBus = n.DirectParentId, n.OverrideBusId
+	}
+
+	if oParent != nParent {
+		changes = append(changes, PropChange{"Parent", idString(oParent, o, names), idString(nParent, n, names)})
+	}
+	if oBus != nBus {
+		changes = append(changes, PropChange{"Override Bus", idString(oBus, o, names), idString(nBus, n, names)})
+	}
+
+	propName := func(p uint8) string {
+		if tp, ok := LookupInverseTranslateProp(p, v); ok {
+			return PropLabel(tp)
+		}
+		return fmt.Sprintf("Unknown %d", p)
+	}
+
+	for _, pv := range oProps {
+		i := slices.IndexFunc(nProps, func(npv PropValue) bool { return npv.P == pv.P })
+		if i == -1 {
+			changes = append(changes, PropChange{propName(pv.P), PropValueString(pv.V), ""})
+		} else if !bytes.Equal(pv.V, nProps[i].V) {
+			changes = append(changes, PropChange{propName(pv.P), PropValueString(pv.V), PropValueString(nProps[i].V)})
+		}
+	}
+	for _, npv := range nProps {
+		if !slices.ContainsFunc(oProps, func(pv PropValue) bool { return pv.P == npv.P }) {
+			changes = append(changes, PropChange{propName(npv.P), "", PropValueString(npv.V)})
+		}
+	}
+
+	rangeString := func(r RangeValue) string {
+		return fmt.Sprintf("[%s, %s]", PropValueString(r.Min), PropValueString(r.Max))
+	}
+	for _, rv := range oRange {
+		i := slices.IndexFunc(nRange, func(nrv RangeValue) bool { return nrv.P == rv.P })
+		if i == -1 {
+			changes = append(changes, PropChange{propName(rv.P) + " (Randomizer)", rangeString(rv), ""})
+		} else if !bytes.Equal(rv.Min, nRange[i].Min) || !bytes.Equal(rv.Max, nRange[i].Max) {
+			changes = append(changes, PropChange{propName(rv.P) + " (Randomizer)", rangeString(rv), rangeString(nRange[i])})
+		}
+	}
+	for _, nrv := range nRange {
+		if !slices.ContainsFunc(oRange, func(rv RangeValue) bool { return rv.P == nrv.P }) {
+			changes = append(changes, PropChange{propName(nrv.P) + " (Randomizer)", "", rangeString(nrv)})
+		}
+	}
+	return changes
+}
+
+func idString(id uint32, b *BaseParameter, names *NameDB) string {
+	if b == nil {
+		return ""
+	}
+	return names.Label(id)
+}
+
+// Property values are stored as 4 bytes which are either float32 or uint32
+// (IDs, enums). Values that are not meaningful float are shown as integer.
+func PropValueString(b []byte) string {
+	if len(b) != 4 {
+		return fmt.Sprintf("%x", b)
+	}
+	var f float32
+	binary.Decode(b, wio.ByteOrder, &f)
+	abs := math.Abs(float64(f))
+	if f != 0 && (abs < 1e-30 || math.IsNaN(float64(f)) || math.IsInf(float64(f), 0)) {
+		var u uint32
+		binary.Decode(b, wio.ByteOrder, &u)
+		return fmt.Sprintf("%d", u)
+	}
+	return fmt.Sprintf("%g", f)
+}
@@ -0,0 +1,108 @@
+package wwise
+
+import (
+	"testing"
+)
+
+func TestDiffBank(t *testing.T) {
+	const v = 141
+	newBank := func(volume float32, extra bool) *Bank {
+		h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+		didx := NewDIDX(0, []byte{'D', 'I', 'D', 'X'}, 0)
+		didx.Append(100, 64)
+		s := &Sound{Id: 10, BaseParam: &BaseParameter{DirectParentId: 30}}
+		s.BankSourceData = BankSourceData{PluginID: VORBIS, StreamType: SourceTypeDATA, SourceID: 100, InMemoryMediaSize: 64}
+		s.BaseParam.PropBundle.AddBaseProp(v)
+		s.BaseParam.PropBundle.SetPropByIdxF32(0, volume)
+		cntr := &RanSeqCntr{Id: 30, BaseParam: BaseParameter{}}
+		cntr.Container.Children = []uint32{10}
+		h.HircObjs = []HircObj{s, cntr}
+		if extra {
+			n := &Sound{Id: 11, BaseParam: &BaseParameter{DirectParentId: 30}}
+			n.BankSourceData = BankSourceData{PluginID: VORBIS, StreamType: SourceTypeDATA, SourceID: 200, InMemoryMediaSize: 32}
+			cntr.Container.Children = append(cntr.Container.Children, 11)
+			h.HircObjs = append(h.HircObjs, n)
+			didx.Append(200, 32)
+		}
+		bnk := NewBank()
+		bnk.AddChunk(&BKHD{BankGenerationVersion: v})
+		bnk.AddChunk(didx)
+		bnk.AddChunk(h)
+		return &bnk
+	}
+
+	if d := DiffBank(newBank(0, false), newBank(0, false), nil); !d.Empty() {
+		t.Fatalf("Expecting no difference, got %v", d)
+	}
+
+	names := NewNameDB()
+	names.Set(30, "Footsteps")
+	names.Set(200, "footstep_03")
+	d := DiffBank(newBank(0, false), newBank(-6, true), names)
+	if len(d.Objects) != 3 {
+		t.Fatalf("Expecting 3 object changes, got %v", d.Objects)
+	}
+	s := d.Objects[0]
+	if s.Kind != ChangeModified || s.ID != 10 || len(s.Props) != 1 || s.Props[0].Old != "0" || s.Props[0].New != "-6" {
+		t.Fatalf("Unexpected change on sound 10: %v", s)
+	}
+	if s.Props[0].Prop != PropLabel(TVolume) {
+		t.Fatalf("Expecting volume change, got %s", s.Props[0].Prop)
+	}
+	if s.Structural {
+		t.Fatalf("Expecting sound 10 to only have property changes, got %v", s)
+	}
+	if c := d.Objects[1]; c.Kind != ChangeModified || c.ID != 30 || !c.Structural {
+		t.Fatalf("Expecting structural change on container 30, got %v", c)
+	}
+	if c := d.Objects[1]; c.Name != "Footsteps" {
+		t.Fatalf("Expecting container 30 to be labeled, got %v", c)
+	}
+	if c := d.Objects[2]; c.Kind != ChangeCreated || c.ID != 11 {
+		t.Fatalf("Expecting sound 11 to be created, got %v", c)
+	}
+	if p := d.Objects[2].Props; len(p) == 0 || p[0].Prop != "Parent" || p[0].New != "Footsteps (30)" {
+		t.Fatalf("Expecting labeled parent of sound 11, got %v", p)
+	}
+	if len(d.Media) != 1 || d.Media[0].Kind != ChangeCreated || d.Media[0].SID != 200 || d.Media[0].NewSize != 32 ||
+		d.Media[0].Name != "footstep_03" {
+		t.Fatalf("Expecting media 200 to be added, got %v", d.Media)
+	}
+
+	// Property changes do not hide other changes on the same object
+	before := newBank(0, false)
+	after := newBank(-6, false)
+	after.HIRC().HircObjs[0].(*Sound).BankSourceData.InMemoryMediaSize = 32
+	for range 2 {
+		d = DiffBank(before, after, nil)
+		if len(d.Objects) != 1 {
+			t.Fatalf("Expecting 1 object change, got %v", d.Objects)
+		}
+		if s := d.Objects[0]; s.ID != 10 || len(s.Props) != 1 || !s.Structural {
+			t.Fatalf("Expecting property and structural change on sound 10, got %v", s)
+		}
+	}
+	if old := before.HIRC().HircObjs[0].BaseParameter().PropBundle.PropValues[0].V; PropValueString(old) != "0" {
+		t.Fatalf("Expecting sound 10 before the change to be left untouched, got %s", PropValueString(old))
+	}
+
+	// Property IDs without translation are still listed
+	o := &BaseParameter{}
+	n := &BaseParameter{}
+	n.PropBundle.PropValues = []PropValue{{0xFF, []byte{0, 0, 0, 0}}}
+	if p := diffBaseParameter(o, n, nil, v); len(p) != 1 || p[0].Prop != "Unknown 255" {
+		t.Fatalf("Expecting unknown property change, got %v", p)
+	}
+}
+
+func TestNameDBLabel(t *testing.T) {
+	var names *NameDB
+	if l := names.Label(5); l != "5" {
+		t.Fatalf("Expecting nil name database labels with the ID, got %s", l)
+	}
+	names = NewNameDB()
+	names.Set(5, "Play_Gun")
+	if l := names.Label(5); l != "Play_Gun (5)" {
+		t.Fatalf("Unexpected label %s", l)
+	}
+}
@@ -1,6 +1,7 @@
 package wwise
 
 import (
+	"fmt"
 	"slices"
 
 	"github.com/Dekr0/wwise-teller/wio"
@@ -14,6 +15,14 @@ type Event struct {
 	ActionIDs    []uint32
 }
 
+// Create an event with no action. The ID of an event should be the short ID of
+// its name so that the game can post it by name.
+func NewEvent(id uint32) *Event {
+	e := &Event{Id: id, ActionIDs: []uint32{}}
+	e.NumActionIDs.Set(0)
+	return e
+}
+
 func (h *Event) NewAction(actionID uint32) {
 	if slices.Contains(h.ActionIDs, actionID) {
 		return
@@ -56,3 +65,48 @@ func (h *Event) AddLeaf(o HircObj) { panic("Panic Trap") }
 func (h *Event) RemoveLeaf(o HircObj) { panic("Panic Trap") }
 
 func (h *Event) Leafs() []uint32 { return []uint32{} }
+
+// Check whether the ID of a new event collides with an existing hierarchy
+// object, or with a sound bank ID listed in the STID chunk.
+func (b *Bank) CheckEventID(id uint32) error {
+	if id == 0 {
+		return fmt.Errorf("Event ID cannot be 0")
+	}
+	if h := b.HIRC(); h != nil {
+		if _, in := h.Events.Load(id); in {
+			return fmt.Errorf("Event %d already exist", id)
+		}
+		if h.TreeArrIdx(id) != -1 {
+			return fmt.Errorf("Hierarchy object %d already exist", id)
+		}
+	}
+	if s := b.STID(); s != nil {
+		entries, err := s.Entries()
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if e.BankID == id {
+				return fmt.Errorf("Event ID %d collides with sound bank %s in STID", id, e.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Create an action with default parameters in this sound bank. Play actions
+// load their target from this sound bank.
+func (b *Bank) NewAction(id uint32, t uint16, target uint32) (*Action, error) {
+	bkhd := b.BKHD()
+	if bkhd == nil {
+		return nil, fmt.Errorf("This sound bank does not have BKHD chunk.")
+	}
+	a, err := NewAction(id, t, target, int(bkhd.BankGenerationVersion))
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := a.ActionParam.(*ActionPlayParam); ok {
+		p.BankID = bkhd.SoundbankID
+	}
+	return a, nil
+}
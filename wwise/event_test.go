@@ -0,0 +1,73 @@
+package wwise
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+func TestEventAuthoring(t *testing.T) {
+	const v = 141
+	bnk := NewBank()
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	s := &Sound{Id: 10, BaseParam: &BaseParameter{}}
+	play, _ := NewAction(20, 0x04, s.Id, v)
+	existing := NewEvent(ShortID("Play_Existing"))
+	existing.NewAction(play.Id)
+	existing.NumActionIDs.Set(1)
+	h.HircObjs = []HircObj{s, play, existing}
+	h.ActorMixerHirc.Store(s.Id, s)
+	h.Actions.Store(play.Id, play)
+	h.Events.Store(existing.Id, existing)
+	bnk.AddChunk(h)
+
+	blob := binary.LittleEndian.AppendUint32(nil, 1)
+	blob = binary.LittleEndian.AppendUint32(blob, 1)
+	blob = binary.LittleEndian.AppendUint32(blob, ShortID("Weapons"))
+	blob = append(blob, uint8(len("Weapons")))
+	blob = append(blob, "Weapons"...)
+	bnk.AddChunk(NewSTID(0, []byte{'S', 'T', 'I', 'D'}, blob))
+	entries, err := bnk.STID().Entries()
+	if err != nil || len(entries) != 1 || entries[0].Name != "Weapons" {
+		t.Fatalf("Unexpected STID entries %v: %v", entries, err)
+	}
+
+	for _, id := range []uint32{0, existing.Id, s.Id, ShortID("Weapons")} {
+		if err := bnk.CheckEventID(id); err == nil {
+			t.Fatalf("Expecting collision on event ID %d", id)
+		}
+	}
+	e := NewEvent(ShortID("Play_MyNewSound"))
+	if err := bnk.CheckEventID(e.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.AppendNewEvent(e); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.AppendNewEvent(NewEvent(e.Id)); err == nil {
+		t.Fatal("Expecting error on duplicate event")
+	}
+	if h.HircObjs[3] != HircObj(e) {
+		t.Fatal("Expecting new event is placed after existing events")
+	}
+
+	stop, _ := NewAction(21, 0x01, s.Id, v)
+	if err := h.AppendNewActionToEvent(stop, e.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.AppendNewActionToEvent(stop, e.Id); err == nil {
+		t.Fatal("Expecting error on duplicate action")
+	}
+	if err := h.AppendNewActionToEvent(stop, s.Id); err == nil {
+		t.Fatal("Expecting error on non event target")
+	}
+	if _, in := h.Actions.Load(stop.Id); !in {
+		t.Fatal("Expecting action is stored among actions")
+	}
+	if h.HircObjs[3] != HircObj(stop) || !slices.Equal(e.ActionIDs, []uint32{stop.Id}) || e.NumActionIDs.Value != 1 {
+		t.Fatal("Expecting action is placed before its event")
+	}
+	if len(e.Encode(v)) != int(SizeOfHircObjHeader + e.DataSize(v)) {
+		t.Fatal("Unexpected encoded event size")
+	}
+}
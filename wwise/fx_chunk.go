@@ -1,11 +1,15 @@
 package wwise
 
 import (
+	"fmt"
 	"slices"
 
 	"github.com/Dekr0/wwise-teller/wio"
 )
 
+// # of FX slots of a hierarchy object or a bus
+const FxChunkMaxSlot = 4
+
 
 type FxChunk struct {
 	// UniqueNumFx uint8
@@ -16,15 +20,72 @@ type FxChunk struct {
 func (f *FxChunk) BypassFx(set bool) {
 	if set {
 		f.BitsFxByPass = 1
-		for i := range f.FxChunkItems {
-			f.FxChunkItems[i].BitVector = 1
-		}
 	} else {
 		f.BitsFxByPass = 0
-		for i := range f.FxChunkItems {
-			f.FxChunkItems[i].BitVector = 0
+	}
+	for i := range f.FxChunkItems {
+		f.FxChunkItems[i].Bypass(set)
+	}
+}
+
+// Return the FX item in a FX slot
+func (f *FxChunk) Slot(slot uint8) *FxChunkItem {
+	for i := range f.FxChunkItems {
+		if f.FxChunkItems[i].UniqueFxIndex == slot {
+			return &f.FxChunkItems[i]
 		}
 	}
+	return nil
+}
+
+// Insert a FX into an empty FX slot. FX items are kept in the order of slot.
+func (f *FxChunk) AddFx(slot uint8, fxID uint32, shareSet bool, v int) error {
+	if slot >= FxChunkMaxSlot {
+		return fmt.Errorf("FX slot %d is out of range (max %d)", slot, FxChunkMaxSlot - 1)
+	}
+	if f.Slot(slot) != nil {
+		return fmt.Errorf("FX slot %d is already used by FX %d", slot, f.Slot(slot).FxId)
+	}
+	item := FxChunkItem{UniqueFxIndex: slot, FxId: fxID}
+	item.SetShareSet(shareSet, v)
+	i, _ := slices.BinarySearchFunc(f.FxChunkItems, slot, func(a FxChunkItem, b uint8) int {
+		return int(a.UniqueFxIndex) - int(b)
+	})
+	f.FxChunkItems = slices.Insert(f.FxChunkItems, i, item)
+	return nil
+}
+
+// Set render and bypass flags of the FX in a FX slot. Only version > 145 can
+// bypass a single FX.
+func (f *FxChunk) SetSlotFlags(slot uint8, render bool, bypass bool, v int) error {
+	item := f.Slot(slot)
+	if item == nil {
+		return fmt.Errorf("FX slot %d is empty", slot)
+	}
+	if bypass && v <= 145 {
+		return fmt.Errorf("Bypassing a single FX is not supported in version %d", v)
+	}
+	item.SetRendered(render, v)
+	if v > 145 {
+		item.Bypass(bypass)
+	}
+	return nil
+}
+
+// Remove a FX from a FX slot. Return the ID of the removed FX.
+func (f *FxChunk) RemoveFx(slot uint8) (uint32, bool) {
+	i := slices.IndexFunc(f.FxChunkItems, func(item FxChunkItem) bool {
+		return item.UniqueFxIndex == slot
+	})
+	if i == -1 {
+		return 0, false
+	}
+	fxID := f.FxChunkItems[i].FxId
+	f.FxChunkItems = slices.Delete(f.FxChunkItems, i, i + 1)
+	if len(f.FxChunkItems) <= 0 {
+		f.BitsFxByPass = 0
+	}
+	return fxID, true
 }
 
 func (f *FxChunk) Clone() FxChunk {
@@ -75,12 +136,45 @@ type FxChunkItem struct {
 	BitVector     uint8
 }
 
+// > 145 only. Share set and render flags in the same bit vector are kept.
 func (f *FxChunkItem) Bypass(set bool) {
-	if set {
-		f.BitVector = 1
-	} else {
-		f.BitVector = 0
+	f.BitVector = wio.SetBit(f.BitVector, 0, set)
+}
+
+func (f *FxChunkItem) IsShareSet(v int) bool {
+	if v <= 145 {
+		return f.BitIsShareSet != 0
+	}
+	return wio.GetBit(f.BitVector, 1)
+}
+
+func (f *FxChunkItem) SetShareSet(set bool, v int) {
+	if v <= 145 {
+		f.BitIsShareSet = 0
+		if set {
+			f.BitIsShareSet = 1
+		}
+		return
+	}
+	f.BitVector = wio.SetBit(f.BitVector, 1, set)
+}
+
+func (f *FxChunkItem) IsRendered(v int) bool {
+	if v <= 145 {
+		return f.BitIsRendered != 0
+	}
+	return wio.GetBit(f.BitVector, 2)
+}
+
+func (f *FxChunkItem) SetRendered(set bool, v int) {
+	if v <= 145 {
+		f.BitIsRendered = 0
+		if set {
+			f.BitIsRendered = 1
+		}
+		return
 	}
+	f.BitVector = wio.SetBit(f.BitVector, 2, set)
 }
 
 type FxChunkMetadata struct {
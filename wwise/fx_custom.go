@@ -122,6 +122,25 @@ type FxCustom struct {
 	PluginProps     []PluginProp
 }
 
+// FX custom of a built-in FX plugin with default parameters
+func NewFxCustom(id uint32, pluginID uint32) (*FxCustom, error) {
+	p, err := NewFxParam(pluginID)
+	if err != nil {
+		return nil, err
+	}
+	f := &FxCustom{
+		Id: id,
+		PluginTypeId: pluginID,
+		PluginParam: &PluginParam{PluginParamSize: p.Size(0), PluginParamData: p},
+		MediaMap: []MediaMapItem{},
+		RTPC: RTPC{RTPCItems: []RTPCItem{}},
+		PluginProps: []PluginProp{},
+	}
+	f.StateProp.NumStateProps.Set(0)
+	f.StateGroup.NumStateGroups.Set(0)
+	return f, nil
+}
+
 const SizeOfMediaMapItem = 5
 type MediaMapItem struct {
 	Index    uint8
@@ -1,10 +1,121 @@
 package wwise
 
 import (
+	"fmt"
+
 	"github.com/Dekr0/wwise-teller/assert"
 	"github.com/Dekr0/wwise-teller/wio"
 )
 
+// Built-in FX plugins
+const (
+	PluginIDParametricEQ      uint32 = 0x00690003
+	PluginIDDelay             uint32 = 0x006A0003
+	PluginIDCompressor        uint32 = 0x006C0003
+	PluginIDExpander          uint32 = 0x006D0003
+	PluginIDPeakLimiter       uint32 = 0x006E0003
+	PluginIDMatrixReverb      uint32 = 0x00730003
+	PluginIDRoomVerb          uint32 = 0x00760003
+	PluginIDFlanger           uint32 = 0x007D0003
+	PluginIDGuitarDistortion  uint32 = 0x007E0003
+	PluginIDConvolutionReverb uint32 = 0x007F0003
+	PluginIDMeter             uint32 = 0x00810003
+	PluginIDTimeStretch       uint32 = 0x00820003
+	PluginIDTremolo           uint32 = 0x00830003
+	PluginIDStereoDelay       uint32 = 0x00870003
+	PluginIDPitchShifter      uint32 = 0x00880003
+	PluginIDHarmonizer        uint32 = 0x008A0003
+	PluginIDGain              uint32 = 0x008B0003
+)
+
+// Built-in FX plugins that can be created with default parameters (see
+// NewFxParam). Plugins whose parameter block is not fully decoded, or that
+// require media (e.g. impulse response of Convolution Reverb) are excluded.
+var NewFxPluginIDs []uint32 = []uint32{
+	PluginIDParametricEQ,
+	PluginIDCompressor,
+	PluginIDExpander,
+	PluginIDPeakLimiter,
+	PluginIDGain,
+	PluginIDDelay,
+	PluginIDMatrixReverb,
+	PluginIDFlanger,
+	PluginIDGuitarDistortion,
+	PluginIDTremolo,
+	PluginIDTimeStretch,
+	PluginIDPitchShifter,
+}
+
+// Plugin parameters of a built-in FX plugin with default values
+func NewFxParam(pluginID uint32) (FxParam, error) {
+	band := func(filterType EQFilterType, freq float32, on uint8) ParametricEQBand {
+		return ParametricEQBand{FilterType: filterType, Frequency: freq, QFactor: 1, OnOff: on}
+	}
+	lfo := FxLFO{Frequency: 1, Waveform: LFOWaveformSine, PWM: 50}
+	switch pluginID {
+	case PluginIDParametricEQ:
+		return &ParametricEQ{
+			EQBand: [3]ParametricEQBand{
+				band(EQFilterTypeLowShelf, 100, 1),
+				band(EQFilterTypePeakingEQ, 1000, 1),
+				band(EQFilterTypeHiShelf, 10000, 1),
+			},
+			ProcessLFE: 1,
+			Data: []byte{},
+		}, nil
+	case PluginIDCompressor:
+		return &Compressor{Threshold: -12, Ratio: 4, Attack: 0.1, Release: 0.5, ProcessLFE: 1, ChannelLink: 1, Data: []byte{}}, nil
+	case PluginIDExpander:
+		return &Expander{Threshold: -40, Ratio: 2, Attack: 0.01, Release: 0.1, ProcessLFE: 1, ChannelLink: 1, Data: []byte{}}, nil
+	case PluginIDPeakLimiter:
+		return &PeakLimiter{Threshold: -12, Ratio: 10, LookAhead: 0.01, Release: 0.2, ProcessLFE: 1, ChannelLink: 1, Data: []byte{}}, nil
+	case PluginIDGain:
+		return &GainFX{Data: []byte{}}, nil
+	case PluginIDDelay:
+		return &DelayFX{DelayTime: 0.5, WetDryMix: 50, ProcessLFE: 1, Data: []byte{}}, nil
+	case PluginIDMatrixReverb:
+		return &MatrixReverb{ReverbTime: 4, HFRatio: 2, NumberOfDelays: 8, WetLevel: -12, ProcessLFE: 1, Data: []byte{}}, nil
+	case PluginIDFlanger:
+		return &Flanger{
+			DelayTime: 2, DryLevel: 0.7, FfwdLevel: 0.7, ModDepth: 50, LFO: lfo,
+			WetDryMix: 50, EnableLFO: 1, ProcessCenter: 1,
+			Data: []byte{},
+		}, nil
+	case PluginIDGuitarDistortion:
+		return &GuitarDistortion{
+			PreEQ: [3]ParametricEQBand{
+				band(EQFilterTypeHiPass, 100, 0),
+				band(EQFilterTypePeakingEQ, 1000, 0),
+				band(EQFilterTypeLowPass, 10000, 0),
+			},
+			PostEQ: [3]ParametricEQBand{
+				band(EQFilterTypeHiPass, 100, 0),
+				band(EQFilterTypePeakingEQ, 1000, 0),
+				band(EQFilterTypeLowPass, 10000, 0),
+			},
+			DistortionType: DistortionTypeOverdrive,
+			Drive: 50, Tone: 50, WetDryMix: 100,
+			Data: []byte{},
+		}, nil
+	case PluginIDTremolo:
+		lfo.Frequency = 2
+		return &Tremolo{ModDepth: 100, LFO: lfo, ProcessCenter: 1, Data: []byte{}}, nil
+	case PluginIDTimeStretch:
+		return &TimeStretch{WindowSize: 1, TimeStretch: 100, Data: []byte{}}, nil
+	case PluginIDPitchShifter:
+		return &PitchShifter{
+			WetLevel: 0, DryLevel: -96.3, DelayTime: 50,
+			Filter: FxFilter{FilterType: EQFilterTypeLowPass, Frequency: 20000, QFactor: 1},
+			Data: []byte{},
+		}, nil
+	}
+	name := fmt.Sprintf("0x%08X", pluginID)
+	if n, in := PluginNameLUT[int32(pluginID)]; in {
+		name = n
+	}
+	return nil, fmt.Errorf("Creating FX plugin %s is not supported", name)
+}
+
 
 type PluginParam struct {
 	PluginParamSize uint32 // U32
@@ -50,7 +161,7 @@ type SourceSine struct {
 	Gain        float32
 	Duration    float32
 	ChannelMask uint32
-	Data        []byte
+	Data        []byte `json:"-"`
 }
 
 func (f *SourceSine) Encode(v int) []byte {
@@ -72,7 +183,7 @@ type SourceSlience struct {
 	Duration              float32
 	RandomizedLengthMinus float32
 	RandomizedLengthPlus  float32
-	Data                  []byte
+	Data                  []byte `json:"-"`
 }
 
 func (f *SourceSlience) Encode(v int) []byte {
@@ -94,14 +205,14 @@ type ToneGen struct {
 	StartFreq    float32
 	StopFreq     float32
 	StartFreqMin float32
-	Data         []byte
+	Data         []byte `json:"-"`
 }
 
 type ParametricEQ struct {
 	EQBand      [3]ParametricEQBand
 	OutputLevel    float32
 	ProcessLFE     uint8
-	Data         []byte
+	Data         []byte `json:"-"`
 }
 
 func (f *ParametricEQ) Encode(v int) []byte {
@@ -184,7 +295,7 @@ type MeterFX struct {
 	Scope                *MeterScope // Non RTPC
 	ApplyDownstreamVolume uint8 // Non RTPC
 	GameParamID           uint32 // Non RTPC
-	Data                []byte
+	Data                []byte `json:"-"`
 }
 
 func (f *MeterFX) Encode(v int) []byte {
@@ -232,7 +343,7 @@ type PeakLimiter struct {
 	OutputLevel float32 // RTPC in db
 	ProcessLFE  uint8   // Non RTPC
 	ChannelLink uint8   // Non RTPC
-	Data        []byte
+	Data        []byte `json:"-"`
 }
 
 func (f *PeakLimiter) Encode(v int) []byte {
@@ -256,7 +367,7 @@ func (f *PeakLimiter) Size(int) uint32 {
 type GainFX struct {
 	FullbandGain float32
 	LFEGain      float32
-	Data         []byte
+	Data         []byte `json:"-"`
 }
 
 func (f *GainFX) Encode(v int) []byte {
@@ -280,7 +391,7 @@ type Compressor struct {
 	OutputGain  float32;
 	ProcessLFE  uint8;
 	ChannelLink uint8;
-	Data        []byte
+	Data        []byte `json:"-"`
 }
 
 func (f *Compressor) Encode(v int) []byte {
@@ -309,7 +420,7 @@ type Expander struct {
 	OutputGain  float32;
 	ProcessLFE  uint8;
 	ChannelLink uint8;
-	Data        []byte
+	Data        []byte `json:"-"`
 }
 
 func (f *Expander) Encode(v int) []byte {
@@ -330,17 +441,458 @@ func (f *Expander) Size(v int) uint32 {
 	return 22 + uint32(len(f.Data))
 }
 
+
+// Filter used by built-in FX with a single filter stage
+const SizeOfFxFilter = 16
+type FxFilter struct {
+	FilterType EQFilterType
+	Gain       float32
+	Frequency  float32
+	QFactor    float32
+}
+
+type LFOWaveform uint32
+const (
+	LFOWaveformSine     LFOWaveform = 0
+	LFOWaveformTriangle LFOWaveform = 1
+	LFOWaveformSquare   LFOWaveform = 2
+	LFOWaveformSawUp    LFOWaveform = 3
+	LFOWaveformSawDown  LFOWaveform = 4
+	LFOWaveformCount    LFOWaveform = 5
+)
+
+var LFOWaveformNames []string = []string{
+	"Sine", "Triangle", "Square", "Saw Up", "Saw Down",
+}
+
+type LFOPhaseMode uint32
+const (
+	LFOPhaseModeLeftRight LFOPhaseMode = 0
+	LFOPhaseModeFrontRear LFOPhaseMode = 1
+	LFOPhaseModeCircular  LFOPhaseMode = 2
+	LFOPhaseModeRandom    LFOPhaseMode = 3
+	LFOPhaseModeCount     LFOPhaseMode = 4
+)
+
+var LFOPhaseModeNames []string = []string{
+	"Left / Right", "Front / Rear", "Circular", "Random",
+}
+
+// LFO of Flanger and Tremolo. All parameters are RTPC.
+const SizeOfFxLFO = 28
+type FxLFO struct {
+	Frequency   float32
+	Waveform    LFOWaveform
+	Smoothing   float32
+	PWM         float32
+	PhaseOffset float32
+	PhaseMode   LFOPhaseMode
+	PhaseSpread float32
+}
+
+const SizeOfDelayFX = 18
+type DelayFX struct {
+	DelayTime       float32 // Non RTPC
+	Feedback        float32 // RTPC
+	WetDryMix       float32 // RTPC
+	OutputLevel     float32 // RTPC
+	FeedbackEnabled uint8   // RTPC
+	ProcessLFE      uint8   // Non RTPC
+	Data          []byte    `json:"-"`
+}
+
+func (f *DelayFX) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.DelayTime)
+	w.Append(f.Feedback)
+	w.Append(f.WetDryMix)
+	w.Append(f.OutputLevel)
+	w.Append(f.FeedbackEnabled)
+	w.Append(f.ProcessLFE)
+	w.AppendBytes(f.Data)
+	return w.BytesAssert(int(size))
+}
+
+func (f *DelayFX) Size(int) uint32 {
+	return SizeOfDelayFX + uint32(len(f.Data))
+}
+
+// Custom delay lengths are kept in Data
+const SizeOfMatrixReverb = 29
+type MatrixReverb struct {
+	ReverbTime       float32 // RTPC
+	HFRatio          float32 // RTPC
+	NumberOfDelays   uint32  // Non RTPC
+	DryLevel         float32 // RTPC
+	WetLevel         float32 // RTPC
+	PreDelay         float32 // Non RTPC
+	ProcessLFE       uint8   // Non RTPC
+	DelayLengthsMode uint32  // Non RTPC
+	Data           []byte    `json:"-"`
+}
+
+func (f *MatrixReverb) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.ReverbTime)
+	w.Append(f.HFRatio)
+	w.Append(f.NumberOfDelays)
+	w.Append(f.DryLevel)
+	w.Append(f.WetLevel)
+	w.Append(f.PreDelay)
+	w.Append(f.ProcessLFE)
+	w.Append(f.DelayLengthsMode)
+	w.AppendBytes(f.Data)
+	return w.BytesAssert(int(size))
+}
+
+func (f *MatrixReverb) Size(int) uint32 {
+	return SizeOfMatrixReverb + uint32(len(f.Data))
+}
+
+const SizeOfRoomVerbFilter = 12
+type RoomVerbFilter struct {
+	Gain      float32
+	Frequency float32
+	QFactor   float32
+}
+
+// Only RTPC parameters are decoded. Non RTPC parameters (early reflections, 
+// room shape, tone control curves, etc.) are kept in Data.
+const SizeOfRoomVerb = 80
+type RoomVerb struct {
+	DecayTime   float32
+	HFDamping   float32
+	Diffusion   float32
+	StereoWidth float32
+	Filters     [3]RoomVerbFilter
+	FrontLevel  float32
+	RearLevel   float32
+	CenterLevel float32
+	LFELevel    float32
+	DryLevel    float32
+	ERLevel     float32
+	ReverbLevel float32
+	Data      []byte `json:"-"`
+}
+
+func (f *RoomVerb) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.DecayTime)
+	w.Append(f.HFDamping)
+	w.Append(f.Diffusion)
+	w.Append(f.StereoWidth)
+	w.Append(f.Filters)
+	w.Append(f.FrontLevel)
+	w.Append(f.RearLevel)
+	w.Append(f.CenterLevel)
+	w.Append(f.LFELevel)
+	w.Append(f.DryLevel)
+	w.Append(f.ERLevel)
+	w.Append(f.ReverbLevel)
+	w.AppendBytes(f.Data)
+	return w.BytesAssert(int(size))
+}
+
+func (f *RoomVerb) Size(int) uint32 {
+	return SizeOfRoomVerb + uint32(len(f.Data))
+}
+
+const SizeOfFlanger = 59
+type Flanger struct {
+	DelayTime     float32 // Non RTPC
+	DryLevel      float32 // RTPC
+	FfwdLevel     float32 // RTPC
+	FbackLevel    float32 // RTPC
+	ModDepth      float32 // RTPC
+	LFO           FxLFO   // RTPC
+	OutputLevel   float32 // RTPC
+	WetDryMix     float32 // RTPC
+	EnableLFO     uint8   // Non RTPC
+	ProcessCenter uint8   // Non RTPC
+	ProcessLFE    uint8   // Non RTPC
+	Data        []byte    `json:"-"`
+}
+
+func (f *Flanger) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.DelayTime)
+	w.Append(f.DryLevel)
+	w.Append(f.FfwdLevel)
+	w.Append(f.FbackLevel)
+	w.Append(f.ModDepth)
+	w.Append(f.LFO)
+	w.Append(f.OutputLevel)
+	w.Append(f.WetDryMix)
+	w.Append(f.EnableLFO)
+	w.Append(f.ProcessCenter)
+	w.Append(f.ProcessLFE)
+	w.AppendBytes(f.Data)
+	return w.BytesAssert(int(size))
+}
+
+func (f *Flanger) Size(int) uint32 {
+	return SizeOfFlanger + uint32(len(f.Data))
+}
+
+type DistortionType uint32
+const (
+	DistortionTypeNone      DistortionType = 0
+	DistortionTypeOverdrive DistortionType = 1
+	DistortionTypeHeavy     DistortionType = 2
+	DistortionTypeFuzz      DistortionType = 3
+	DistortionTypeClip      DistortionType = 4
+	DistortionTypeCount     DistortionType = 5
+)
+
+var DistortionTypeNames []string = []string{
+	"None", "Overdrive", "Heavy", "Fuzz", "Clip",
+}
+
+const SizeOfGuitarDistortion = 126
+type GuitarDistortion struct {
+	PreEQ          [3]ParametricEQBand
+	PostEQ         [3]ParametricEQBand
+	DistortionType DistortionType // Non RTPC
+	Drive          float32        // RTPC
+	Tone           float32        // RTPC
+	Rectification  float32        // RTPC
+	OutputLevel    float32        // RTPC
+	WetDryMix      float32        // RTPC
+	Data         []byte           `json:"-"`
+}
+
+func (f *GuitarDistortion) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.PreEQ)
+	w.Append(f.PostEQ)
+	w.Append(f.DistortionType)
+	w.Append(f.Drive)
+	w.Append(f.Tone)
+	w.Append(f.Rectification)
+	w.Append(f.OutputLevel)
+	w.Append(f.WetDryMix)
+	w.AppendBytes(f.Data)
+	return w.BytesAssert(int(size))
+}
+
+func (f *GuitarDistortion) Size(int) uint32 {
+	return SizeOfGuitarDistortion + uint32(len(f.Data))
+}
+
+const SizeOfTremolo = 38
+type Tremolo struct {
+	ModDepth      float32 // RTPC
+	LFO           FxLFO   // RTPC
+	OutputGain    float32 // RTPC
+	ProcessCenter uint8   // Non RTPC
+	ProcessLFE    uint8   // Non RTPC
+	Data        []byte    `json:"-"`
+}
+
+func (f *Tremolo) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.ModDepth)
+	w.Append(f.LFO)
+	w.Append(f.OutputGain)
+	w.Append(f.ProcessCenter)
+	w.Append(f.ProcessLFE)
+	w.AppendBytes(f.Data)
+	return w.BytesAssert(int(size))
+}
+
+func (f *Tremolo) Size(int) uint32 {
+	return SizeOfTremolo + uint32(len(f.Data))
+}
+
+const SizeOfTimeStretch = 16
+type TimeStretch struct {
+	WindowSize        uint32  // Non RTPC
+	TimeStretch       float32 // Non RTPC, in %
+	TimeStretchRandom float32 // Non RTPC, in %
+	OutputGain        float32 // RTPC
+	Data            []byte    `json:"-"`
+}
+
+func (f *TimeStretch) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.WindowSize)
+	w.Append(f.TimeStretch)
+	w.Append(f.TimeStretchRandom)
+	w.Append(f.OutputGain)
+	w.AppendBytes(f.Data)
+	return w.BytesAssert(int(size))
+}
+
+func (f *TimeStretch) Size(int) uint32 {
+	return SizeOfTimeStretch + uint32(len(f.Data))
+}
+
+const SizeOfPitchShifter = 38
+type PitchShifter struct {
+	InputType  uint32   // Non RTPC
+	ProcessLFE uint8    // Non RTPC
+	SyncDry    uint8    // Non RTPC
+	DryLevel   float32  // RTPC
+	WetLevel   float32  // RTPC
+	DelayTime  float32  // Non RTPC
+	Pitch      float32  // RTPC, in cents
+	Filter     FxFilter // RTPC
+	Data     []byte     `json:"-"`
+}
+
+func (f *PitchShifter) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.InputType)
+	w.Append(f.ProcessLFE)
+	w.Append(f.SyncDry)
+	w.Append(f.DryLevel)
+	w.Append(f.WetLevel)
+	w.Append(f.DelayTime)
+	w.Append(f.Pitch)
+	w.Append(f.Filter)
+	w.AppendBytes(f.Data)
+	return w.BytesAssert(int(size))
+}
+
+func (f *PitchShifter) Size(int) uint32 {
+	return SizeOfPitchShifter + uint32(len(f.Data))
+}
+
+const SizeOfHarmonizerVoice = 25
+type HarmonizerVoice struct {
+	Enable uint8    // Non RTPC
+	Pitch  float32  // RTPC, in cents
+	Gain   float32  // RTPC
+	Filter FxFilter // RTPC
+}
+
+const SizeOfHarmonizer = 68
+type Harmonizer struct {
+	InputType  uint32             // Non RTPC
+	ProcessLFE uint8              // Non RTPC
+	SyncDry    uint8              // Non RTPC
+	DryLevel   float32            // RTPC
+	WetLevel   float32            // RTPC
+	WindowSize uint32             // Non RTPC
+	Voices     [2]HarmonizerVoice
+	Data     []byte               `json:"-"`
+}
+
+func (f *Harmonizer) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.InputType)
+	w.Append(f.ProcessLFE)
+	w.Append(f.SyncDry)
+	w.Append(f.DryLevel)
+	w.Append(f.WetLevel)
+	w.Append(f.WindowSize)
+	w.Append(f.Voices)
+	w.AppendBytes(f.Data)
+	return w.BytesAssert(int(size))
+}
+
+func (f *Harmonizer) Size(int) uint32 {
+	return SizeOfHarmonizer + uint32(len(f.Data))
+}
+
+const SizeOfStereoDelayChannel = 16
+type StereoDelayChannel struct {
+	InputType uint32  // Non RTPC
+	DelayTime float32 // Non RTPC
+	Feedback  float32 // RTPC
+	CrossFeed float32 // RTPC
+}
+
+const SizeOfStereoDelay = 62
+type StereoDelay struct {
+	Channels         [2]StereoDelayChannel // Left, Right
+	Filter           FxFilter              // RTPC
+	DryLevel         float32               // RTPC
+	WetLevel         float32               // RTPC
+	FrontRearBalance float32               // RTPC
+	EnableFeedback   uint8                 // Non RTPC
+	EnableCrossFeed  uint8                 // Non RTPC
+	Data           []byte                  `json:"-"`
+}
+
+func (f *StereoDelay) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.Channels)
+	w.Append(f.Filter)
+	w.Append(f.DryLevel)
+	w.Append(f.WetLevel)
+	w.Append(f.FrontRearBalance)
+	w.Append(f.EnableFeedback)
+	w.Append(f.EnableCrossFeed)
+	w.AppendBytes(f.Data)
+	return w.BytesAssert(int(size))
+}
+
+func (f *StereoDelay) Size(int) uint32 {
+	return SizeOfStereoDelay + uint32(len(f.Data))
+}
+
+// InputStereoWidth is only serialized since version 150. It is nil when the
+// parameters are decoded from an older sound bank.
+const SizeOfConvolutionReverb = 48
 type ConvolutionReverb struct {
-	PreDelay         float32
-	FrontRearDelay   float32
-	StereoWidth      float32
-	InputCenterLevel float32
-	InputLFELevel    float32
-	InputStereoWidth *float32
-	FrontLevel       float32
-	RearLevel        float32
-	CenterLevel      float32
-	LFELevel         float32
-	DryLevel         float32
-	WetLevel         float32
+	PreDelay         float32  // Non RTPC
+	FrontRearDelay   float32  // RTPC
+	StereoWidth      float32  // RTPC
+	InputCenterLevel float32  // RTPC
+	InputLFELevel    float32  // RTPC
+	InputStereoWidth *float32 // RTPC
+	FrontLevel       float32  // RTPC
+	RearLevel        float32  // RTPC
+	CenterLevel      float32  // RTPC
+	LFELevel         float32  // RTPC
+	DryLevel         float32  // RTPC
+	WetLevel         float32  // RTPC
+	AlgoType         uint32   // Non RTPC
+}
+
+func ConvolutionReverbSize(v int) uint32 {
+	if v >= 150 {
+		return SizeOfConvolutionReverb + 4
+	}
+	return SizeOfConvolutionReverb
+}
+
+func (f *ConvolutionReverb) Encode(v int) []byte {
+	size := f.Size(v)
+	w := wio.NewWriter(uint64(size))
+	w.Append(f.PreDelay)
+	w.Append(f.FrontRearDelay)
+	w.Append(f.StereoWidth)
+	w.Append(f.InputCenterLevel)
+	w.Append(f.InputLFELevel)
+	if v >= 150 {
+		var width float32
+		if f.InputStereoWidth != nil {
+			width = *f.InputStereoWidth
+		}
+		w.Append(width)
+	}
+	w.Append(f.FrontLevel)
+	w.Append(f.RearLevel)
+	w.Append(f.CenterLevel)
+	w.Append(f.LFELevel)
+	w.Append(f.DryLevel)
+	w.Append(f.WetLevel)
+	w.Append(f.AlgoType)
+	return w.BytesAssert(int(size))
+}
+
+func (f *ConvolutionReverb) Size(v int) uint32 {
+	return ConvolutionReverbSize(v)
 }
@@ -139,6 +139,25 @@ type FxShareSet struct {
 	PluginProps     []PluginProp
 }
 
+// FX share set of a built-in FX plugin with default parameters
+func NewFxShareSet(id uint32, pluginID uint32) (*FxShareSet, error) {
+	p, err := NewFxParam(pluginID)
+	if err != nil {
+		return nil, err
+	}
+	f := &FxShareSet{
+		Id: id,
+		PluginTypeId: pluginID,
+		PluginParam: &PluginParam{PluginParamSize: p.Size(0), PluginParamData: p},
+		MediaMap: []MediaMapItem{},
+		RTPC: RTPC{RTPCItems: []RTPCItem{}},
+		PluginProps: []PluginProp{},
+	}
+	f.StateProp.NumStateProps.Set(0)
+	f.StateGroup.NumStateGroups.Set(0)
+	return f, nil
+}
+
 func (h *FxShareSet) HasParam() bool {
 	return h.PluginTypeId >= 0
 }
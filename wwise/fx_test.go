@@ -0,0 +1,92 @@
+package wwise
+
+import (
+	"testing"
+)
+
+func TestAttachDetachFx(t *testing.T) {
+	const v = 154
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	a := &Sound{Id: 10, BaseParam: &BaseParameter{DirectParentId: 30}}
+	b := &Sound{Id: 20, BaseParam: &BaseParameter{DirectParentId: 30}}
+	mixer := &ActorMixer{Id: 30, BaseParam: &BaseParameter{}}
+	mixer.Container.Children = []uint32{10, 20}
+	h.HircObjs = []HircObj{a, b, mixer}
+	for _, o := range h.HircObjs {
+		id, _ := o.HircID()
+		h.ActorMixerHirc.Store(id, o)
+	}
+
+	eq, err := NewFxShareSet(100, PluginIDParametricEQ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	distortion, err := NewFxCustom(200, PluginIDGuitarDistortion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFxCustom(300, PluginIDConvolutionReverb); err == nil {
+		t.Fatal("Expecting error on creating convolution reverb")
+	}
+	for _, f := range []HircObj{eq, distortion} {
+		if err := h.AppendNewFx(f); err != nil {
+			t.Fatal(err)
+		}
+		if l := len(f.Encode(v)); l != int(SizeOfHircObjHeader + f.(interface{ DataSize(int) uint32 }).DataSize(v)) {
+			t.Fatalf("Unexpected encoded size %d of %T", l, f)
+		}
+	}
+	if err := h.AppendNewFx(eq); err == nil {
+		t.Fatal("Expecting error on appending the same FX twice")
+	}
+
+	if err := h.AttachFx(10, 2, 200, v); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.AttachFx(10, 0, 100, v); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.AttachFx(10, 0, 200, v); err == nil {
+		t.Fatal("Expecting error on inserting into a used slot")
+	}
+	if err := h.AttachFx(10, FxChunkMaxSlot, 200, v); err == nil {
+		t.Fatal("Expecting error on inserting into an out of range slot")
+	}
+	if err := h.AttachFx(20, 1, 100, v); err != nil {
+		t.Fatal(err)
+	}
+	items := a.BaseParam.FxChunk.FxChunkItems
+	if len(items) != 2 || items[0].FxId != 100 || items[1].FxId != 200 {
+		t.Fatalf("Unexpected FX slots %+v", items)
+	}
+	if !items[0].IsShareSet(v) || items[1].IsShareSet(v) || a.BaseParam.BitIsOverrideParentFx != 1 {
+		t.Fatalf("Unexpected FX flags %+v", items)
+	}
+	items[0].Bypass(true)
+	if !items[0].IsShareSet(v) {
+		t.Fatal("Bypassing a FX must not clear share set flag")
+	}
+
+	// Share set is still used by sound 20
+	if id, err := h.DetachFx(10, 0, v); err != nil || id != 100 {
+		t.Fatalf("Expecting FX 100 is detached, got %d (%v)", id, err)
+	}
+	if _, in := h.FxShareSets.Load(uint32(100)); !in {
+		t.Fatal("FX share set 100 is still referenced by sound 20")
+	}
+	if _, err := h.DetachFx(20, 1, v); err != nil {
+		t.Fatal(err)
+	}
+	if _, in := h.FxShareSets.Load(uint32(100)); in {
+		t.Fatal("Unreferenced FX share set 100 is not removed")
+	}
+	if _, err := h.DetachFx(10, 2, v); err != nil {
+		t.Fatal(err)
+	}
+	if _, in := h.FxCustoms.Load(uint32(200)); in || len(h.HircObjs) != 3 {
+		t.Fatalf("Unreferenced FX custom 200 is not removed, %d hierarchy objects left", len(h.HircObjs))
+	}
+	if _, err := h.DetachFx(10, 2, v); err == nil {
+		t.Fatal("Expecting error on detaching an empty slot")
+	}
+}
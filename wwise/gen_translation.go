@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -143,7 +144,10 @@ func ReadTranslationCSV(versionTable string, nameTable string) error {
 	var pyBuilder strings.Builder
 	var builder strings.Builder
 	builder.WriteString("package wwise\n\n")
-	for version, versionLUT := range versionLUTs {
+	// Emit in version order so that regenerating the tables is reproducible
+	slices.Sort(versions)
+	for _, version := range versions {
+		versionLUT := versionLUTs[version]
 		pyBuilder.WriteString(fmt.Sprintf("ForwardTranslationV%d = {\n", version))
 		builder.WriteString(fmt.Sprintf("var ForwardTranslationV%d = map[PropType]uint8{\n", version))
 		for _, ev := range evArr {
@@ -187,6 +187,48 @@ func (h *HIRC) HierarchyCount() HircCount {
 	return c
 }
 
+// IDs of a hierarchy object and all of its descendants
+func (h *HIRC) subtreeIDs(id uint32) map[uint32]struct{} {
+	ids := map[uint32]struct{}{id: {}}
+	queue := []uint32{id}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		i := h.TreeArrIdx(curr)
+		if i == -1 {
+			continue
+		}
+		for _, c := range h.HircObjs[i].Leafs() {
+			if _, in := ids[c]; !in {
+				ids[c] = struct{}{}
+				queue = append(queue, c)
+			}
+		}
+	}
+	return ids
+}
+
+// Take a hierarchy object and all of its descendants out of HircObjs. Their
+// relative order is kept so that children stay in front of their parent when
+// the subtree is inserted back as a whole.
+func (h *HIRC) takeSubtree(id uint32) []HircObj {
+	ids := h.subtreeIDs(id)
+	subtree := make([]HircObj, 0, len(ids))
+	rest := make([]HircObj, 0, len(h.HircObjs))
+	for _, o := range h.HircObjs {
+		oid, err := o.HircID()
+		if _, in := ids[oid]; err == nil && in {
+			subtree = append(subtree, o)
+		} else {
+			rest = append(rest, o)
+		}
+	}
+	h.HircObjs = rest
+	return subtree
+}
+
+// Move a hierarchy object and its descendants under a new parent. The subtree
+// is placed right before the new parent in HircObjs.
 func (h *HIRC) ChangeRoot(id, newRootID, oldRootID uint32, syncUITree bool) {
 	if newRootID == 0 {
 		h.RemoveRoot(id, oldRootID, syncUITree)
@@ -232,6 +274,10 @@ func (h *HIRC) ChangeRoot(id, newRootID, oldRootID uint32, syncUITree bool) {
 	if !newRoot.IsCntr() {
 		panic(fmt.Sprintf("ID %d is not a container", newRootID))
 	}
+	if _, in := h.subtreeIDs(id)[newRootID]; in {
+		slog.Warn(fmt.Sprintf("Cannot move %d under itself or its descendant %d", id, newRootID))
+		return
+	}
 
 	if oldRootID != 0 {
 		idx = slices.IndexFunc(h.HircObjs, func(h HircObj) bool {
@@ -261,14 +307,8 @@ func (h *HIRC) ChangeRoot(id, newRootID, oldRootID uint32, syncUITree bool) {
 	newRoot.AddLeaf(leaf)
 
 	l := len(h.HircObjs)
-	h.HircObjs = slices.DeleteFunc(h.HircObjs, func(h HircObj) bool {
-		tid, err := h.HircID()
-		if err != nil {
-			return false
-		}
-		return tid == id
-	})
-	if l <= len(h.HircObjs) {
+	subtree := h.takeSubtree(id)
+	if len(subtree) == 0 {
 		panic(fmt.Sprintf("%d does not exists in the HIRC", id))
 	}
 
@@ -283,9 +323,8 @@ func (h *HIRC) ChangeRoot(id, newRootID, oldRootID uint32, syncUITree bool) {
 		panic(fmt.Sprintf("%d does not exists in the HIRC", newRootID))
 	}
 
-	l = len(h.HircObjs)
-	h.HircObjs = slices.Insert(h.HircObjs, newRootIdx, leaf)
-	if l >= len(h.HircObjs) {
+	h.HircObjs = slices.Insert(h.HircObjs, newRootIdx, subtree...)
+	if l != len(h.HircObjs) {
 		panic(fmt.Sprintf("%d does not added back to the HIRC", id))
 	}
 
@@ -313,10 +352,6 @@ func (h *HIRC) RemoveRoot(id, oldRootID uint32, syncUITree bool) {
 	if b := leaf.BaseParameter(); b == nil {
 		panic(fmt.Sprintf("%d is not containable", id))
 	}
-	if leaf.HircType() != HircTypeSound {
-		slog.Warn("Parent rewiring is only supported for Sound right now")
-		return
-	}
 	_, err := leaf.HircID()
 	if err != nil {
 		panic(fmt.Sprintf("ID %d has an associated hiearchy but its HircID interface returns error.", id))
@@ -344,14 +379,8 @@ func (h *HIRC) RemoveRoot(id, oldRootID uint32, syncUITree bool) {
 
 		oldRoot.RemoveLeaf(leaf)
 		l := len(h.HircObjs)
-		h.HircObjs = slices.DeleteFunc(h.HircObjs, func(h HircObj) bool {
-			tid, err := h.HircID()
-			if err != nil {
-				return false
-			}
-			return tid == id
-		})
-		if l <= len(h.HircObjs) {
+		subtree := h.takeSubtree(id)
+		if len(subtree) == 0 {
 			panic(fmt.Sprintf("%d does not exists in the HIRC", id))
 		}
 
@@ -359,13 +388,12 @@ func (h *HIRC) RemoveRoot(id, oldRootID uint32, syncUITree bool) {
 		idx = slices.IndexFunc(h.HircObjs, func(h HircObj) bool {
 			return h.HircType() == HircTypeAction
 		})
-		l = len(h.HircObjs)
 		if idx != -1 {
-			h.HircObjs = slices.Insert(h.HircObjs, idx, leaf)
+			h.HircObjs = slices.Insert(h.HircObjs, idx, subtree...)
 		} else {
-			h.HircObjs = append(h.HircObjs, leaf)
+			h.HircObjs = append(h.HircObjs, subtree...)
 		}
-		if l >= len(h.HircObjs) {
+		if l != len(h.HircObjs) {
 			panic(fmt.Sprintf("%d does not added back to the HIRC", id))
 		}
 	}
@@ -442,28 +470,147 @@ func (h *HIRC) AppendNewRanSeqCntrToActorMixer(r *RanSeqCntr, actorId uint32, sy
 	return nil
 }
 
+// Prototyping
+func (h *HIRC) AppendNewSwitchCntrToActorMixer(s *SwitchCntr, actorId uint32, syncUITree bool) error {
+	if s.BaseParam.DirectParentId != 0 {
+		return fmt.Errorf("Switch Container %d already has a parent", s.Id)
+	}
+	idx := h.TreeArrIdx(actorId)
+	if idx == -1 {
+		return fmt.Errorf("No Actor Mixer has ID of %d", actorId)
+	}
+	mixer, ok := h.HircObjs[idx].(*ActorMixer)
+	if !ok {
+		return fmt.Errorf("Hierarchy object %d is not an actor mixer", actorId)
+	}
+	mixer.AddLeaf(s)
+	h.HircObjs = slices.Insert(h.HircObjs, idx, HircObj(s))
+	_, in := h.ActorMixerHirc.LoadOrStore(s.Id, s)
+	if in {
+		panic(fmt.Sprintf("Switch container %d already exist!", s.Id))
+	}
+	if syncUITree {
+		h.BuildTree()
+	}
+	return nil
+}
+
+// Prototyping
+func (h *HIRC) AppendNewRanSeqCntrToSwitchCntr(r *RanSeqCntr, switchCntrId uint32, syncUITree bool) error {
+	if r.BaseParam.DirectParentId != 0 {
+		return fmt.Errorf("Random / Sequence Container %d already has a parent", r.Id)
+	}
+	idx := h.TreeArrIdx(switchCntrId)
+	if idx == -1 {
+		return fmt.Errorf("No Switch Container has ID of %d", switchCntrId)
+	}
+	s, ok := h.HircObjs[idx].(*SwitchCntr)
+	if !ok {
+		return fmt.Errorf("Hierarchy object %d is not a switch container", switchCntrId)
+	}
+	s.AddLeaf(r)
+	h.HircObjs = slices.Insert(h.HircObjs, idx, HircObj(r))
+	_, in := h.ActorMixerHirc.LoadOrStore(r.Id, r)
+	if in {
+		panic(fmt.Sprintf("Randome / Sequence object %d already exist!", r.Id))
+	}
+	if syncUITree {
+		h.BuildTree()
+	}
+	return nil
+}
+
+// Prototyping
+func (h *HIRC) AppendNewMusicSegmentToMusicCntr(m *MusicSegment, cntrId uint32, syncUITree bool) error {
+	if m.BaseParam.DirectParentId != 0 {
+		return fmt.Errorf("Music Segment %d already has a parent", m.Id)
+	}
+	idx := h.TreeArrIdx(cntrId)
+	if idx == -1 {
+		return fmt.Errorf("No Music Container has ID of %d", cntrId)
+	}
+	switch cntr := h.HircObjs[idx].(type) {
+	case *MusicRanSeqCntr, *MusicSwitchCntr:
+		cntr.AddLeaf(m)
+	default:
+		return fmt.Errorf("Hierarchy object %d is not a music random / sequence container or a music switch container", cntrId)
+	}
+	h.HircObjs = slices.Insert(h.HircObjs, idx, HircObj(m))
+	_, in := h.MusicHirc.LoadOrStore(m.Id, m)
+	if in {
+		panic(fmt.Sprintf("Music segment %d already exist!", m.Id))
+	}
+	if syncUITree {
+		h.BuildTree()
+	}
+	return nil
+}
+
+// Prototyping
+func (h *HIRC) AppendNewMusicTrackToMusicSegment(t *MusicTrack, segmentId uint32, syncUITree bool) error {
+	if t.BaseParam.DirectParentId != 0 {
+		return fmt.Errorf("Music Track %d already has a parent", t.Id)
+	}
+	idx := h.TreeArrIdx(segmentId)
+	if idx == -1 {
+		return fmt.Errorf("No Music Segment has ID of %d", segmentId)
+	}
+	m, ok := h.HircObjs[idx].(*MusicSegment)
+	if !ok {
+		return fmt.Errorf("Hierarchy object %d is not a music segment", segmentId)
+	}
+	m.AddLeaf(t)
+	h.HircObjs = slices.Insert(h.HircObjs, idx, HircObj(t))
+	_, in := h.MusicHirc.LoadOrStore(t.Id, t)
+	if in {
+		panic(fmt.Sprintf("Music track %d already exist!", t.Id))
+	}
+	if syncUITree {
+		h.BuildTree()
+	}
+	return nil
+}
+
 // Prototyping
 func (h *HIRC) AppendNewActionToEvent(a *Action, eventID uint32) error {
-	idx := slices.IndexFunc(h.HircObjs, func(o HircObj) bool {
-		id, err := o.HircID()
-		if err != nil {
-			return false
-		}
-		return eventID == id
-	})
+	idx := h.TreeArrIdx(eventID)
 	if idx == -1 {
 		return fmt.Errorf("No Event has ID of %d", eventID)
 	}
-	event := h.HircObjs[idx].(*Event)
+	event, ok := h.HircObjs[idx].(*Event)
+	if !ok {
+		return fmt.Errorf("Hierarchy object %d is not an event", eventID)
+	}
+	if _, in := h.Actions.Load(a.Id); in {
+		return fmt.Errorf("Action object %d already exist", a.Id)
+	}
 	event.NewAction(a.Id)
 	h.HircObjs = slices.Insert(h.HircObjs, idx, HircObj(a))
-	_, in := h.ActorMixerHirc.LoadOrStore(a.Id, a)
-	if in {
-		panic(fmt.Sprintf("Action object %d already exist!", a.Id))
-	}
+	h.Actions.Store(a.Id, a)
 	return event.NumActionIDs.Set(uint64(len(event.ActionIDs)))
 }
 
+// Prototyping
+// Events are placed after the last event since actions of an event are
+// placed before it.
+func (h *HIRC) AppendNewEvent(e *Event) error {
+	if _, in := h.Events.Load(e.Id); in {
+		return fmt.Errorf("Event %d already exist", e.Id)
+	}
+	if h.TreeArrIdx(e.Id) != -1 {
+		return fmt.Errorf("Hierarchy object %d already exist", e.Id)
+	}
+	i := len(h.HircObjs)
+	for j, o := range h.HircObjs {
+		if o.HircType() == HircTypeEvent {
+			i = j + 1
+		}
+	}
+	h.HircObjs = slices.Insert(h.HircObjs, i, HircObj(e))
+	h.Events.Store(e.Id, e)
+	return nil
+}
+
 // Prototyping
 func (h *HIRC) AppendNewAttenuation(a *Attenuation) {
 	firstAttenuation := slices.IndexFunc(h.HircObjs, func(o HircObj) bool {
@@ -481,3 +628,815 @@ func (h *HIRC) AppendNewAttenuation(a *Attenuation) {
 		panic(fmt.Sprintf("Attenuation object %d already exist!", a.Id))
 	}
 }
+
+// Prototyping
+func (h *HIRC) AppendNewFx(o HircObj) error {
+	id, _ := o.HircID()
+	var m *sync.Map
+	switch o.(type) {
+	case *FxShareSet:
+		m = &h.FxShareSets
+	case *FxCustom:
+		m = &h.FxCustoms
+	default:
+		return fmt.Errorf("Hierarchy object %d is not a FX share set or a FX custom", id)
+	}
+	if _, in := m.Load(id); in {
+		return fmt.Errorf("FX %d already exist", id)
+	}
+	firstFx := slices.IndexFunc(h.HircObjs, FxHircType)
+	if firstFx == -1 {
+		firstFx = 0
+	}
+	h.HircObjs = slices.Insert(h.HircObjs, firstFx, o)
+	m.Store(id, o)
+	return nil
+}
+
+// Remove a FX share set or a FX custom. It does not check whether the FX is
+// still referenced. Use RemoveFxIfUnreferenced for that.
+func (h *HIRC) RemoveFx(id uint32) bool {
+	removed := false
+	h.HircObjs = slices.DeleteFunc(h.HircObjs, func(o HircObj) bool {
+		if !FxHircType(o) {
+			return false
+		}
+		oid, _ := o.HircID()
+		if oid == id {
+			removed = true
+			return true
+		}
+		return false
+	})
+	h.FxShareSets.Delete(id)
+	h.FxCustoms.Delete(id)
+	return removed
+}
+
+// Remove a FX share set or a FX custom if no hierarchy object references it.
+func (h *HIRC) RemoveFxIfUnreferenced(id uint32, v int) bool {
+	if len(h.BuildXRef(v).References(id)) > 0 {
+		return false
+	}
+	return h.RemoveFx(id)
+}
+
+// Return FX chunk of a hierarchy object with base parameter, a bus or an
+// auxiliary bus.
+func (h *HIRC) FxChunkOf(id uint32) (*FxChunk, error) {
+	var o HircObj
+	for _, m := range []*sync.Map{&h.ActorMixerHirc, &h.MusicHirc, &h.Buses, &h.AuxBuses} {
+		if v, in := m.Load(id); in {
+			o = v.(HircObj)
+			break
+		}
+	}
+	if o == nil {
+		return nil, fmt.Errorf("No hierarchy object, bus or auxiliary bus has ID %d", id)
+	}
+	switch o := o.(type) {
+	case *Bus:
+		return &o.BusFxParam.FxChunk, nil
+	case *AuxBus:
+		return &o.BusFxParam.FxChunk, nil
+	}
+	if b := o.BaseParameter(); b != nil {
+		return &b.FxChunk, nil
+	}
+	return nil, fmt.Errorf("%s %d does not have FX", HircTypeName[o.HircType()], id)
+}
+
+// Insert an existing FX into a FX slot of a hierarchy object or a bus. A
+// hierarchy object with a parent will override parent FX.
+func (h *HIRC) AttachFx(id uint32, slot uint8, fxID uint32, v int) error {
+	shareSet := false
+	if _, in := h.FxShareSets.Load(fxID); in {
+		shareSet = true
+	} else if _, in := h.FxCustoms.Load(fxID); !in {
+		return fmt.Errorf("No FX share set or FX custom has ID %d", fxID)
+	}
+	f, err := h.FxChunkOf(id)
+	if err != nil {
+		return err
+	}
+	if err := f.AddFx(slot, fxID, shareSet, v); err != nil {
+		return err
+	}
+	for _, m := range []*sync.Map{&h.ActorMixerHirc, &h.MusicHirc} {
+		if o, in := m.Load(id); in {
+			if b := o.(HircObj).BaseParameter(); b != nil && b.DirectParentId != 0 {
+				b.BitIsOverrideParentFx = 1
+			}
+		}
+	}
+	return nil
+}
+
+// Remove a FX from a FX slot of a hierarchy object or a bus, and remove the FX
+// itself if nothing else references it. Return the ID of the detached FX.
+func (h *HIRC) DetachFx(id uint32, slot uint8, v int) (uint32, error) {
+	f, err := h.FxChunkOf(id)
+	if err != nil {
+		return 0, err
+	}
+	fxID, ok := f.RemoveFx(slot)
+	if !ok {
+		return 0, fmt.Errorf("FX slot %d of %d is empty", slot, id)
+	}
+	h.RemoveFxIfUnreferenced(fxID, v)
+	return fxID, nil
+}
+
+func (h *HIRC) modulatorMap(t HircType) *sync.Map {
+	switch t {
+	case HircTypeLFOModulator:
+		return &h.LFOModulators
+	case HircTypeEnvelopeModulator:
+		return &h.EnvelopeModulator
+	case HircTypeTimeModulator:
+		return &h.TimeModulator
+	}
+	return nil
+}
+
+func (h *HIRC) Modulator(id uint32) (*Modulator, bool) {
+	for _, t := range []HircType{HircTypeLFOModulator, HircTypeEnvelopeModulator, HircTypeTimeModulator} {
+		if v, in := h.modulatorMap(t).Load(id); in {
+			return v.(*Modulator), true
+		}
+	}
+	return nil, false
+}
+
+// Prototyping
+// Modulators are placed before hierarchy objects since they are referenced by
+// RTPC of hierarchy objects.
+func (h *HIRC) AppendNewModulator(m *Modulator) error {
+	mm := h.modulatorMap(m.ModulatorType)
+	if mm == nil {
+		return fmt.Errorf("%s is not a modulator type", HircTypeName[m.ModulatorType])
+	}
+	if _, in := h.Modulator(m.Id); in {
+		return fmt.Errorf("Modulator %d already exist", m.Id)
+	}
+	i := slices.IndexFunc(h.HircObjs, ModulatorType)
+	if i == -1 {
+		i = 0
+	}
+	h.HircObjs = slices.Insert(h.HircObjs, i, HircObj(m))
+	mm.Store(m.Id, m)
+	return nil
+}
+
+func (h *HIRC) rtpcOwner(id uint32) (HircObj, *RTPC, error) {
+	var o HircObj
+	for _, m := range []*sync.Map{&h.ActorMixerHirc, &h.MusicHirc, &h.Buses, &h.AuxBuses, &h.Attenuations} {
+		if v, in := m.Load(id); in {
+			o = v.(HircObj)
+			break
+		}
+	}
+	if o == nil {
+		return nil, nil, fmt.Errorf("No hierarchy object, bus, auxiliary bus or attenuation has ID %d", id)
+	}
+	switch o := o.(type) {
+	case *Bus:
+		return o, &o.BusRTPC, nil
+	case *AuxBus:
+		return o, &o.BusRTPC, nil
+	case *Attenuation:
+		return o, &o.RTPC, nil
+	}
+	if b := o.BaseParameter(); b != nil {
+		return o, &b.RTPC, nil
+	}
+	return nil, nil, fmt.Errorf("%s %d does not have RTPC", HircTypeName[o.HircType()], id)
+}
+
+// RTPC of a hierarchy object, a bus or an attenuation
+func (h *HIRC) RTPCOf(id uint32) (*RTPC, error) {
+	_, r, err := h.rtpcOwner(id)
+	return r, err
+}
+
+// Prototyping
+// Add a RTPC item to a hierarchy object, a bus or an attenuation. The RTPC
+// parameter must be available on that kind of object in this bank version.
+func (h *HIRC) AddRTPC(id uint32, item RTPCItem, v int) error {
+	o, r, err := h.rtpcOwner(id)
+	if err != nil {
+		return err
+	}
+	if err := CheckRTPCParameter(o, RTPCParameterType(item.ParamID.Value), v); err != nil {
+		return err
+	}
+	if item.RTPCType >= RTPCTypeCount {
+		return fmt.Errorf("Invalid RTPC type %d", item.RTPCType)
+	}
+	if item.RTPCType == RTPCTypeModulator {
+		if _, in := h.Modulator(item.RTPCID); !in {
+			return fmt.Errorf("No modulator has ID %d", item.RTPCID)
+		}
+	}
+	if item.RTPCID == 0 {
+		return fmt.Errorf("RTPC ID must not be 0")
+	}
+	return r.AddRTPCItem(item)
+}
+
+// Prototyping
+// Drive a parameter of a hierarchy object or a bus with an existing modulator.
+// The modulator output is mapped linearly from min to max.
+func (h *HIRC) BindModulator(id uint32, modulatorID uint32, curveID uint32, p RTPCParameterType, min float32, max float32, v int) error {
+	item, err := NewModulatorRTPCItem(modulatorID, curveID, p, min, max)
+	if err != nil {
+		return err
+	}
+	return h.AddRTPC(id, item, v)
+}
+
+// IDs of game parameters referenced in this sound bank (RTPC, set game
+// parameter actions, etc.) in ascending order
+func (h *HIRC) GameParameterIDs(v int) []uint32 {
+	return h.referencedIDs(RefTypeGameParameter, v)
+}
+
+// IDs of state groups referenced in this sound bank (state groups of objects,
+// set state actions, etc.) in ascending order
+func (h *HIRC) StateGroupIDs(v int) []uint32 {
+	return h.referencedIDs(RefTypeStateGroup, v)
+}
+
+func (h *HIRC) referencedIDs(t RefType, v int) []uint32 {
+	x := h.BuildXRef(v)
+	ids := []uint32{}
+	for id := range x.Refs {
+		if len(x.ReferencesOf(id, t)) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func stateComponents(o HircObj) (*StateProp, *StateGroup) {
+	switch o := o.(type) {
+	case *Bus:
+		return &o.StateProp, &o.StateGroup
+	case *AuxBus:
+		return &o.StateProp, &o.StateGroup
+	case *FxShareSet:
+		return &o.StateProp, &o.StateGroup
+	case *FxCustom:
+		return &o.StateProp, &o.StateGroup
+	}
+	if b := o.BaseParameter(); b != nil {
+		return &b.StateProp, &b.StateGroup
+	}
+	return nil, nil
+}
+
+func (h *HIRC) stateOwner(id uint32) (HircObj, *StateProp, *StateGroup, error) {
+	var o HircObj
+	for _, m := range []*sync.Map{&h.ActorMixerHirc, &h.MusicHirc, &h.Buses, &h.AuxBuses} {
+		if v, in := m.Load(id); in {
+			o = v.(HircObj)
+			break
+		}
+	}
+	if o == nil {
+		return nil, nil, nil, fmt.Errorf("No hierarchy object, bus or auxiliary bus has ID %d", id)
+	}
+	sp, sg := stateComponents(o)
+	if sg == nil {
+		return nil, nil, nil, fmt.Errorf("%s %d does not have state group", HircTypeName[o.HircType()], id)
+	}
+	return o, sp, sg, nil
+}
+
+// State group of a hierarchy object, a bus or an auxiliary bus
+func (h *HIRC) StateGroupOf(id uint32) (*StateProp, *StateGroup, error) {
+	_, sp, sg, err := h.stateOwner(id)
+	return sp, sg, err
+}
+
+// Prototyping
+func (h *HIRC) AppendNewState(s *State) error {
+	if _, in := h.States.Load(s.StateID); in {
+		return fmt.Errorf("State %d already exist", s.StateID)
+	}
+	i := slices.IndexFunc(h.HircObjs, func(o HircObj) bool {
+		return o.HircType() == HircTypeState
+	})
+	if i == -1 {
+		i = 0
+	}
+	h.HircObjs = slices.Insert(h.HircObjs, i, HircObj(s))
+	h.States.Store(s.StateID, s)
+	return nil
+}
+
+// Remove a state instance if no state of any object uses it.
+func (h *HIRC) removeStateIfUnused(instanceID uint32) {
+	if instanceID == 0 {
+		return
+	}
+	for _, o := range h.HircObjs {
+		_, sg := stateComponents(o)
+		if sg == nil {
+			continue
+		}
+		for _, g := range sg.StateGroupItems {
+			for _, s := range g.States {
+				if s.StateInstanceID == instanceID {
+					return
+				}
+			}
+		}
+	}
+	h.HircObjs = slices.DeleteFunc(h.HircObjs, func(o HircObj) bool {
+		if o.HircType() != HircTypeState {
+			return false
+		}
+		id, _ := o.HircID()
+		return id == instanceID
+	})
+	h.States.Delete(instanceID)
+}
+
+// IDs of states of a state group used by any object in ascending order
+func (h *HIRC) StateIDsOf(groupID uint32) []uint32 {
+	ids := []uint32{}
+	for _, o := range h.HircObjs {
+		_, sg := stateComponents(o)
+		if sg == nil {
+			continue
+		}
+		if g := sg.Group(groupID); g != nil {
+			for _, s := range g.States {
+				if !slices.Contains(ids, s.StateID) {
+					ids = append(ids, s.StateID)
+				}
+			}
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Prototyping
+func (h *HIRC) AddStateGroup(id uint32, groupID uint32, syncType uint8) error {
+	_, _, sg, err := h.stateOwner(id)
+	if err != nil {
+		return err
+	}
+	return sg.AddGroup(groupID, syncType)
+}
+
+// Prototyping
+// Remove a state group and the state instances (version <= 145) only it uses.
+func (h *HIRC) RemoveStateGroup(id uint32, groupID uint32) error {
+	_, _, sg, err := h.stateOwner(id)
+	if err != nil {
+		return err
+	}
+	g := sg.Group(groupID)
+	if g == nil {
+		return fmt.Errorf("%d does not have state group %d", id, groupID)
+	}
+	states := g.States
+	sg.RemoveGroup(groupID)
+	for _, s := range states {
+		h.removeStateIfUnused(s.StateInstanceID)
+	}
+	return nil
+}
+
+// Prototyping
+func (h *HIRC) SetStateSyncType(id uint32, groupID uint32, syncType uint8) error {
+	_, _, sg, err := h.stateOwner(id)
+	if err != nil {
+		return err
+	}
+	g := sg.Group(groupID)
+	if g == nil {
+		return fmt.Errorf("%d does not have state group %d", id, groupID)
+	}
+	if syncType >= NumSyncType {
+		return fmt.Errorf("Invalid sync type %d", syncType)
+	}
+	g.StateSyncType = syncType
+	return nil
+}
+
+// Prototyping
+// Add a state to a state group of an object. For version <= 145, property
+// values of a state are stored in a new State hierarchy object instanceID.
+// Otherwise, instanceID is ignored.
+func (h *HIRC) AddState(id uint32, groupID uint32, stateID uint32, instanceID uint32, v int) error {
+	_, _, sg, err := h.stateOwner(id)
+	if err != nil {
+		return err
+	}
+	g := sg.Group(groupID)
+	if g == nil {
+		return fmt.Errorf("%d does not have state group %d", id, groupID)
+	}
+	if v > 145 {
+		return g.AddState(stateID, 0)
+	}
+	if instanceID == 0 {
+		return fmt.Errorf("State instance ID must not be 0")
+	}
+	if g.State(stateID) != nil {
+		return fmt.Errorf("State %d already exist in state group %d", stateID, groupID)
+	}
+	if err := h.AppendNewState(NewState(instanceID)); err != nil {
+		return err
+	}
+	return g.AddState(stateID, instanceID)
+}
+
+// Prototyping
+func (h *HIRC) RemoveState(id uint32, groupID uint32, stateID uint32) error {
+	_, _, sg, err := h.stateOwner(id)
+	if err != nil {
+		return err
+	}
+	g := sg.Group(groupID)
+	if g == nil {
+		return fmt.Errorf("%d does not have state group %d", id, groupID)
+	}
+	s, removed := g.RemoveState(stateID)
+	if !removed {
+		return fmt.Errorf("State group %d does not have state %d", groupID, stateID)
+	}
+	h.removeStateIfUnused(s.StateInstanceID)
+	return nil
+}
+
+// Property value of a state. It returns false if the state does not change
+// this property.
+func (h *HIRC) StateValue(s *StateGroupItemState, p RTPCParameterType, v int) (float32, bool) {
+	if v > 145 {
+		return s.StatePropBundle.Value(StatePropType(p))
+	}
+	if inst, in := h.States.Load(s.StateInstanceID); in {
+		return inst.(*State).Value(uint16(p))
+	}
+	return 0, false
+}
+
+// Prototyping
+// Set a property value of a state. The property is allowed to change with
+// states if it is not already.
+func (h *HIRC) SetStateProp(id uint32, groupID uint32, stateID uint32, p RTPCParameterType, val float32, v int) error {
+	o, sp, sg, err := h.stateOwner(id)
+	if err != nil {
+		return err
+	}
+	if err := CheckRTPCParameter(o, p, v); err != nil {
+		return err
+	}
+	g := sg.Group(groupID)
+	if g == nil {
+		return fmt.Errorf("%d does not have state group %d", id, groupID)
+	}
+	s := g.State(stateID)
+	if s == nil {
+		return fmt.Errorf("State group %d does not have state %d", groupID, stateID)
+	}
+	if v > 145 {
+		s.StatePropBundle.Set(StatePropType(p), val)
+	} else {
+		inst, in := h.States.Load(s.StateInstanceID)
+		if !in {
+			return fmt.Errorf("No state instance has ID %d", s.StateInstanceID)
+		}
+		inst.(*State).SetValue(uint16(p), val)
+	}
+	return sp.Add(p)
+}
+
+// Prototyping
+// Allow a property of an object to change with states.
+func (h *HIRC) AddStateProp(id uint32, p RTPCParameterType, v int) error {
+	o, sp, _, err := h.stateOwner(id)
+	if err != nil {
+		return err
+	}
+	if err := CheckRTPCParameter(o, p, v); err != nil {
+		return err
+	}
+	return sp.Add(p)
+}
+
+// Prototyping
+// Stop a property from changing with states, and remove its value from every
+// state.
+func (h *HIRC) RemoveStateProp(id uint32, p RTPCParameterType, v int) error {
+	_, sp, sg, err := h.stateOwner(id)
+	if err != nil {
+		return err
+	}
+	sp.Remove(p)
+	for i := range sg.StateGroupItems {
+		for j := range sg.StateGroupItems[i].States {
+			s := &sg.StateGroupItems[i].States[j]
+			if v > 145 {
+				s.StatePropBundle.Remove(StatePropType(p))
+			} else if inst, in := h.States.Load(s.StateInstanceID); in {
+				inst.(*State).RemoveValue(uint16(p))
+			}
+		}
+	}
+	return nil
+}
+
+// Bus or auxiliary bus
+func (h *HIRC) Bus(id uint32) (HircObj, bool) {
+	if v, in := h.Buses.Load(id); in {
+		return v.(HircObj), true
+	}
+	if v, in := h.AuxBuses.Load(id); in {
+		return v.(HircObj), true
+	}
+	return nil, false
+}
+
+func busParentID(o HircObj) uint32 {
+	switch b := o.(type) {
+	case *Bus:
+		return b.OverrideBusId
+	case *AuxBus:
+		return b.OverrideBusId
+	}
+	return 0
+}
+
+func busIsHDR(o HircObj) bool {
+	switch b := o.(type) {
+	case *Bus:
+		return b.IsHDRBus()
+	case *AuxBus:
+		return b.IsHDRBus()
+	}
+	return false
+}
+
+// Whether bus id is bus ancestorID or one of its descendants
+func (h *HIRC) busUnder(id uint32, ancestorID uint32) bool {
+	for id != 0 {
+		if id == ancestorID {
+			return true
+		}
+		o, in := h.Bus(id)
+		if !in {
+			return false
+		}
+		id = busParentID(o)
+	}
+	return false
+}
+
+// Whether HDR is enabled in bus id or any of its descendants
+func (h *HIRC) busTreeHasHDR(id uint32) bool {
+	for _, o := range h.HircObjs {
+		if !BusHircType(o) || !busIsHDR(o) {
+			continue
+		}
+		bid, _ := o.HircID()
+		if h.busUnder(bid, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether HDR is enabled in any ancestor of a bus whose parent is parentID
+func (h *HIRC) hdrAbove(parentID uint32) bool {
+	return h.CanSetHDR(&Bus{}, parentID) == 0
+}
+
+// Prototyping
+// Add a new bus or auxiliary bus under an existing parent bus. The bus
+// hierarchy tree and HDR availability are rebuilt.
+func (h *HIRC) AppendNewBus(o HircObj) error {
+	if !BusHircType(o) {
+		return fmt.Errorf("%s is not a bus type", HircTypeName[o.HircType()])
+	}
+	id, _ := o.HircID()
+	parentID := busParentID(o)
+	if h.TreeArrIdx(id) != -1 {
+		return fmt.Errorf("Hierarchy object %d already exist", id)
+	}
+	if _, in := h.Bus(parentID); !in {
+		return fmt.Errorf("No bus or auxiliary bus has ID %d", parentID)
+	}
+	if busIsHDR(o) && h.hdrAbove(parentID) {
+		return fmt.Errorf("HDR is enabled in a parent bus of %d", id)
+	}
+	i := len(h.HircObjs)
+	for j, o := range h.HircObjs {
+		if BusHircType(o) {
+			i = j + 1
+		}
+	}
+	h.HircObjs = slices.Insert(h.HircObjs, i, o)
+	switch b := o.(type) {
+	case *Bus:
+		h.Buses.Store(b.Id, b)
+	case *AuxBus:
+		h.AuxBuses.Store(b.Id, b)
+	}
+	h.BuildTree()
+	h.HDRAvailability()
+	return nil
+}
+
+// Prototyping
+// Move a bus or an auxiliary bus under another parent bus. The bus hierarchy
+// tree and HDR availability are rebuilt.
+func (h *HIRC) SetBusParent(id uint32, parentID uint32) error {
+	o, in := h.Bus(id)
+	if !in {
+		return fmt.Errorf("No bus or auxiliary bus has ID %d", id)
+	}
+	if busParentID(o) == 0 {
+		return fmt.Errorf("Bus %d is a master bus", id)
+	}
+	if _, in := h.Bus(parentID); !in {
+		return fmt.Errorf("No bus or auxiliary bus has ID %d", parentID)
+	}
+	if h.busUnder(parentID, id) {
+		return fmt.Errorf("Bus %d cannot be moved under itself or its descendant %d", id, parentID)
+	}
+	if h.busTreeHasHDR(id) && h.hdrAbove(parentID) {
+		return fmt.Errorf("HDR is enabled in both %d (or its descendants) and a parent bus of %d", id, parentID)
+	}
+	switch b := o.(type) {
+	case *Bus:
+		b.OverrideBusId = parentID
+	case *AuxBus:
+		b.OverrideBusId = parentID
+	}
+	h.BuildTree()
+	h.HDRAvailability()
+	return nil
+}
+
+// Prototyping
+// Enable or disable HDR of a bus. HDR cannot be nested.
+func (h *HIRC) SetHDRBus(id uint32, set bool) error {
+	v, in := h.Buses.Load(id)
+	if !in {
+		return fmt.Errorf("No bus has ID %d", id)
+	}
+	b := v.(*Bus)
+	if set {
+		if h.CanSetHDR(b, b.OverrideBusId) == 0 {
+			return fmt.Errorf("HDR is enabled in a parent bus of %d", id)
+		}
+		if !b.IsHDRBus() && h.busTreeHasHDR(id) {
+			return fmt.Errorf("HDR is enabled in a child bus of %d", id)
+		}
+	}
+	b.SetHDRBus(set)
+	h.HDRAvailability()
+	return nil
+}
+
+// Prototyping
+// Route a hierarchy object to an output bus. Bus outside of this sound bank
+// (e.g. in Init.bnk) is allowed. A hierarchy object with a parent can use
+// 0 to stop overriding the output bus of its parent.
+func (h *HIRC) SetOutputBus(id uint32, busID uint32) error {
+	var o HircObj
+	for _, m := range []*sync.Map{&h.ActorMixerHirc, &h.MusicHirc} {
+		if v, in := m.Load(id); in {
+			o = v.(HircObj)
+			break
+		}
+	}
+	if o == nil || o.BaseParameter() == nil {
+		return fmt.Errorf("No hierarchy object with base parameter has ID %d", id)
+	}
+	b := o.BaseParameter()
+	if busID == 0 && b.DirectParentId == 0 {
+		return fmt.Errorf("%s %d does not have parent and must have an output bus", HircTypeName[o.HircType()], id)
+	}
+	if busID != 0 {
+		if _, in := h.Bus(busID); !in && h.TreeArrIdx(busID) != -1 {
+			return fmt.Errorf("%d is not a bus or an auxiliary bus", busID)
+		}
+	}
+	b.OverrideBusId = busID
+	return nil
+}
+
+func (h *HIRC) auxSendOwner(id uint32) (*AuxParam, *PropBundle, *BaseParameter, error) {
+	if o, in := h.Bus(id); in {
+		switch b := o.(type) {
+		case *Bus:
+			return &b.AuxParam, &b.PropBundle, nil, nil
+		case *AuxBus:
+			return &b.AuxParam, &b.PropBundle, nil, nil
+		}
+	}
+	for _, m := range []*sync.Map{&h.ActorMixerHirc, &h.MusicHirc} {
+		if v, in := m.Load(id); in {
+			if b := v.(HircObj).BaseParameter(); b != nil {
+				return &b.AuxParam, &b.PropBundle, b, nil
+			}
+		}
+	}
+	return nil, nil, nil, fmt.Errorf("No hierarchy object, bus or auxiliary bus has ID %d", id)
+}
+
+// Prototyping
+// Send a hierarchy object or a bus to an auxiliary bus through a
+// user-defined auxiliary send slot with a send volume. Auxiliary bus outside
+// of this sound bank is allowed. Use 0 to clear the slot. A hierarchy object
+// with a parent will override parent user-defined auxiliary sends.
+func (h *HIRC) SetAuxSend(id uint32, slot uint8, auxBusID uint32, volume float32, v int) error {
+	if int(slot) >= len(AuxParam{}.AuxIds) {
+		return fmt.Errorf("User-defined auxiliary send slot %d is out of range", slot)
+	}
+	a, p, b, err := h.auxSendOwner(id)
+	if err != nil {
+		return err
+	}
+	if auxBusID != 0 {
+		if _, in := h.AuxBuses.Load(auxBusID); !in && h.TreeArrIdx(auxBusID) != -1 {
+			return fmt.Errorf("%d is not an auxiliary bus", auxBusID)
+		}
+	}
+	if b != nil && b.DirectParentId != 0 && !a.OverrideAuxSends() {
+		b.SetOverrideAuxSends(true, v)
+	}
+	prop := TUserAuxSendVolume0 + PropType(slot)
+	a.AuxIds[slot] = auxBusID
+	if auxBusID == 0 {
+		p.Remove(prop, v)
+		return nil
+	}
+	a.SetAux(true)
+	p.Add(prop, v)
+	i, _ := p.Prop(prop, v)
+	p.SetPropByIdxF32(i, volume)
+	return nil
+}
+
+func (h *HIRC) duckOwner(id uint32) (*[]DuckInfo, error) {
+	o, in := h.Bus(id)
+	if !in {
+		return nil, fmt.Errorf("No bus or auxiliary bus has ID %d", id)
+	}
+	switch b := o.(type) {
+	case *Bus:
+		return &b.DuckInfoList, nil
+	case *AuxBus:
+		return &b.DuckInfoList, nil
+	}
+	panic("Panic Trap")
+}
+
+// Prototyping
+// Duck bus d.BusID when bus id is playing
+func (h *HIRC) AddDuck(id uint32, d DuckInfo) error {
+	ducks, err := h.duckOwner(id)
+	if err != nil {
+		return err
+	}
+	if d.BusID == id {
+		return fmt.Errorf("Bus %d cannot duck itself", id)
+	}
+	if _, in := h.Bus(d.BusID); !in {
+		return fmt.Errorf("No bus or auxiliary bus has ID %d", d.BusID)
+	}
+	if slices.ContainsFunc(*ducks, func(e DuckInfo) bool { return e.BusID == d.BusID }) {
+		return fmt.Errorf("Bus %d already ducks %d", id, d.BusID)
+	}
+	if d.EnumFadeCurve >= InterpCurveTypeCount {
+		return fmt.Errorf("Invalid fade curve %d", d.EnumFadeCurve)
+	}
+	if d.FadeInTime < 0 || d.FadeOutTime < 0 {
+		return fmt.Errorf("Fade time must not be negative")
+	}
+	*ducks = append(*ducks, d)
+	return nil
+}
+
+// Prototyping
+func (h *HIRC) RemoveDuck(id uint32, busID uint32) error {
+	ducks, err := h.duckOwner(id)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(*ducks, func(e DuckInfo) bool { return e.BusID == busID })
+	if i == -1 {
+		return fmt.Errorf("Bus %d does not duck %d", id, busID)
+	}
+	*ducks = slices.Delete(*ducks, i, i + 1)
+	return nil
+}
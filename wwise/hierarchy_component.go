@@ -4,6 +4,7 @@ package wwise
 
 import (
 	"encoding/binary"
+	"fmt"
 	"slices"
 
 	"github.com/Dekr0/wwise-teller/wio"
@@ -183,6 +184,40 @@ func (s *StateProp) Size(v int) uint32 {
 	return size
 }
 
+func (s *StateProp) Index(p RTPCParameterType) int {
+	return slices.IndexFunc(s.StatePropItems, func(i StatePropItem) bool {
+		return i.PropertyId.Value == uint64(p)
+	})
+}
+
+// Allow states to change a property. Volume like properties are in dB.
+func (s *StateProp) Add(p RTPCParameterType) error {
+	if s.Index(p) != -1 {
+		return nil
+	}
+	item := StatePropItem{AccumType: DefaultRTPCAccum(p)}
+	if err := item.PropertyId.Set(uint64(p)); err != nil {
+		return err
+	}
+	if DefaultCurveScaling(p) == CurveScalingTypeDb {
+		item.InDb = 1
+	}
+	s.StatePropItems = append(s.StatePropItems, item)
+	return s.NumStateProps.Set(uint64(len(s.StatePropItems)))
+}
+
+func (s *StateProp) Remove(p RTPCParameterType) bool {
+	i := s.Index(p)
+	if i == -1 {
+		return false
+	}
+	s.StatePropItems = slices.Delete(s.StatePropItems, i, i + 1)
+	if err := s.NumStateProps.Set(uint64(len(s.StatePropItems))); err != nil {
+		panic(err)
+	}
+	return true
+}
+
 type StatePropItem struct {
 	PropertyId wio.Var // var (at least 1 byte / 8 bits)
 	AccumType  RTPCAccumType // U8x
@@ -216,11 +251,57 @@ func (s *StateGroup) Clone() StateGroup {
 	for i := range s.StateGroupItems {
 		cs.StateGroupItems[i].StateGroupID = s.StateGroupItems[i].StateGroupID
 		cs.StateGroupItems[i].StateSyncType = s.StateGroupItems[i].StateSyncType
+		cs.StateGroupItems[i].NumStates = wio.Var{
+			Bytes: slices.Clone(s.StateGroupItems[i].NumStates.Bytes),
+			Value: s.StateGroupItems[i].NumStates.Value,
+		}
 		cs.StateGroupItems[i].States = slices.Clone(s.StateGroupItems[i].States)
+		for j := range cs.StateGroupItems[i].States {
+			cs.StateGroupItems[i].States[j].StatePropBundle = s.StateGroupItems[i].States[j].StatePropBundle.Clone()
+		}
 	}
 	return cs
 }
 
+func (s *StateGroup) Group(id uint32) *StateGroupItem {
+	for i := range s.StateGroupItems {
+		if s.StateGroupItems[i].StateGroupID == id {
+			return &s.StateGroupItems[i]
+		}
+	}
+	return nil
+}
+
+func (s *StateGroup) AddGroup(id uint32, syncType uint8) error {
+	if id == 0 {
+		return fmt.Errorf("State group ID must not be 0")
+	}
+	if syncType >= NumSyncType {
+		return fmt.Errorf("Invalid sync type %d", syncType)
+	}
+	if s.Group(id) != nil {
+		return fmt.Errorf("State group %d already exist", id)
+	}
+	item := StateGroupItem{StateGroupID: id, StateSyncType: syncType, States: []StateGroupItemState{}}
+	item.NumStates.Set(0)
+	s.StateGroupItems = append(s.StateGroupItems, item)
+	return s.NumStateGroups.Set(uint64(len(s.StateGroupItems)))
+}
+
+func (s *StateGroup) RemoveGroup(id uint32) bool {
+	i := slices.IndexFunc(s.StateGroupItems, func(g StateGroupItem) bool {
+		return g.StateGroupID == id
+	})
+	if i == -1 {
+		return false
+	}
+	s.StateGroupItems = slices.Delete(s.StateGroupItems, i, i + 1)
+	if err := s.NumStateGroups.Set(uint64(len(s.StateGroupItems))); err != nil {
+		panic(err)
+	}
+	return true
+}
+
 func (s *StateGroup) Encode(v int) []byte {
 	size := s.Size(v)
 	w := wio.NewWriter(uint64(size))
@@ -258,6 +339,48 @@ func (s * StateGroupItem) Encode(v int) []byte {
 	return w.BytesAssert(int(size))
 }
 
+func (s *StateGroupItem) State(id uint32) *StateGroupItemState {
+	for i := range s.States {
+		if s.States[i].StateID == id {
+			return &s.States[i]
+		}
+	}
+	return nil
+}
+
+// Add a state whose property values are different from the default. For
+// version <= 145, property values are stored in State hierarchy object
+// instanceID.
+func (s *StateGroupItem) AddState(id uint32, instanceID uint32) error {
+	if id == 0 {
+		return fmt.Errorf("State ID must not be 0")
+	}
+	if s.State(id) != nil {
+		return fmt.Errorf("State %d already exist in state group %d", id, s.StateGroupID)
+	}
+	s.States = append(s.States, StateGroupItemState{
+		StateID: id,
+		StateInstanceID: instanceID,
+		StatePropBundle: StatePropBundle{StatePropValues: []StatePropValue{}},
+	})
+	return s.NumStates.Set(uint64(len(s.States)))
+}
+
+func (s *StateGroupItem) RemoveState(id uint32) (StateGroupItemState, bool) {
+	i := slices.IndexFunc(s.States, func(state StateGroupItemState) bool {
+		return state.StateID == id
+	})
+	if i == -1 {
+		return StateGroupItemState{}, false
+	}
+	state := s.States[i]
+	s.States = slices.Delete(s.States, i, i + 1)
+	if err := s.NumStates.Set(uint64(len(s.States))); err != nil {
+		panic(err)
+	}
+	return state, true
+}
+
 func (s *StateGroupItem) Size(v int) uint32 {
 	size := 4 + 1 + uint32(len(s.NumStates.Bytes))
 	for _, s := range s.States {
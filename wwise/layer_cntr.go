@@ -1,7 +1,8 @@
 package wwise
 
 import (
-	"log/slog"
+	"fmt"
+	"slices"
 
 	"github.com/Dekr0/wwise-teller/wio"
 )
@@ -55,16 +56,105 @@ func (l *LayerCntr) NumLeaf() int { return len(l.Container.Children) }
 
 func (l *LayerCntr) ParentID() uint32 { return l.BaseParam.DirectParentId }
 
+// A new leaf is not associated with any layer.
 func (l *LayerCntr) AddLeaf(o HircObj) {
-	slog.Warn("Adding new leaf is not implemented for layer container.")
+	id, err := o.HircID()
+	if err != nil {
+		panic("Passing a hierarchy without a hierarchy ID.")
+	}
+	b := o.BaseParameter()
+	if b == nil {
+		panic(fmt.Sprintf("%d is not containable.", id))
+	}
+	if b.DirectParentId != 0 {
+		panic(fmt.Sprintf("%d is already attach to root %d. AddLeaf is an atomic operation.", id, b.DirectParentId))
+	}
+	if slices.Contains(l.Container.Children, id) {
+		panic(fmt.Sprintf("%d is already in layer container %d", id, l.Id))
+	}
+	l.Container.Children = append(l.Container.Children, id)
+	b.DirectParentId = l.Id
 }
 
+// The leaf is also unassociated from all layers.
 func (l *LayerCntr) RemoveLeaf(o HircObj) {
-	slog.Warn("Removing old leaf is not implemented for layer container.")
+	id, err := o.HircID()
+	if err != nil {
+		panic("Passing a hierarchy without a hierarchy ID.")
+	}
+	b := o.BaseParameter()
+	if b == nil {
+		panic(fmt.Sprintf("%d is not containable.", id))
+	}
+	c := len(l.Container.Children)
+	l.Container.Children = slices.DeleteFunc(l.Container.Children, func(c uint32) bool {
+		return c == id
+	})
+	if c <= len(l.Container.Children) {
+		panic(fmt.Sprintf("%d is not in layer container %d", id, l.Id))
+	}
+	for i := range l.Layers {
+		l.Layers[i].Unassociate(id)
+	}
+	b.DirectParentId = 0
 }
 
 func (h *LayerCntr) Leafs() []uint32 { return h.Container.Children }
 
+func (l *LayerCntr) Layer(id uint32) *Layer {
+	i := slices.IndexFunc(l.Layers, func(layer Layer) bool { return layer.Id == id })
+	if i == -1 {
+		return nil
+	}
+	return &l.Layers[i]
+}
+
+// A new layer is not driven by any game parameter and has no associated
+// children.
+func (l *LayerCntr) NewLayer(id uint32) *Layer {
+	if l.Layer(id) != nil {
+		panic(fmt.Sprintf("Layer %d is already in layer container %d", id, l.Id))
+	}
+	l.Layers = append(l.Layers, Layer{
+		Id: id,
+		InitialRTPC: RTPC{RTPCItems: []RTPCItem{}},
+		LayerRTPCs: []LayerRTPC{},
+	})
+	return &l.Layers[len(l.Layers) - 1]
+}
+
+func (l *LayerCntr) RemoveLayer(id uint32) {
+	l.Layers = slices.DeleteFunc(l.Layers, func(layer Layer) bool { return layer.Id == id })
+}
+
+// Play a leaf in a layer with a crossfade curve over the game parameter of the
+// layer. Replace the curve if the leaf is already associated. The leaf must
+// be a child of this container.
+func (l *LayerCntr) Associate(layerID uint32, id uint32, points []RTPCGraphPoint) error {
+	if !slices.Contains(l.Container.Children, id) {
+		return fmt.Errorf("%d is not in layer container %d", id, l.Id)
+	}
+	layer := l.Layer(layerID)
+	if layer == nil {
+		return fmt.Errorf("No layer has ID %d in layer container %d", layerID, l.Id)
+	}
+	if len(points) < 2 {
+		return fmt.Errorf("Crossfade curve of %d needs at least two points", id)
+	}
+	for i := 1; i < len(points); i++ {
+		if points[i].From <= points[i - 1].From {
+			return fmt.Errorf("Crossfade curve of %d is not sorted by game parameter value", id)
+		}
+	}
+	points = slices.Clone(points)
+	if a := layer.Association(id); a != nil {
+		a.RTPCGraphPoints = points
+		return nil
+	}
+	layer.LayerRTPCs = append(layer.LayerRTPCs, LayerRTPC{AssociatedChildID: id, RTPCGraphPoints: points})
+	return nil
+}
+
 type Layer struct {
 	Id uint32 // tid
 	InitialRTPC RTPC
@@ -76,6 +166,18 @@ type Layer struct {
 	LayerRTPCs []LayerRTPC
 }
 
+func (l *Layer) Association(id uint32) *LayerRTPC {
+	i := slices.IndexFunc(l.LayerRTPCs, func(a LayerRTPC) bool { return a.AssociatedChildID == id })
+	if i == -1 {
+		return nil
+	}
+	return &l.LayerRTPCs[i]
+}
+
+func (l *Layer) Unassociate(id uint32) {
+	l.LayerRTPCs = slices.DeleteFunc(l.LayerRTPCs, func(a LayerRTPC) bool { return a.AssociatedChildID == id })
+}
+
 func (l *Layer) Encode(v int) []byte {
 	size := l.Size(v)
 	w := wio.NewWriter(uint64(size))
@@ -120,3 +222,33 @@ func (l *LayerRTPC) Encode(v int) []byte {
 func (l *LayerRTPC) Size(v int) uint32 {
 	return uint32(4 + 4 + len(l.RTPCGraphPoints) * SizeOfRTPCGraphPoint)
 }
+
+// Volume (dB) of crossfade curves where a child is silent
+const LayerCurveSilence float32 = -96.3
+
+// Crossfade curve of a child that plays between start and end of a game
+// parameter, fading in over fadeIn and fading out over fadeOut. A zero fade
+// starts or ends the child abruptly.
+func LayerCrossfadeCurve(start, end, fadeIn, fadeOut float32) []RTPCGraphPoint {
+	points := []RTPCGraphPoint{}
+	// Points overlapped by a preceding fade are dropped
+	curve := func(x float32, y float32) {
+		if len(points) > 0 && x <= points[len(points) - 1].From {
+			return
+		}
+		points = append(points, RTPCGraphPoint{x, y, uint32(InterpCurveTypeLinear)})
+	}
+	if fadeIn > 0 {
+		curve(start, LayerCurveSilence)
+		curve(start + fadeIn, 0)
+	} else {
+		curve(start, 0)
+	}
+	if fadeOut > 0 {
+		curve(end - fadeOut, 0)
+		curve(end, LayerCurveSilence)
+	} else {
+		curve(end, 0)
+	}
+	return points
+}
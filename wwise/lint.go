@@ -0,0 +1,318 @@
+package wwise
+
+import (
+	"fmt"
+)
+
+type Severity uint8
+
+const (
+	SeverityInfo    Severity = 0
+	SeverityWarning Severity = 1
+	SeverityError   Severity = 2
+	SeverityCount   Severity = 3
+)
+
+var SeverityName []string = []string{
+	"Info",
+	"Warning",
+	"Error",
+}
+
+type LintCode uint8
+
+const (
+	LintParentMismatch      LintCode = 0
+	LintMissingChild        LintCode = 1
+	LintPlayListNotChild    LintCode = 2
+	LintSwitchNodeNotChild  LintCode = 3
+	LintMissingEventAction  LintCode = 4
+	LintMissingActionTarget LintCode = 5
+	LintUnusedMedia         LintCode = 6
+	LintMissingMedia        LintCode = 7
+	LintMediaSizeMismatch   LintCode = 8
+	LintRedundantMedia      LintCode = 9
+	LintUnsortedCurve       LintCode = 10
+	LintMalformedCurve      LintCode = 11
+	LintChildMismatch       LintCode = 12
+	LintCodeCount           LintCode = 13
+)
+
+var LintCodeName []string = []string{
+	"Parent Mismatch",
+	"Missing Child",
+	"Play List Item Not Child",
+	"Switch Node Not Child",
+	"Missing Event Action",
+	"Missing Action Target",
+	"Unused Media",
+	"Missing Media",
+	"Media Size Mismatch",
+	"Redundant Media",
+	"Unsorted Curve",
+	"Malformed Curve",
+	"Child Mismatch",
+}
+
+// A single problem found by Lint. ID is the hierarchy object (or the source
+// ID for media related diagnostics) the problem is found on.
+type Diagnostic struct {
+	Severity Severity
+	Code     LintCode
+	ID       uint32
+	HircType HircType
+	Message  string
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("[%s] %s (%d): %s", SeverityName[d.Severity], LintCodeName[d.Code], d.ID, d.Message)
+}
+
+func HasLintError(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type linter struct {
+	objs        map[uint32]HircObj
+	media       map[uint32]uint32
+	usedMedia   map[uint32]bool
+	diagnostics []Diagnostic
+}
+
+func (l *linter) report(s Severity, c LintCode, id uint32, t HircType, format string, a ...any) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{s, c, id, t, fmt.Sprintf(format, a...)})
+}
+
+// Validate a parsed sound bank. Problems that the sound engine will likely
+// fail on (or silently misbehave with) are reported as error. Problems that
+// can be legitimate when the referenced object lives in another sound bank
+// are reported as warning.
+func (b *Bank) Lint() []Diagnostic {
+	l := &linter{
+		objs: make(map[uint32]HircObj),
+		media: make(map[uint32]uint32),
+		usedMedia: make(map[uint32]bool),
+		diagnostics: []Diagnostic{},
+	}
+
+	// Index media from DIDX entries directly instead of MediaIndexsMap, which
+	// can go stale after media is appended
+	didx := b.DIDX()
+	if didx != nil {
+		for _, m := range didx.MediaIndexs {
+			l.media[m.Sid] = m.Size
+		}
+	}
+
+	h := b.HIRC()
+	if h != nil {
+		for _, o := range h.HircObjs {
+			if id, err := o.HircID(); err == nil {
+				l.objs[id] = o
+			}
+		}
+		for _, o := range h.HircObjs {
+			l.lintHircObj(o)
+		}
+	}
+
+	if didx != nil {
+		for _, m := range didx.MediaIndexs {
+			if !l.usedMedia[m.Sid] {
+				l.report(SeverityWarning, LintUnusedMedia, m.Sid, HircTypeAll, "Media is not used by any sound or music track in this sound bank")
+			}
+		}
+	}
+
+	return l.diagnostics
+}
+
+func (l *linter) lintHircObj(o HircObj) {
+	id, err := o.HircID()
+	if err != nil {
+		return
+	}
+	t := o.HircType()
+
+	if b := o.BaseParameter(); b != nil {
+		l.lintParent(o, id, t, b.DirectParentId)
+		l.lintRTPC(&b.RTPC, id, t)
+	}
+	for _, leaf := range o.Leafs() {
+		l.lintChild(id, t, leaf)
+	}
+
+	switch o := o.(type) {
+	case *Sound:
+		l.lintSource(&o.BankSourceData, id, t)
+	case *MusicTrack:
+		for i := range o.Sources {
+			l.lintSource(&o.Sources[i], id, t)
+		}
+	case *RanSeqCntr:
+		for _, p := range o.PlayListItems {
+			if !containsLeaf(o, p.UniquePlayID) {
+				l.report(SeverityError, LintPlayListNotChild, id, t, "Play list item %d is not a child of this container", p.UniquePlayID)
+			}
+		}
+	case *SwitchCntr:
+		for _, g := range o.SwitchGroups {
+			for _, node := range g.NodeList {
+				if !containsLeaf(o, node) {
+					l.report(SeverityError, LintSwitchNodeNotChild, id, t, "Node %d assigned to switch %d is not a child of this container", node, g.SwitchID)
+				}
+			}
+		}
+	case *Event:
+		for _, a := range o.ActionIDs {
+			if _, in := l.objs[a]; !in {
+				l.report(SeverityError, LintMissingEventAction, id, t, "Action %d does not exist", a)
+			}
+		}
+	case *Action:
+		l.lintAction(o, id, t)
+	case *Bus:
+		l.lintRTPC(&o.BusRTPC, id, t)
+	case *AuxBus:
+		l.lintRTPC(&o.BusRTPC, id, t)
+	case *Attenuation:
+		for i := range o.AttenuationConversionTables {
+			c := &o.AttenuationConversionTables[i]
+			l.lintCurve(c.RTPCGraphPointsX, c.RTPCGraphPointsY, c.RTPCGraphPointsInterp, id, t, fmt.Sprintf("Attenuation curve %d", i))
+		}
+		l.lintRTPC(&o.RTPC, id, t)
+	case *FxShareSet:
+		for _, m := range o.MediaMap {
+			l.usedMedia[m.SourceId] = true
+		}
+		l.lintRTPC(&o.RTPC, id, t)
+	case *FxCustom:
+		for _, m := range o.MediaMap {
+			l.usedMedia[m.SourceId] = true
+		}
+		l.lintRTPC(&o.RTPC, id, t)
+	case *Modulator:
+		l.lintRTPC(&o.RTPC, id, t)
+	}
+}
+
+func containsLeaf(o HircObj, id uint32) bool {
+	for _, leaf := range o.Leafs() {
+		if leaf == id {
+			return true
+		}
+	}
+	return false
+}
+
+// A child whose direct parent is in this sound bank must be listed by that
+// parent.
+func (l *linter) lintParent(o HircObj, id uint32, t HircType, parentID uint32) {
+	if parentID == 0 {
+		return
+	}
+	parent, in := l.objs[parentID]
+	if !in {
+		return
+	}
+	if !containsLeaf(parent, id) {
+		l.report(SeverityError, LintParentMismatch, id, t, "Direct parent %d does not list this object as a child", parentID)
+	}
+}
+
+// A child listed by a container must point back to the container. Reported on
+// the container with a code different from lintParent so that one broken link
+// seen from both sides is not counted as the same problem twice.
+func (l *linter) lintChild(id uint32, t HircType, leaf uint32) {
+	child, in := l.objs[leaf]
+	if !in {
+		l.report(SeverityWarning, LintMissingChild, id, t, "Child %d does not exist in this sound bank", leaf)
+		return
+	}
+	b := child.BaseParameter()
+	if b == nil {
+		return
+	}
+	if b.DirectParentId != id {
+		l.report(SeverityError, LintChildMismatch, id, t, "Child %d has direct parent %d instead of this container", leaf, b.DirectParentId)
+	}
+}
+
+func (l *linter) lintAction(a *Action, id uint32, t HircType) {
+	switch p := a.ActionParam.(type) {
+	case *ActionSetStateParam, *ActionSetSwitchParam, *ActionSetRTPCParam:
+		// Targets are game syncs, which are not hierarchy objects
+		return
+	case *ActionSetValueParam:
+		if _, ok := p.AkSpecificParam.(*ActionSetGameParameterSpecificParam); ok {
+			return
+		}
+	}
+	if a.IdExt == 0 {
+		return
+	}
+	if _, in := l.objs[a.IdExt]; !in {
+		l.report(SeverityWarning, LintMissingActionTarget, id, t, "Target %d does not exist in this sound bank", a.IdExt)
+	}
+}
+
+func (l *linter) lintSource(s *BankSourceData, id uint32, t HircType) {
+	if s.PluginType() != 1 {
+		// Source plugins (e.g. Silence, Tone Generator) do not have media
+		return
+	}
+	l.usedMedia[s.SourceID] = true
+
+	size, in := l.media[s.SourceID]
+
+	switch s.StreamType {
+	case SourceTypeDATA:
+		if !in {
+			l.report(SeverityError, LintMissingMedia, id, t, "Audio source %d is in-memory but no media is found in this sound bank", s.SourceID)
+			return
+		}
+		if size != s.InMemoryMediaSize {
+			l.report(SeverityError, LintMediaSizeMismatch, id, t, "Audio source %d declares in-memory media size %d but media has size %d", s.SourceID, s.InMemoryMediaSize, size)
+		}
+	case SourceTypePrefetchStreaming:
+		if !in {
+			l.report(SeverityError, LintMissingMedia, id, t, "Audio source %d is prefetch streamed but no prefetch media is found in this sound bank", s.SourceID)
+			return
+		}
+		if size != s.InMemoryMediaSize {
+			l.report(SeverityError, LintMediaSizeMismatch, id, t, "Audio source %d declares prefetch size %d but prefetch media has size %d", s.SourceID, s.InMemoryMediaSize, size)
+		}
+	case SourceTypeStreaming:
+		if in {
+			l.report(SeverityWarning, LintRedundantMedia, id, t, "Audio source %d is streamed but its media is also stored in this sound bank", s.SourceID)
+		}
+		if s.InMemoryMediaSize == 0 {
+			l.report(SeverityWarning, LintMediaSizeMismatch, id, t, "Audio source %d is streamed but declares media size 0", s.SourceID)
+		}
+	}
+}
+
+func (l *linter) lintRTPC(r *RTPC, id uint32, t HircType) {
+	for i := range r.RTPCItems {
+		item := &r.RTPCItems[i]
+		l.lintCurve(item.RTPCGraphPointsX, item.RTPCGraphPointsY, item.RTPCGraphPointsInterp, id, t, fmt.Sprintf("RTPC curve %d (RTPC %d)", i, item.RTPCID))
+	}
+}
+
+func (l *linter) lintCurve(xs []float32, ys []float32, interps []uint32, id uint32, t HircType, name string) {
+	if len(xs) != len(ys) || len(xs) != len(interps) {
+		l.report(SeverityError, LintMalformedCurve, id, t, "%s has %d X values, %d Y values and %d interpolations", name, len(xs), len(ys), len(interps))
+		return
+	}
+	for i := 1; i < len(xs); i++ {
+		if xs[i] < xs[i - 1] {
+			l.report(SeverityError, LintUnsortedCurve, id, t, "%s has X value %f at point %d smaller than X value %f at point %d", name, xs[i], i, xs[i - 1], i - 1)
+			return
+		}
+	}
+}
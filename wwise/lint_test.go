@@ -0,0 +1,70 @@
+package wwise
+
+import (
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	didx := NewDIDX(0, []byte{'D', 'I', 'D', 'X'}, 0)
+	didx.Append(100, 64)
+	didx.Append(200, 32)
+
+	// Media size mismatch
+	s1 := &Sound{Id: 10, BaseParam: &BaseParameter{DirectParentId: 30}}
+	s1.BankSourceData = BankSourceData{PluginID: VORBIS, StreamType: SourceTypeDATA, SourceID: 100, InMemoryMediaSize: 60}
+	// Wrong parent (reported on both the child and the container listing it)
+	// and missing media
+	s2 := &Sound{Id: 11, BaseParam: &BaseParameter{DirectParentId: 31}}
+	s2.BankSourceData = BankSourceData{PluginID: VORBIS, StreamType: SourceTypeDATA, SourceID: 300, InMemoryMediaSize: 60}
+	s2.BaseParam.RTPC.RTPCItems = []RTPCItem{{
+		RTPCID: 1,
+		RTPCGraphPointsX: []float32{0, 10, 5},
+		RTPCGraphPointsY: []float32{0, 1, 2},
+		RTPCGraphPointsInterp: []uint32{4, 4, 4},
+	}}
+	cntr := &RanSeqCntr{Id: 30, BaseParam: BaseParameter{}}
+	cntr.Container.Children = []uint32{10, 11}
+	cntr.PlayListItems = []PlayListItem{{10, 50000}, {12, 50000}}
+	other := &RanSeqCntr{Id: 31, BaseParam: BaseParameter{}}
+	action := &Action{Id: 60, IdExt: 99, ActionParam: &ActionPlayParam{}}
+	event := &Event{Id: 70, ActionIDs: []uint32{60, 61}}
+	h.HircObjs = []HircObj{s1, s2, cntr, other, action, event}
+
+	bnk := NewBank()
+	bnk.AddChunk(didx)
+	bnk.AddChunk(h)
+
+	expects := []struct {
+		severity Severity
+		code     LintCode
+		id       uint32
+	}{
+		{SeverityError, LintMediaSizeMismatch, 10},
+		{SeverityError, LintParentMismatch, 11},
+		{SeverityError, LintUnsortedCurve, 11},
+		{SeverityError, LintMissingMedia, 11},
+		{SeverityError, LintChildMismatch, 30},
+		{SeverityError, LintPlayListNotChild, 30},
+		{SeverityWarning, LintMissingActionTarget, 60},
+		{SeverityError, LintMissingEventAction, 70},
+		{SeverityWarning, LintUnusedMedia, 200},
+	}
+
+	diagnostics := bnk.Lint()
+	if len(diagnostics) != len(expects) {
+		for _, d := range diagnostics {
+			t.Log(d.String())
+		}
+		t.Fatalf("Expect %d diagnostics, got %d", len(expects), len(diagnostics))
+	}
+	for i, e := range expects {
+		d := diagnostics[i]
+		if d.Severity != e.severity || d.Code != e.code || d.ID != e.id {
+			t.Fatalf("Diagnostic %d: expect %s %s on %d, got %s", i, SeverityName[e.severity], LintCodeName[e.code], e.id, d.String())
+		}
+	}
+	if !HasLintError(diagnostics) {
+		t.Fatal("Expect lint error")
+	}
+}
@@ -1,6 +1,12 @@
 package wwise
 
-import "github.com/Dekr0/wwise-teller/wio"
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/wio"
+)
 
 type ModulatorPropType uint8
 
@@ -135,3 +141,125 @@ func (h *Modulator) AddLeaf(o HircObj) { panic("Panic Trap") }
 func (h *Modulator) RemoveLeaf(o HircObj) { panic("Panic Trap") }
 
 func (h *Modulator) Leafs() []uint32 { return []uint32{} }
+
+// Properties of each modulator type. Scope applies to all modulator types.
+var ModulatorProps map[HircType][]ModulatorPropType = map[HircType][]ModulatorPropType{
+	HircTypeLFOModulator: {
+		ModulatorPropTypeScope,
+		ModulatorPropTypeLFODepth,
+		ModulatorPropTypeLFOAttack,
+		ModulatorPropTypeLFOFrequency,
+		ModulatorPropTypeLFOWaveform,
+		ModulatorPropTypeLFOSmoothing,
+		ModulatorPropTypeLFOPWM,
+		ModulatorPropTypeLFOInitialPhase,
+	},
+	HircTypeEnvelopeModulator: {
+		ModulatorPropTypeScope,
+		ModulatorPropTypeEnvelopeStopPlayback,
+		ModulatorPropTypeEnvelopeAttackTime,
+		ModulatorPropTypeEnvelopeAttackCurve,
+		ModulatorPropTypeEnvelopeDecayTime,
+		ModulatorPropTypeEnvelopeSustainLevel,
+		ModulatorPropTypeEnvelopeSustainTime,
+		ModulatorPropTypeEnvelopeReleaseTime,
+		ModulatorPropTypeEnvelopeTriggerOn,
+	},
+	HircTypeTimeModulator: {
+		ModulatorPropTypeScope,
+		ModulatorPropTypeEnvelopeStopPlayback,
+		ModulatorPropTypeTimeDuration,
+		ModulatorPropTypeTimeLoops,
+		ModulatorPropTypeTimePlaybackRate,
+		ModulatorPropTypeTimeInitialDelay,
+	},
+}
+
+// Default property values of a new modulator (Wwise authoring defaults)
+var ModulatorPropDefault map[ModulatorPropType]float32 = map[ModulatorPropType]float32{
+	ModulatorPropTypeScope:                0, // Voice
+	ModulatorPropTypeEnvelopeStopPlayback: 1,
+	ModulatorPropTypeLFODepth:             100,
+	ModulatorPropTypeLFOAttack:            0,
+	ModulatorPropTypeLFOFrequency:         1,
+	ModulatorPropTypeLFOWaveform:          0, // Sine
+	ModulatorPropTypeLFOSmoothing:         0,
+	ModulatorPropTypeLFOPWM:               50,
+	ModulatorPropTypeLFOInitialPhase:      0,
+	ModulatorPropTypeEnvelopeAttackTime:   0.2,
+	ModulatorPropTypeEnvelopeAttackCurve:  50,
+	ModulatorPropTypeEnvelopeDecayTime:    0.2,
+	ModulatorPropTypeEnvelopeSustainLevel: 100,
+	ModulatorPropTypeEnvelopeSustainTime:  0,
+	ModulatorPropTypeEnvelopeReleaseTime:  0.5,
+	ModulatorPropTypeEnvelopeTriggerOn:    0, // Play
+	ModulatorPropTypeTimeDuration:         1,
+	ModulatorPropTypeTimeLoops:            1,
+	ModulatorPropTypeTimePlaybackRate:     1,
+	ModulatorPropTypeTimeInitialDelay:     0,
+}
+
+// Modulator properties stored as integer instead of float
+func ModulatorPropIsInt(t ModulatorPropType) bool {
+	return t == ModulatorPropTypeScope                ||
+	       t == ModulatorPropTypeEnvelopeStopPlayback ||
+	       t == ModulatorPropTypeLFOWaveform          ||
+	       t == ModulatorPropTypeEnvelopeTriggerOn    ||
+	       t == ModulatorPropTypeTimeLoops
+}
+
+// Create a modulator of the given type with default properties
+func NewModulator(id uint32, t HircType) (*Modulator, error) {
+	props, in := ModulatorProps[t]
+	if !in {
+		return nil, fmt.Errorf("%s is not a modulator type", HircTypeName[t])
+	}
+	m := &Modulator{
+		ModulatorType: t,
+		Id: id,
+		PropBundle: PropBundle{Modulator: true, PropValues: []PropValue{}},
+		RangePropBundle: RangePropBundle{Modulator: true, RangeValues: []RangeValue{}},
+		RTPC: RTPC{Modulator: true, RTPCItems: []RTPCItem{}},
+	}
+	for _, p := range props {
+		if err := m.SetProp(p, ModulatorPropDefault[p]); err != nil {
+			panic(err)
+		}
+	}
+	return m, nil
+}
+
+func (m *Modulator) Prop(t ModulatorPropType) (float32, bool) {
+	i, in := m.PropBundle.HasPidRaw(uint8(t))
+	if !in {
+		return 0, false
+	}
+	if ModulatorPropIsInt(t) {
+		var u uint32
+		binary.Decode(m.PropBundle.PropValues[i].V, wio.ByteOrder, &u)
+		return float32(u), true
+	}
+	var f float32
+	binary.Decode(m.PropBundle.PropValues[i].V, wio.ByteOrder, &f)
+	return f, true
+}
+
+// Set a property. The property is added if it does not exist.
+func (m *Modulator) SetProp(t ModulatorPropType, val float32) error {
+	if !slices.Contains(ModulatorProps[m.ModulatorType], t) {
+		return fmt.Errorf("%s does not have property %s", HircTypeName[m.ModulatorType], ModulatorPropTypeName[t])
+	}
+	if ModulatorPropIsInt(t) && val < 0 {
+		return fmt.Errorf("Property %s must not be negative", ModulatorPropTypeName[t])
+	}
+	i, in := m.PropBundle.HasPidRaw(uint8(t))
+	if !in {
+		m.PropBundle.PropValues = slices.Insert(m.PropBundle.PropValues, i, PropValue{uint8(t), []byte{0, 0, 0, 0}})
+	}
+	if ModulatorPropIsInt(t) {
+		m.PropBundle.SetPropByIdxU32(i, uint32(val))
+	} else {
+		m.PropBundle.SetPropByIdxF32(i, val)
+	}
+	return nil
+}
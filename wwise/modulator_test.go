@@ -0,0 +1,77 @@
+package wwise
+
+import (
+	"testing"
+)
+
+func TestBindModulator(t *testing.T) {
+	const v = 141
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	s := &Sound{Id: 10, BaseParam: &BaseParameter{}}
+	h.HircObjs = []HircObj{s}
+	h.ActorMixerHirc.Store(s.Id, s)
+
+	lfo, err := NewModulator(100, HircTypeLFOModulator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewModulator(101, HircTypeSound); err == nil {
+		t.Fatal("Expecting error on creating a modulator of a non modulator type")
+	}
+	if err := lfo.SetProp(ModulatorPropTypeLFOFrequency, 6); err != nil {
+		t.Fatal(err)
+	}
+	if err := lfo.SetProp(ModulatorPropTypeLFOWaveform, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := lfo.SetProp(ModulatorPropTypeTimeLoops, 1); err == nil {
+		t.Fatal("Expecting error on setting a time modulator property on a LFO")
+	}
+	if f, _ := lfo.Prop(ModulatorPropTypeLFOFrequency); f != 6 {
+		t.Fatalf("Expecting LFO frequency 6, got %f", f)
+	}
+	if w, _ := lfo.Prop(ModulatorPropTypeLFOWaveform); w != 1 {
+		t.Fatalf("Expecting LFO waveform 1, got %f", w)
+	}
+	if d, _ := lfo.Prop(ModulatorPropTypeLFODepth); d != 100 {
+		t.Fatalf("Expecting default LFO depth 100, got %f", d)
+	}
+	if l := len(lfo.Encode(v)); l != int(SizeOfHircObjHeader + lfo.DataSize(v)) {
+		t.Fatalf("Unexpected encoded size %d", l)
+	}
+
+	if err := h.BindModulator(10, 100, 200, RTPCParameterTypePitch, -50, 50, v); err == nil {
+		t.Fatal("Expecting error on binding a modulator not in the hierarchy")
+	}
+	if err := h.AppendNewModulator(lfo); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.AppendNewModulator(lfo); err == nil {
+		t.Fatal("Expecting error on appending the same modulator twice")
+	}
+	if id, _ := h.HircObjs[0].HircID(); id != 100 {
+		t.Fatalf("Expecting modulator placed before hierarchy objects, got %d", id)
+	}
+	if err := h.BindModulator(10, 100, 200, RTPCParameterTypePitch, -50, 50, v); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.BindModulator(10, 100, 201, RTPCParameterTypePitch, -50, 50, v); err == nil {
+		t.Fatal("Expecting error on binding the same modulator to the same parameter twice")
+	}
+	if err := h.BindModulator(10, 100, 202, RTPCParameterTypeVolume, -3, 0, v); err != nil {
+		t.Fatal(err)
+	}
+	items := s.BaseParam.RTPC.RTPCItems
+	if len(items) != 2 || items[0].RTPCType != RTPCTypeModulator || items[0].RTPCID != 100 {
+		t.Fatalf("Unexpected RTPC items %+v", items)
+	}
+	if items[0].RTPCAccum != RTPCAccumTypeAdditive || items[0].Scaling != CurveScalingTypeNone || items[1].Scaling != CurveScalingTypeDb {
+		t.Fatalf("Unexpected RTPC accumulation or scaling %+v", items)
+	}
+	if items[0].RTPCGraphPointsY[0] != -50 || items[0].RTPCGraphPointsY[1] != 50 {
+		t.Fatalf("Unexpected RTPC curve %+v", items[0])
+	}
+	if refs := h.BuildXRef(v).References(100); len(refs) != 2 {
+		t.Fatalf("Expecting modulator 100 referenced twice, got %+v", refs)
+	}
+}
@@ -1,6 +1,11 @@
 package wwise
 
-import "github.com/Dekr0/wwise-teller/wio"
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/wio"
+)
 
 type MusicRanSeqCntr struct {
 	Id                  uint32
@@ -66,12 +71,134 @@ func (h *MusicRanSeqCntr) NumLeaf() int { return len(h.Children.Children) }
 
 func (h *MusicRanSeqCntr) ParentID() uint32 { return h.BaseParam.DirectParentId }
 
-func (h *MusicRanSeqCntr) AddLeaf(o HircObj) {}
+func (h *MusicRanSeqCntr) AddLeaf(o HircObj) {
+	addMusicLeaf(h.Id, &h.Children, o, HircTypeMusicSegment, HircTypeMusicRanSeqCntr, HircTypeMusicSwitchCntr)
+}
 
-func (h *MusicRanSeqCntr) RemoveLeaf(o HircObj) {}
+// Removing a child also removes its play list nodes and its references in
+// transition rules.
+func (h *MusicRanSeqCntr) RemoveLeaf(o HircObj) {
+	id := removeMusicLeaf(h.Id, &h.Children, o)
+	h.PlayListNode.RemoveSegment(id)
+	h.TransitionRules = removeTransitionNode(h.TransitionRules, id)
+	for i := range h.TransitionRules {
+		d := &h.TransitionRules[i].TransitionDestRule
+		if d.JumpToType == JumpToTypeSpecificItem && h.PlayListNode.Node(d.JumpToID) == nil {
+			d.JumpToType = JumpToTypeStartOfPlaylist
+			d.JumpToID = 0
+		}
+	}
+}
 
 func (h *MusicRanSeqCntr) Leafs() []uint32 { return h.Children.Children }
 
+// Add a new group node under group node groupItemID
+func (h *MusicRanSeqCntr) AddPlayListGroup(groupItemID uint32, itemID uint32, rsType uint32) error {
+	if rsType >= MusicRSTypeCount {
+		return fmt.Errorf("Invalid random / sequence type %d", rsType)
+	}
+	g, err := h.playListGroup(groupItemID, itemID)
+	if err != nil {
+		return err
+	}
+	g.AppendLeaf(NewMusicPlayListGroup(itemID, rsType))
+	return nil
+}
+
+// Add a new segment node under group node groupItemID. The segment must be a
+// child of the container.
+func (h *MusicRanSeqCntr) AddPlayListSegment(groupItemID uint32, itemID uint32, segmentID uint32) error {
+	if !slices.Contains(h.Children.Children, segmentID) {
+		return fmt.Errorf("%d is not in music random / sequence container %d", segmentID, h.Id)
+	}
+	g, err := h.playListGroup(groupItemID, itemID)
+	if err != nil {
+		return err
+	}
+	g.AppendLeaf(NewMusicPlayListSegment(itemID, segmentID))
+	return nil
+}
+
+func (h *MusicRanSeqCntr) playListGroup(groupItemID uint32, itemID uint32) (*MusicPlayListNode, error) {
+	if h.PlayListNode.Node(itemID) != nil {
+		return nil, fmt.Errorf("Play list item %d already exists", itemID)
+	}
+	g := h.PlayListNode.Node(groupItemID)
+	if g == nil {
+		return nil, fmt.Errorf("No play list item has ID %d", groupItemID)
+	}
+	if !g.IsGroup() {
+		return nil, fmt.Errorf("Play list item %d is a segment", groupItemID)
+	}
+	return g, nil
+}
+
+// Source and destination of a transition rule must be a child, 
+// MusicTransitionAny or MusicTransitionNothing. A transition segment must be
+// a child.
+func (h *MusicRanSeqCntr) AddTransitionRule(r MusicTransitionRule) error {
+	if err := r.Validate(h.Children.Children); err != nil {
+		return err
+	}
+	h.TransitionRules = append(h.TransitionRules, r)
+	return nil
+}
+
+// The first transition rule is the default rule and cannot be removed.
+func (h *MusicRanSeqCntr) RemoveTransitionRule(i int) error {
+	rules, err := removeTransitionRule(h.TransitionRules, i)
+	if err != nil {
+		return err
+	}
+	h.TransitionRules = rules
+	return nil
+}
+
+func addMusicLeaf(cntrID uint32, c *Container, o HircObj, types ...HircType) {
+	id, err := o.HircID()
+	if err != nil {
+		panic("Passing a hierarchy without a hierarchy ID.")
+	}
+	if !slices.Contains(types, o.HircType()) {
+		panic(fmt.Sprintf("%s %d cannot be a child of %d", HircTypeName[o.HircType()], id, cntrID))
+	}
+	b := o.BaseParameter()
+	if b.DirectParentId != 0 {
+		panic(fmt.Sprintf("%d is already attach to root %d. AddLeaf is an atomic operation.", id, b.DirectParentId))
+	}
+	if slices.Contains(c.Children, id) {
+		panic(fmt.Sprintf("%d is already in %d", id, cntrID))
+	}
+	c.Children = append(c.Children, id)
+	b.DirectParentId = cntrID
+}
+
+func removeMusicLeaf(cntrID uint32, c *Container, o HircObj) uint32 {
+	id, err := o.HircID()
+	if err != nil {
+		panic("Passing a hierarchy without a hierarchy ID.")
+	}
+	b := o.BaseParameter()
+	if b == nil {
+		panic(fmt.Sprintf("Hierarchy object %d is not containable.", id))
+	}
+	l := len(c.Children)
+	c.Children = slices.DeleteFunc(c.Children, func(c uint32) bool { return c == id })
+	if l <= len(c.Children) {
+		panic(fmt.Sprintf("%d is not in %d", id, cntrID))
+	}
+	b.DirectParentId = 0
+	return id
+}
+
+const (
+	JumpToTypeStartOfPlaylist uint16 = 0
+	JumpToTypeSpecificItem    uint16 = 1
+	JumpToTypeLastPlayedSegment uint16 = 2
+	JumpToTypeNextSegment     uint16 = 3
+	JumpToTypeCount           int32  = 4
+)
+
 var JumpToTypeName []string = []string{
   	"StartOfPlaylist",
   	"SpecificItem",
@@ -79,6 +206,8 @@ var JumpToTypeName []string = []string{
   	"NextSegment",
 }
 
+const EntryTypeCount = 5
+
 var EntryTypeName []string = []string{
    "EntryMarker",
    "SameTime",
@@ -87,6 +216,13 @@ var EntryTypeName []string = []string{
    "LastExitTime",
 }
 
+// Source or destination ID of a transition rule matching any object
+const MusicTransitionAny uint32 = 0xFFFFFFFF
+
+// Source or destination ID of a transition rule matching nothing (i.e.,
+// transition from or to silence)
+const MusicTransitionNothing uint32 = 0
+
 const SizeOfTransitionObj = 4 + 12 + 12 + 2
 const SizeOfTransitionRulePair = 21 + 26
 type MusicTransitionRule struct {
@@ -169,6 +305,107 @@ func (m *MusicTransitionRule) HasTransitionObj() bool {
 	return m.AllocTransitionObjFlag != 0
 }
 
+// A transition rule that exits immediately and enters at the entry marker of
+// the destination without fading.
+func NewMusicTransitionRule(srcIDs []uint32, destIDs []uint32) MusicTransitionRule {
+	r := MusicTransitionRule{SrcIDs: slices.Clone(srcIDs), DestIDs: slices.Clone(destIDs)}
+	r.TransitionSourceRule.FadeCurve = uint32(InterpCurveTypeLinear)
+	r.TransitionSourceRule.PlayPostExit = 1
+	r.TransitionDestRule.FadeCurve = uint32(InterpCurveTypeLinear)
+	r.TransitionDestRule.JumpToType = JumpToTypeStartOfPlaylist
+	r.TransitionDestRule.PlayPreEntry = 1
+	return r
+}
+
+// Play a transition segment between source and destination. The segment must
+// be a child of the container owning this transition rule.
+func (m *MusicTransitionRule) SetTransitionSegment(segmentID uint32) {
+	if !m.HasTransitionObj() {
+		m.TransitionObj.FadeInParam.FadeCurve = uint32(InterpCurveTypeLinear)
+		m.TransitionObj.FadeOutParam.FadeCurve = uint32(InterpCurveTypeLinear)
+		m.TransitionObj.PlayPreEntry = 1
+		m.TransitionObj.PlayPostExit = 1
+	}
+	m.AllocTransitionObjFlag = 1
+	m.TransitionObj.SegmentID = segmentID
+}
+
+func (m *MusicTransitionRule) RemoveTransitionSegment() {
+	m.AllocTransitionObjFlag = 0
+	m.TransitionObj.SegmentID = 0
+}
+
+func (m *MusicTransitionRule) Validate(children []uint32) error {
+	if len(m.SrcIDs) == 0 || len(m.DestIDs) == 0 {
+		return fmt.Errorf("Transition rule needs at least one source and one destination")
+	}
+	for _, id := range slices.Concat(m.SrcIDs, m.DestIDs) {
+		if id != MusicTransitionAny && id != MusicTransitionNothing && !slices.Contains(children, id) {
+			return fmt.Errorf("Transition rule source / destination %d is not a child", id)
+		}
+	}
+	if m.TransitionSourceRule.SyncType >= NumSyncType {
+		return fmt.Errorf("Invalid sync type %d", m.TransitionSourceRule.SyncType)
+	}
+	if m.TransitionSourceRule.FadeCurve >= uint32(InterpCurveTypeCount) ||
+	   m.TransitionDestRule.FadeCurve >= uint32(InterpCurveTypeCount) {
+		return fmt.Errorf("Invalid fade curve")
+	}
+	if int32(m.TransitionDestRule.JumpToType) >= JumpToTypeCount {
+		return fmt.Errorf("Invalid jump to type %d", m.TransitionDestRule.JumpToType)
+	}
+	if m.TransitionDestRule.EntryType >= EntryTypeCount {
+		return fmt.Errorf("Invalid entry type %d", m.TransitionDestRule.EntryType)
+	}
+	if m.HasTransitionObj() && !slices.Contains(children, m.TransitionObj.SegmentID) {
+		return fmt.Errorf("Transition segment %d is not a child", m.TransitionObj.SegmentID)
+	}
+	return nil
+}
+
+func removeTransitionRule(rules []MusicTransitionRule, i int) ([]MusicTransitionRule, error) {
+	if i == 0 {
+		return rules, fmt.Errorf("The default transition rule cannot be removed")
+	}
+	if i < 0 || i >= len(rules) {
+		return rules, fmt.Errorf("Transition rule index %d is out of range", i)
+	}
+	return slices.Delete(rules, i, i + 1), nil
+}
+
+// Remove a removed child from sources, destinations and transition segments.
+// Rules left without a source or a destination are dropped.
+func removeTransitionNode(rules []MusicTransitionRule, id uint32) []MusicTransitionRule {
+	for i := range rules {
+		r := &rules[i]
+		r.SrcIDs = slices.DeleteFunc(r.SrcIDs, func(s uint32) bool { return s == id })
+		r.DestIDs = slices.DeleteFunc(r.DestIDs, func(d uint32) bool { return d == id })
+		if r.HasTransitionObj() && r.TransitionObj.SegmentID == id {
+			r.RemoveTransitionSegment()
+		}
+	}
+	return slices.DeleteFunc(rules, func(r MusicTransitionRule) bool {
+		return len(r.SrcIDs) == 0 || len(r.DestIDs) == 0
+	})
+}
+
+var MusicRSTypeName []string = []string{
+	"Continuous Sequence",
+	"Step Sequence",
+	"Continuous Random",
+	"Step Random",
+}
+
+const (
+	MusicRSTypeContinuousSequence uint32 = 0
+	MusicRSTypeStepSequence       uint32 = 1
+	MusicRSTypeContinuousRandom   uint32 = 2
+	MusicRSTypeStepRandom         uint32 = 3
+	MusicRSTypeCount              uint32 = 4
+	// Segment nodes do not have a random / sequence type
+	MusicRSTypeNone               uint32 = 0xFFFFFFFF
+)
+
 const SizeOfPlayListNode = 4 + 4 + 4 + 4 + 2 + 2 + 2 + 4 + 2 + 1 + 1
 type MusicPlayListNode struct {
 	SegmentID        uint32
@@ -213,6 +450,91 @@ func (p *MusicPlayListNode) Size(v int) uint32 {
 	return size 
 }
 
+func NewMusicPlayListGroup(itemID uint32, rsType uint32) MusicPlayListNode {
+	return MusicPlayListNode{
+		PlayListItemID: itemID,
+		RSType: rsType,
+		Loop: 1,
+		Weight: 50000,
+		PlayListLeafs: []MusicPlayListNode{},
+	}
+}
+
+func NewMusicPlayListSegment(itemID uint32, segmentID uint32) MusicPlayListNode {
+	return MusicPlayListNode{
+		SegmentID: segmentID,
+		PlayListItemID: itemID,
+		RSType: MusicRSTypeNone,
+		Loop: 1,
+		Weight: 50000,
+		PlayListLeafs: []MusicPlayListNode{},
+	}
+}
+
+func (p *MusicPlayListNode) IsGroup() bool { return p.SegmentID == 0 }
+
+func (p *MusicPlayListNode) IsRandom() bool {
+	return p.RSType == MusicRSTypeContinuousRandom || p.RSType == MusicRSTypeStepRandom
+}
+
+// Find a node by its play list item ID in this sub tree
+func (p *MusicPlayListNode) Node(itemID uint32) *MusicPlayListNode {
+	if p.PlayListItemID == itemID {
+		return p
+	}
+	for i := range p.PlayListLeafs {
+		if n := p.PlayListLeafs[i].Node(itemID); n != nil {
+			return n
+		}
+	}
+	return nil
+}
+
+func (p *MusicPlayListNode) AppendLeaf(n MusicPlayListNode) {
+	if !p.IsGroup() {
+		panic(fmt.Sprintf("Play list item %d is a segment and it cannot have children", p.PlayListItemID))
+	}
+	p.PlayListLeafs = append(p.PlayListLeafs, n)
+}
+
+// Remove a node and its sub tree from this sub tree. The root node itself
+// cannot be removed.
+func (p *MusicPlayListNode) RemoveNode(itemID uint32) bool {
+	l := len(p.PlayListLeafs)
+	p.PlayListLeafs = slices.DeleteFunc(p.PlayListLeafs, func(n MusicPlayListNode) bool {
+		return n.PlayListItemID == itemID
+	})
+	if l > len(p.PlayListLeafs) {
+		return true
+	}
+	for i := range p.PlayListLeafs {
+		if p.PlayListLeafs[i].RemoveNode(itemID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Move the leaf at index a to index b
+func (p *MusicPlayListNode) MoveLeaf(a int, b int) {
+	n := p.PlayListLeafs[a]
+	p.PlayListLeafs = slices.Insert(slices.Delete(p.PlayListLeafs, a, a + 1), b, n)
+}
+
+// Remove all nodes playing a segment in this sub tree. Return the number of
+// removed nodes.
+func (p *MusicPlayListNode) RemoveSegment(segmentID uint32) int {
+	l := len(p.PlayListLeafs)
+	p.PlayListLeafs = slices.DeleteFunc(p.PlayListLeafs, func(n MusicPlayListNode) bool {
+		return !n.IsGroup() && n.SegmentID == segmentID
+	})
+	removed := l - len(p.PlayListLeafs)
+	for i := range p.PlayListLeafs {
+		removed += p.PlayListLeafs[i].RemoveSegment(segmentID)
+	}
+	return removed
+}
+
 func (p *MusicPlayListNode) NumNodes() uint32 {
 	n := uint32(1)
 	for _, l := range p.PlayListLeafs {
@@ -85,9 +85,13 @@ func (h *MusicSegment) NumLeaf() int { return len(h.Children.Children) }
 
 func (h *MusicSegment) ParentID() uint32 { return h.BaseParam.DirectParentId }
 
-func (h *MusicSegment) AddLeaf(o HircObj) {}
+func (h *MusicSegment) AddLeaf(o HircObj) {
+	addMusicLeaf(h.Id, &h.Children, o, HircTypeMusicTrack)
+}
 
-func (h *MusicSegment) RemoveLeaf(o HircObj) {}
+func (h *MusicSegment) RemoveLeaf(o HircObj) {
+	removeMusicLeaf(h.Id, &h.Children, o)
+}
 
 func (h *MusicSegment) Leafs() []uint32 { return h.Children.Children }
 
@@ -113,6 +117,21 @@ type Stinger struct {
 	NumSegmentLookAhead uint32
 }
 
+// A stinger playing a segment immediately when a trigger is posted
+func NewStinger(triggerID uint32, segmentID uint32) Stinger {
+	return Stinger{TriggerID: triggerID, SegmentID: segmentID}
+}
+
+// IDs of entry cue and exit cue markers (FNV-1 hash of "entry cue" and "exit
+// cue")
+const (
+	MusicMarkerEntryID uint32 = 43573010
+	MusicMarkerExitID  uint32 = 1539036744
+)
+
+// Meter information overrides the one of parent
+const MeterInfoFlagOverrideParent uint8 = 1
+
 type MusicSegmentMarker struct {
 	ID         uint32
 	Position   float64
@@ -1,6 +1,14 @@
 package wwise
 
-import "github.com/Dekr0/wwise-teller/wio"
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/wio"
+)
 
 type MusicSwitchCntr struct {
 	Id                  uint32
@@ -68,8 +76,287 @@ func (h *MusicSwitchCntr) NumLeaf() int { return len(h.Children.Children) }
 
 func (h *MusicSwitchCntr) ParentID() uint32 { return h.BaseParam.DirectParentId }
 
-func (h *MusicSwitchCntr) AddLeaf(o HircObj) {}
+func (h *MusicSwitchCntr) AddLeaf(o HircObj) {
+	addMusicLeaf(h.Id, &h.Children, o, HircTypeMusicSegment, HircTypeMusicRanSeqCntr, HircTypeMusicSwitchCntr)
+}
+
+// Removing a child also removes its references in transition rules and paths
+// of the decision tree pointing at it.
+func (h *MusicSwitchCntr) RemoveLeaf(o HircObj) {
+	id := removeMusicLeaf(h.Id, &h.Children, o)
+	h.TransitionRules = removeTransitionNode(h.TransitionRules, id)
+	t, err := h.DecisionTree()
+	if err != nil {
+		slog.Warn(
+			fmt.Sprintf("Decision tree of music switch container %d might still reference %d", h.Id, id),
+			"error", err,
+		)
+		return
+	}
+	if t.Unassign(id) > 0 {
+		h.DecisionTreeData = t.Encode()
+	}
+}
 
-func (h *MusicSwitchCntr) RemoveLeaf(o HircObj) {}
+// See MusicRanSeqCntr.AddTransitionRule
+func (h *MusicSwitchCntr) AddTransitionRule(r MusicTransitionRule) error {
+	if err := r.Validate(h.Children.Children); err != nil {
+		return err
+	}
+	h.TransitionRules = append(h.TransitionRules, r)
+	return nil
+}
+
+// The first transition rule is the default rule and cannot be removed.
+func (h *MusicSwitchCntr) RemoveTransitionRule(i int) error {
+	rules, err := removeTransitionRule(h.TransitionRules, i)
+	if err != nil {
+		return err
+	}
+	h.TransitionRules = rules
+	return nil
+}
 
 func (h *MusicSwitchCntr) Leafs() []uint32 { return h.Children.Children }
+
+// Argument of a decision tree. Each level of the tree branches on the current
+// switch / state of its argument.
+type DecisionTreeArgument struct {
+	GroupID   uint32
+	GroupType GroupType
+	// Switches / states on the paths of this level. 0 is the default path (*).
+	Keys    []uint32
+}
+
+// A node at depth of the number of arguments is a leaf and plays AudioNodeID.
+// Children are sorted by key.
+type DecisionTreeNode struct {
+	// Switch / state of the argument of this level. 0 is the default path (*).
+	// The root node has no key.
+	Key           uint32
+	AudioNodeID   uint32
+	Weight        uint16
+	Probability   uint16
+	Children    []DecisionTreeNode
+}
+
+type DecisionTree struct {
+	Arguments []DecisionTreeArgument
+	Mode        uint8
+	Root        DecisionTreeNode
+}
+
+// Size of an encoded decision tree node: key, audio node ID (leaf) or child
+// index and child count (branch), weight, probability.
+const SizeOfDecisionTreeNode = 12
+
+const (
+	DecisionTreeDefaultWeight      = 50
+	DecisionTreeDefaultProbability = 100
+)
+
+func (h *MusicSwitchCntr) DecisionTree() (*DecisionTree, error) {
+	r := wio.NewReader(bytes.NewReader(h.DecisionTreeData), wio.ByteOrder)
+	depth, err := r.U32()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read decision tree depth: %w", err)
+	}
+	if uint64(depth) * 5 > uint64(len(h.DecisionTreeData)) {
+		return nil, fmt.Errorf("Decision tree depth %d exceeds decision tree data", depth)
+	}
+	t := &DecisionTree{Arguments: make([]DecisionTreeArgument, depth)}
+	for i := range t.Arguments {
+		if t.Arguments[i].GroupID, err = r.U32(); err != nil {
+			return nil, fmt.Errorf("Failed to read decision tree argument group: %w", err)
+		}
+	}
+	for i := range t.Arguments {
+		g, err := r.U8()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read decision tree argument group type: %w", err)
+		}
+		t.Arguments[i].GroupType = GroupType(g)
+	}
+	treeSize, err := r.U32()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read decision tree size: %w", err)
+	}
+	if t.Mode, err = r.U8(); err != nil {
+		return nil, fmt.Errorf("Failed to read decision tree mode: %w", err)
+	}
+	if uint64(treeSize) > uint64(len(h.DecisionTreeData)) - r.Pos() {
+		return nil, fmt.Errorf("Decision tree size %d exceeds decision tree data", treeSize)
+	}
+	nodes, err := r.ReadN(uint64(treeSize), 0)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read decision tree nodes: %w", err)
+	}
+	if len(nodes) < SizeOfDecisionTreeNode {
+		return nil, fmt.Errorf("Decision tree does not have a root node")
+	}
+	if err := decodeDecisionTreeNode(nodes, 0, 0, int(depth), &t.Root); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func decodeDecisionTreeNode(nodes []byte, i int, level int, depth int, n *DecisionTreeNode) error {
+	b := nodes[i * SizeOfDecisionTreeNode:]
+	n.Key = wio.ByteOrder.Uint32(b)
+	n.Weight = wio.ByteOrder.Uint16(b[8:])
+	n.Probability = wio.ByteOrder.Uint16(b[10:])
+	if level == depth {
+		n.AudioNodeID = wio.ByteOrder.Uint32(b[4:])
+		return nil
+	}
+	child := int(wio.ByteOrder.Uint16(b[4:]))
+	count := int(wio.ByteOrder.Uint16(b[6:]))
+	if count == 0 {
+		return nil
+	}
+	if child <= i || (child + count) * SizeOfDecisionTreeNode > len(nodes) {
+		return fmt.Errorf("Decision tree node %d has invalid children %d to %d", i, child, child + count)
+	}
+	n.Children = make([]DecisionTreeNode, count)
+	for c := range n.Children {
+		if err := decodeDecisionTreeNode(nodes, child + c, level + 1, depth, &n.Children[c]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Nodes are laid out in breadth first order so that children of a node are
+// next to each other.
+func (t *DecisionTree) Encode() []byte {
+	depth := len(t.Arguments)
+	queue := []*DecisionTreeNode{&t.Root}
+	levels := []int{0}
+	nodes := []byte{}
+	next := 1
+	for i := 0; i < len(queue); i++ {
+		n := queue[i]
+		nodes = wio.ByteOrder.AppendUint32(nodes, n.Key)
+		if levels[i] == depth {
+			nodes = wio.ByteOrder.AppendUint32(nodes, n.AudioNodeID)
+		} else {
+			nodes = wio.ByteOrder.AppendUint16(nodes, uint16(next))
+			nodes = wio.ByteOrder.AppendUint16(nodes, uint16(len(n.Children)))
+			for c := range n.Children {
+				queue = append(queue, &n.Children[c])
+				levels = append(levels, levels[i] + 1)
+			}
+			next += len(n.Children)
+		}
+		nodes = wio.ByteOrder.AppendUint16(nodes, n.Weight)
+		nodes = wio.ByteOrder.AppendUint16(nodes, n.Probability)
+	}
+
+	b := wio.ByteOrder.AppendUint32(nil, uint32(depth))
+	for _, a := range t.Arguments {
+		b = wio.ByteOrder.AppendUint32(b, a.GroupID)
+	}
+	for _, a := range t.Arguments {
+		b = append(b, uint8(a.GroupType))
+	}
+	b = wio.ByteOrder.AppendUint32(b, uint32(len(nodes)))
+	b = append(b, t.Mode)
+	return append(b, nodes...)
+}
+
+// Point the path of switches / states (one per argument, 0 for default) at
+// an audio node. Missing nodes on the path are created.
+func (t *DecisionTree) Assign(path []uint32, audioNodeID uint32) error {
+	if len(path) != len(t.Arguments) {
+		return fmt.Errorf("Decision tree path needs %d switches / states, got %d", len(t.Arguments), len(path))
+	}
+	n := &t.Root
+	for _, key := range path {
+		i, in := slices.BinarySearchFunc(n.Children, key, func(c DecisionTreeNode, k uint32) int {
+			return cmp.Compare(c.Key, k)
+		})
+		if !in {
+			n.Children = slices.Insert(n.Children, i, DecisionTreeNode{
+				Key: key,
+				Weight: DecisionTreeDefaultWeight,
+				Probability: DecisionTreeDefaultProbability,
+			})
+		}
+		n = &n.Children[i]
+	}
+	n.AudioNodeID = audioNodeID
+	return nil
+}
+
+// Remove paths playing an audio node. Branches left without any path are
+// removed as well. Return the number of removed paths.
+func (t *DecisionTree) Unassign(audioNodeID uint32) int {
+	depth := len(t.Arguments)
+	if depth == 0 {
+		if t.Root.AudioNodeID != audioNodeID {
+			return 0
+		}
+		t.Root.AudioNodeID = 0
+		return 1
+	}
+	removed := 0
+	var prune func(n *DecisionTreeNode, level int)
+	prune = func(n *DecisionTreeNode, level int) {
+		kept := n.Children[:0]
+		for _, c := range n.Children {
+			if level + 1 == depth {
+				if c.AudioNodeID == audioNodeID {
+					removed += 1
+					continue
+				}
+			} else if len(c.Children) > 0 {
+				prune(&c, level + 1)
+				if len(c.Children) == 0 {
+					continue
+				}
+			}
+			kept = append(kept, c)
+		}
+		n.Children = kept
+	}
+	prune(&t.Root, 0)
+	return removed
+}
+
+// Prototyping
+func (h *MusicSwitchCntr) AssignDecisionTreePath(path []uint32, audioNodeID uint32) error {
+	if !slices.Contains(h.Children.Children, audioNodeID) {
+		return fmt.Errorf("%d is not a child of music switch container %d", audioNodeID, h.Id)
+	}
+	t, err := h.DecisionTree()
+	if err != nil {
+		return err
+	}
+	if err := t.Assign(path, audioNodeID); err != nil {
+		return err
+	}
+	h.DecisionTreeData = t.Encode()
+	return nil
+}
+
+// Arguments of the decision tree with switches / states used on its paths
+func (h *MusicSwitchCntr) DecisionTreeArguments() ([]DecisionTreeArgument, error) {
+	t, err := h.DecisionTree()
+	if err != nil {
+		return nil, err
+	}
+	var collect func(n *DecisionTreeNode, level int)
+	collect = func(n *DecisionTreeNode, level int) {
+		if level > 0 {
+			a := &t.Arguments[level - 1]
+			if !slices.Contains(a.Keys, n.Key) {
+				a.Keys = append(a.Keys, n.Key)
+			}
+		}
+		for c := range n.Children {
+			collect(&n.Children[c], level + 1)
+		}
+	}
+	collect(&t.Root, 0)
+	return t.Arguments, nil
+}
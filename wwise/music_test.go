@@ -0,0 +1,250 @@
+package wwise
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+func TestMusicRanSeqCntrLeaf(t *testing.T) {
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	cntr := &MusicRanSeqCntr{Id: 1, PlayListNode: NewMusicPlayListGroup(100, MusicRSTypeContinuousSequence)}
+	intro := &MusicSegment{Id: 10}
+	loop := &MusicSegment{Id: 11, BaseParam: BaseParameter{DirectParentId: 1}}
+	cntr.Children.Children = []uint32{11}
+	h.HircObjs = append(h.HircObjs, intro, loop, cntr)
+
+	h.ChangeRoot(10, 1, 0, false)
+	if !slices.Equal(cntr.Children.Children, []uint32{11, 10}) || intro.BaseParam.DirectParentId != 1 {
+		t.Fatalf("Expecting 10 to be added to music random / sequence container, got %v", cntr.Children.Children)
+	}
+
+	if err := cntr.AddPlayListSegment(100, 101, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := cntr.AddPlayListGroup(100, 102, MusicRSTypeStepRandom); err != nil {
+		t.Fatal(err)
+	}
+	for i := range uint32(2) {
+		if err := cntr.AddPlayListSegment(102, 103 + i, 11 - i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cntr.AddPlayListSegment(101, 105, 11); err == nil {
+		t.Fatal("Expecting segment node to not have children")
+	}
+	if err := cntr.AddPlayListSegment(100, 105, 12); err == nil {
+		t.Fatal("Expecting play list to only contain children")
+	}
+	if cntr.PlayListNode.NumNodes() != 5 {
+		t.Fatalf("Expecting 5 play list nodes, got %d", cntr.PlayListNode.NumNodes())
+	}
+
+	any := NewMusicTransitionRule([]uint32{MusicTransitionAny}, []uint32{MusicTransitionAny})
+	if err := cntr.AddTransitionRule(any); err != nil {
+		t.Fatal(err)
+	}
+	rule := NewMusicTransitionRule([]uint32{10, 11}, []uint32{11, MusicTransitionNothing})
+	rule.SetTransitionSegment(10)
+	rule.TransitionDestRule.JumpToType = JumpToTypeSpecificItem
+	rule.TransitionDestRule.JumpToID = 104
+	if err := cntr.AddTransitionRule(rule); err != nil {
+		t.Fatal(err)
+	}
+	rule = NewMusicTransitionRule([]uint32{11}, []uint32{10})
+	if err := cntr.AddTransitionRule(rule); err != nil {
+		t.Fatal(err)
+	}
+	rule.SetTransitionSegment(12)
+	if err := cntr.AddTransitionRule(rule); err == nil {
+		t.Fatal("Expecting transition segment to be a child")
+	}
+
+	h.RemoveRoot(10, 1, false)
+	if intro.BaseParam.DirectParentId != 0 || slices.Contains(cntr.Children.Children, 10) {
+		t.Fatal("Expecting 10 to be removed from music random / sequence container")
+	}
+	if cntr.PlayListNode.NumNodes() != 3 || cntr.PlayListNode.Node(101) != nil || cntr.PlayListNode.Node(104) != nil {
+		t.Fatalf("Expecting play list nodes of 10 to be removed, got %+v", cntr.PlayListNode)
+	}
+	if len(cntr.TransitionRules) != 2 {
+		t.Fatalf("Expecting the rule from 11 to 10 to be dropped, got %d rules", len(cntr.TransitionRules))
+	}
+	r := &cntr.TransitionRules[1]
+	if !slices.Equal(r.SrcIDs, []uint32{11}) || !slices.Equal(r.DestIDs, []uint32{11, 0}) {
+		t.Fatalf("Unexpected transition rule %+v", r)
+	}
+	if r.HasTransitionObj() || r.TransitionDestRule.JumpToType != JumpToTypeStartOfPlaylist {
+		t.Fatalf("Expecting references to 10 to be cleared, got %+v", r)
+	}
+
+	if err := cntr.RemoveTransitionRule(0); err == nil {
+		t.Fatal("Expecting the default transition rule to not be removed")
+	}
+	if err := cntr.RemoveTransitionRule(2); err == nil {
+		t.Fatal("Expecting out of range transition rule to not be removed")
+	}
+	if err := cntr.RemoveTransitionRule(1); err != nil || len(cntr.TransitionRules) != 1 {
+		t.Fatalf("Expecting transition rule 1 to be removed, got %v", err)
+	}
+}
+
+func TestMusicPlayListNode(t *testing.T) {
+	root := NewMusicPlayListGroup(1, MusicRSTypeContinuousSequence)
+	root.AppendLeaf(NewMusicPlayListSegment(2, 10))
+	root.AppendLeaf(NewMusicPlayListGroup(3, MusicRSTypeContinuousRandom))
+	root.AppendLeaf(NewMusicPlayListSegment(4, 11))
+	root.Node(3).AppendLeaf(NewMusicPlayListSegment(5, 10))
+
+	root.MoveLeaf(2, 0)
+	order := []uint32{}
+	for _, l := range root.PlayListLeafs {
+		order = append(order, l.PlayListItemID)
+	}
+	if !slices.Equal(order, []uint32{4, 2, 3}) {
+		t.Fatalf("Unexpected play list order %v", order)
+	}
+	if !root.Node(3).IsRandom() || root.Node(5).IsGroup() {
+		t.Fatal("Unexpected node type")
+	}
+	if !root.RemoveNode(3) || root.Node(5) != nil || root.RemoveNode(3) {
+		t.Fatal("Expecting group 3 and its sub tree to be removed once")
+	}
+}
+
+func TestMusicSegmentLeaf(t *testing.T) {
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	seg := &MusicSegment{Id: 1}
+	track := &MusicTrack{Id: 10}
+	h.HircObjs = append(h.HircObjs, track, seg)
+	h.ChangeRoot(10, 1, 0, false)
+	if !slices.Equal(seg.Children.Children, []uint32{10}) || track.BaseParam.DirectParentId != 1 {
+		t.Fatal("Expecting music track to be added to music segment")
+	}
+	h.RemoveRoot(10, 1, false)
+	if len(seg.Children.Children) != 0 || track.BaseParam.DirectParentId != 0 {
+		t.Fatal("Expecting music track to be removed from music segment")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expecting music segment to reject a music segment child")
+		}
+	}()
+	seg.AddLeaf(&MusicSegment{Id: 2})
+}
+
+func hircOrder(h *HIRC) []uint32 {
+	order := []uint32{}
+	for _, o := range h.HircObjs {
+		id, _ := o.HircID()
+		order = append(order, id)
+	}
+	return order
+}
+
+func TestChangeRootSubtree(t *testing.T) {
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	mixer := &ActorMixer{Id: 1, BaseParam: &BaseParameter{}}
+	sound := &Sound{Id: 11, BaseParam: &BaseParameter{DirectParentId: 10}}
+	cntr := &RanSeqCntr{Id: 10, Container: Container{Children: []uint32{11}}}
+	action, _ := NewAction(30, 0x04, 11, 141)
+	h.HircObjs = []HircObj{mixer, sound, cntr, action}
+
+	h.ChangeRoot(10, 1, 0, false)
+	if !slices.Equal(hircOrder(h), []uint32{11, 10, 1, 30}) || cntr.BaseParam.DirectParentId != 1 {
+		t.Fatalf("Expecting random / sequence container to move with its children, got %v", hircOrder(h))
+	}
+	h.ChangeRoot(1, 10, 0, false)
+	if !slices.Equal(hircOrder(h), []uint32{11, 10, 1, 30}) || mixer.BaseParam.DirectParentId != 0 {
+		t.Fatal("Expecting moving under a descendant to be rejected")
+	}
+	h.RemoveRoot(10, 1, false)
+	if !slices.Equal(hircOrder(h), []uint32{1, 11, 10, 30}) || cntr.BaseParam.DirectParentId != 0 {
+		t.Fatalf("Expecting random / sequence container to be detached with its children, got %v", hircOrder(h))
+	}
+
+	h = NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	music := &MusicRanSeqCntr{Id: 2, PlayListNode: NewMusicPlayListGroup(100, MusicRSTypeContinuousSequence)}
+	track := &MusicTrack{Id: 21, BaseParam: BaseParameter{DirectParentId: 20}}
+	seg := &MusicSegment{Id: 20, Children: Container{Children: []uint32{21}}}
+	h.HircObjs = []HircObj{music, track, seg}
+	h.ChangeRoot(20, 2, 0, false)
+	if !slices.Equal(hircOrder(h), []uint32{21, 20, 2}) {
+		t.Fatalf("Expecting music segment to move with its tracks, got %v", hircOrder(h))
+	}
+}
+
+func TestDecisionTree(t *testing.T) {
+	le := binary.LittleEndian
+	node := func(b []byte, key uint32, child uint32, weight uint16) []byte {
+		b = le.AppendUint32(b, key)
+		b = le.AppendUint32(b, child)
+		b = le.AppendUint16(b, weight)
+		return le.AppendUint16(b, 100)
+	}
+	// Root -> switch 0 (*) and 101 -> state 201 -> segments 10 and 11.
+	// Branch nodes store child index and count as two uint16.
+	nodes := node(nil, 0, 1 | 2 << 16, 50)
+	nodes = node(nodes, 0, 3 | 1 << 16, 50)
+	nodes = node(nodes, 101, 4 | 1 << 16, 50)
+	nodes = node(nodes, 201, 10, 30)
+	nodes = node(nodes, 201, 11, 70)
+	data := le.AppendUint32(nil, 2)
+	data = le.AppendUint32(data, 100)
+	data = le.AppendUint32(data, 200)
+	data = append(data, uint8(GroupTypeSwitch), uint8(GroupTypeState))
+	data = le.AppendUint32(data, uint32(len(nodes)))
+	data = append(data, 1)
+	data = append(data, nodes...)
+
+	sw := &MusicSwitchCntr{Id: 1, DecisionTreeData: data}
+	sw.Children.Children = []uint32{10, 11, 12}
+	tree, err := sw.DecisionTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Mode != 1 || len(tree.Root.Children) != 2 || tree.Root.Children[1].Children[0].AudioNodeID != 11 {
+		t.Fatalf("Unexpected decision tree %+v", tree)
+	}
+	if !bytes.Equal(tree.Encode(), data) {
+		t.Fatal("Expecting decision tree to encode back into the same bytes")
+	}
+
+	if err := sw.AssignDecisionTreePath([]uint32{101, 202}, 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.AssignDecisionTreePath([]uint32{0, 201}, 12); err != nil {
+		t.Fatal(err)
+	}
+	tree, err = sw.DecisionTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := tree.Root.Children[1].Children
+	if len(states) != 2 || states[0].AudioNodeID != 11 || states[1].Key != 202 || states[1].AudioNodeID != 12 {
+		t.Fatalf("Expecting a new path 101 -> 202, got %+v", states)
+	}
+	if tree.Root.Children[0].Children[0].AudioNodeID != 12 {
+		t.Fatal("Expecting existing path * -> 201 to be pointed at 12")
+	}
+
+	if err := sw.AssignDecisionTreePath([]uint32{101}, 12); err == nil {
+		t.Fatal("Expecting path shorter than the number of arguments to fail")
+	}
+	if err := sw.AssignDecisionTreePath([]uint32{101, 203}, 13); err == nil {
+		t.Fatal("Expecting path to a non child to fail")
+	}
+
+	// Path * -> 201 is the only path under * so the whole branch goes away
+	sw.RemoveLeaf(&MusicSegment{Id: 12, BaseParam: BaseParameter{DirectParentId: 1}})
+	tree, err = sw.DecisionTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := tree.Root.Children
+	if len(paths) != 1 || paths[0].Key != 101 || len(paths[0].Children) != 1 || paths[0].Children[0].AudioNodeID != 11 {
+		t.Fatalf("Expecting paths to removed child to be removed, got %+v", paths)
+	}
+}
@@ -0,0 +1,87 @@
+package wwise
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Dekr0/wwise-teller/utils"
+)
+
+// Short ID of a Wwise object name. Wwise computes IDs of events, buses, game
+// parameters, state groups, states, switch groups and switches by using
+// FNV-1 32 bit on the lower case name.
+func ShortID(name string) uint32 {
+	id, err := utils.FNV32([]byte(strings.ToLower(name)))
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// Reverse lookup from ID to name. Safe for concurrent use.
+type NameDB struct {
+	l     sync.RWMutex
+	names map[uint32]string
+}
+
+func NewNameDB() *NameDB {
+	return &NameDB{names: make(map[uint32]string, 1024)}
+}
+
+// Hash a candidate name and register it. Return its short ID.
+func (n *NameDB) Add(name string) uint32 {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0
+	}
+	id := ShortID(name)
+	n.Set(id, name)
+	return id
+}
+
+// Register a known ID and name pair without hashing (e.g. from
+// SoundbanksInfo, where media and GUID based IDs are not name hashes).
+// An existing name is kept.
+func (n *NameDB) Set(id uint32, name string) {
+	if id == 0 || name == "" {
+		return
+	}
+	n.l.Lock()
+	if _, in := n.names[id]; !in {
+		n.names[id] = name
+	}
+	n.l.Unlock()
+}
+
+func (n *NameDB) Name(id uint32) (string, bool) {
+	n.l.RLock()
+	name, in := n.names[id]
+	n.l.RUnlock()
+	return name, in
+}
+
+func (n *NameDB) Len() int {
+	n.l.RLock()
+	defer n.l.RUnlock()
+	return len(n.names)
+}
+
+// Known name of an ID. Empty if there's none or the name database is nil.
+func (n *NameDB) nameOf(id uint32) string {
+	if n == nil {
+		return ""
+	}
+	name, _ := n.Name(id)
+	return name
+}
+
+// Return "name (ID)" if the ID has a known name. Otherwise, or if the name
+// database is nil, return the ID.
+func (n *NameDB) Label(id uint32) string {
+	sid := strconv.FormatUint(uint64(id), 10)
+	if name := n.nameOf(id); name != "" {
+		return name + " (" + sid + ")"
+	}
+	return sid
+}
@@ -46,6 +46,34 @@ func InverseTranslateProp(p uint8, v int) PropType {
 	panic(fmt.Sprintf("Inverse Translation is not implemented for version %d", v))
 }
 
+// Same as ForwardTranslateProp except that it reports a property that is not
+// available in the version instead of panicking.
+func LookupForwardTranslateProp(p PropType, v int) (uint8, bool) {
+	if v < 150 {
+		tp, in := ForwardTranslationV128[p]
+		return tp, in
+	}
+	if v >= 154 {
+		tp, in := ForwardTranslationV154[p]
+		return tp, in
+	}
+	return 0, false
+}
+
+// Same as InverseTranslateProp except that it reports an untranslatable
+// property ID instead of panicking.
+func LookupInverseTranslateProp(p uint8, v int) (PropType, bool) {
+	if v < 150 {
+		tp, in := InverseTranslationV128[p]
+		return tp, in
+	}
+	if v >= 154 {
+		tp, in := InverseTranslationV154[p]
+		return tp, in
+	}
+	return 0, false
+}
+
 func PropLabel(p PropType) string {
 	name, in := TranslateName[p]
 	if !in {
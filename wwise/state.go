@@ -1,6 +1,10 @@
 package wwise
 
-import "github.com/Dekr0/wwise-teller/wio"
+import (
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/wio"
+)
 
 type GroupType uint8
 const (
@@ -24,6 +28,45 @@ type State struct {
 	}
 }
 
+func NewState(id uint32) *State {
+	return &State{StateID: id, StateProps: []struct{
+		PID uint16
+		Val float32
+	}{}}
+}
+
+func (s *State) Value(pid uint16) (float32, bool) {
+	for _, sp := range s.StateProps {
+		if sp.PID == pid {
+			return sp.Val, true
+		}
+	}
+	return 0, false
+}
+
+// Set a property value. The property is added if it does not exist.
+func (s *State) SetValue(pid uint16, val float32) {
+	for i := range s.StateProps {
+		if s.StateProps[i].PID == pid {
+			s.StateProps[i].Val = val
+			return
+		}
+	}
+	s.StateProps = append(s.StateProps, struct{
+		PID uint16
+		Val float32
+	}{pid, val})
+}
+
+func (s *State) RemoveValue(pid uint16) {
+	s.StateProps = slices.DeleteFunc(s.StateProps, func(sp struct{
+		PID uint16
+		Val float32
+	}) bool {
+		return sp.PID == pid
+	})
+}
+
 func (s *State) Encode(v int) []byte {
 	dataSize := s.DataSize(v)
 	size := SizeOfHircObjHeader + dataSize
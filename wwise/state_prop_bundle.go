@@ -1,6 +1,12 @@
 package wwise
 
-import "github.com/Dekr0/wwise-teller/wio"
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+
+	"github.com/Dekr0/wwise-teller/wio"
+)
 
 type StatePropType uint16
 
@@ -28,3 +34,35 @@ func (p *StatePropBundle) Encode(v int) []byte {
 func (s *StatePropBundle) Size(int) uint32 {
 	return 2 + uint32(len(s.StatePropValues)) * 6
 }
+
+func (p *StatePropBundle) Clone() StatePropBundle {
+	c := StatePropBundle{StatePropValues: make([]StatePropValue, len(p.StatePropValues))}
+	for i, pv := range p.StatePropValues {
+		c.StatePropValues[i] = StatePropValue{pv.P, bytes.Clone(pv.V)}
+	}
+	return c
+}
+
+func (p *StatePropBundle) Value(t StatePropType) (float32, bool) {
+	i := slices.IndexFunc(p.StatePropValues, func(pv StatePropValue) bool { return pv.P == t })
+	if i == -1 {
+		return 0, false
+	}
+	var f float32
+	binary.Decode(p.StatePropValues[i].V, wio.ByteOrder, &f)
+	return f, true
+}
+
+// Set a property value. The property is added if it does not exist.
+func (p *StatePropBundle) Set(t StatePropType, val float32) {
+	i := slices.IndexFunc(p.StatePropValues, func(pv StatePropValue) bool { return pv.P == t })
+	if i == -1 {
+		p.StatePropValues = append(p.StatePropValues, StatePropValue{t, []byte{0, 0, 0, 0}})
+		i = len(p.StatePropValues) - 1
+	}
+	binary.Encode(p.StatePropValues[i].V, wio.ByteOrder, val)
+}
+
+func (p *StatePropBundle) Remove(t StatePropType) {
+	p.StatePropValues = slices.DeleteFunc(p.StatePropValues, func(pv StatePropValue) bool { return pv.P == t })
+}
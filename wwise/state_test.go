@@ -0,0 +1,89 @@
+package wwise
+
+import "testing"
+
+func TestStateGroupEditing(t *testing.T) {
+	health := ShortID("Health")
+	low := ShortID("Low")
+	for _, v := range []int{141, 154} {
+		h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+		s := &Sound{Id: 10, BaseParam: &BaseParameter{}}
+		h.HircObjs = []HircObj{s}
+		h.ActorMixerHirc.Store(s.Id, s)
+		b := s.BaseParam
+
+		if err := h.AddStateGroup(10, health, NumSyncType); err == nil {
+			t.Fatal("Expecting error on invalid sync type")
+		}
+		if err := h.AddStateGroup(10, health, 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.AddStateGroup(10, health, 0); err == nil {
+			t.Fatal("Expecting error on adding the same state group twice")
+		}
+		if err := h.SetStateSyncType(10, health, 3); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.AddState(10, health, low, 1000, v); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.SetStateProp(10, health, low, RTPCParameterTypeVolume, -6, v); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.SetStateProp(10, health, low, RTPCParameterTypeLPF, 30, v); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.SetStateProp(10, health, low, RTPCParameterTypeBusVolume, 0, v); err == nil {
+			t.Fatal("Expecting error on a bus only property")
+		}
+		if err := h.SetStateProp(10, health, ShortID("High"), RTPCParameterTypeVolume, 0, v); err == nil {
+			t.Fatal("Expecting error on a missing state")
+		}
+
+		g := b.StateGroup.Group(health)
+		if g == nil || g.StateSyncType != 3 || g.NumStates.Value != 1 || b.StateGroup.NumStateGroups.Value != 1 {
+			t.Fatalf("Unexpected state group %+v", b.StateGroup)
+		}
+		if b.StateProp.NumStateProps.Value != 2 || b.StateProp.StatePropItems[0].InDb != 1 || b.StateProp.StatePropItems[1].InDb != 0 {
+			t.Fatalf("Unexpected state properties %+v", b.StateProp)
+		}
+		if val, in := h.StateValue(g.State(low), RTPCParameterTypeVolume, v); !in || val != -6 {
+			t.Fatalf("Expecting volume -6 in version %d, got %f", v, val)
+		}
+		_, inst := h.States.Load(uint32(1000))
+		if inst != (v <= 145) || (len(h.HircObjs) == 2) != inst {
+			t.Fatalf("Unexpected state instance in version %d", v)
+		}
+		b.StateProp.Encode(v)
+		b.StateGroup.Encode(v)
+
+		c := b.StateGroup.Clone()
+		c.StateGroupItems[0].States[0].StatePropBundle.Set(StatePropType(RTPCParameterTypeVolume), 0)
+		if val, _ := h.StateValue(g.State(low), RTPCParameterTypeVolume, v); val != -6 {
+			t.Fatal("Clone shares state property values")
+		}
+		if c.StateGroupItems[0].NumStates.Value != 1 {
+			t.Fatal("Clone does not copy number of states")
+		}
+
+		if err := h.RemoveStateProp(10, RTPCParameterTypeLPF, v); err != nil {
+			t.Fatal(err)
+		}
+		if _, in := h.StateValue(g.State(low), RTPCParameterTypeLPF, v); in || b.StateProp.Index(RTPCParameterTypeLPF) != -1 {
+			t.Fatal("Expecting LPF is removed from state properties")
+		}
+		if err := h.RemoveState(10, health, low); err != nil {
+			t.Fatal(err)
+		}
+		if _, in := h.States.Load(uint32(1000)); in || len(h.HircObjs) != 1 {
+			t.Fatal("Expecting unused state instance is removed")
+		}
+		if err := h.RemoveStateGroup(10, health); err != nil {
+			t.Fatal(err)
+		}
+		if len(b.StateGroup.StateGroupItems) != 0 || b.StateGroup.NumStateGroups.Value != 0 {
+			t.Fatal("Expecting state group is removed")
+		}
+		b.StateGroup.Encode(v)
+	}
+}
@@ -2,7 +2,8 @@ package wwise
 
 import (
 	"encoding/binary"
-	"log/slog"
+	"fmt"
+	"slices"
 
 	"github.com/Dekr0/wwise-teller/wio"
 )
@@ -132,12 +133,85 @@ func (s *SwitchCntr) NumLeaf() int { return len(s.Container.Children) }
 
 func (s *SwitchCntr) ParentID() uint32 { return s.BaseParam.DirectParentId }
 
+// A new leaf is not assigned to any switch. It comes with default playback
+// setting.
 func (s *SwitchCntr) AddLeaf(o HircObj) {
-	slog.Warn("Adding new leaf is not implemented for switch container.")
+	id, err := o.HircID()
+	if err != nil {
+		panic("Passing a hierarchy without a hierarchy ID.")
+	}
+	b := o.BaseParameter()
+	if b == nil {
+		panic(fmt.Sprintf("%d is not containable.", id))
+	}
+	if b.DirectParentId != 0 {
+		panic(fmt.Sprintf("%d is already attach to root %d. AddLeaf is an atomic operation.", id, b.DirectParentId))
+	}
+	if slices.Contains(s.Container.Children, id) {
+		panic(fmt.Sprintf("%d is already in switch container %d", id, s.Id))
+	}
+	s.Container.Children = append(s.Container.Children, id)
+	if !slices.ContainsFunc(s.SwitchParams, func(p SwitchParam) bool { return p.NodeId == id }) {
+		s.SwitchParams = append(s.SwitchParams, SwitchParam{NodeId: id})
+	}
+	b.DirectParentId = s.Id
 }
 
+// The leaf is also unassigned from all switches.
 func (s *SwitchCntr) RemoveLeaf(o HircObj) {
-	slog.Warn("Removing old leaf is not implemented for switch container.")
+	id, err := o.HircID()
+	if err != nil {
+		panic("Passing a hierarchy without a hierarchy ID.")
+	}
+	b := o.BaseParameter()
+	if b == nil {
+		panic(fmt.Sprintf("%d is not containable.", id))
+	}
+	l := len(s.Container.Children)
+	s.Container.Children = slices.DeleteFunc(s.Container.Children, func(c uint32) bool {
+		return c == id
+	})
+	if l <= len(s.Container.Children) {
+		panic(fmt.Sprintf("%d is not in switch container %d", id, s.Id))
+	}
+	for i := range s.SwitchGroups {
+		s.UnassignSwitch(s.SwitchGroups[i].SwitchID, id)
+	}
+	s.SwitchParams = slices.DeleteFunc(s.SwitchParams, func(p SwitchParam) bool {
+		return p.NodeId == id
+	})
+	b.DirectParentId = 0
+}
+
+// Play a leaf when the switch is active. The leaf must be a child of this
+// container.
+func (s *SwitchCntr) AssignSwitch(switchID uint32, id uint32) {
+	if !slices.Contains(s.Container.Children, id) {
+		panic(fmt.Sprintf("%d is not in switch container %d", id, s.Id))
+	}
+	i := slices.IndexFunc(s.SwitchGroups, func(g SwitchGroupItem) bool {
+		return g.SwitchID == switchID
+	})
+	if i == -1 {
+		s.SwitchGroups = append(s.SwitchGroups, SwitchGroupItem{SwitchID: switchID, NodeList: []uint32{}})
+		i = len(s.SwitchGroups) - 1
+	}
+	if !slices.Contains(s.SwitchGroups[i].NodeList, id) {
+		s.SwitchGroups[i].NodeList = append(s.SwitchGroups[i].NodeList, id)
+	}
+}
+
+// A switch without any leaf remains in the switch list.
+func (s *SwitchCntr) UnassignSwitch(switchID uint32, id uint32) {
+	i := slices.IndexFunc(s.SwitchGroups, func(g SwitchGroupItem) bool {
+		return g.SwitchID == switchID
+	})
+	if i == -1 {
+		return
+	}
+	s.SwitchGroups[i].NodeList = slices.DeleteFunc(s.SwitchGroups[i].NodeList, func(n uint32) bool {
+		return n == id
+	})
 }
 
 func (s *SwitchCntr) Leafs() []uint32 { return s.Container.Children }
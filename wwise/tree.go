@@ -206,21 +206,31 @@ func (h *HIRC) HDRAvailability() {
 	}
 }
 
+// HDR can be enabled on a bus only if HDR is not enabled in any of its parent
+// buses. Parent buses not in this sound bank are ignored.
 func (h *HIRC) CanSetHDR(b *Bus, parentID uint32) int8 {
-	if parentID == 0 {
-		b.CanSetHDR = 1
-		return b.CanSetHDR
-	}
-	v, in := h.Buses.Load(parentID)
-	if !in {
-		panic("Panic Trap")
-	}
-	parentBus := v.(*Bus)
-	if parentBus.CanSetHDR != -1 {
-		b.CanSetHDR = parentBus.CanSetHDR
-		return b.CanSetHDR
+	b.CanSetHDR = 1
+	for parentID != 0 {
+		o, in := h.Bus(parentID)
+		if !in {
+			break
+		}
+		switch parent := o.(type) {
+		case *Bus:
+			if parent.IsHDRBus() {
+				b.CanSetHDR = 0
+			}
+			parentID = parent.OverrideBusId
+		case *AuxBus:
+			if parent.IsHDRBus() {
+				b.CanSetHDR = 0
+			}
+			parentID = parent.OverrideBusId
+		}
+		if b.CanSetHDR == 0 {
+			break
+		}
 	}
-	b.CanSetHDR = h.CanSetHDR(parentBus, parentBus.OverrideBusId)
 	return b.CanSetHDR
 }
 
@@ -0,0 +1,312 @@
+package wwise
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+
+	"github.com/Dekr0/wwise-teller/wio"
+)
+
+type RefType uint8
+
+const (
+	RefTypeParent         RefType = 0
+	RefTypeChild          RefType = 1
+	RefTypeActionTarget   RefType = 2
+	RefTypeEventAction    RefType = 3
+	RefTypeSource         RefType = 4
+	RefTypeAttenuation    RefType = 5
+	RefTypeFxShareSet     RefType = 6
+	RefTypeFxCustom       RefType = 7
+	RefTypeModulator      RefType = 8
+	RefTypeGameParameter  RefType = 9
+	RefTypeStateGroup     RefType = 10
+	RefTypeState          RefType = 11
+	RefTypeSwitchGroup    RefType = 12
+	RefTypeSwitch         RefType = 13
+	RefTypeOutputBus      RefType = 14
+	RefTypeAuxBus         RefType = 15
+	RefTypeDuckBus        RefType = 16
+	RefTypeEvent          RefType = 17
+	RefTypeExcept         RefType = 18
+	RefTypeCount          RefType = 19
+)
+
+var RefTypeName []string = []string{
+	"Parent",
+	"Child",
+	"Action Target",
+	"Event Action",
+	"Audio Source",
+	"Attenuation",
+	"FX Share Set",
+	"FX Custom",
+	"Modulator",
+	"Game Parameter",
+	"State Group",
+	"State",
+	"Switch Group",
+	"Switch",
+	"Output Bus",
+	"Auxiliary Bus",
+	"Ducking Bus",
+	"Event",
+	"Action Exception",
+}
+
+// A hierarchy object (From) references an ID in a specific way.
+type Ref struct {
+	From     uint32
+	FromType HircType
+	Type     RefType
+}
+
+// Reverse index of HIRC: ID -> hierarchy objects that reference this ID.
+// The index is a snapshot. It needs to rebuild after HIRC is modified.
+type XRef struct {
+	Refs map[uint32][]Ref
+}
+
+func (x *XRef) add(id uint32, from uint32, fromType HircType, t RefType) {
+	if id == 0 {
+		return
+	}
+	x.Refs[id] = append(x.Refs[id], Ref{from, fromType, t})
+}
+
+// Return all hierarchy objects referencing this ID
+func (x *XRef) References(id uint32) []Ref {
+	return x.Refs[id]
+}
+
+// Return all hierarchy objects referencing this ID in a specific way
+func (x *XRef) ReferencesOf(id uint32, t RefType) []Ref {
+	refs := []Ref{}
+	for _, r := range x.Refs[id] {
+		if r.Type == t {
+			refs = append(refs, r)
+		}
+	}
+	return refs
+}
+
+// Whether an ID is referenced by any hierarchy object other than its
+// children and its parent. Mainly for automation safety check before removing
+// or rewiring an object.
+func (x *XRef) Referenced(id uint32) bool {
+	for _, r := range x.Refs[id] {
+		if r.Type != RefTypeChild && r.Type != RefTypeParent {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *HIRC) BuildXRef(v int) *XRef {
+	x := &XRef{Refs: make(map[uint32][]Ref, len(h.HircObjs))}
+	for _, o := range h.HircObjs {
+		id, err := o.HircID()
+		if err != nil {
+			continue
+		}
+		t := o.HircType()
+
+		if b := o.BaseParameter(); b != nil {
+			x.addBaseParameter(b, id, t, v)
+		}
+		for _, leaf := range o.Leafs() {
+			x.add(leaf, id, t, RefTypeParent)
+		}
+
+		switch o := o.(type) {
+		case *Sound:
+			x.add(o.BankSourceData.SourceID, id, t, RefTypeSource)
+		case *MusicTrack:
+			for _, s := range o.Sources {
+				x.add(s.SourceID, id, t, RefTypeSource)
+			}
+			for _, p := range o.PlayListItems {
+				x.add(p.EventID, id, t, RefTypeEvent)
+			}
+		case *SwitchCntr:
+			if GroupType(o.GroupType) == GroupTypeState {
+				x.add(o.GroupID, id, t, RefTypeStateGroup)
+				x.add(o.DefaultSwitch, id, t, RefTypeState)
+			} else {
+				x.add(o.GroupID, id, t, RefTypeSwitchGroup)
+				x.add(o.DefaultSwitch, id, t, RefTypeSwitch)
+			}
+		case *MusicSwitchCntr:
+			x.addDecisionTree(o, id, t)
+		case *Event:
+			for _, a := range o.ActionIDs {
+				x.add(a, id, t, RefTypeEventAction)
+			}
+		case *Action:
+			x.addAction(o, id, t)
+		case *Bus:
+			x.add(o.OverrideBusId, id, t, RefTypeOutputBus)
+			x.addAuxParam(&o.AuxParam, id, t)
+			x.addFxChunk(&o.BusFxParam.FxChunk, id, t, v)
+			if v <= 145 && o.BusFxParam.FxID_0 != 0 {
+				if o.BusFxParam.IsShareSet_0 != 0 {
+					x.add(o.BusFxParam.FxID_0, id, t, RefTypeFxShareSet)
+				} else {
+					x.add(o.BusFxParam.FxID_0, id, t, RefTypeFxCustom)
+				}
+			}
+			for _, d := range o.DuckInfoList {
+				x.add(d.BusID, id, t, RefTypeDuckBus)
+			}
+			x.addRTPC(&o.BusRTPC, id, t)
+			x.addStateGroup(&o.StateGroup, id, t)
+		case *AuxBus:
+			x.add(o.OverrideBusId, id, t, RefTypeOutputBus)
+			x.addAuxParam(&o.AuxParam, id, t)
+			x.addFxChunk(&o.BusFxParam.FxChunk, id, t, v)
+			for _, d := range o.DuckInfoList {
+				x.add(d.BusID, id, t, RefTypeDuckBus)
+			}
+			x.addRTPC(&o.BusRTPC, id, t)
+			x.addStateGroup(&o.StateGroup, id, t)
+		case *Attenuation:
+			x.addRTPC(&o.RTPC, id, t)
+		case *FxShareSet:
+			for _, m := range o.MediaMap {
+				x.add(m.SourceId, id, t, RefTypeSource)
+			}
+			x.addRTPC(&o.RTPC, id, t)
+			x.addStateGroup(&o.StateGroup, id, t)
+		case *FxCustom:
+			for _, m := range o.MediaMap {
+				x.add(m.SourceId, id, t, RefTypeSource)
+			}
+			x.addRTPC(&o.RTPC, id, t)
+			x.addStateGroup(&o.StateGroup, id, t)
+		case *Modulator:
+			x.addRTPC(&o.RTPC, id, t)
+		}
+	}
+	return x
+}
+
+func (x *XRef) addBaseParameter(b *BaseParameter, id uint32, t HircType, v int) {
+	x.add(b.DirectParentId, id, t, RefTypeChild)
+	x.add(b.OverrideBusId, id, t, RefTypeOutputBus)
+	x.addFxChunk(&b.FxChunk, id, t, v)
+	x.addAuxParam(&b.AuxParam, id, t)
+	if _, p := b.PropBundle.Prop(TAttenuationID, v); p != nil {
+		var attenuationID uint32
+		binary.Decode(p.V, wio.ByteOrder, &attenuationID)
+		x.add(attenuationID, id, t, RefTypeAttenuation)
+	}
+	x.addRTPC(&b.RTPC, id, t)
+	x.addStateGroup(&b.StateGroup, id, t)
+}
+
+func (x *XRef) addFxChunk(f *FxChunk, id uint32, t HircType, v int) {
+	for _, item := range f.FxChunkItems {
+		if item.IsShareSet(v) {
+			x.add(item.FxId, id, t, RefTypeFxShareSet)
+		} else {
+			x.add(item.FxId, id, t, RefTypeFxCustom)
+		}
+	}
+}
+
+func (x *XRef) addAuxParam(a *AuxParam, id uint32, t HircType) {
+	for _, aux := range a.AuxIds {
+		x.add(aux, id, t, RefTypeAuxBus)
+	}
+	x.add(a.ReflectionAuxBus, id, t, RefTypeAuxBus)
+}
+
+func (x *XRef) addRTPC(r *RTPC, id uint32, t HircType) {
+	for _, item := range r.RTPCItems {
+		switch item.RTPCType {
+		case RTPCTypeGameParameter:
+			x.add(item.RTPCID, id, t, RefTypeGameParameter)
+		case RTPCTypeSwitch:
+			x.add(item.RTPCID, id, t, RefTypeSwitchGroup)
+		case RTPCTypeState:
+			x.add(item.RTPCID, id, t, RefTypeStateGroup)
+		case RTPCTypeModulator:
+			x.add(item.RTPCID, id, t, RefTypeModulator)
+		}
+	}
+}
+
+func (x *XRef) addStateGroup(s *StateGroup, id uint32, t HircType) {
+	for _, g := range s.StateGroupItems {
+		x.add(g.StateGroupID, id, t, RefTypeStateGroup)
+		for _, state := range g.States {
+			x.add(state.StateID, id, t, RefTypeState)
+		}
+	}
+}
+
+func (x *XRef) addDecisionTree(m *MusicSwitchCntr, id uint32, t HircType) {
+	args, err := m.DecisionTreeArguments()
+	if err != nil {
+		slog.Warn(fmt.Sprintf("Failed to index decision tree of music switch container %d", id), "error", err)
+		return
+	}
+	for _, a := range args {
+		groupRef, keyRef := RefTypeSwitchGroup, RefTypeSwitch
+		if a.GroupType == GroupTypeState {
+			groupRef, keyRef = RefTypeStateGroup, RefTypeState
+		}
+		x.add(a.GroupID, id, t, groupRef)
+		for _, k := range a.Keys {
+			x.add(k, id, t, keyRef)
+		}
+	}
+}
+
+func (x *XRef) addExcepts(excepts []ExceptParam, id uint32, t HircType) {
+	for _, e := range excepts {
+		x.add(e.ID, id, t, RefTypeExcept)
+	}
+}
+
+func (x *XRef) addAction(a *Action, id uint32, t HircType) {
+	switch p := a.ActionParam.(type) {
+	case *ActionSetStateParam:
+		x.add(p.StateGroupID, id, t, RefTypeStateGroup)
+		x.add(p.TargetStateID, id, t, RefTypeState)
+	case *ActionSetSwitchParam:
+		x.add(p.SwitchGroupID, id, t, RefTypeSwitchGroup)
+		x.add(p.SwitchStateID, id, t, RefTypeSwitch)
+	case *ActionSetRTPCParam:
+		x.add(p.RTPCID, id, t, RefTypeGameParameter)
+	case *ActionSetFXParam:
+		x.add(a.IdExt, id, t, RefTypeActionTarget)
+		if p.IsShared != 0 {
+			x.add(p.FXID, id, t, RefTypeFxShareSet)
+		} else {
+			x.add(p.FXID, id, t, RefTypeFxCustom)
+		}
+		x.addExcepts(p.ExceptParams, id, t)
+	case *ActionPlayEventParam:
+		x.add(a.IdExt, id, t, RefTypeEvent)
+	case *ActionSetValueParam:
+		if _, ok := p.AkSpecificParam.(*ActionSetGameParameterSpecificParam); ok {
+			x.add(a.IdExt, id, t, RefTypeGameParameter)
+		} else {
+			x.add(a.IdExt, id, t, RefTypeActionTarget)
+		}
+		x.addExcepts(p.ExceptParams, id, t)
+	case *ActionActiveParam:
+		x.add(a.IdExt, id, t, RefTypeActionTarget)
+		x.addExcepts(p.ExceptParams, id, t)
+	case *ActionByPassFXParam:
+		x.add(a.IdExt, id, t, RefTypeActionTarget)
+		x.addExcepts(p.ExceptParams, id, t)
+	case *ActionSeekParam:
+		x.add(a.IdExt, id, t, RefTypeActionTarget)
+		x.addExcepts(p.ExceptParams, id, t)
+	default:
+		x.add(a.IdExt, id, t, RefTypeActionTarget)
+	}
+}
@@ -0,0 +1,98 @@
+package wwise
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestXRef(t *testing.T) {
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	sound := &Sound{Id: 10, BaseParam: &BaseParameter{DirectParentId: 20}}
+	sound.BankSourceData.SourceID = 30
+	sound.BaseParam.AuxParam.AuxIds[0] = 40
+	sound.BaseParam.RTPC.RTPCItems = []RTPCItem{{RTPCID: 50, RTPCType: 0}}
+	cntr := &ActorMixer{Id: 20, BaseParam: &BaseParameter{}}
+	cntr.Container.Children = []uint32{10}
+	action := &Action{Id: 60, IdExt: 10, ActionParam: &ActionPlayParam{}}
+	event := &Event{Id: 70, ActionIDs: []uint32{60}}
+	h.HircObjs = []HircObj{sound, cntr, action, event}
+
+	x := h.BuildXRef(141)
+
+	check := func(id uint32, from uint32, r RefType) {
+		for _, ref := range x.References(id) {
+			if ref.From == from && ref.Type == r {
+				return
+			}
+		}
+		t.Fatalf("Expect %d references %d as %s", from, id, RefTypeName[r])
+	}
+	check(10, 20, RefTypeParent)
+	check(20, 10, RefTypeChild)
+	check(10, 60, RefTypeActionTarget)
+	check(60, 70, RefTypeEventAction)
+	check(30, 10, RefTypeSource)
+	check(40, 10, RefTypeAuxBus)
+	check(50, 10, RefTypeGameParameter)
+
+	if !x.Referenced(10) {
+		t.Fatal("Sound 10 is referenced by action 60")
+	}
+	if x.Referenced(20) {
+		t.Fatal("Actor mixer 20 is only referenced by its child")
+	}
+	if len(x.ReferencesOf(10, RefTypeActionTarget)) != 1 {
+		t.Fatal("Sound 10 should be referenced by exactly one action")
+	}
+}
+
+func TestXRefMusicSwitchCntr(t *testing.T) {
+	le := binary.LittleEndian
+	node := func(b []byte, key uint32, child uint16, count uint16) []byte {
+		b = le.AppendUint32(b, key)
+		b = le.AppendUint16(b, child)
+		b = le.AppendUint16(b, count)
+		return le.AppendUint32(b, 0)
+	}
+	nodes := node(nil, 0, 1, 2)
+	nodes = node(nodes, 101, 3, 1)
+	nodes = node(nodes, 0, 4, 1)
+	nodes = node(nodes, 201, 0, 0)
+	nodes = node(nodes, 202, 0, 0)
+	tree := le.AppendUint32(nil, 2)
+	tree = le.AppendUint32(tree, 100)
+	tree = le.AppendUint32(tree, 200)
+	tree = append(tree, uint8(GroupTypeSwitch), uint8(GroupTypeState))
+	tree = le.AppendUint32(tree, uint32(len(nodes)))
+	tree = append(tree, 0)
+	tree = append(tree, nodes...)
+
+	h := NewHIRC(0, []byte{'H', 'I', 'R', 'C'}, 0)
+	sw := &MusicSwitchCntr{Id: 10, DecisionTreeData: tree}
+	broken := &MusicSwitchCntr{Id: 20, DecisionTreeData: []byte{1, 0, 0, 0, 2, 0, 0, 0}}
+	h.HircObjs = []HircObj{sw, broken}
+
+	args, err := sw.DecisionTreeArguments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 2 || len(args[0].Keys) != 2 || len(args[1].Keys) != 2 {
+		t.Fatalf("Unexpected decision tree arguments %+v", args)
+	}
+	if _, err := broken.DecisionTreeArguments(); err == nil {
+		t.Fatal("Expecting error on truncated decision tree")
+	}
+
+	x := h.BuildXRef(141)
+	for id, r := range map[uint32]RefType{
+		100: RefTypeSwitchGroup,
+		101: RefTypeSwitch,
+		200: RefTypeStateGroup,
+		201: RefTypeState,
+		202: RefTypeState,
+	} {
+		if len(x.ReferencesOf(id, r)) != 1 {
+			t.Fatalf("Expect music switch container 10 references %d as %s", id, RefTypeName[r])
+		}
+	}
+}